@@ -45,6 +45,8 @@ func startHttpServer() {
 	e.GET("/volumes", fetchAllVolumes)
 	e.GET("/volumes/:uuid", fetchVolume)
 	e.POST("/volumes/:uuid/size", fetchVolumeSize)
+	e.GET("/volumes/:uuid/backup", backupVolume)
+	e.GET("/volumes/:uuid/restore", restoreVolume)
 	e.POST("/volumes", createVolume)
 	e.DELETE("/volumes/:uuid", deleteVolume)
 
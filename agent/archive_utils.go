@@ -0,0 +1,363 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const transferProgressInterval = 2 * time.Second
+
+// directorySize returns the total size of regular files in the directory
+func directorySize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// clearDirectory removes all the contents of the directory except the skipped names, but keeps the directory itself
+func clearDirectory(path string, skip ...string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if slices.Contains(skip, entry.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTarGzArchive writes a tar.gz archive of the source directory to the writer
+// Progress is reported in terms of bytes of file content read from the source directory
+func createTarGzArchive(sourcePath string, writer io.Writer, reporter *transferProgressReporter) error {
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := filepath.WalkDir(sourcePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(sourcePath, path)
+		if err != nil {
+			return err
+		}
+		if relativePath == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relativePath)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tarWriter, &progressReader{reader: file, reporter: reporter})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	reporter.Done()
+	return nil
+}
+
+// extractTarGzArchive extracts a tar.gz archive into the destination directory
+// Entries and links which resolve outside the destination, or which are written through a symlink, are rejected
+func extractTarGzArchive(reader io.Reader, destinationPath string) error {
+	destinationPath = filepath.Clean(destinationPath)
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Prevent path traversal
+		targetPath := filepath.Join(destinationPath, filepath.Clean("/"+header.Name))
+		if targetPath == destinationPath || !isPathInsideDirectory(destinationPath, targetPath) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+		// Prevent writing outside the destination through a symlink extracted earlier
+		if err := ensureNoSymlinkInPath(destinationPath, targetPath); err != nil {
+			return fmt.Errorf("invalid path in archive: %s: %w", header.Name, err)
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(targetPath, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tarReader)
+			_ = file.Close()
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Relative target is resolved from the directory of the link, absolute target can't be confined to the destination
+			if filepath.IsAbs(header.Linkname) || !isPathInsideDirectory(destinationPath, filepath.Join(filepath.Dir(targetPath), header.Linkname)) {
+				return fmt.Errorf("invalid symlink in archive: %s -> %s", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, targetPath); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			// Target of a hard link is a path in the archive
+			linkTargetPath := filepath.Join(destinationPath, header.Linkname)
+			if !isPathInsideDirectory(destinationPath, linkTargetPath) {
+				return fmt.Errorf("invalid hard link in archive: %s -> %s", header.Name, header.Linkname)
+			}
+			if err := ensureNoSymlinkInPath(destinationPath, linkTargetPath); err != nil {
+				return fmt.Errorf("invalid hard link in archive: %s -> %s: %w", header.Name, header.Linkname, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return err
+			}
+			if err := os.Link(linkTargetPath, targetPath); err != nil {
+				return err
+			}
+			continue
+		default:
+			// Skip devices and fifos
+			continue
+		}
+		// Restore ownership, it can fail on CIFS shares as ownership is fixed by mount options
+		_ = os.Lchown(targetPath, header.Uid, header.Gid)
+		if header.Typeflag != tar.TypeSymlink {
+			_ = os.Chmod(targetPath, mode)
+			_ = os.Chtimes(targetPath, header.ModTime, header.ModTime)
+		}
+	}
+}
+
+// isPathInsideDirectory checks if the cleaned path is the directory itself or inside it
+func isPathInsideDirectory(directory string, path string) bool {
+	relativePath, err := filepath.Rel(directory, path)
+	if err != nil {
+		return false
+	}
+	return relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(os.PathSeparator))
+}
+
+// ensureNoSymlinkInPath returns an error if any existing component of the path below the root is a symlink
+func ensureNoSymlinkInPath(root string, path string) error {
+	relativePath, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	currentPath := root
+	for _, component := range strings.Split(relativePath, string(os.PathSeparator)) {
+		currentPath = filepath.Join(currentPath, component)
+		info, err := os.Lstat(currentPath)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink", currentPath)
+		}
+	}
+	return nil
+}
+
+// restoreTarGzArchive replaces the contents of the directory with the contents of the archive
+// The archive is extracted in a staging directory first, so the existing contents are kept if the archive is corrupt
+// Staging directory is created inside the directory, as the directory can be the root of a mounted share which can't be replaced
+func restoreTarGzArchive(reader io.Reader, dataPath string) error {
+	stagingPath, err := os.MkdirTemp(dataPath, ".swiftwave-restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(stagingPath)
+	}()
+	if err := extractTarGzArchive(reader, stagingPath); err != nil {
+		return err
+	}
+	previousPath, err := os.MkdirTemp(dataPath, ".swiftwave-previous-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(previousPath)
+	}()
+	skip := []string{filepath.Base(stagingPath), filepath.Base(previousPath)}
+	// Move the existing contents aside, and put them back if the swap fails
+	if err := moveDirectoryEntries(dataPath, previousPath, skip); err != nil {
+		_ = moveDirectoryEntries(previousPath, dataPath, nil)
+		return fmt.Errorf("failed to move existing volume contents: %w", err)
+	}
+	if err := moveDirectoryEntries(stagingPath, dataPath, nil); err != nil {
+		_ = clearDirectory(dataPath, skip...)
+		_ = moveDirectoryEntries(previousPath, dataPath, nil)
+		return fmt.Errorf("failed to move restored volume contents: %w", err)
+	}
+	return nil
+}
+
+// moveDirectoryEntries renames all the entries of the source directory into the destination directory, except the skipped names
+func moveDirectoryEntries(sourcePath string, destinationPath string, skip []string) error {
+	entries, err := os.ReadDir(sourcePath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if slices.Contains(skip, entry.Name()) {
+			continue
+		}
+		if err := os.Rename(filepath.Join(sourcePath, entry.Name()), filepath.Join(destinationPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveMountAddress replaces the hostname in the addr option with the ip address
+func resolveMountAddress(options string) (string, error) {
+	parts := strings.Split(options, ",")
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "addr=") {
+			continue
+		}
+		host := strings.TrimPrefix(part, "addr=")
+		if net.ParseIP(host) != nil {
+			return options, nil
+		}
+		ipAddr, err := net.ResolveIPAddr("ip", host)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+		parts[i] = "addr=" + ipAddr.String()
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// transferProgressReporter sends progress of a transfer stage to the channel
+// Updates are throttled and dropped if the receiver is not ready, so a slow receiver never blocks the transfer
+type transferProgressReporter struct {
+	stage            VolumeTransferStage
+	totalBytes       int64
+	transferredBytes int64
+	lastReportedAt   time.Time
+	progressChannel  chan VolumeTransferProgress
+}
+
+func newTransferProgressReporter(stage VolumeTransferStage, totalBytes int64, progressChannel chan VolumeTransferProgress) *transferProgressReporter {
+	return &transferProgressReporter{
+		stage:           stage,
+		totalBytes:      totalBytes,
+		progressChannel: progressChannel,
+	}
+}
+
+func (r *transferProgressReporter) Add(n int64) {
+	r.transferredBytes += n
+	if time.Since(r.lastReportedAt) < transferProgressInterval {
+		return
+	}
+	r.report()
+}
+
+func (r *transferProgressReporter) Done() {
+	r.report()
+}
+
+func (r *transferProgressReporter) report() {
+	r.lastReportedAt = time.Now()
+	if r.progressChannel == nil {
+		return
+	}
+	select {
+	case r.progressChannel <- VolumeTransferProgress{
+		Stage:            r.stage,
+		TransferredBytes: r.transferredBytes,
+		TotalBytes:       r.totalBytes,
+	}:
+	default:
+	}
+}
+
+type progressReader struct {
+	reader   io.Reader
+	reporter *transferProgressReporter
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.reporter.Add(int64(n))
+	}
+	return n, err
+}
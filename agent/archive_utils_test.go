@@ -0,0 +1,180 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testArchiveEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+func createTestArchive(t *testing.T, entries []testArchiveEntry) *bytes.Buffer {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Mode:     0644,
+			Size:     int64(len(entry.content)),
+		}
+		if entry.typeflag == tar.TypeDir {
+			header.Mode = 0755
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertTestFile(t *testing.T, path string, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if string(content) != expected {
+		t.Fatalf("unexpected content of %s: %q", path, content)
+	}
+}
+
+func assertDirectoryEntries(t *testing.T, path string, expected ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != len(expected) {
+		t.Fatalf("unexpected entries in %s: %v", path, names)
+	}
+	for i := range names {
+		if names[i] != expected[i] {
+			t.Fatalf("unexpected entries in %s: %v", path, names)
+		}
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	sourcePath := t.TempDir()
+	writeTestFile(t, filepath.Join(sourcePath, "config.yml"), "port: 80")
+	writeTestFile(t, filepath.Join(sourcePath, "data", "records.db"), "records")
+	if err := os.Symlink("data/records.db", filepath.Join(sourcePath, "latest.db")); err != nil {
+		t.Fatal(err)
+	}
+	archive := &bytes.Buffer{}
+	if err := createTarGzArchive(sourcePath, archive, newTransferProgressReporter(VolumeTransferStageArchiving, 0, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	dataPath := t.TempDir()
+	writeTestFile(t, filepath.Join(dataPath, "stale.txt"), "stale")
+	if err := restoreTarGzArchive(archive, dataPath); err != nil {
+		t.Fatal(err)
+	}
+	// existing contents are replaced and the staging directories are removed
+	assertDirectoryEntries(t, dataPath, "config.yml", "data", "latest.db")
+	assertTestFile(t, filepath.Join(dataPath, "config.yml"), "port: 80")
+	assertTestFile(t, filepath.Join(dataPath, "latest.db"), "records")
+}
+
+func TestRestoreOfBadArchiveKeepsData(t *testing.T) {
+	dataPath := t.TempDir()
+	writeTestFile(t, filepath.Join(dataPath, "data", "records.db"), "records")
+
+	// not a gzip stream
+	if err := restoreTarGzArchive(bytes.NewBufferString("not an archive"), dataPath); err == nil {
+		t.Fatal("restore of invalid archive should fail")
+	}
+	assertDirectoryEntries(t, dataPath, "data")
+	assertTestFile(t, filepath.Join(dataPath, "data", "records.db"), "records")
+
+	// truncated archive
+	archive := createTestArchive(t, []testArchiveEntry{
+		{name: "config.yml", typeflag: tar.TypeReg, content: "port: 80"},
+		{name: "large.bin", typeflag: tar.TypeReg, content: string(bytes.Repeat([]byte("swiftwave"), 10000))},
+	})
+	truncated := bytes.NewBuffer(archive.Bytes()[:archive.Len()/2])
+	if err := restoreTarGzArchive(truncated, dataPath); err == nil {
+		t.Fatal("restore of truncated archive should fail")
+	}
+	assertDirectoryEntries(t, dataPath, "data")
+	assertTestFile(t, filepath.Join(dataPath, "data", "records.db"), "records")
+}
+
+func TestExtractRejectsEscapingLinks(t *testing.T) {
+	testCases := map[string][]testArchiveEntry{
+		"absolute symlink": {
+			{name: "etc", typeflag: tar.TypeSymlink, linkname: "/etc"},
+		},
+		"relative symlink outside": {
+			{name: "data/parent", typeflag: tar.TypeSymlink, linkname: "../../"},
+		},
+		"hard link outside": {
+			{name: "passwd", typeflag: tar.TypeLink, linkname: "../../etc/passwd"},
+		},
+		"write through symlink": {
+			{name: "data", typeflag: tar.TypeDir},
+			{name: "link", typeflag: tar.TypeSymlink, linkname: "data"},
+			{name: "link/file.txt", typeflag: tar.TypeReg, content: "escaped"},
+		},
+	}
+	for name, entries := range testCases {
+		t.Run(name, func(t *testing.T) {
+			destinationPath := filepath.Join(t.TempDir(), "volume")
+			if err := os.Mkdir(destinationPath, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := extractTarGzArchive(createTestArchive(t, entries), destinationPath); err == nil {
+				t.Fatal("archive with escaping link should be rejected")
+			}
+		})
+	}
+
+	// links inside the destination are allowed
+	destinationPath := t.TempDir()
+	err := extractTarGzArchive(createTestArchive(t, []testArchiveEntry{
+		{name: "data/records.db", typeflag: tar.TypeReg, content: "records"},
+		{name: "data/latest.db", typeflag: tar.TypeSymlink, linkname: "records.db"},
+		{name: "backup.db", typeflag: tar.TypeLink, linkname: "data/records.db"},
+	}), destinationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTestFile(t, filepath.Join(destinationPath, "data", "latest.db"), "records")
+	assertTestFile(t, filepath.Join(destinationPath, "backup.db"), "records")
+}
@@ -31,6 +31,8 @@ func init() {
 	setupCmd.Flags().String("docker-network-subnet", "", "Docker network subnet")
 	setupCmd.Flags().String("swiftwave-service-address", "", "Swiftwave service address ip:port")
 	setupCmd.Flags().Bool("enable-haproxy", false, "Enable haproxy")
+	setupCmd.Flags().String("analytics-token", "", "Analytics service token of the server <id>:<token>, stats are pushed only if provided")
+	setupCmd.Flags().String("analytics-endpoint", "", "Analytics endpoint, defaults to http://<swiftwave-service-address>/service/analytics")
 
 	setupCmd.Flags().Bool("master-node", false, "Setup as a master node")
 	setupCmd.Flags().String("master-node-endpoint", "", "Master server endpoint")
@@ -68,6 +70,8 @@ var startCmd = &cobra.Command{
 		_ = SetupIptables()
 		// Start background workers for containers
 		go StartContainerBgWorker()
+		// Start stats collector
+		go StartStatsCollector()
 		// Start main process
 		go startHttpServer()
 		go startDnsServer()
@@ -168,6 +172,11 @@ var setupCmd = &cobra.Command{
 				Username: GenerateRandomString(10),
 				Password: GenerateRandomString(30),
 			},
+			AnalyticsConfig: AnalyticsConfig{
+				Enabled:  cmd.Flag("analytics-token").Value.String() != "",
+				Endpoint: cmd.Flag("analytics-endpoint").Value.String(),
+				Token:    cmd.Flag("analytics-token").Value.String(),
+			},
 		}
 
 		if !isMasterNode {
@@ -21,6 +21,7 @@ type AgentConfig struct {
 	MasterNodeConnectConfig MasterNodeConnectConfig `json:"master_node_connect_config" gorm:"embedded;embeddedPrefix:master_node_connect_config_"`
 	DockerNetwork           DockerNetworkConfig     `json:"docker_network" gorm:"embedded;embeddedPrefix:docker_network_"`
 	HaproxyConfig           HAProxyConfig           `json:"haproxy_config" gorm:"embedded;embeddedPrefix:haproxy_"`
+	AnalyticsConfig         AnalyticsConfig         `json:"analytics_config" gorm:"embedded;embeddedPrefix:analytics_"`
 }
 
 type WireguardConfig struct {
@@ -46,6 +47,20 @@ type HAProxyConfig struct {
 	Password string `json:"password" gorm:"column:password"`
 }
 
+type AnalyticsConfig struct {
+	Enabled  bool   `json:"enabled" gorm:"column:enabled"`
+	Endpoint string `json:"endpoint" gorm:"column:endpoint"` // full url, defaults to http://<swiftwave service address>/service/analytics
+	Token    string `json:"token" gorm:"column:token"`       // analytics service token of the server in <id>:<token> format
+}
+
+// AnalyticsEndpoint : endpoint where the collected stats are pushed
+func (c *AgentConfig) AnalyticsEndpoint() string {
+	if c.AnalyticsConfig.Endpoint != "" {
+		return c.AnalyticsConfig.Endpoint
+	}
+	return fmt.Sprintf("http://%s/service/analytics", c.SwiftwaveServiceAddress)
+}
+
 func GetConfig() (*AgentConfig, error) {
 	var config AgentConfig
 	if err := rDB.First(&config).Error; err != nil {
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// stats are sampled every minute, aligned to the minute boundaries
+// so that samples of all the servers get merged in the same record by swiftwave service
+const statsCollectInterval = time.Minute
+
+// statsCollector : keeps the previous samples to report the deltas
+type statsCollector struct {
+	hostCpu    cpuSample
+	hostNet    netSample
+	containers map[string]containerSample
+}
+
+func StartStatsCollector() {
+	config, err := GetConfig()
+	if err != nil {
+		log.Println("Failed to fetch config for stats collector", err)
+		return
+	}
+	if !config.AnalyticsConfig.Enabled {
+		log.Println("Analytics is disabled, stats collector will not run")
+		return
+	}
+	httpClient := &http.Client{Timeout: 15 * time.Second}
+	collector := &statsCollector{}
+	// take the initial sample
+	if _, err := collector.collect(context.Background(), statsCollectInterval); err != nil {
+		log.Println("Failed to collect initial stats", err)
+	}
+	for {
+		// wait till next minute
+		now := time.Now()
+		time.Sleep(now.Truncate(statsCollectInterval).Add(statsCollectInterval).Sub(now))
+		data, err := collector.collect(context.Background(), statsCollectInterval)
+		if err != nil {
+			log.Println("Failed to collect stats", err)
+			continue
+		}
+		err = pushStats(httpClient, config.AnalyticsEndpoint(), config.AnalyticsConfig.Token, data)
+		if err != nil {
+			log.Println("Failed to push stats", err)
+		}
+	}
+}
+
+// collect : sample the host and containers, and return the usage since last sample
+func (s *statsCollector) collect(ctx context.Context, interval time.Duration) (*StatsData, error) {
+	timestamp := time.Now().Truncate(interval)
+	// host stats
+	hostCpu, err := readHostCpuSample()
+	if err != nil {
+		return nil, err
+	}
+	memoryStat, err := readHostMemoryStat()
+	if err != nil {
+		return nil, err
+	}
+	diskStats, err := readHostDiskStats()
+	if err != nil {
+		return nil, err
+	}
+	hostNet, err := readHostNetSample()
+	if err != nil {
+		return nil, err
+	}
+	data := &StatsData{
+		SystemStat: HostStats{
+			CpuUsagePercent: cpuUsagePercent(s.hostCpu, hostCpu),
+			MemStat:         memoryStat,
+			DiskStats:       diskStats,
+			NetStat: NetStat{
+				SentKB: counterDelta(s.hostNet.sentBytes, hostNet.sentBytes) / 1024,
+				RecvKB: counterDelta(s.hostNet.recvBytes, hostNet.recvBytes) / 1024,
+			},
+		},
+		ServiceStats:    make(map[string]*ServiceStats),
+		TimeStamp:       uint64(timestamp.Unix()),
+		IntervalSeconds: uint64(interval.Seconds()),
+	}
+	s.hostCpu = hostCpu
+	s.hostNet = hostNet
+	// container stats, grouped by service
+	containers, err := readContainerSamples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for containerId, current := range containers {
+		serviceStats, ok := data.ServiceStats[current.serviceName]
+		if !ok {
+			serviceStats = &ServiceStats{}
+			data.ServiceStats[current.serviceName] = serviceStats
+		}
+		serviceStats.UsedMemoryMB += current.usedMemory / (1024 * 1024)
+		// cpu and network needs previous sample of the container
+		previous, ok := s.containers[containerId]
+		if !ok {
+			continue
+		}
+		serviceStats.ServiceCpuTime += counterDelta(previous.cpuTime, current.cpuTime)
+		serviceStats.SystemCpuTime += counterDelta(previous.systemCpuTime, current.systemCpuTime)
+		serviceStats.NetStat.SentKB += counterDelta(previous.net.sentBytes, current.net.sentBytes) / 1024
+		serviceStats.NetStat.RecvKB += counterDelta(previous.net.recvBytes, current.net.recvBytes) / 1024
+	}
+	for _, serviceStats := range data.ServiceStats {
+		if serviceStats.SystemCpuTime > 0 {
+			serviceStats.CpuUsagePercent = uint8(float64(serviceStats.ServiceCpuTime) / float64(serviceStats.SystemCpuTime) * 100)
+		}
+	}
+	// stopped containers are dropped from the previous samples
+	s.containers = containers
+	return data, nil
+}
+
+func pushStats(httpClient *http.Client, endpoint string, token string, data *StatsData) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("analytics endpoint responded with status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+const swarmServiceNameLabel = "com.docker.swarm.service.name"
+
+// cpuSample : cumulative cpu time from /proc/stat
+type cpuSample struct {
+	total uint64
+	idle  uint64
+}
+
+// netSample : cumulative network transfer in bytes
+type netSample struct {
+	sentBytes uint64
+	recvBytes uint64
+}
+
+// containerSample : cumulative counters of a container
+type containerSample struct {
+	serviceName   string
+	cpuTime       uint64 // nanoseconds
+	systemCpuTime uint64 // nanoseconds
+	usedMemory    uint64 // bytes
+	net           netSample
+}
+
+func readHostCpuSample() (cpuSample, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuSample{}, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		return parseCpuSample(fields[1:])
+	}
+	return cpuSample{}, errors.New("cpu line not found in /proc/stat")
+}
+
+// parseCpuSample : user nice system idle iowait irq softirq steal [guest guest_nice]
+// guest time is already included in user time, so it's not counted
+func parseCpuSample(fields []string) (cpuSample, error) {
+	var sample cpuSample
+	for i, field := range fields {
+		if i >= 8 {
+			break
+		}
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return cpuSample{}, err
+		}
+		sample.total += value
+		// idle and iowait
+		if i == 3 || i == 4 {
+			sample.idle += value
+		}
+	}
+	return sample, nil
+}
+
+func cpuUsagePercent(previous cpuSample, current cpuSample) uint8 {
+	if current.total <= previous.total {
+		return 0
+	}
+	totalDelta := float64(current.total - previous.total)
+	idleDelta := float64(current.idle - previous.idle)
+	if idleDelta > totalDelta {
+		return 0
+	}
+	return uint8((totalDelta - idleDelta) / totalDelta * 100)
+}
+
+func readHostMemoryStat() (HostMemoryStat, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return HostMemoryStat{}, err
+	}
+	defer file.Close()
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = value
+	}
+	// values are in KB
+	const kbInGB = 1024 * 1024
+	total := values["MemTotal"]
+	available := values["MemAvailable"]
+	if total == 0 {
+		return HostMemoryStat{}, errors.New("failed to read total memory")
+	}
+	return HostMemoryStat{
+		TotalGB:  float32(total) / kbInGB,
+		UsedGB:   float32(total-available) / kbInGB,
+		CachedGB: float32(values["Cached"]+values["Buffers"]) / kbInGB,
+	}, nil
+}
+
+// readHostDiskStats : usage of the block devices mounted on the host
+func readHostDiskStats() ([]HostDiskStat, error) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	const bytesInGB = 1024 * 1024 * 1024
+	disks := make([]HostDiskStat, 0)
+	seenDevices := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		device, mountPoint := fields[0], fields[1]
+		if !strings.HasPrefix(device, "/dev/") || strings.HasPrefix(device, "/dev/loop") || seenDevices[device] {
+			continue
+		}
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(mountPoint, &stat); err != nil {
+			continue
+		}
+		seenDevices[device] = true
+		blockSize := uint64(stat.Bsize)
+		disks = append(disks, HostDiskStat{
+			Path:       device,
+			MountPoint: mountPoint,
+			TotalGB:    float32(stat.Blocks*blockSize) / bytesInGB,
+			UsedGB:     float32((stat.Blocks-stat.Bfree)*blockSize) / bytesInGB,
+		})
+	}
+	return disks, nil
+}
+
+// readHostNetSample : transfer of the physical interfaces
+// virtual interfaces (docker bridges, veth, wireguard) are skipped to avoid counting same traffic twice
+func readHostNetSample() (netSample, error) {
+	file, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return netSample{}, err
+	}
+	defer file.Close()
+	var sample netSample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		iface, counters, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		iface = strings.TrimSpace(iface)
+		if _, err := os.Stat(filepath.Join("/sys/class/net", iface, "device")); err != nil {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+		recvBytes, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		sentBytes, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			continue
+		}
+		sample.recvBytes += recvBytes
+		sample.sentBytes += sentBytes
+	}
+	return sample, nil
+}
+
+// readContainerSamples : counters of the running containers which are part of a service
+func readContainerSamples(ctx context.Context) (map[string]containerSample, error) {
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	samples := make(map[string]containerSample)
+	for _, c := range containers {
+		serviceName := c.Labels[swarmServiceNameLabel]
+		if serviceName == "" {
+			continue
+		}
+		sample, err := readContainerSample(ctx, c.ID)
+		if err != nil {
+			continue
+		}
+		sample.serviceName = serviceName
+		samples[c.ID] = sample
+	}
+	return samples, nil
+}
+
+func readContainerSample(ctx context.Context, containerId string) (containerSample, error) {
+	res, err := dockerClient.ContainerStatsOneShot(ctx, containerId)
+	if err != nil {
+		return containerSample{}, err
+	}
+	defer res.Body.Close()
+	var stats container.StatsResponse
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return containerSample{}, err
+	}
+	sample := containerSample{
+		cpuTime:       stats.CPUStats.CPUUsage.TotalUsage,
+		systemCpuTime: stats.CPUStats.SystemUsage,
+		usedMemory:    containerUsedMemory(stats.MemoryStats),
+	}
+	for _, network := range stats.Networks {
+		sample.net.recvBytes += network.RxBytes
+		sample.net.sentBytes += network.TxBytes
+	}
+	return sample, nil
+}
+
+// containerUsedMemory : memory usage without the page cache, same as `docker stats`
+func containerUsedMemory(memoryStats container.MemoryStats) uint64 {
+	// cgroup v1
+	if value, ok := memoryStats.Stats["total_inactive_file"]; ok && value < memoryStats.Usage {
+		return memoryStats.Usage - value
+	}
+	// cgroup v2
+	if value, ok := memoryStats.Stats["inactive_file"]; ok && value < memoryStats.Usage {
+		return memoryStats.Usage - value
+	}
+	return memoryStats.Usage
+}
+
+// counterDelta : difference of two cumulative counters, counters can be reset (e.g. container restart)
+func counterDelta(previous uint64, current uint64) uint64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
@@ -39,3 +39,72 @@ type JournalRequest struct {
 	Fields    []string `json:"fields"`
 	SinceTime string   `json:"since_time"` // RFC3339 format timestamp
 }
+
+type VolumeBackupRequest struct {
+	UploadURL string `json:"upload_url"` // URL to upload the tar.gz archive with a PUT request
+}
+
+type VolumeRestoreRequest struct {
+	DownloadURL string `json:"download_url"` // URL to download the tar.gz archive from
+	Checksum    string `json:"checksum"`     // sha256 checksum of the archive, optional
+}
+
+type VolumeTransferStage string
+
+const (
+	VolumeTransferStageArchiving   VolumeTransferStage = "archiving"
+	VolumeTransferStageUploading   VolumeTransferStage = "uploading"
+	VolumeTransferStageDownloading VolumeTransferStage = "downloading"
+	VolumeTransferStageExtracting  VolumeTransferStage = "extracting"
+	VolumeTransferStageCompleted   VolumeTransferStage = "completed"
+	VolumeTransferStageFailed      VolumeTransferStage = "failed"
+)
+
+type VolumeTransferProgress struct {
+	Stage            VolumeTransferStage `json:"stage"`
+	TransferredBytes int64               `json:"transferred_bytes"`
+	TotalBytes       int64               `json:"total_bytes"` // -1 if unknown
+	Checksum         string              `json:"checksum,omitempty"`
+	Error            string              `json:"error,omitempty"`
+}
+
+// StatsData : payload pushed to the analytics endpoint of swiftwave service
+type StatsData struct {
+	SystemStat      HostStats                `json:"system"`
+	ServiceStats    map[string]*ServiceStats `json:"services"`
+	TimeStamp       uint64                   `json:"timestamp"`        // unix timestamp (in seconds)
+	IntervalSeconds uint64                   `json:"interval_seconds"` // cpu and network counters are deltas of this duration
+}
+
+type HostStats struct {
+	CpuUsagePercent uint8          `json:"cpu_usage_percent"`
+	MemStat         HostMemoryStat `json:"memory"`
+	DiskStats       []HostDiskStat `json:"disks"`
+	NetStat         NetStat        `json:"network"`
+}
+
+type HostMemoryStat struct {
+	TotalGB  float32 `json:"total_gb"`
+	UsedGB   float32 `json:"used_gb"`
+	CachedGB float32 `json:"cached_gb"`
+}
+
+type HostDiskStat struct {
+	Path       string  `json:"path"`
+	MountPoint string  `json:"mount_point"`
+	TotalGB    float32 `json:"total_gb"`
+	UsedGB     float32 `json:"used_gb"`
+}
+
+type NetStat struct {
+	SentKB uint64 `json:"sent_kb"`
+	RecvKB uint64 `json:"recv_kb"`
+}
+
+type ServiceStats struct {
+	ServiceCpuTime  uint64  `json:"service_cpu_time"`
+	SystemCpuTime   uint64  `json:"system_cpu_time"`
+	CpuUsagePercent uint8   `json:"cpu_usage_percent"`
+	UsedMemoryMB    uint64  `json:"used_memory_mb"`
+	NetStat         NetStat `json:"network"`
+}
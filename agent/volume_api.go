@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
 )
 
 func createVolume(c echo.Context) error {
@@ -112,3 +113,89 @@ func fetchVolumeSize(c echo.Context) error {
 		Data:    size,
 	})
 }
+
+func backupVolume(c echo.Context) error {
+	v, err := FetchVolumeByUUID(c.Param("uuid"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, Response{
+			Message: "Failed to fetch volume",
+			Error:   err.Error(),
+		})
+	}
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		var req VolumeBackupRequest
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			c.Logger().Error("Failed to receive request:", err)
+			return
+		}
+		if req.UploadURL == "" {
+			_ = websocket.JSON.Send(ws, VolumeTransferProgress{Stage: VolumeTransferStageFailed, Error: "upload_url is required"})
+			return
+		}
+
+		streamVolumeTransferProgress(c, ws, func(progressChannel chan VolumeTransferProgress) (string, error) {
+			return v.Backup(req.UploadURL, progressChannel)
+		})
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+func restoreVolume(c echo.Context) error {
+	v, err := FetchVolumeByUUID(c.Param("uuid"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, Response{
+			Message: "Failed to fetch volume",
+			Error:   err.Error(),
+		})
+	}
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		var req VolumeRestoreRequest
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			c.Logger().Error("Failed to receive request:", err)
+			return
+		}
+		if req.DownloadURL == "" {
+			_ = websocket.JSON.Send(ws, VolumeTransferProgress{Stage: VolumeTransferStageFailed, Error: "download_url is required"})
+			return
+		}
+
+		streamVolumeTransferProgress(c, ws, func(progressChannel chan VolumeTransferProgress) (string, error) {
+			return req.Checksum, v.Restore(req.DownloadURL, req.Checksum, progressChannel)
+		})
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// streamVolumeTransferProgress runs the transfer and sends the progress updates over the websocket
+// The last message is always either completed (with the checksum) or failed (with the error)
+func streamVolumeTransferProgress(c echo.Context, ws *websocket.Conn, transfer func(progressChannel chan VolumeTransferProgress) (string, error)) {
+	progressChannel := make(chan VolumeTransferProgress, 10)
+	resultChannel := make(chan VolumeTransferProgress, 1)
+
+	go func() {
+		checksum, err := transfer(progressChannel)
+		if err != nil {
+			resultChannel <- VolumeTransferProgress{Stage: VolumeTransferStageFailed, Error: err.Error()}
+			return
+		}
+		resultChannel <- VolumeTransferProgress{Stage: VolumeTransferStageCompleted, Checksum: checksum}
+	}()
+
+	for {
+		select {
+		case progress := <-progressChannel:
+			if err := websocket.JSON.Send(ws, progress); err != nil {
+				c.Logger().Debug("WebSocket send error:", err)
+			}
+		case result := <-resultChannel:
+			if err := websocket.JSON.Send(ws, result); err != nil {
+				c.Logger().Debug("WebSocket send error:", err)
+			}
+			return
+		}
+	}
+}
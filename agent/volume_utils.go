@@ -2,9 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/docker/docker/api/types/volume"
 )
@@ -16,7 +23,7 @@ func init() {
 	if _, err := os.Stat(volumeBindsDefaultPath); os.IsNotExist(err) {
 		err := os.MkdirAll(volumeBindsDefaultPath, 0700)
 		if err != nil {
-			fmt.Printf("Failed to create volume binds directory: %v", err)
+			log.Printf("Failed to create volume binds directory: %v", err)
 			os.Exit(1)
 		}
 	}
@@ -66,20 +73,179 @@ func (v *Volume) CreateVolume() error {
 	}
 }
 
+// Size returns the total size of the volume contents in bytes
 func (v *Volume) Size() (int64, error) {
-	return 0, nil
+	dataPath, unmount, err := v.mountDataDirectory()
+	if err != nil {
+		return 0, err
+	}
+	defer unmount()
+	return directorySize(dataPath)
 }
 
-func (v *Volume) Backup(uploadUrl string) error {
-	return nil
+// Backup archives the volume contents as tar.gz and uploads it to the provided URL with a PUT request
+// It returns the sha256 checksum of the uploaded archive, which should be passed to Restore
+func (v *Volume) Backup(uploadUrl string, progressChannel chan VolumeTransferProgress) (string, error) {
+	dataPath, unmount, err := v.mountDataDirectory()
+	if err != nil {
+		return "", err
+	}
+	defer unmount()
+
+	totalSize, err := directorySize(dataPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate volume size: %w", err)
+	}
+
+	// Create the archive in a temporary file, so that the content length and checksum are known before upload
+	archiveFile, err := os.CreateTemp("", "swiftwave-volume-backup-*.tar.gz")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary archive: %w", err)
+	}
+	defer func() {
+		_ = archiveFile.Close()
+		_ = os.Remove(archiveFile.Name())
+	}()
+
+	hasher := sha256.New()
+	err = createTarGzArchive(dataPath, io.MultiWriter(archiveFile, hasher), newTransferProgressReporter(VolumeTransferStageArchiving, totalSize, progressChannel))
+	if err != nil {
+		return "", fmt.Errorf("failed to archive volume: %w", err)
+	}
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+
+	archiveInfo, err := archiveFile.Stat()
+	if err != nil {
+		return "", err
+	}
+	if _, err := archiveFile.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	// Upload the archive
+	reporter := newTransferProgressReporter(VolumeTransferStageUploading, archiveInfo.Size(), progressChannel)
+	req, err := http.NewRequest(http.MethodPut, uploadUrl, &progressReader{reader: archiveFile, reporter: reporter})
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = archiveInfo.Size()
+	req.Header.Set("Content-Type", "application/gzip")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload backup: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to upload backup: unexpected status code %d", res.StatusCode)
+	}
+	reporter.Done()
+	return checksum, nil
 }
 
-func (v *Volume) Restore(downloadUrl string) error {
+// Restore downloads a tar.gz archive from the provided URL and replaces the volume contents with it
+// If checksum is provided, the downloaded archive is verified against it before touching the volume
+func (v *Volume) Restore(downloadUrl string, checksum string, progressChannel chan VolumeTransferProgress) error {
+	dataPath, unmount, err := v.mountDataDirectory()
+	if err != nil {
+		return err
+	}
+	defer unmount()
+
+	// Download the archive to a temporary file
+	archiveFile, err := os.CreateTemp("", "swiftwave-volume-restore-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary archive: %w", err)
+	}
+	defer func() {
+		_ = archiveFile.Close()
+		_ = os.Remove(archiveFile.Name())
+	}()
+
+	res, err := http.Get(downloadUrl)
+	if err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("failed to download backup: unexpected status code %d", res.StatusCode)
+	}
+	hasher := sha256.New()
+	reporter := newTransferProgressReporter(VolumeTransferStageDownloading, res.ContentLength, progressChannel)
+	if _, err := io.Copy(io.MultiWriter(archiveFile, hasher), &progressReader{reader: res.Body, reporter: reporter}); err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+	reporter.Done()
+
+	// Verify the checksum
+	downloadedChecksum := hex.EncodeToString(hasher.Sum(nil))
+	if checksum != "" && !strings.EqualFold(checksum, downloadedChecksum) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, downloadedChecksum)
+	}
+
+	archiveInfo, err := archiveFile.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := archiveFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// Extract the archive, existing contents are replaced only if the extraction succeeds
+	reporter = newTransferProgressReporter(VolumeTransferStageExtracting, archiveInfo.Size(), progressChannel)
+	if err := restoreTarGzArchive(&progressReader{reader: archiveFile, reporter: reporter}, dataPath); err != nil {
+		return fmt.Errorf("failed to extract backup: %w", err)
+	}
+	reporter.Done()
 	return nil
 }
 
 // Private functions
 
+// mountDataDirectory returns a host path with the contents of the volume
+// For local volumes, it's the bind directory. For NFS and CIFS volumes, the share is mounted in a temporary directory
+// The returned function must be called to release the mount
+func (v *Volume) mountDataDirectory() (string, func(), error) {
+	if v.Type == LocalVolume {
+		return v.LocalVolumeFullPath(), func() {}, nil
+	}
+	mountType, device, options, err := v.mountOptions()
+	if err != nil {
+		return "", nil, err
+	}
+	// Docker resolves the address before mounting, do the same
+	options, err = resolveMountAddress(options)
+	if err != nil {
+		return "", nil, err
+	}
+	mountPath, err := os.MkdirTemp("", "swiftwave-volume-mount-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create mount directory: %w", err)
+	}
+	if err := syscall.Mount(device, mountPath, mountType, 0, options); err != nil {
+		_ = os.Remove(mountPath)
+		return "", nil, fmt.Errorf("failed to mount %s volume: %w", mountType, err)
+	}
+	return mountPath, func() {
+		if err := syscall.Unmount(mountPath, 0); err != nil {
+			log.Printf("Failed to unmount volume %s: %v", v.UUID, err)
+			return
+		}
+		_ = os.Remove(mountPath)
+	}, nil
+}
+
+// mountOptions returns the mount type, device and options for NFS and CIFS volumes
+func (v *Volume) mountOptions() (mountType string, device string, options string, err error) {
+	switch v.Type {
+	case NFSVolume:
+		return "nfs", ":" + v.NFSConfig.Path, "addr=" + v.NFSConfig.Host + ",rw,nfsvers=" + fmt.Sprint(v.NFSConfig.Version), nil
+	case CIFSVolume:
+		return "cifs", v.CIFSConfig.Share, fmt.Sprintf("addr=%s,username=%s,password=%s,file_mode=%s,dir_mode=%s,uid=%d,gid=%d", v.CIFSConfig.Host, v.CIFSConfig.Username, v.CIFSConfig.Password, v.CIFSConfig.FileMode, v.CIFSConfig.DirMode, v.CIFSConfig.Uid, v.CIFSConfig.Gid), nil
+	default:
+		return "", "", "", fmt.Errorf("unsupported volume type: %s", v.Type)
+	}
+}
+
 func createLocalVolume(v *Volume) error {
 	// create volume directory
 	err := os.MkdirAll(v.LocalVolumeFullPath(), 0755)
@@ -100,26 +266,25 @@ func createLocalVolume(v *Volume) error {
 }
 
 func createNFSVolume(v *Volume) error {
-	_, err := dockerClient.VolumeCreate(context.Background(), volume.CreateOptions{
-		Name:   v.UUID,
-		Driver: "local",
-		DriverOpts: map[string]string{
-			"type":   "nfs",
-			"o":      "addr=" + v.NFSConfig.Host + ",rw,nfsvers=" + fmt.Sprint(v.NFSConfig.Version),
-			"device": ":" + v.NFSConfig.Path,
-		},
-	})
-	return err
+	return createRemoteVolume(v)
 }
 
 func createCIFSVolume(v *Volume) error {
-	_, err := dockerClient.VolumeCreate(context.Background(), volume.CreateOptions{
+	return createRemoteVolume(v)
+}
+
+func createRemoteVolume(v *Volume) error {
+	mountType, device, options, err := v.mountOptions()
+	if err != nil {
+		return err
+	}
+	_, err = dockerClient.VolumeCreate(context.Background(), volume.CreateOptions{
 		Name:   v.UUID,
 		Driver: "local",
 		DriverOpts: map[string]string{
-			"type":   "cifs",
-			"o":      fmt.Sprintf("addr=%s,username=%s,password=%s,file_mode=%s,dir_mode=%s,uid=%d,gid=%d", v.CIFSConfig.Host, v.CIFSConfig.Username, v.CIFSConfig.Password, v.CIFSConfig.FileMode, v.CIFSConfig.DirMode, v.CIFSConfig.Uid, v.CIFSConfig.Gid),
-			"device": v.CIFSConfig.Share,
+			"type":   mountType,
+			"o":      options,
+			"device": device,
 		},
 	})
 	return err
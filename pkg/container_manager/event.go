@@ -0,0 +1,110 @@
+package containermanger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+const (
+	swarmServiceNameLabel = "com.docker.swarm.service.name"
+	swarmTaskIDLabel      = "com.docker.swarm.task.id"
+	swarmTaskNameLabel    = "com.docker.swarm.task.name"
+)
+
+// WatchContainerEvents Stream the events of the containers of swarm tasks running on the node
+// If since is not zero, the past events after it are streamed first, so the events missed while reconnecting are not lost
+// Events are streamed till the context of the manager is cancelled or the connection is lost, then the error channel receives the reason
+func (m Manager) WatchContainerEvents(since time.Time) (<-chan ContainerEvent, <-chan error) {
+	options := events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("label", swarmServiceNameLabel),
+			filters.Arg("event", string(events.ActionStart)),
+			filters.Arg("event", string(events.ActionKill)),
+			filters.Arg("event", string(events.ActionDie)),
+			filters.Arg("event", string(events.ActionOOM)),
+			filters.Arg("event", string(events.ActionHealthStatus)),
+		),
+	}
+	if !since.IsZero() {
+		options.Since = fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond())
+	}
+	messages, errs := m.client.Events(m.ctx, options)
+	containerEvents := make(chan ContainerEvent)
+	containerErrs := make(chan error, 1)
+	go func() {
+		defer close(containerEvents)
+		for {
+			select {
+			case err := <-errs:
+				if err == nil {
+					err = errors.New("container event stream closed")
+				}
+				containerErrs <- err
+				return
+			case message := <-messages:
+				event, ok := parseContainerEvent(message)
+				if !ok {
+					continue
+				}
+				select {
+				case containerEvents <- event:
+				case <-m.ctx.Done():
+					containerErrs <- m.ctx.Err()
+					return
+				}
+			}
+		}
+	}()
+	return containerEvents, containerErrs
+}
+
+// parseContainerEvent Convert the docker event to container event, returns false for the events which are not of interest
+func parseContainerEvent(message events.Message) (ContainerEvent, bool) {
+	var action ContainerEventAction
+	switch message.Action {
+	case events.ActionStart:
+		action = ContainerEventStart
+	case events.ActionKill:
+		action = ContainerEventKill
+	case events.ActionDie:
+		action = ContainerEventDie
+	case events.ActionOOM:
+		action = ContainerEventOOM
+	case events.ActionHealthStatusUnhealthy:
+		action = ContainerEventUnhealthy
+	default:
+		return ContainerEvent{}, false
+	}
+	attributes := message.Actor.Attributes
+	event := ContainerEvent{
+		Action:      action,
+		ContainerID: message.Actor.ID,
+		ServiceName: attributes[swarmServiceNameLabel],
+		TaskID:      attributes[swarmTaskIDLabel],
+		Time:        time.Unix(0, message.TimeNano),
+	}
+	if event.ServiceName == "" {
+		return ContainerEvent{}, false
+	}
+	if message.TimeNano == 0 {
+		event.Time = time.Unix(message.Time, 0)
+	}
+	// task name is of the format <service name>.<slot or node id>.<task id>
+	taskName := attributes[swarmTaskNameLabel]
+	taskName = strings.TrimPrefix(taskName, event.ServiceName+".")
+	event.TaskSlot = strings.TrimSuffix(taskName, "."+event.TaskID)
+	if action == ContainerEventDie {
+		exitCode, err := strconv.Atoi(attributes["exitCode"])
+		if err == nil {
+			event.ExitCode = exitCode
+		}
+	}
+	return event, true
+}
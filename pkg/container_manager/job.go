@@ -0,0 +1,113 @@
+package containermanger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stdcopy"
+	"io"
+)
+
+// JobStatus Fetch the status of the task of a replicated job service
+// Job is not completed till the task reaches a terminal state
+func (m Manager) JobStatus(serviceName string) (JobStatus, error) {
+	tasks, err := m.client.TaskList(m.ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("service", serviceName),
+		),
+	})
+	if err != nil {
+		return JobStatus{}, errors.New("error getting task list")
+	}
+	if len(tasks) == 0 {
+		return JobStatus{}, nil
+	}
+	// consider the latest task
+	task := tasks[0]
+	for _, t := range tasks[1:] {
+		if t.CreatedAt.After(task.CreatedAt) {
+			task = t
+		}
+	}
+	status := JobStatus{
+		Message: task.Status.Err,
+	}
+	switch task.Status.State {
+	case swarm.TaskStateComplete:
+		status.Completed = true
+		status.Succeeded = true
+	case swarm.TaskStateFailed, swarm.TaskStateRejected, swarm.TaskStateShutdown, swarm.TaskStateOrphaned, swarm.TaskStateRemove:
+		status.Completed = true
+		status.ExitCode = -1
+		if task.Status.ContainerStatus != nil && task.Status.ContainerStatus.ExitCode != 0 {
+			status.ExitCode = task.Status.ContainerStatus.ExitCode
+		}
+		if status.Message == "" {
+			status.Message = "task " + string(task.Status.State)
+		}
+	}
+	return status, nil
+}
+
+// JobLogs Fetch the logs of a replicated job service
+// Only the last `maxBytes` bytes are kept
+func (m Manager) JobLogs(serviceName string, maxBytes int) (string, error) {
+	logs, err := m.client.ServiceLogs(m.ctx, serviceName, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return "", errors.New("error getting service logs")
+	}
+	defer func(logs io.ReadCloser) {
+		_ = logs.Close()
+	}(logs)
+	var buffer bytes.Buffer
+	// stdout and stderr are multiplexed in the same stream
+	_, err = stdcopy.StdCopy(&buffer, &buffer, logs)
+	if err != nil {
+		return "", errors.New("error reading service logs")
+	}
+	content := buffer.Bytes()
+	if maxBytes > 0 && len(content) > maxBytes {
+		content = content[len(content)-maxBytes:]
+	}
+	return string(content), nil
+}
+
+// FollowJobLogs Stream the logs of a replicated job service line by line
+// It blocks till the context is cancelled, so the caller should cancel it once the job is completed
+func (m Manager) FollowJobLogs(ctx context.Context, serviceName string, onLine func(line string)) error {
+	logs, err := m.client.ServiceLogs(ctx, serviceName, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return errors.New("error getting service logs")
+	}
+	defer func(logs io.ReadCloser) {
+		_ = logs.Close()
+	}(logs)
+	// stdout and stderr are multiplexed in the same stream
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		_ = writer.CloseWithError(err)
+	}()
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		onLine(scanner.Text())
+	}
+	err = scanner.Err()
+	if err != nil && ctx.Err() == nil {
+		return errors.New("error reading service logs")
+	}
+	return nil
+}
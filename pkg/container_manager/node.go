@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
 	"strings"
 )
@@ -95,6 +96,65 @@ func (m Manager) ListNodes() (*map[string]swarm.Node, error) {
 	return &nodeMap, nil
 }
 
+// NodeResources fetches the total resources of the nodes, mapped by hostname
+func (m Manager) NodeResources() (map[string]Resource, error) {
+	nodes, err := m.client.NodeList(m.ctx, types.NodeListOptions{})
+	if err != nil {
+		return nil, errors.New("error fetching swarm nodes list")
+	}
+	resources := make(map[string]Resource)
+	for _, node := range nodes {
+		resources[node.Description.Hostname] = Resource{
+			MemoryMB: int(node.Description.Resources.MemoryBytes / 1024 / 1024),
+			NanoCPUs: node.Description.Resources.NanoCPUs,
+		}
+	}
+	return resources, nil
+}
+
+// NodeReservedResources fetches the resources reserved by the running tasks on the nodes, mapped by hostname
+// Tasks of the excluded services are not counted
+func (m Manager) NodeReservedResources(excludedServiceNames ...string) (map[string]Resource, error) {
+	nodes, err := m.client.NodeList(m.ctx, types.NodeListOptions{})
+	if err != nil {
+		return nil, errors.New("error fetching swarm nodes list")
+	}
+	hostnames := make(map[string]string)
+	for _, node := range nodes {
+		hostnames[node.ID] = node.Description.Hostname
+	}
+	excludedServiceIds := make(map[string]bool)
+	for _, serviceName := range excludedServiceNames {
+		service, _, err := m.client.ServiceInspectWithRaw(m.ctx, serviceName, types.ServiceInspectOptions{})
+		if err == nil {
+			excludedServiceIds[service.ID] = true
+		}
+	}
+	tasks, err := m.client.TaskList(m.ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("desired-state", string(swarm.TaskStateRunning)),
+		),
+	})
+	if err != nil {
+		return nil, errors.New("error getting task list")
+	}
+	resources := make(map[string]Resource)
+	for _, task := range tasks {
+		if excludedServiceIds[task.ServiceID] || task.Spec.Resources == nil || task.Spec.Resources.Reservations == nil {
+			continue
+		}
+		hostname, ok := hostnames[task.NodeID]
+		if !ok {
+			continue
+		}
+		resource := resources[hostname]
+		resource.MemoryMB += int(task.Spec.Resources.Reservations.MemoryBytes / 1024 / 1024)
+		resource.NanoCPUs += task.Spec.Resources.Reservations.NanoCPUs
+		resources[hostname] = resource
+	}
+	return resources, nil
+}
+
 // FetchNodeStatus fetches the status of a node
 func (m Manager) FetchNodeStatus(hostname string) (string, error) {
 	node, _, err := m.client.NodeInspectWithRaw(m.ctx, hostname)
@@ -0,0 +1,63 @@
+package containermanger
+
+import (
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// secrets are immutable in docker swarm
+// so the name of the secret is derived from its content, and a new secret is created whenever the content changes
+// Name of a secret is public, so it should be derived from a keyed hash (HMAC) of the content, a plain hash can be brute forced
+
+// SecretName generates the name of the secret of the application from the fingerprint of its key and content
+func SecretName(applicationId string, fingerprint string) string {
+	if len(fingerprint) > 16 {
+		fingerprint = fingerprint[:16]
+	}
+	return applicationId + "-" + fingerprint
+}
+
+// CreateSecret creates the secret with the given name, if it doesn't exist
+func (m Manager) CreateSecret(name string, content string, applicationId string) error {
+	_, _, err := m.client.SecretInspectWithRaw(m.ctx, name)
+	if err == nil {
+		return nil
+	}
+	_, err = m.client.SecretCreate(m.ctx, swarm.SecretSpec{
+		Annotations: swarm.Annotations{
+			Name: name,
+			Labels: map[string]string{
+				"applicationId": applicationId,
+			},
+		},
+		Data: []byte(content),
+	})
+	return err
+}
+
+// FetchDockerSecretId fetches the docker secret id of a secret
+func (m Manager) FetchDockerSecretId(name string) (string, error) {
+	secret, _, err := m.client.SecretInspectWithRaw(m.ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return secret.ID, nil
+}
+
+// PruneSecrets removes all the secrets with the given applicationId.
+// Secrets in use by the service can't be removed, so only the unused secrets will be removed
+// It will not raise any error if failed to remove a secret
+func (m Manager) PruneSecrets(applicationId string) {
+	res, err := m.client.SecretList(m.ctx, types.SecretListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", "applicationId="+applicationId),
+		),
+	})
+	if err != nil {
+		return
+	}
+	for _, s := range res {
+		_ = m.client.SecretRemove(m.ctx, s.ID)
+	}
+}
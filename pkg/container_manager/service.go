@@ -79,13 +79,16 @@ func (m Manager) GetService(serviceName string) (Service, error) {
 	// Set Resource data
 	if serviceData.Spec.TaskTemplate.Resources != nil && serviceData.Spec.TaskTemplate.Resources.Limits != nil {
 		service.ResourceLimit = Resource{
-			MemoryMB: int(serviceData.Spec.TaskTemplate.Resources.Limits.MemoryBytes / 1024 / 1024),
+			MemoryMB:  int(serviceData.Spec.TaskTemplate.Resources.Limits.MemoryBytes / 1024 / 1024),
+			NanoCPUs:  serviceData.Spec.TaskTemplate.Resources.Limits.NanoCPUs,
+			PidsLimit: serviceData.Spec.TaskTemplate.Resources.Limits.Pids,
 		}
 	}
 	// Set reserved resource
 	if serviceData.Spec.TaskTemplate.Resources != nil && serviceData.Spec.TaskTemplate.Resources.Reservations != nil {
 		service.ReservedResource = Resource{
 			MemoryMB: int(serviceData.Spec.TaskTemplate.Resources.Reservations.MemoryBytes / 1024 / 1024),
+			NanoCPUs: serviceData.Spec.TaskTemplate.Resources.Reservations.NanoCPUs,
 		}
 	}
 	return service, nil
@@ -276,6 +279,60 @@ func (m Manager) NoOfRunningTasks(serviceName string) (int, error) {
 	return runningCount, nil
 }
 
+// ServiceConvergence Fetch the rollout state of a service
+// Only the tasks of the current spec (desired state running) are considered, so old tasks of a rolling update are ignored
+// Tasks with a health check stay in `starting` state until they are healthy, so running replicas are healthy replicas
+func (m Manager) ServiceConvergence(serviceName string) (ServiceConvergence, error) {
+	serviceData, _, err := m.client.ServiceInspectWithRaw(m.ctx, serviceName, types.ServiceInspectOptions{})
+	if err != nil {
+		return ServiceConvergence{}, errors.New("error getting service")
+	}
+	tasks, err := m.client.TaskList(m.ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("service", serviceName),
+			filters.Arg("desired-state", string(swarm.TaskStateRunning)),
+		),
+	})
+	if err != nil {
+		return ServiceConvergence{}, errors.New("error getting task list")
+	}
+	convergence := ServiceConvergence{}
+	for _, task := range tasks {
+		if task.Status.State == swarm.TaskStateRunning {
+			convergence.RunningReplicas++
+		}
+	}
+	if serviceData.Spec.Mode.Replicated != nil && serviceData.Spec.Mode.Replicated.Replicas != nil {
+		convergence.DesiredReplicas = int(*serviceData.Spec.Mode.Replicated.Replicas)
+	} else {
+		// global service > one task per eligible node
+		convergence.DesiredReplicas = len(tasks)
+	}
+	if serviceData.UpdateStatus != nil {
+		convergence.UpdateState = string(serviceData.UpdateStatus.State)
+		convergence.UpdateMessage = serviceData.UpdateStatus.Message
+	}
+	return convergence, nil
+}
+
+// IsConverged : all the desired replicas are running and the update (if any) has completed
+func (c ServiceConvergence) IsConverged() bool {
+	if c.UpdateState != "" && c.UpdateState != string(swarm.UpdateStateCompleted) {
+		return false
+	}
+	return c.DesiredReplicas > 0 && c.RunningReplicas >= c.DesiredReplicas
+}
+
+// IsFailed : swarm has paused or rolled back the update, so it will never converge without intervention
+func (c ServiceConvergence) IsFailed() bool {
+	switch swarm.UpdateState(c.UpdateState) {
+	case swarm.UpdateStatePaused, swarm.UpdateStateRollbackStarted, swarm.UpdateStateRollbackPaused, swarm.UpdateStateRollbackCompleted:
+		return true
+	default:
+		return false
+	}
+}
+
 // ServiceRunningServers Fetch the servers where a service is running
 func (m Manager) ServiceRunningServers(serviceName string) ([]string, error) {
 	// fetch all nodes and store in map > nodeID:nodeDetails
@@ -400,6 +457,16 @@ func (m Manager) serviceToServiceSpec(service Service) (swarm.ServiceSpec, error
 		serviceMode = swarm.ServiceMode{
 			Global: &swarm.GlobalService{},
 		}
+	} else if service.DeploymentMode == DeploymentModeReplicatedJob {
+		// run once till completion
+		maxConcurrent := uint64(1)
+		totalCompletions := uint64(1)
+		serviceMode = swarm.ServiceMode{
+			ReplicatedJob: &swarm.ReplicatedJob{
+				MaxConcurrent:    &maxConcurrent,
+				TotalCompletions: &totalCompletions,
+			},
+		}
 	} else {
 		print(service.DeploymentMode)
 		panic("invalid deployment mode > ")
@@ -424,6 +491,25 @@ func (m Manager) serviceToServiceSpec(service Service) (swarm.ServiceSpec, error
 		})
 	}
 
+	// secret references
+	var secrets = make([]*swarm.SecretReference, 0)
+	for _, secret := range service.SecretMounts {
+		secretId, err := m.FetchDockerSecretId(secret.SecretName)
+		if err != nil {
+			return swarm.ServiceSpec{}, err
+		}
+		secrets = append(secrets, &swarm.SecretReference{
+			SecretName: secret.SecretName,
+			SecretID:   secretId,
+			File: &swarm.SecretReferenceFileTarget{
+				Name: secret.Target,
+				UID:  "0",
+				GID:  "0",
+				Mode: 0444,
+			},
+		})
+	}
+
 	// memory bytes
 	var reservedMemoryBytes int64 = 0
 	if service.ReservedResource.MemoryMB >= 6 {
@@ -463,6 +549,7 @@ func (m Manager) serviceToServiceSpec(service Service) (swarm.ServiceSpec, error
 				Env:      env,
 				Mounts:   volumeMounts,
 				Configs:  configs,
+				Secrets:  secrets,
 				Privileges: &swarm.Privileges{
 					NoNewPrivileges: true,
 					AppArmor: &swarm.AppArmorOpts{
@@ -482,9 +569,12 @@ func (m Manager) serviceToServiceSpec(service Service) (swarm.ServiceSpec, error
 			Resources: &swarm.ResourceRequirements{
 				Reservations: &swarm.Resources{
 					MemoryBytes: reservedMemoryBytes,
+					NanoCPUs:    service.ReservedResource.NanoCPUs,
 				},
 				Limits: &swarm.Limit{
 					MemoryBytes: limitMemoryBytes,
+					NanoCPUs:    service.ResourceLimit.NanoCPUs,
+					Pids:        service.ResourceLimit.PidsLimit,
 				},
 			},
 			// Set network name
@@ -497,5 +587,11 @@ func (m Manager) serviceToServiceSpec(service Service) (swarm.ServiceSpec, error
 			Mode: swarm.ResolutionModeDNSRR,
 		},
 	}
+	if service.DeploymentMode == DeploymentModeReplicatedJob {
+		// exit status of the job should be reported as it is
+		serviceSpec.TaskTemplate.RestartPolicy = &swarm.RestartPolicy{
+			Condition: swarm.RestartPolicyConditionNone,
+		}
+	}
 	return serviceSpec, nil
 }
@@ -2,6 +2,7 @@ package containermanger
 
 import (
 	"context"
+	"time"
 
 	"github.com/docker/docker/client"
 )
@@ -16,6 +17,8 @@ type DeploymentMode string
 const (
 	DeploymentModeReplicated DeploymentMode = "replicated"
 	DeploymentModeGlobal     DeploymentMode = "global"
+	// DeploymentModeReplicatedJob : run a single task till completion, it's not restarted on exit
+	DeploymentModeReplicatedJob DeploymentMode = "replicated-job"
 )
 
 type Service struct {
@@ -27,6 +30,7 @@ type Service struct {
 	Capabilities         []string          `json:"capabilities,omitempty"`
 	Sysctls              map[string]string `json:"sysctl,omitempty"`
 	ConfigMounts         []ConfigMount     `json:"configmounts,omitempty"`
+	SecretMounts         []SecretMount     `json:"secretmounts,omitempty"`
 	VolumeMounts         []VolumeMount     `json:"volumemounts,omitempty"`
 	VolumeBinds          []VolumeBind      `json:"volumebinds,omitempty"`
 	Networks             []string          `json:"networks,omitempty"`
@@ -55,6 +59,22 @@ type ServiceRealtimeInfo struct {
 	ReplicatedService bool   `json:"replicatedservice"`
 }
 
+// ServiceConvergence : rollout state of a service, used to verify a deployment after update
+type ServiceConvergence struct {
+	DesiredReplicas int    `json:"desiredreplicas"`
+	RunningReplicas int    `json:"runningreplicas"`
+	UpdateState     string `json:"updatestate"` // empty if the service has never been updated
+	UpdateMessage   string `json:"updatemessage"`
+}
+
+// JobStatus : status of the task of a replicated job
+type JobStatus struct {
+	Completed bool   `json:"completed"`
+	Succeeded bool   `json:"succeeded"`
+	ExitCode  int    `json:"exitcode"`
+	Message   string `json:"message"`
+}
+
 type ServiceTaskPlacementInfo struct {
 	NodeID          string `json:"nodeid"`
 	NodeName        string `json:"nodename"`
@@ -83,8 +103,18 @@ type ConfigMount struct {
 	MountingPath string `json:"mounting_path"`
 }
 
+// SecretMount : secret is mounted at /run/secrets/<target>
+type SecretMount struct {
+	SecretName string `json:"secret_name"`
+	Target     string `json:"target"`
+}
+
 type Resource struct {
 	MemoryMB int `json:"memory_mb,omitempty"`
+	// NanoCPUs - cpu in units of 10^-9 CPUs
+	NanoCPUs int64 `json:"nano_cpus,omitempty"`
+	// PidsLimit - maximum number of processes, only applicable for limit
+	PidsLimit int64 `json:"pids_limit,omitempty"`
 }
 
 type DockerProxyConfig struct {
@@ -127,3 +157,24 @@ type DockerProxyPermission struct {
 	Tasks        DockerProxyPermissionType `json:"tasks" gorm:"default:none"`
 	Volumes      DockerProxyPermissionType `json:"volumes" gorm:"default:none"`
 }
+
+// ContainerEventAction : actions of the containers of swarm tasks which are streamed by WatchContainerEvents
+type ContainerEventAction string
+
+const (
+	ContainerEventStart     ContainerEventAction = "start"
+	ContainerEventKill      ContainerEventAction = "kill"
+	ContainerEventDie       ContainerEventAction = "die"
+	ContainerEventOOM       ContainerEventAction = "oom"
+	ContainerEventUnhealthy ContainerEventAction = "unhealthy"
+)
+
+type ContainerEvent struct {
+	Action      ContainerEventAction `json:"action"`
+	ContainerID string               `json:"containerid"`
+	ServiceName string               `json:"servicename"`
+	TaskID      string               `json:"taskid"`
+	TaskSlot    string               `json:"taskslot"` // slot number for replicated service, node id for global service
+	ExitCode    int                  `json:"exitcode"` // only set for die action
+	Time        time.Time            `json:"time"`
+}
@@ -0,0 +1,190 @@
+package cronschedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule : parsed standard 5-field cron expression (minute hour day-of-month month day-of-week)
+type Schedule struct {
+	minute     fieldSet
+	hour       fieldSet
+	dayOfMonth fieldSet
+	month      fieldSet
+	dayOfWeek  fieldSet
+	// as per cron, if both day fields are restricted, the schedule matches when either of them matches
+	dayOfMonthRestricted bool
+	dayOfWeekRestricted  bool
+}
+
+// fieldSet : bitmap of the allowed values of a field
+type fieldSet uint64
+
+type fieldBounds struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteBounds     = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds       = fieldBounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = fieldBounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as sunday too
+	dayOfWeekBounds = fieldBounds{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse : parse a cron expression
+// Supports `*`, values, ranges (1-5), steps (*/15, 1-30/5), lists (1,15), month and weekday names and macros like @daily
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := macros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, errors.New("cron expression should have 5 fields (minute hour day-of-month month day-of-week)")
+	}
+	schedule := &Schedule{}
+	var err error
+	if schedule.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return nil, err
+	}
+	// fold 7 into 0, both are sunday
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek = (schedule.dayOfWeek | 1) &^ (1 << 7)
+	}
+	schedule.dayOfMonthRestricted = !strings.HasPrefix(fields[2], "*")
+	schedule.dayOfWeekRestricted = !strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+// Matches : check if the schedule is due at the minute of the given time
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.minute.has(t.Minute()) && s.hour.has(t.Hour()) && s.month.has(int(t.Month())) && s.dayMatches(t)
+}
+
+// Next : next time after the given time at which the schedule is due
+// Returns zero time if the schedule is never due (e.g. 30th February) within 5 years
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !s.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !s.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dayOfMonthMatch := s.dayOfMonth.has(t.Day())
+	dayOfWeekMatch := s.dayOfWeek.has(int(t.Weekday()))
+	if s.dayOfMonthRestricted && s.dayOfWeekRestricted {
+		return dayOfMonthMatch || dayOfWeekMatch
+	}
+	return dayOfMonthMatch && dayOfWeekMatch
+}
+
+func (f fieldSet) has(value int) bool {
+	return f&(1<<uint(value)) != 0
+}
+
+func parseField(field string, bounds fieldBounds) (fieldSet, error) {
+	var set fieldSet
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("invalid %s field %q", bounds.name, field)
+		}
+		rangePart, step := part, 1
+		if index := strings.Index(part, "/"); index != -1 {
+			rangePart = part[:index]
+			value, err := strconv.Atoi(part[index+1:])
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", bounds.name, part)
+			}
+			step = value
+		}
+		start, end := bounds.min, bounds.max
+		if rangePart != "*" {
+			bound := strings.SplitN(rangePart, "-", 2)
+			var err error
+			start, err = parseValue(bound[0], bounds)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if len(bound) == 2 {
+				end, err = parseValue(bound[1], bounds)
+				if err != nil {
+					return 0, err
+				}
+			} else if step != 1 {
+				// `5/15` means from 5 till the end with step 15
+				end = bounds.max
+			}
+			if end < start {
+				return 0, fmt.Errorf("invalid range in %s field %q", bounds.name, part)
+			}
+		}
+		for value := start; value <= end; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+func parseValue(value string, bounds fieldBounds) (int, error) {
+	if number, ok := bounds.names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", value, bounds.name)
+	}
+	if number < bounds.min || number > bounds.max {
+		return 0, fmt.Errorf("%s should be between %d and %d", bounds.name, bounds.min, bounds.max)
+	}
+	return number, nil
+}
@@ -0,0 +1,74 @@
+package cronschedule
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("valid expressions", func(t *testing.T) {
+		for _, expression := range []string{"* * * * *", "*/15 2 * * 1-5", "0 0 1,15 * *", "30 4 * jan-mar sun", "@daily", "5/10 * * * 7"} {
+			_, err := Parse(expression)
+			assert.NoError(t, err, expression)
+		}
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "1,,2 * * * *"} {
+			_, err := Parse(expression)
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestMatches(t *testing.T) {
+	// 2024-06-03 is a monday
+	monday := time.Date(2024, 6, 3, 2, 30, 0, 0, time.UTC)
+
+	t.Run("step and range", func(t *testing.T) {
+		schedule, err := Parse("*/15 2 * * 1-5")
+		assert.NoError(t, err)
+		assert.True(t, schedule.Matches(monday))
+		assert.False(t, schedule.Matches(monday.Add(time.Minute)))
+		assert.False(t, schedule.Matches(monday.AddDate(0, 0, 5)))
+	})
+
+	t.Run("sunday can be 0 or 7", func(t *testing.T) {
+		schedule, err := Parse("30 2 * * 7")
+		assert.NoError(t, err)
+		assert.True(t, schedule.Matches(monday.AddDate(0, 0, -1)))
+	})
+
+	t.Run("restricted day of month and day of week match either", func(t *testing.T) {
+		schedule, err := Parse("30 2 15 * mon")
+		assert.NoError(t, err)
+		assert.True(t, schedule.Matches(monday))
+		assert.True(t, schedule.Matches(time.Date(2024, 6, 15, 2, 30, 0, 0, time.UTC)))
+		assert.False(t, schedule.Matches(time.Date(2024, 6, 16, 2, 30, 0, 0, time.UTC)))
+	})
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2024, 6, 3, 2, 30, 20, 0, time.UTC)
+
+	t.Run("next minute", func(t *testing.T) {
+		schedule, _ := Parse("* * * * *")
+		assert.Equal(t, time.Date(2024, 6, 3, 2, 31, 0, 0, time.UTC), schedule.Next(from))
+	})
+
+	t.Run("next day", func(t *testing.T) {
+		schedule, _ := Parse("@daily")
+		assert.Equal(t, time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC), schedule.Next(from))
+	})
+
+	t.Run("next year", func(t *testing.T) {
+		schedule, _ := Parse("0 12 1 jan *")
+		assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), schedule.Next(from))
+	})
+
+	t.Run("never due", func(t *testing.T) {
+		schedule, _ := Parse("0 0 30 2 *")
+		assert.True(t, schedule.Next(from).IsZero())
+	})
+}
@@ -29,14 +29,21 @@ func (s Manager) AddBackendSwitch(transactionId string, listenerMode ListenerMod
 			"name":  backendName,
 		}
 	} else {
-		condTest := `{ hdr(host) -i ` + strings.TrimSpace(domainName) + `:` + strconv.Itoa(bindPort) + ` }`
-		if bindPort == 80 || bindPort == 443 {
-			condTest = `{ hdr(host) -i ` + strings.TrimSpace(domainName) + ` }`
-		}
+		condTest := backendSwitchCondition(bindPort, domainName)
 		aclIndex := 0
 		if listenerMode == HTTPMode && (bindPort == 80 || bindPort == 443) {
 			aclIndex = 1
 		}
+		// rules of wildcard domains are kept at the end, so the subdomains with their own rules are matched first
+		if isWildcardDomain(domainName) {
+			rules, err := s.fetchBackendSwitchRules(transactionId, listenerMode, bindPort)
+			if err != nil {
+				return err
+			}
+			if len(rules) > aclIndex {
+				aclIndex = len(rules)
+			}
+		}
 		reqBody = map[string]interface{}{
 			"cond":      "if",
 			"cond_test": condTest,
@@ -60,29 +67,11 @@ func (s Manager) AddBackendSwitch(transactionId string, listenerMode ListenerMod
 }
 
 func (s Manager) FetchBackendSwitchIndex(transactionId string, listenerMode ListenerMode, bindPort int, backendName string, domainName string) (int, error) {
-	frontendName := s.GenerateFrontendName(listenerMode, bindPort)
-	params := QueryParameters{}
-	params.add("transaction_id", transactionId)
-	params.add("frontend", frontendName)
-	// Send request
-	getBackendSwitchRes, getBackendSwitchErr := s.getRequest("/services/haproxy/configuration/backend_switching_rules", params)
-	if getBackendSwitchErr != nil || !isValidStatusCode(getBackendSwitchRes.StatusCode) {
-		return -1, errors.New("failed to fetch backend switch index")
-	}
-	defer func(body io.ReadCloser) {
-		_ = body.Close()
-	}(getBackendSwitchRes.Body)
-	// Parse response
-	var backendSwitchRulesData map[string]interface{}
-	err := json.NewDecoder(getBackendSwitchRes.Body).Decode(&backendSwitchRulesData)
+	backendSwitchRules, err := s.fetchBackendSwitchRules(transactionId, listenerMode, bindPort)
 	if err != nil {
 		return -1, err
 	}
-	backendSwitchRules := backendSwitchRulesData["data"].([]interface{})
-	condTest := `{ hdr(host) -i ` + strings.TrimSpace(domainName) + `:` + strconv.Itoa(bindPort) + ` }`
-	if bindPort == 80 || bindPort == 443 {
-		condTest = `{ hdr(host) -i ` + strings.TrimSpace(domainName) + ` }`
-	}
+	condTest := backendSwitchCondition(bindPort, domainName)
 	for _, r := range backendSwitchRules {
 		rule := r.(map[string]interface{})
 
@@ -129,3 +118,34 @@ func (s Manager) DeleteBackendSwitch(transactionId string, listenerMode Listener
 	}(deleteReq.Body)
 	return nil
 }
+
+func (s Manager) fetchBackendSwitchRules(transactionId string, listenerMode ListenerMode, bindPort int) ([]interface{}, error) {
+	frontendName := s.GenerateFrontendName(listenerMode, bindPort)
+	params := QueryParameters{}
+	params.add("transaction_id", transactionId)
+	params.add("frontend", frontendName)
+	// Send request
+	getBackendSwitchRes, getBackendSwitchErr := s.getRequest("/services/haproxy/configuration/backend_switching_rules", params)
+	if getBackendSwitchErr != nil || !isValidStatusCode(getBackendSwitchRes.StatusCode) {
+		return nil, errors.New("failed to fetch backend switch index")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(getBackendSwitchRes.Body)
+	// Parse response
+	var backendSwitchRulesData map[string]interface{}
+	err := json.NewDecoder(getBackendSwitchRes.Body).Decode(&backendSwitchRulesData)
+	if err != nil {
+		return nil, err
+	}
+	backendSwitchRules, _ := backendSwitchRulesData["data"].([]interface{})
+	return backendSwitchRules, nil
+}
+
+// backendSwitchCondition : host header has the port, except for the default http and https ports
+func backendSwitchCondition(bindPort int, domainName string) string {
+	if bindPort == 80 || bindPort == 443 {
+		return `{ ` + hostCondition(domainName) + ` }`
+	}
+	return `{ ` + hostCondition(strings.TrimSpace(domainName)+`:`+strconv.Itoa(bindPort)) + ` }`
+}
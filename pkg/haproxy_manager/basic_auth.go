@@ -183,7 +183,7 @@ func (s Manager) DeleteUserFromUserList(transactionId string, userListName strin
 }
 
 func createHttpRequestAuthCondition(bindPort int, domain string, userListName string) string {
-	rule := fmt.Sprintf("!{ http_auth(%s) } { hdr(host) -i %s }", userListName, domain)
+	rule := fmt.Sprintf("!{ http_auth(%s) } { %s }", userListName, hostCondition(domain))
 	if bindPort == 80 || bindPort == 443 {
 		return rule + " !letsencrypt-acl"
 	}
@@ -0,0 +1,137 @@
+package haproxymanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// maxServerWeight : maximum weight of a server in HAProxy
+const maxServerWeight = 256
+
+// SetCanaryTraffic : Route a percentage of the traffic of the backend to the canary service
+// -- Canary service is added as a second server template in the same backend, so the frontend and backend switch rules stay untouched
+// -- Weights of both server templates are derived from the percentage, so that the split doesn't depend on the replica counts
+func (s Manager) SetCanaryTraffic(transactionId string, backendProtocol BackendProtocol, serviceName string, canaryServiceName string, port int, replicas int, canaryReplicas int, canaryPercent int) error {
+	backendName := s.GenerateBackendName(backendProtocol, serviceName, port)
+	isBackendExist, err := s.IsBackendExist(transactionId, backendName)
+	if err != nil {
+		return err
+	}
+	if !isBackendExist {
+		return errors.New("backend does not exist")
+	}
+	weight, canaryWeight := TrafficSplitWeights(replicas, canaryReplicas, canaryPercent)
+	err = s.upsertServerTemplate(transactionId, backendName, serviceName, port, replicas, weight)
+	if err != nil {
+		return err
+	}
+	return s.upsertServerTemplate(transactionId, backendName, canaryServiceName, port, canaryReplicas, canaryWeight)
+}
+
+// RemoveCanaryTraffic : Remove the canary server template from the backend and route all the traffic to the service
+func (s Manager) RemoveCanaryTraffic(transactionId string, backendProtocol BackendProtocol, serviceName string, canaryServiceName string, port int, replicas int) error {
+	backendName := s.GenerateBackendName(backendProtocol, serviceName, port)
+	isBackendExist, err := s.IsBackendExist(transactionId, backendName)
+	if err != nil {
+		return err
+	}
+	if !isBackendExist {
+		return nil
+	}
+	params := QueryParameters{}
+	params.add("transaction_id", transactionId)
+	params.add("backend", backendName)
+	res, err := s.deleteRequest("/services/haproxy/configuration/server_templates/"+canaryServiceName+"_container-", params)
+	if err != nil {
+		return errors.New("failed to delete canary server template")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+	if res.StatusCode != 404 && !isValidStatusCode(res.StatusCode) {
+		return errors.New("failed to delete canary server template")
+	}
+	// reset the weight of the service
+	return s.UpdateBackendReplicas(transactionId, backendProtocol, serviceName, port, replicas)
+}
+
+// TrafficSplitWeights : weights of the servers of the service and the canary service for the given canary traffic percentage
+func TrafficSplitWeights(replicas int, canaryReplicas int, canaryPercent int) (int, int) {
+	if canaryPercent < 0 {
+		canaryPercent = 0
+	}
+	if canaryPercent > 100 {
+		canaryPercent = 100
+	}
+	return serverWeight(100-canaryPercent, replicas), serverWeight(canaryPercent, canaryReplicas)
+}
+
+// serverWeight : weight of each server, so that all the servers together receive `percent` of the traffic
+// -- weight 0 means the server doesn't receive any traffic
+func serverWeight(percent int, replicas int) int {
+	if percent == 0 {
+		return 0
+	}
+	if replicas <= 0 {
+		replicas = 1
+	}
+	weight := int(math.Round(float64(percent) * maxServerWeight / float64(100*replicas)))
+	if weight < 1 {
+		weight = 1
+	}
+	if weight > maxServerWeight {
+		weight = maxServerWeight
+	}
+	return weight
+}
+
+// upsertServerTemplate : Add or update the server template of a service in the backend
+func (s Manager) upsertServerTemplate(transactionId string, backendName string, serviceName string, port int, replicas int, weight int) error {
+	if replicas <= 0 {
+		replicas = 1
+	}
+	serverTemplatePrefix := serviceName + "_container-"
+	params := QueryParameters{}
+	params.add("transaction_id", transactionId)
+	params.add("backend", backendName)
+	reqBody := map[string]interface{}{
+		"prefix":       serverTemplatePrefix,
+		"fqdn":         serviceName,
+		"port":         port,
+		"check":        "disabled",
+		"resolvers":    "docker",
+		"init-addr":    "none",
+		"num_or_range": strconv.Itoa(replicas),
+		"weight":       weight,
+	}
+	reqBodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return errors.New("failed to marshal server template request body")
+	}
+	// check if server template exists
+	getRes, err := s.getRequest("/services/haproxy/configuration/server_templates/"+serverTemplatePrefix, params)
+	if err != nil {
+		return errors.New("failed to fetch server template")
+	}
+	_ = getRes.Body.Close()
+	var res *http.Response
+	if getRes.StatusCode == 404 {
+		res, err = s.postRequest("/services/haproxy/configuration/server_templates", params, bytes.NewReader(reqBodyBytes))
+	} else if isValidStatusCode(getRes.StatusCode) {
+		res, err = s.putRequest("/services/haproxy/configuration/server_templates/"+serverTemplatePrefix, params, bytes.NewReader(reqBodyBytes))
+	} else {
+		return errors.New("failed to fetch server template")
+	}
+	if err != nil || !isValidStatusCode(res.StatusCode) {
+		return errors.New("failed to update server template of " + serviceName)
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+	return nil
+}
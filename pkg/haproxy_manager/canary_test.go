@@ -0,0 +1,75 @@
+package haproxymanager
+
+import (
+	"fmt"
+	"gotest.tools/v3/assert"
+	"strings"
+	"testing"
+)
+
+func TestTrafficSplitWeights(t *testing.T) {
+	t.Run("no traffic to canary", func(t *testing.T) {
+		weight, canaryWeight := TrafficSplitWeights(2, 2, 0)
+		assert.Equal(t, weight, 128)
+		assert.Equal(t, canaryWeight, 0)
+	})
+	t.Run("all traffic to canary", func(t *testing.T) {
+		weight, canaryWeight := TrafficSplitWeights(2, 1, 100)
+		assert.Equal(t, weight, 0)
+		assert.Equal(t, canaryWeight, 256)
+	})
+	t.Run("split doesn't depend on replica count", func(t *testing.T) {
+		weight, canaryWeight := TrafficSplitWeights(3, 1, 10)
+		assert.Equal(t, weight, 77)
+		assert.Equal(t, canaryWeight, 26)
+	})
+	t.Run("small share keeps the servers enabled", func(t *testing.T) {
+		_, canaryWeight := TrafficSplitWeights(1, 100, 1)
+		assert.Equal(t, canaryWeight, 1)
+	})
+}
+
+func TestCanaryTraffic(t *testing.T) {
+	serviceName := "test-service"
+	canaryServiceName := "test-service-canary"
+	servicePort := 8080
+	backendProtocol := HTTPBackend
+
+	t.Run("set canary traffic", func(t *testing.T) {
+		transactionId := newTransaction()
+		defer deleteTransaction(transactionId)
+		_, err := haproxyTestManager.AddBackend(transactionId, backendProtocol, serviceName, servicePort, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = haproxyTestManager.SetCanaryTraffic(transactionId, backendProtocol, serviceName, canaryServiceName, servicePort, 1, 1, 25)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config := fetchConfig(transactionId)
+		assert.Check(t, strings.Contains(config, fmt.Sprintf("server-template %s_container- 1 %s:%d", serviceName, serviceName, servicePort)), "server template of service should be in config")
+		assert.Check(t, strings.Contains(config, fmt.Sprintf("server-template %s_container- 1 %s:%d", canaryServiceName, canaryServiceName, servicePort)), "server template of canary should be in config")
+		assert.Check(t, strings.Contains(config, "weight 192"), "weight of service should be in config")
+		assert.Check(t, strings.Contains(config, "weight 64"), "weight of canary should be in config")
+	})
+
+	t.Run("remove canary traffic", func(t *testing.T) {
+		transactionId := newTransaction()
+		defer deleteTransaction(transactionId)
+		_, err := haproxyTestManager.AddBackend(transactionId, backendProtocol, serviceName, servicePort, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = haproxyTestManager.SetCanaryTraffic(transactionId, backendProtocol, serviceName, canaryServiceName, servicePort, 1, 1, 25)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = haproxyTestManager.RemoveCanaryTraffic(transactionId, backendProtocol, serviceName, canaryServiceName, servicePort, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config := fetchConfig(transactionId)
+		assert.Check(t, !strings.Contains(config, canaryServiceName), "canary should not be in config")
+		assert.Check(t, !strings.Contains(config, "weight"), "weight of service should be reset")
+	})
+}
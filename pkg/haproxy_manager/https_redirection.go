@@ -33,7 +33,7 @@ func (s Manager) EnableHTTPSRedirection(transactionId string, domainName string)
 		"redir_value": "https",
 		"index":       0,
 		"cond":        "if",
-		"cond_test":   `{ hdr(host) -i ` + strings.TrimSpace(domainName) + ` }`,
+		"cond_test":   `{ ` + hostCondition(domainName) + ` }`,
 	}
 	reqBodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
@@ -70,7 +70,7 @@ func (s Manager) FetchIndexOfHTTPSRedirection(transactionId string, domainName s
 		return -1, err
 	}
 	httpRequestRules := httpRequestRulesData["data"].([]interface{})
-	condTest := `{ hdr(host) -i ` + strings.TrimSpace(domainName) + ` }`
+	condTest := `{ ` + hostCondition(domainName) + ` }`
 	// check if http-request rule already exists
 	for _, r := range httpRequestRules {
 		rule := r.(map[string]interface{})
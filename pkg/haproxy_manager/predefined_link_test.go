@@ -154,4 +154,31 @@ func TestPredefinedLink(t *testing.T) {
 		assert.Equal(t, err, nil, "delete non-existing https link should not return error")
 	})
 
+	t.Run("wildcard link matches subdomains and is placed after other links", func(t *testing.T) {
+		transactionId := newTransaction()
+		defer deleteTransaction(transactionId)
+
+		wildcardOutput := "use_backend wildcard_backend if { hdr_end(host) -i .example.com }"
+		subdomainOutput := "use_backend dummy_backend if { hdr(host) -i api.example.com }"
+		// add wildcard link before the link of subdomain
+		err := haproxyTestManager.AddHTTPLink(transactionId, "wildcard_backend", "*.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = haproxyTestManager.AddHTTPLink(transactionId, "dummy_backend", "api.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		config := fetchConfig(transactionId)
+		assert.Equal(t, strings.Contains(config, wildcardOutput), true, "wildcard link should exist")
+		assert.Equal(t, strings.Index(config, subdomainOutput) < strings.Index(config, wildcardOutput), true, "subdomain link should be matched before wildcard link")
+		// delete wildcard link
+		err = haproxyTestManager.DeleteHTTPLink(transactionId, "wildcard_backend", "*.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		config = fetchConfig(transactionId)
+		assert.Equal(t, strings.Contains(config, wildcardOutput), false, "wildcard link should not exist")
+	})
+
 }
@@ -30,7 +30,7 @@ func (s Manager) AddHTTPRedirectRule(transactionId string, matchDomain string, r
 		"redir_value": redirectUrl,
 		"index":       0,
 		"cond":        "if",
-		"cond_test":   `{ hdr(host) -i ` + strings.TrimSpace(matchDomain) + ` } !letsencrypt-acl`,
+		"cond_test":   `{ ` + hostCondition(matchDomain) + ` } !letsencrypt-acl`,
 	}
 	// Create request bytes
 	addHttpRedirectRuleRequestBodyBytes, err := json.Marshal(addHttpRedirectRuleRequestBody)
@@ -71,7 +71,7 @@ func (s Manager) AddHTTPSRedirectRule(transactionId string, matchDomain string,
 		"redir_value": redirectUrl,
 		"index":       0,
 		"cond":        "if",
-		"cond_test":   `{ hdr(host) -i ` + strings.TrimSpace(matchDomain) + ` } !letsencrypt-acl`,
+		"cond_test":   `{ ` + hostCondition(matchDomain) + ` } !letsencrypt-acl`,
 	}
 	// Create request bytes
 	addHttpsRedirectRuleRequestBodyBytes, err := json.Marshal(addHttpsRedirectRuleRequestBody)
@@ -127,7 +127,7 @@ func (s Manager) DeleteHTTPRedirectRule(transactionId string, matchDomain string
 	getHttpRedirectRules := getHttpRedirectRulesResBodyJson["data"].([]interface{})
 	for _, httpRedirectRule := range getHttpRedirectRules {
 		httpRedirectRuleItem := httpRedirectRule.(map[string]interface{})
-		if httpRedirectRuleItem["cond_test"] == `{ hdr(host) -i `+strings.TrimSpace(matchDomain)+` } !letsencrypt-acl` {
+		if httpRedirectRuleItem["cond_test"] == `{ `+hostCondition(matchDomain)+` } !letsencrypt-acl` {
 			index = int(httpRedirectRuleItem["index"].(float64))
 			break
 		}
@@ -183,7 +183,7 @@ func (s Manager) DeleteHTTPSRedirectRule(transactionId string, matchDomain strin
 	getHttpsRedirectRules := getHttpsRedirectRulesResBodyJson["data"].([]interface{})
 	for _, httpsRedirectRule := range getHttpsRedirectRules {
 		httpsRedirectRuleItem := httpsRedirectRule.(map[string]interface{})
-		if httpsRedirectRuleItem["cond_test"] == `{ hdr(host) -i `+strings.TrimSpace(matchDomain)+` } !letsencrypt-acl` {
+		if httpsRedirectRuleItem["cond_test"] == `{ `+hostCondition(matchDomain)+` } !letsencrypt-acl` {
 			index = int(httpsRedirectRuleItem["index"].(float64))
 			break
 		}
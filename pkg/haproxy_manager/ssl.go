@@ -23,7 +23,8 @@ func (s Manager) UpdateSSL(transactionId string, domain string, privateKey []byt
 	updateSSLRequired := false
 
 	ioReader := bytes.NewReader(buffer.Bytes())
-	domainSanitizedName := strings.ReplaceAll(domain, ".", "_") + ".pem"
+	// `*` of wildcard domain is not safe for file name
+	domainSanitizedName := strings.ReplaceAll(strings.ReplaceAll(domain, "*", "_wildcard"), ".", "_") + ".pem"
 
 	// Try to Upload the file
 	res, err := s.uploadSSL("/services/haproxy/storage/ssl_certificates", domainSanitizedName, ioReader)
@@ -0,0 +1,44 @@
+package haproxymanager
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// FetchBackendStats : Fetch the runtime stats of a backend
+// -- Counters are reset by HAProxy on every reload, so only compare them between two consecutive calls
+func (s Manager) FetchBackendStats(backendName string) (BackendStats, error) {
+	params := QueryParameters{}
+	params.add("type", "backend")
+	params.add("name", backendName)
+	res, err := s.getRequest("/services/haproxy/stats/native", params)
+	if err != nil {
+		return BackendStats{}, errors.New("failed to fetch backend stats")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+	if !isValidStatusCode(res.StatusCode) {
+		return BackendStats{}, errors.New("failed to fetch backend stats")
+	}
+	var collections []nativeStatsCollection
+	err = json.NewDecoder(res.Body).Decode(&collections)
+	if err != nil {
+		return BackendStats{}, errors.New("failed to decode backend stats")
+	}
+	for _, collection := range collections {
+		if collection.Error != "" {
+			return BackendStats{}, errors.New("failed to fetch backend stats > " + collection.Error)
+		}
+		for _, stat := range collection.Stats {
+			if stat.Type == "backend" && stat.Name == backendName {
+				return BackendStats{
+					TotalSessions: stat.Stats.TotalSessions,
+					TotalRequests: stat.Stats.TotalRequests,
+				}, nil
+			}
+		}
+	}
+	return BackendStats{}, errors.New("backend stats not found")
+}
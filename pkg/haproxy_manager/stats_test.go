@@ -0,0 +1,79 @@
+package haproxymanager
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// newStatsTestManager : manager backed by a fake dataplane api which responds with the given native stats
+func newStatsTestManager(t *testing.T, statusCode int, body string) Manager {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/services/haproxy/stats/native" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return New(func() (net.Conn, error) {
+		return net.Dial("tcp", server.Listener.Addr().String())
+	}, "admin", "admin")
+}
+
+func TestFetchBackendStats(t *testing.T) {
+	backendName := "be_test-service_8080"
+
+	t.Run("counters of the backend", func(t *testing.T) {
+		manager := newStatsTestManager(t, http.StatusOK, `[{"runtimeAPI": "/var/run/haproxy.sock", "stats": [
+			{"name": "fe_http", "type": "frontend", "stats": {"stot": 900, "req_tot": 1000}},
+			{"name": "server_1", "type": "server", "backend_name": "be_test-service_8080", "stats": {"stot": 40, "req_tot": 0}},
+			{"name": "be_test-service_8080", "type": "backend", "stats": {"stot": 42, "req_tot": 57}}
+		]}]`)
+		stats, err := manager.FetchBackendStats(backendName)
+		assert.NilError(t, err)
+		assert.Equal(t, stats.TotalSessions, uint64(42))
+		assert.Equal(t, stats.TotalRequests, uint64(57))
+	})
+
+	t.Run("tcp backend has no request counter", func(t *testing.T) {
+		manager := newStatsTestManager(t, http.StatusOK, `[{"stats": [
+			{"name": "be_test-service_8080", "type": "backend", "stats": {"stot": 7}}
+		]}]`)
+		stats, err := manager.FetchBackendStats(backendName)
+		assert.NilError(t, err)
+		assert.Equal(t, stats.TotalSessions, uint64(7))
+		assert.Equal(t, stats.TotalRequests, uint64(0))
+	})
+
+	t.Run("missing backend", func(t *testing.T) {
+		manager := newStatsTestManager(t, http.StatusOK, `[{"stats": [
+			{"name": "be_other-service_8080", "type": "backend", "stats": {"stot": 7, "req_tot": 9}}
+		]}]`)
+		_, err := manager.FetchBackendStats(backendName)
+		assert.ErrorContains(t, err, "backend stats not found")
+	})
+
+	t.Run("runtime api error", func(t *testing.T) {
+		manager := newStatsTestManager(t, http.StatusOK, `[{"runtimeAPI": "/var/run/haproxy.sock", "error": "connection refused"}]`)
+		_, err := manager.FetchBackendStats(backendName)
+		assert.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("invalid status code", func(t *testing.T) {
+		manager := newStatsTestManager(t, http.StatusInternalServerError, `{"message": "internal error"}`)
+		_, err := manager.FetchBackendStats(backendName)
+		assert.ErrorContains(t, err, "failed to fetch backend stats")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		manager := newStatsTestManager(t, http.StatusOK, `not json`)
+		_, err := manager.FetchBackendStats(backendName)
+		assert.ErrorContains(t, err, "failed to decode backend stats")
+	})
+}
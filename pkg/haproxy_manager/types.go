@@ -30,3 +30,27 @@ const (
 	HTTPBackend BackendProtocol = "http"
 	TCPBackend  BackendProtocol = "tcp"
 )
+
+// BackendStats : runtime counters of a backend
+type BackendStats struct {
+	// TotalSessions : cumulative number of sessions, available for both http and tcp backends
+	TotalSessions uint64
+	// TotalRequests : cumulative number of http requests, zero for tcp backends
+	TotalRequests uint64
+}
+
+type nativeStatsCollection struct {
+	RuntimeAPI string        `json:"runtimeAPI"`
+	Error      string        `json:"error"`
+	Stats      []nativeStats `json:"stats"`
+}
+
+type nativeStats struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	BackendName string `json:"backend_name"`
+	Stats       struct {
+		TotalSessions uint64 `json:"stot"`
+		TotalRequests uint64 `json:"req_tot"`
+	} `json:"stats"`
+}
@@ -43,3 +43,17 @@ func (q *QueryParameters) add(key string, value string) {
 func isValidStatusCode(statusCode int) bool {
 	return statusCode >= 200 && statusCode < 300
 }
+
+// hostCondition : ACL condition matching the host header of the domain
+// Wildcard domain (*.example.com) matches all of its subdomains
+func hostCondition(domainName string) string {
+	domainName = strings.TrimSpace(domainName)
+	if isWildcardDomain(domainName) {
+		return `hdr_end(host) -i ` + strings.TrimPrefix(domainName, "*")
+	}
+	return `hdr(host) -i ` + domainName
+}
+
+func isWildcardDomain(domainName string) bool {
+	return strings.HasPrefix(strings.TrimSpace(domainName), "*.")
+}
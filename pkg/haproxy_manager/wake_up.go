@@ -0,0 +1,166 @@
+package haproxymanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WakeUpServerName : name of the backup server which forwards requests of a sleeping application to the wake-up handler
+const WakeUpServerName = "swiftwave_wake_up"
+
+// WakeUpHeader : header added to the requests forwarded to the wake-up handler, holds the name of the service
+const WakeUpHeader = "X-SwiftWave-Wake-Up"
+
+// IsWakeUpServerExist : Check if wake-up server exist in backend
+func (s Manager) IsWakeUpServerExist(transactionId string, backendName string) (bool, error) {
+	params := QueryParameters{}
+	params.add("transaction_id", transactionId)
+	params.add("backend", backendName)
+	res, err := s.getRequest("/services/haproxy/configuration/servers/"+WakeUpServerName, params)
+	if err != nil {
+		return false, errors.New("failed to check if wake-up server exist")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+	if res.StatusCode == 404 {
+		return false, nil
+	} else if res.StatusCode == 200 {
+		return true, nil
+	}
+	return false, errors.New("failed to check if wake-up server exist")
+}
+
+// EnableWakeUpServer : Add a backup server in the backend which points to the wake-up handler
+// -- Backup server is used by HAProxy only when all the containers of the service are down
+// -- Requests are tagged with WakeUpHeader, so that the handler can identify the service
+func (s Manager) EnableWakeUpServer(transactionId string, backendName string, serviceName string, address string, port int, useTLS bool) error {
+	isExist, err := s.IsWakeUpServerExist(transactionId, backendName)
+	if err != nil {
+		return err
+	}
+	if isExist {
+		return nil
+	}
+	// add backup server
+	params := QueryParameters{}
+	params.add("transaction_id", transactionId)
+	params.add("backend", backendName)
+	serverReqBody := map[string]interface{}{
+		"name":    WakeUpServerName,
+		"address": address,
+		"port":    port,
+		"backup":  "enabled",
+		"check":   "disabled",
+	}
+	if useTLS {
+		serverReqBody["ssl"] = "enabled"
+		serverReqBody["verify"] = "none"
+	}
+	serverReqBodyBytes, err := json.Marshal(serverReqBody)
+	if err != nil {
+		return errors.New("failed to marshal wake-up server request body")
+	}
+	serverRes, serverErr := s.postRequest("/services/haproxy/configuration/servers", params, bytes.NewReader(serverReqBodyBytes))
+	if serverErr != nil || !isValidStatusCode(serverRes.StatusCode) {
+		return errors.New("failed to add wake-up server")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(serverRes.Body)
+	// tag the requests with the service name
+	ruleParams := QueryParameters{}
+	ruleParams.add("transaction_id", transactionId)
+	ruleParams.add("parent_type", "backend")
+	ruleParams.add("parent_name", backendName)
+	ruleReqBody := map[string]interface{}{
+		"type":       "set-header",
+		"hdr_name":   WakeUpHeader,
+		"hdr_format": strings.TrimSpace(serviceName),
+		"index":      0,
+	}
+	ruleReqBodyBytes, err := json.Marshal(ruleReqBody)
+	if err != nil {
+		return errors.New("failed to marshal wake-up header request body")
+	}
+	ruleRes, ruleErr := s.postRequest("/services/haproxy/configuration/http_request_rules", ruleParams, bytes.NewReader(ruleReqBodyBytes))
+	if ruleErr != nil || !isValidStatusCode(ruleRes.StatusCode) {
+		return errors.New("failed to add wake-up header")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(ruleRes.Body)
+	return nil
+}
+
+// DisableWakeUpServer : Remove the wake-up server and the header rule from the backend
+func (s Manager) DisableWakeUpServer(transactionId string, backendName string) error {
+	isExist, err := s.IsWakeUpServerExist(transactionId, backendName)
+	if err != nil {
+		return err
+	}
+	if isExist {
+		params := QueryParameters{}
+		params.add("transaction_id", transactionId)
+		params.add("backend", backendName)
+		deleteRes, deleteErr := s.deleteRequest("/services/haproxy/configuration/servers/"+WakeUpServerName, params)
+		if deleteErr != nil || !isValidStatusCode(deleteRes.StatusCode) {
+			return errors.New("failed to delete wake-up server")
+		}
+		_ = deleteRes.Body.Close()
+	}
+	// remove the header rule
+	index, err := s.fetchWakeUpHeaderIndex(transactionId, backendName)
+	if err != nil {
+		return err
+	}
+	if index == -1 {
+		return nil
+	}
+	ruleParams := QueryParameters{}
+	ruleParams.add("transaction_id", transactionId)
+	ruleParams.add("parent_type", "backend")
+	ruleParams.add("parent_name", backendName)
+	ruleRes, ruleErr := s.deleteRequest("/services/haproxy/configuration/http_request_rules/"+strconv.Itoa(index), ruleParams)
+	if ruleErr != nil || !isValidStatusCode(ruleRes.StatusCode) {
+		return errors.New("failed to delete wake-up header")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(ruleRes.Body)
+	return nil
+}
+
+func (s Manager) fetchWakeUpHeaderIndex(transactionId string, backendName string) (int, error) {
+	params := QueryParameters{}
+	params.add("transaction_id", transactionId)
+	params.add("parent_type", "backend")
+	params.add("parent_name", backendName)
+	res, err := s.getRequest("/services/haproxy/configuration/http_request_rules", params)
+	if err != nil || !isValidStatusCode(res.StatusCode) {
+		return -1, errors.New("failed to fetch http-request rules")
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+	var httpRequestRulesData map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&httpRequestRulesData)
+	if err != nil {
+		return -1, err
+	}
+	httpRequestRules, ok := httpRequestRulesData["data"].([]interface{})
+	if !ok {
+		return -1, nil
+	}
+	for _, r := range httpRequestRules {
+		rule := r.(map[string]interface{})
+		if rule["type"] == "set-header" && rule["hdr_name"] == WakeUpHeader {
+			return int(rule["index"].(float64)), nil
+		}
+	}
+	return -1, nil
+}
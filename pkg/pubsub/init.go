@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/hashicorp/go-set"
+	"github.com/nats-io/nats.go/jetstream"
 	"strings"
 	"sync"
 )
@@ -13,6 +14,8 @@ func NewClient(options Options) (Client, error) {
 		return createLocalPubSubClient(options)
 	} else if options.Type == Remote {
 		return createRemotePubSubClient(options)
+	} else if options.Type == Nats {
+		return createNatsPubSubClient(options)
 	} else {
 		return nil, errors.New("invalid pubsub type")
 	}
@@ -61,3 +64,46 @@ func createRemotePubSubClient(options Options) (Client, error) {
 	go client.listenForBroadcastEvents(client.eventsContext)
 	return &client, nil
 }
+
+func createNatsPubSubClient(options Options) (Client, error) {
+	// validate options
+	if options.NatsConnection == nil {
+		return nil, errors.New("nats connection is nil")
+	}
+	if strings.Compare(options.TopicsChannelName, "") == 0 {
+		return nil, errors.New("topics channel name is empty")
+	}
+	if strings.Compare(options.EventsChannelName, "") == 0 {
+		return nil, errors.New("events channel name is empty")
+	}
+	if options.BufferLength <= 0 {
+		return nil, errors.New("buffer length cannot be less than or equal to 0")
+	}
+	js, err := jetstream.New(options.NatsConnection)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	topicsKeyValue, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket: options.TopicsChannelName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mutex := sync.RWMutex{}
+	client := natsPubSub{
+		natsConnection:    options.NatsConnection,
+		topicsKeyValue:    topicsKeyValue,
+		mutex:             &mutex,
+		bufferLength:      options.BufferLength,
+		subscriptions:     make(map[string]map[string]*natsPubSubSubscription),
+		eventsChannelName: options.EventsChannelName,
+		closed:            false,
+	}
+	err = client.listenForBroadcastEvents()
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
@@ -0,0 +1,230 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// natsTopicSubjectPrefix : messages of a topic are published on this prefix + encoded topic name
+const natsTopicSubjectPrefix = "swiftwave.pubsub.topic."
+
+const natsOperationTimeout = 10 * time.Second
+
+func (n *natsPubSub) CreateTopic(topic string) error {
+	if n.closed {
+		return errors.New("pubsub client is closed")
+	}
+	// add this to key-value bucket of topics
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	_, err := n.topicsKeyValue.Put(ctx, natsEncodeTopic(topic), []byte(topic))
+	if err != nil {
+		return err
+	}
+	// create a map for this topic
+	n.mutex.Lock()
+	if _, ok := n.subscriptions[topic]; !ok {
+		n.subscriptions[topic] = make(map[string]*natsPubSubSubscription)
+	}
+	n.mutex.Unlock()
+	return nil
+}
+
+func (n *natsPubSub) RemoveTopic(topic string) error {
+	if n.closed {
+		return errors.New("pubsub client is closed")
+	}
+	// remove this from key-value bucket of topics
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	err := n.topicsKeyValue.Delete(ctx, natsEncodeTopic(topic))
+	if err != nil {
+		return err
+	}
+	// broadcast to all the instances to close the subscriptions of this topic
+	err = n.natsConnection.Publish(n.eventsChannelName, []byte("close-topic-"+topic))
+	if err != nil {
+		return errors.New("error in broadcasting close topic message")
+	}
+	return nil
+}
+
+func (n *natsPubSub) Subscribe(topic string) (string, <-chan string, error) {
+	if n.closed {
+		return "", nil, errors.New("pubsub client is closed")
+	}
+	exists, err := n.isTopicExists(topic)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		err = n.CreateTopic(topic)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	subscriptionId := topic + "_" + uuid.NewString()
+	subscription := &natsPubSubSubscription{
+		Mutex:   &sync.RWMutex{},
+		Channel: make(chan string, n.bufferLength),
+	}
+	subscription.Subscription, err = n.natsConnection.Subscribe(natsTopicSubjectPrefix+natsEncodeTopic(topic), func(msg *nats.Msg) {
+		subscription.Mutex.RLock()
+		defer subscription.Mutex.RUnlock()
+		if subscription.closed {
+			return
+		}
+		select {
+		case subscription.Channel <- string(msg.Data):
+		default:
+			log.Println("dropping message of topic", topic, "for subscription", subscriptionId, ", buffer is full")
+		}
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	// wait till the server registers the subscription, so no message published after this call is missed
+	err = n.natsConnection.Flush()
+	if err != nil {
+		_ = subscription.Subscription.Unsubscribe()
+		return "", nil, err
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if _, ok := n.subscriptions[topic]; !ok {
+		n.subscriptions[topic] = make(map[string]*natsPubSubSubscription)
+	}
+	n.subscriptions[topic][subscriptionId] = subscription
+	return subscriptionId, subscription.Channel, nil
+}
+
+func (n *natsPubSub) Unsubscribe(topic string, subscriptionId string) error {
+	if n.closed {
+		return errors.New("pubsub client is closed")
+	}
+	// cancel subscription
+	err := n.cancelSubscription(topic, subscriptionId)
+	if err != nil {
+		return err
+	}
+	n.mutex.Lock()
+	// delete subscription
+	delete(n.subscriptions[topic], subscriptionId)
+	n.mutex.Unlock()
+	return nil
+}
+
+func (n *natsPubSub) Publish(topic string, data string) error {
+	if n.closed {
+		return errors.New("pubsub client is closed")
+	}
+	return n.natsConnection.Publish(natsTopicSubjectPrefix+natsEncodeTopic(topic), []byte(data))
+}
+
+func (n *natsPubSub) Close() error {
+	n.mutex.RLock()
+	topics := make([]string, 0, len(n.subscriptions))
+	for topic := range n.subscriptions {
+		topics = append(topics, topic)
+	}
+	n.mutex.RUnlock()
+	// close all subscriptions
+	for _, topic := range topics {
+		n.cancelAllSubscriptionsOfTopic(topic)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.eventsSubscription != nil {
+		_ = n.eventsSubscription.Unsubscribe()
+	}
+	// close nats connection
+	n.natsConnection.Close()
+	// set closed to true
+	n.closed = true
+	return nil
+}
+
+// private functions
+// natsEncodeTopic encodes the topic name to be usable as a subject token and a key of key-value bucket
+func natsEncodeTopic(topic string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(topic))
+}
+
+func (n *natsPubSub) isTopicExists(topic string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	_, err := n.topicsKeyValue.Get(ctx, natsEncodeTopic(topic))
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (n *natsPubSub) cancelAllSubscriptionsOfTopic(topic string) {
+	n.mutex.RLock()
+	ids := make([]string, 0, len(n.subscriptions[topic]))
+	for id := range n.subscriptions[topic] {
+		ids = append(ids, id)
+	}
+	n.mutex.RUnlock()
+	for _, id := range ids {
+		err := n.cancelSubscription(topic, id)
+		if err != nil {
+			log.Println("error in canceling subscription of topic", topic, "with id", id, ":", err)
+		}
+	}
+}
+
+func (n *natsPubSub) cancelSubscription(topic string, subscriptionId string) error {
+	n.mutex.RLock()
+	subscription, ok := n.subscriptions[topic][subscriptionId]
+	n.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	subscription.Mutex.Lock()
+	defer subscription.Mutex.Unlock()
+	if subscription.closed {
+		return nil
+	}
+	subscription.closed = true
+	// close channel
+	close(subscription.Channel)
+	// close nats subscription
+	return subscription.Subscription.Unsubscribe()
+}
+
+func (n *natsPubSub) removeTopicAndCleanup(topic string) {
+	// cancel all subscriptions of this topic
+	n.cancelAllSubscriptionsOfTopic(topic)
+	n.mutex.Lock()
+	// delete topic
+	delete(n.subscriptions, topic)
+	n.mutex.Unlock()
+}
+
+func (n *natsPubSub) listenForBroadcastEvents() error {
+	subscription, err := n.natsConnection.Subscribe(n.eventsChannelName, func(msg *nats.Msg) {
+		payload := string(msg.Data)
+		// check if message is for closing topic
+		if strings.HasPrefix(payload, "close-topic-") {
+			go n.removeTopicAndCleanup(strings.TrimPrefix(payload, "close-topic-"))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	n.eventsSubscription = subscription
+	return n.natsConnection.Flush()
+}
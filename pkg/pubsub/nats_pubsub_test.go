@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newNatsTestClient(t *testing.T, natsServer *server.Server) Client {
+	natsConnection, err := nats.Connect(natsServer.ClientURL())
+	assert.NoError(t, err)
+	client, err := NewClient(Options{
+		Type:              Nats,
+		BufferLength:      10,
+		NatsConnection:    natsConnection,
+		TopicsChannelName: "topics",
+		EventsChannelName: "events",
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestNatsPubSub(t *testing.T) {
+	natsServer, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	assert.NoError(t, err)
+	go natsServer.Start()
+	if !natsServer.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	defer natsServer.Shutdown()
+
+	// two instances sharing the same nats server
+	publisher := newNatsTestClient(t, natsServer)
+	subscriber := newNatsTestClient(t, natsServer)
+
+	subscriptionId, channel, err := subscriber.Subscribe("deployment/1")
+	assert.NoError(t, err)
+	assert.NoError(t, publisher.Publish("deployment/1", "hello"))
+	select {
+	case message := <-channel:
+		assert.Equal(t, "hello", message)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not received")
+	}
+
+	// unsubscribe closes the channel
+	assert.NoError(t, subscriber.Unsubscribe("deployment/1", subscriptionId))
+	_, ok := <-channel
+	assert.False(t, ok)
+
+	// removing the topic closes the subscriptions of all the instances
+	_, channel, err = subscriber.Subscribe("deployment/1")
+	assert.NoError(t, err)
+	assert.NoError(t, publisher.RemoveTopic("deployment/1"))
+	select {
+	case _, ok := <-channel:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription was not closed after removing the topic")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/go-redis/redis/v8"
 	"github.com/hashicorp/go-set"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"sync"
 )
 
@@ -48,11 +50,31 @@ type remotePubSubSubscription struct {
 	PubSub  *redis.PubSub
 }
 
+type natsPubSub struct {
+	natsConnection *nats.Conn
+	topicsKeyValue jetstream.KeyValue
+	mutex          *sync.RWMutex
+	bufferLength   int
+	subscriptions  map[string]map[string]*natsPubSubSubscription
+	// <topic> -> [<subscriber> -> <subscription>]
+	eventsChannelName  string
+	eventsSubscription *nats.Subscription
+	closed             bool
+}
+
+type natsPubSubSubscription struct {
+	Mutex        *sync.RWMutex
+	Channel      chan string
+	Subscription *nats.Subscription
+	closed       bool
+}
+
 type Type string
 
 const (
 	Local  Type = "local"
 	Remote Type = "remote"
+	Nats   Type = "nats"
 )
 
 type Options struct {
@@ -60,7 +82,10 @@ type Options struct {
 	// to store max number of messages in channel if no subscriber is listening
 	BufferLength int
 	// Only for remote pubsub, to store redis client
-	RedisClient       *redis.Client
+	RedisClient *redis.Client
+	// Only for nats pubsub, JetStream should be enabled on the server
+	NatsConnection *nats.Conn
+	// Set of topics (key-value bucket for nats) and channel (subject for nats) to broadcast events
 	TopicsChannelName string
 	EventsChannelName string
 }
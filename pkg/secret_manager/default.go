@@ -0,0 +1,44 @@
+package secretmanager
+
+import "errors"
+
+// keyring used by the Encrypt and Decrypt functions
+// if it's not configured, values are stored as plaintext
+var defaultKeyring *Keyring
+
+// UseKeyring : set the keyring used by the Encrypt and Decrypt functions
+func UseKeyring(keyring *Keyring) {
+	defaultKeyring = keyring
+}
+
+// DefaultKeyring : keyring used by the Encrypt and Decrypt functions, nil if encryption is not configured
+func DefaultKeyring() *Keyring {
+	return defaultKeyring
+}
+
+// Encrypt : encrypt the value with the configured keyring
+func Encrypt(plaintext string) (string, error) {
+	if defaultKeyring == nil {
+		return plaintext, nil
+	}
+	return defaultKeyring.Encrypt(plaintext)
+}
+
+// Decrypt : decrypt the value with the configured keyring
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if defaultKeyring == nil {
+		return "", errors.New("value is encrypted, but no master key is configured")
+	}
+	return defaultKeyring.Decrypt(value)
+}
+
+// Fingerprint : keyed hash of the value with the configured keyring
+func Fingerprint(value string) (string, error) {
+	if defaultKeyring == nil {
+		return "", errors.New("no master key is configured to fingerprint the value")
+	}
+	return defaultKeyring.Fingerprint(value), nil
+}
@@ -0,0 +1,50 @@
+package secretmanager
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// ReadKeyFile : read the master keys from the key file
+// Each line holds a base64 encoded key, the first one is the primary key and the rest are the previous keys
+func ReadKeyFile(path string) ([][]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([][]byte, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := DecodeKey(line)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no master key found in key file")
+	}
+	return keys, nil
+}
+
+// WriteKeyFile : write the master keys to the key file, the first one is the primary key
+// The file is replaced atomically, so that a crash doesn't leave a partially written key file
+func WriteKeyFile(path string, keys [][]byte) error {
+	if len(keys) == 0 {
+		return errors.New("at least one master key is required")
+	}
+	var content strings.Builder
+	for _, key := range keys {
+		content.WriteString(EncodeKey(key) + "\n")
+	}
+	tempPath := path + ".tmp"
+	err := os.WriteFile(tempPath, []byte(content.String()), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
@@ -0,0 +1,214 @@
+package secretmanager
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Envelope encryption
+// -- Every value is encrypted with a new random data key (AES-256-GCM)
+// -- The data key is encrypted with the master key and stored along with the value
+// -- Format : enc:v1:<master key id>:<base64 encrypted data key>:<base64 encrypted value>
+
+const (
+	encryptedValuePrefix = "enc:v1:"
+	// KeySize : size of the master key in bytes
+	KeySize = 32
+)
+
+// Keyring : master keys used for envelope encryption
+// The primary key encrypts new values, other keys are kept to decrypt the values encrypted before a key rotation
+type Keyring struct {
+	primary *masterKey
+	keys    map[string]*masterKey
+}
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+	// fingerprintKey : key of the HMAC of the fingerprints, derived from the master key
+	fingerprintKey []byte
+}
+
+// NewKeyring : create a keyring with the primary key and the previous keys
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	primaryKey, err := newMasterKey(primary)
+	if err != nil {
+		return nil, err
+	}
+	keyring := &Keyring{
+		primary: primaryKey,
+		keys:    map[string]*masterKey{primaryKey.id: primaryKey},
+	}
+	for _, key := range previous {
+		previousKey, err := newMasterKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := keyring.keys[previousKey.id]; !ok {
+			keyring.keys[previousKey.id] = previousKey
+		}
+	}
+	return keyring, nil
+}
+
+// PrimaryKeyID : id of the key used to encrypt new values
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary.id
+}
+
+// Encrypt : encrypt the value with a new data key, empty value is kept as it is
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", errors.New("failed to generate data key")
+	}
+	dataKeyCipher, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	encryptedValue, err := seal(dataKeyCipher, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	encryptedDataKey, err := seal(k.primary.aead, dataKey)
+	if err != nil {
+		return "", err
+	}
+	return encryptedValuePrefix + k.primary.id + ":" + base64.StdEncoding.EncodeToString(encryptedDataKey) + ":" + base64.StdEncoding.EncodeToString(encryptedValue), nil
+}
+
+// Decrypt : decrypt the value, plaintext value (stored before the encryption was enabled) is returned as it is
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, encryptedValuePrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+	key, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("master key %s is not available to decrypt the value", parts[0])
+	}
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	encryptedValue, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	dataKey, err := open(key.aead, encryptedDataKey)
+	if err != nil {
+		return "", errors.New("failed to decrypt data key")
+	}
+	dataKeyCipher, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKeyCipher, encryptedValue)
+	if err != nil {
+		return "", errors.New("failed to decrypt value")
+	}
+	return string(plaintext), nil
+}
+
+// Fingerprint : keyed hash of the value with the primary key
+// Same value has the same fingerprint till the key is rotated, and it can be published without revealing the value
+func (k *Keyring) Fingerprint(value string) string {
+	mac := hmac.New(sha256.New, k.primary.fingerprintKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncryptedWithPrimaryKey : check if the value is already encrypted with the primary key
+func (k *Keyring) IsEncryptedWithPrimaryKey(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix+k.primary.id+":")
+}
+
+// IsEncrypted : check if the value has been encrypted by a keyring
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix)
+}
+
+// GenerateKey : generate a new random master key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.New("failed to generate master key")
+	}
+	return key, nil
+}
+
+// EncodeKey : encode the master key to store it in config or key file
+func EncodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// DecodeKey : decode the base64 encoded master key
+func DecodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.New("master key should be base64 encoded")
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key should be of %d bytes", KeySize)
+	}
+	return key, nil
+}
+
+func newMasterKey(key []byte) (*masterKey, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	// id is derived from the key, so that the key used for a value can be identified without storing any state
+	hash := sha256.Sum256(key)
+	fingerprintKey := hmac.New(sha256.New, key)
+	fingerprintKey.Write([]byte("swiftwave-fingerprint"))
+	return &masterKey{
+		id:             hex.EncodeToString(hash[:4]),
+		aead:           aead,
+		fingerprintKey: fingerprintKey.Sum(nil),
+	}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key should be of %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal : encrypt the data, nonce is prepended to the ciphertext
+func seal(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.New("failed to generate nonce")
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
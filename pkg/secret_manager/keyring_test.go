@@ -0,0 +1,124 @@
+package secretmanager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestKeyring(t *testing.T, previous ...[]byte) (*Keyring, []byte) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := NewKeyring(key, previous...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring, key
+}
+
+func TestKeyring(t *testing.T) {
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		keyring, _ := newTestKeyring(t)
+		encrypted, err := keyring.Encrypt("s3cr3t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(encrypted) || strings.Contains(encrypted, "s3cr3t") {
+			t.Fatalf("value is not encrypted > %s", encrypted)
+		}
+		if !keyring.IsEncryptedWithPrimaryKey(encrypted) {
+			t.Fatal("value should be encrypted with primary key")
+		}
+		decrypted, err := keyring.Decrypt(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted != "s3cr3t" {
+			t.Fatalf("expected s3cr3t, got %s", decrypted)
+		}
+	})
+
+	t.Run("same value encrypts differently", func(t *testing.T) {
+		keyring, _ := newTestKeyring(t)
+		first, _ := keyring.Encrypt("value")
+		second, _ := keyring.Encrypt("value")
+		if first == second {
+			t.Fatal("encrypted values should differ")
+		}
+	})
+
+	t.Run("empty and plaintext values are kept as it is", func(t *testing.T) {
+		keyring, _ := newTestKeyring(t)
+		encrypted, _ := keyring.Encrypt("")
+		if encrypted != "" {
+			t.Fatal("empty value should not be encrypted")
+		}
+		decrypted, err := keyring.Decrypt("plaintext")
+		if err != nil || decrypted != "plaintext" {
+			t.Fatal("plaintext value should be returned as it is")
+		}
+	})
+
+	t.Run("previous key decrypts after rotation", func(t *testing.T) {
+		oldKeyring, oldKey := newTestKeyring(t)
+		encrypted, _ := oldKeyring.Encrypt("value")
+		newKeyring, _ := newTestKeyring(t, oldKey)
+		if newKeyring.IsEncryptedWithPrimaryKey(encrypted) {
+			t.Fatal("value should not be encrypted with new primary key")
+		}
+		decrypted, err := newKeyring.Decrypt(encrypted)
+		if err != nil || decrypted != "value" {
+			t.Fatal("previous key should decrypt the value")
+		}
+	})
+
+	t.Run("unknown key fails", func(t *testing.T) {
+		oldKeyring, _ := newTestKeyring(t)
+		encrypted, _ := oldKeyring.Encrypt("value")
+		newKeyring, _ := newTestKeyring(t)
+		if _, err := newKeyring.Decrypt(encrypted); err == nil {
+			t.Fatal("decryption should fail without the master key")
+		}
+	})
+
+	t.Run("fingerprint is keyed", func(t *testing.T) {
+		keyring, key := newTestKeyring(t)
+		fingerprint := keyring.Fingerprint("DB_PASSWORD=s3cr3t")
+		if fingerprint != keyring.Fingerprint("DB_PASSWORD=s3cr3t") {
+			t.Fatal("fingerprint of the same value should be stable")
+		}
+		if fingerprint == keyring.Fingerprint("DB_PASSWORD=other") {
+			t.Fatal("fingerprint of different values should differ")
+		}
+		sameKeyring, _ := NewKeyring(key)
+		if fingerprint != sameKeyring.Fingerprint("DB_PASSWORD=s3cr3t") {
+			t.Fatal("fingerprint should depend only on the master key")
+		}
+		otherKeyring, _ := newTestKeyring(t)
+		if fingerprint == otherKeyring.Fingerprint("DB_PASSWORD=s3cr3t") {
+			t.Fatal("fingerprint should depend on the master key")
+		}
+	})
+}
+
+func TestKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "master.key")
+	primary, _ := GenerateKey()
+	previous, _ := GenerateKey()
+	err := WriteKeyFile(path, [][]byte{primary, previous})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := ReadKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || EncodeKey(keys[0]) != EncodeKey(primary) || EncodeKey(keys[1]) != EncodeKey(previous) {
+		t.Fatal("keys should be read in the same order")
+	}
+	if _, err := DecodeKey("c2hvcnQ="); err == nil {
+		t.Fatal("short key should be rejected")
+	}
+}
@@ -7,7 +7,8 @@ import (
 )
 
 // Initialize the ACME client
-func initiateACMEAccount(ctx context.Context, client *acmez.Client, accountPrivateKey string, accountEmail string) (acme.Account, error) {
+// eab is required for the CAs which need to bind the acme account with an existing account (e.g. ZeroSSL)
+func initiateACMEAccount(ctx context.Context, client *acmez.Client, accountPrivateKey string, accountEmail string, eab *acme.EAB) (acme.Account, error) {
 	// Read the private key from file
 	accountPrivateRSAKey, err := decodePrivateKey(accountPrivateKey)
 	if err != nil {
@@ -28,6 +29,12 @@ func initiateACMEAccount(ctx context.Context, client *acmez.Client, accountPriva
 		finalAccount = fetchedAccount
 	} else {
 		// If account does not exist, create a new one
+		if eab != nil {
+			err = account.SetExternalAccountBinding(ctx, client.Client, *eab)
+			if err != nil {
+				return acme.Account{}, err
+			}
+		}
 		finalAccount, err = client.NewAccount(ctx, account)
 		if err != nil {
 			return acme.Account{}, err
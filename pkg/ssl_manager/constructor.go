@@ -3,6 +3,7 @@ package Manager
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"net/http"
 
@@ -19,18 +20,16 @@ func (s *Manager) Init(ctx context.Context, db gorm.DB, options ManagerOptions)
 	s.dbClient = db
 	s.options = options
 	// Initialize account
-	acmeDirectory := "https://acme-staging-v02.api.letsencrypt.org/directory"
-	if !options.IsStaging {
-		acmeDirectory = "https://acme-v02.api.letsencrypt.org/directory"
+	tlsConfig, err := options.tlsConfig()
+	if err != nil {
+		return err
 	}
 	s.client = acmez.Client{
 		Client: &acme.Client{
-			Directory: acmeDirectory,
+			Directory: options.Directory(),
 			HTTPClient: &http.Client{
 				Transport: &http.Transport{
-					TLSClientConfig: &tls.Config{
-						InsecureSkipVerify: options.IsStaging,
-					},
+					TLSClientConfig: tlsConfig,
 				},
 			},
 		},
@@ -41,10 +40,50 @@ func (s *Manager) Init(ctx context.Context, db gorm.DB, options ManagerOptions)
 		},
 	}
 	// Init acme account
-	acme_account, err := initiateACMEAccount(s.ctx, &s.client, options.AccountPrivateKey, options.Email)
+	acme_account, err := initiateACMEAccount(s.ctx, &s.client, options.AccountPrivateKey, options.Email, options.eab())
 	if err != nil {
-		return errors.New("error while initiating acme account")
+		return errors.New("error while initiating acme account > " + err.Error())
 	}
 	s.account = acme_account
 	return nil
 }
+
+// Directory : ACME directory url of the CA
+func (o ManagerOptions) Directory() string {
+	if o.DirectoryURL != "" {
+		return o.DirectoryURL
+	}
+	if o.IsStaging {
+		return letsEncryptStagingDirectoryURL
+	}
+	return letsEncryptDirectoryURL
+}
+
+func (o ManagerOptions) tlsConfig() (*tls.Config, error) {
+	if o.CACertificate == "" {
+		return &tls.Config{
+			// keep the old behaviour for let's encrypt staging
+			InsecureSkipVerify: o.IsStaging && o.DirectoryURL == "",
+		}, nil
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM([]byte(o.CACertificate)) {
+		return nil, errors.New("invalid CA certificate of ACME server")
+	}
+	return &tls.Config{
+		RootCAs: rootCAs,
+	}, nil
+}
+
+func (o ManagerOptions) eab() *acme.EAB {
+	if o.EABKeyID == "" || o.EABHMACKey == "" {
+		return nil
+	}
+	return &acme.EAB{
+		KeyID:  o.EABKeyID,
+		MACKey: o.EABHMACKey,
+	}
+}
@@ -0,0 +1,100 @@
+package Manager
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestManagerOptions(t *testing.T) {
+	t.Run("let's encrypt is used by default", func(t *testing.T) {
+		assert.Equal(t, letsEncryptDirectoryURL, ManagerOptions{}.Directory())
+		assert.Equal(t, letsEncryptStagingDirectoryURL, ManagerOptions{IsStaging: true}.Directory())
+	})
+	t.Run("custom directory overrides staging flag", func(t *testing.T) {
+		options := ManagerOptions{IsStaging: true, DirectoryURL: "https://ca.internal:9000/acme/acme/directory"}
+		assert.Equal(t, "https://ca.internal:9000/acme/acme/directory", options.Directory())
+		tlsConfig, err := options.tlsConfig()
+		assert.NoError(t, err)
+		assert.False(t, tlsConfig.InsecureSkipVerify)
+	})
+	t.Run("eab requires both key id and hmac key", func(t *testing.T) {
+		assert.Nil(t, ManagerOptions{EABKeyID: "kid"}.eab())
+		eab := ManagerOptions{EABKeyID: "kid", EABHMACKey: "hmac"}.eab()
+		assert.NotNil(t, eab)
+		assert.Equal(t, "kid", eab.KeyID)
+		assert.Equal(t, "hmac", eab.MACKey)
+	})
+	t.Run("invalid ca certificate is rejected", func(t *testing.T) {
+		_, err := ManagerOptions{CACertificate: "not a certificate"}.tlsConfig()
+		assert.Error(t, err)
+	})
+	t.Run("issuer name is derived from directory", func(t *testing.T) {
+		manager := Manager{options: ManagerOptions{DirectoryURL: "https://acme.zerossl.com/v2/DV90"}}
+		assert.Equal(t, "acme.zerossl.com", manager.FetchIssuerName())
+		assert.Equal(t, "https://acme.zerossl.com/v2/DV90", manager.FetchDirectoryURL())
+	})
+}
+
+// noopDNSProvider : works with pebble started with PEBBLE_VA_ALWAYS_VALID=1
+type noopDNSProvider struct{}
+
+func (noopDNSProvider) Present(context.Context, string, string) error { return nil }
+func (noopDNSProvider) CleanUp(context.Context, string, string) error { return nil }
+
+// TestObtainCertificateWithPebble : run against a local pebble instance
+//
+//	PEBBLE_VA_ALWAYS_VALID=1 pebble -config test/config/pebble-config.json
+//	SWIFTWAVE_TEST_ACME_DIRECTORY=https://localhost:14000/dir SWIFTWAVE_TEST_ACME_CA=test/certs/pebble.minica.pem go test ./pkg/ssl_manager/
+//
+// Set SWIFTWAVE_TEST_ACME_EAB_KEY_ID and SWIFTWAVE_TEST_ACME_EAB_HMAC_KEY if pebble requires external account binding
+func TestObtainCertificateWithPebble(t *testing.T) {
+	directoryURL := os.Getenv("SWIFTWAVE_TEST_ACME_DIRECTORY")
+	if directoryURL == "" {
+		t.Skip("SWIFTWAVE_TEST_ACME_DIRECTORY is not set")
+	}
+	caCertificate := ""
+	if caPath := os.Getenv("SWIFTWAVE_TEST_ACME_CA"); caPath != "" {
+		content, err := os.ReadFile(caPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		caCertificate = string(content)
+	}
+	accountKey := generateTestRSAPrivateKey(t)
+	manager := Manager{}
+	err := manager.Init(context.Background(), gorm.DB{}, ManagerOptions{
+		Email:             "test@example.com",
+		AccountPrivateKey: accountKey,
+		DirectoryURL:      directoryURL,
+		EABKeyID:          os.Getenv("SWIFTWAVE_TEST_ACME_EAB_KEY_ID"),
+		EABHMACKey:        os.Getenv("SWIFTWAVE_TEST_ACME_EAB_HMAC_KEY"),
+		CACertificate:     caCertificate,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	fullChain, err := manager.ObtainCertificateWithDNSChallenge("*.swiftwave.test", generateTestRSAPrivateKey(t), noopDNSProvider{})
+	assert.NoError(t, err)
+	block, _ := pem.Decode([]byte(fullChain))
+	if assert.NotNil(t, block) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		assert.Contains(t, cert.DNSNames, "*.swiftwave.test")
+	}
+}
+
+func generateTestRSAPrivateKey(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
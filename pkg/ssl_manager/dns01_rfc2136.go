@@ -0,0 +1,113 @@
+package Manager
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// This file consists dns provider for RFC2136 (dynamic updates)
+// Supported by BIND, PowerDNS, Knot DNS and most of the self-hosted nameservers
+
+// Present : add the TXT record
+func (p RFC2136Provider) Present(ctx context.Context, fqdn string, value string) error {
+	return p.update(ctx, fqdn, value, true)
+}
+
+// CleanUp : remove the TXT record
+func (p RFC2136Provider) CleanUp(ctx context.Context, fqdn string, value string) error {
+	return p.update(ctx, fqdn, value, false)
+}
+
+func (p RFC2136Provider) update(ctx context.Context, fqdn string, value string, isInsert bool) error {
+	fqdn = dns.Fqdn(fqdn)
+	zone, err := p.findZone(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+	ttl := p.TTL
+	if ttl == 0 {
+		ttl = defaultDNS01RecordTTL
+	}
+	record := &dns.TXT{
+		Hdr: dns.RR_Header{
+			Name:   fqdn,
+			Rrtype: dns.TypeTXT,
+			Class:  dns.ClassINET,
+			Ttl:    ttl,
+		},
+		Txt: []string{value},
+	}
+	message := new(dns.Msg)
+	message.SetUpdate(zone)
+	if isInsert {
+		message.Insert([]dns.RR{record})
+	} else {
+		message.Remove([]dns.RR{record})
+	}
+	response, err := p.exchange(ctx, message)
+	if err != nil {
+		return errors.New("failed to update TXT record > " + err.Error())
+	}
+	if response.Rcode != dns.RcodeSuccess {
+		return errors.New("nameserver refused to update TXT record > " + dns.RcodeToString[response.Rcode])
+	}
+	return nil
+}
+
+// findZone : find the zone of the name by querying the SOA record
+// for a non-existing name, SOA of the zone is returned in the authority section
+func (p RFC2136Provider) findZone(ctx context.Context, fqdn string) (string, error) {
+	message := new(dns.Msg)
+	message.SetQuestion(fqdn, dns.TypeSOA)
+	message.RecursionDesired = false
+	response, err := p.exchange(ctx, message)
+	if err != nil {
+		return "", errors.New("failed to find zone of " + fqdn + " > " + err.Error())
+	}
+	for _, section := range [][]dns.RR{response.Answer, response.Ns} {
+		for _, record := range section {
+			if soa, ok := record.(*dns.SOA); ok {
+				return soa.Hdr.Name, nil
+			}
+		}
+	}
+	return "", errors.New("nameserver is not authoritative for " + fqdn)
+}
+
+func (p RFC2136Provider) exchange(ctx context.Context, message *dns.Msg) (*dns.Msg, error) {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = defaultDNSQueryTimeout
+	}
+	client := &dns.Client{
+		Net:     "tcp",
+		Timeout: timeout,
+	}
+	if p.TSIGKeyName != "" && p.TSIGSecret != "" {
+		keyName := dns.Fqdn(p.TSIGKeyName)
+		client.TsigSecret = map[string]string{keyName: p.TSIGSecret}
+		message.SetTsig(keyName, p.tsigAlgorithm(), 300, time.Now().Unix())
+	}
+	response, _, err := client.ExchangeContext(ctx, message, p.nameserverAddress())
+	return response, err
+}
+
+// nameserverAddress : nameserver with the default port
+func (p RFC2136Provider) nameserverAddress() string {
+	if _, _, err := net.SplitHostPort(p.Nameserver); err == nil {
+		return p.Nameserver
+	}
+	return net.JoinHostPort(strings.Trim(p.Nameserver, "[]"), "53")
+}
+
+func (p RFC2136Provider) tsigAlgorithm() string {
+	if p.TSIGAlgorithm == "" {
+		return dns.HmacSHA256
+	}
+	return dns.Fqdn(strings.ToLower(p.TSIGAlgorithm))
+}
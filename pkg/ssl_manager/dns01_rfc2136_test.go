@@ -0,0 +1,164 @@
+package Manager
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testZone       = "example.com."
+	testTSIGKey    = "swiftwave."
+	testTSIGSecret = "c3dpZnR3YXZlLXRlc3Qtc2VjcmV0LWtleQ=="
+)
+
+// testNameserver : authoritative nameserver of testZone which accepts signed dynamic updates
+type testNameserver struct {
+	mutex   sync.Mutex
+	records map[string][]string
+}
+
+func (n *testNameserver) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	res := new(dns.Msg)
+	res.SetReply(req)
+	res.Authoritative = true
+	soa := &dns.SOA{
+		Hdr:     dns.RR_Header{Name: testZone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+		Ns:      "ns1." + testZone,
+		Mbox:    "admin." + testZone,
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  60,
+	}
+	switch req.Opcode {
+	case dns.OpcodeQuery:
+		if !dns.IsSubDomain(testZone, req.Question[0].Name) {
+			res.Rcode = dns.RcodeRefused
+		} else if req.Question[0].Name == testZone {
+			res.Answer = append(res.Answer, soa)
+		} else {
+			res.Rcode = dns.RcodeNameError
+			res.Ns = append(res.Ns, soa)
+		}
+	case dns.OpcodeUpdate:
+		if req.IsTsig() == nil || w.TsigStatus() != nil || req.Question[0].Name != testZone {
+			res.Rcode = dns.RcodeRefused
+			break
+		}
+		n.mutex.Lock()
+		for _, record := range req.Ns {
+			txt, ok := record.(*dns.TXT)
+			if !ok {
+				continue
+			}
+			if txt.Hdr.Class == dns.ClassNONE {
+				n.records[txt.Hdr.Name] = removeValue(n.records[txt.Hdr.Name], txt.Txt[0])
+			} else {
+				n.records[txt.Hdr.Name] = append(n.records[txt.Hdr.Name], txt.Txt[0])
+			}
+		}
+		n.mutex.Unlock()
+	}
+	if req.IsTsig() != nil && w.TsigStatus() == nil {
+		res.SetTsig(testTSIGKey, dns.HmacSHA256, 300, time.Now().Unix())
+	}
+	_ = w.WriteMsg(res)
+}
+
+func (n *testNameserver) values(name string) []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]string{}, n.records[name]...)
+}
+
+func removeValue(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func startTestNameserver(t *testing.T) (*testNameserver, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameserver := &testNameserver{records: make(map[string][]string)}
+	started := make(chan struct{})
+	server := &dns.Server{
+		Listener:          listener,
+		Handler:           nameserver,
+		TsigSecret:        map[string]string{testTSIGKey: testTSIGSecret},
+		NotifyStartedFunc: func() { close(started) },
+		// default accept func rejects dynamic updates
+		MsgAcceptFunc: func(dh dns.Header) dns.MsgAcceptAction {
+			return dns.MsgAccept
+		},
+	}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+	return nameserver, listener.Addr().String()
+}
+
+func TestRFC2136Provider(t *testing.T) {
+	nameserver, address := startTestNameserver(t)
+	ctx := context.Background()
+	fqdn := "_acme-challenge.app.example.com."
+
+	t.Run("present and cleanup txt record", func(t *testing.T) {
+		provider := RFC2136Provider{
+			Nameserver:  address,
+			TSIGKeyName: "swiftwave",
+			TSIGSecret:  testTSIGSecret,
+		}
+		err := provider.Present(ctx, fqdn, "token-1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"token-1"}, nameserver.values(fqdn))
+		err = provider.CleanUp(ctx, fqdn, "token-1")
+		assert.NoError(t, err)
+		assert.Empty(t, nameserver.values(fqdn))
+	})
+
+	t.Run("finds zone of the name", func(t *testing.T) {
+		provider := RFC2136Provider{Nameserver: address}
+		zone, err := provider.findZone(ctx, fqdn)
+		assert.NoError(t, err)
+		assert.Equal(t, testZone, zone)
+	})
+
+	t.Run("fails with invalid tsig secret", func(t *testing.T) {
+		provider := RFC2136Provider{
+			Nameserver:  address,
+			TSIGKeyName: "swiftwave",
+			TSIGSecret:  "aW52YWxpZC1zZWNyZXQ=",
+		}
+		err := provider.Present(ctx, fqdn, "token-2")
+		assert.Error(t, err)
+		assert.Empty(t, nameserver.values(fqdn))
+	})
+
+	t.Run("fails for the zone not served by nameserver", func(t *testing.T) {
+		provider := RFC2136Provider{
+			Nameserver:  address,
+			TSIGKeyName: "swiftwave",
+			TSIGSecret:  testTSIGSecret,
+		}
+		err := provider.Present(ctx, "_acme-challenge.example.org.", "token-3")
+		assert.Error(t, err)
+	})
+}
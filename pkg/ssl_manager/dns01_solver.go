@@ -0,0 +1,56 @@
+package Manager
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mholt/acmez/acme"
+)
+
+// This file consists dns-01 challenge solver
+
+// Required for acmez.Solver interface
+func (s dns01Solver) Present(ctx context.Context, chal acme.Challenge) error {
+	return s.provider.Present(ctx, toFqdn(chal.DNS01TXTRecordName()), chal.DNS01KeyAuthorization())
+}
+
+// Required for acmez.Solver interface
+func (s dns01Solver) CleanUp(ctx context.Context, chal acme.Challenge) error {
+	return s.provider.CleanUp(ctx, toFqdn(chal.DNS01TXTRecordName()), chal.DNS01KeyAuthorization())
+}
+
+// Wait : Required for acmez.Waiter interface
+// Wait till the TXT record is visible to the resolvers, so that the CA doesn't check it too early
+// If the record is not visible in time, the challenge is still attempted
+func (s dns01Solver) Wait(ctx context.Context, chal acme.Challenge) error {
+	recordName := chal.DNS01TXTRecordName()
+	value := chal.DNS01KeyAuthorization()
+	timeout := time.After(dns01PropagationTimeout)
+	for {
+		records, err := net.DefaultResolver.LookupTXT(ctx, recordName)
+		if err == nil {
+			for _, record := range records {
+				if strings.Compare(record, value) == 0 {
+					return nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return nil
+		case <-time.After(dns01PropagationCheckInterval):
+		}
+	}
+}
+
+// toFqdn : add the trailing dot to the name
+func toFqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
@@ -0,0 +1,60 @@
+package Manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// This file consists dns provider which delegates the record management to a http endpoint
+// It can be used to integrate the dns providers which are not supported natively
+
+// Present : ask the webhook to add the TXT record
+func (p WebhookDNSProvider) Present(ctx context.Context, fqdn string, value string) error {
+	return p.call(ctx, "present", fqdn, value)
+}
+
+// CleanUp : ask the webhook to remove the TXT record
+func (p WebhookDNSProvider) CleanUp(ctx context.Context, fqdn string, value string) error {
+	return p.call(ctx, "cleanup", fqdn, value)
+}
+
+func (p WebhookDNSProvider) call(ctx context.Context, action string, fqdn string, value string) error {
+	body, err := json.Marshal(webhookDNSRequest{
+		Action: action,
+		FQDN:   fqdn,
+		Value:  value,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Secret != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Secret)
+	}
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	client := http.Client{
+		Timeout: timeout,
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.New("failed to call dns webhook > " + err.Error())
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("dns webhook responded with status code " + strconv.Itoa(res.StatusCode))
+	}
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+
+	"github.com/mholt/acmez"
+	"github.com/mholt/acmez/acme"
 )
 
 // This file consists functions to generate SSL certificate
@@ -39,3 +42,28 @@ func (s Manager) ObtainCertificate(domain string, privateKeyStr string) (string,
 	fullchainStr := string(fullchain)
 	return fullchainStr, nil
 }
+
+// ObtainCertificateWithDNSChallenge : obtain certificate by solving dns-01 challenge
+// Domain doesn't need to point to the server, so it can be used for wildcard domains (e.g. *.example.com)
+// - return fullchain of the certificate, error
+func (s Manager) ObtainCertificateWithDNSChallenge(domain string, privateKeyStr string, provider DNS01Provider) (string, error) {
+	if provider == nil {
+		return "", errors.New("dns provider is required for dns-01 challenge")
+	}
+	privateKey, err := decodePrivateKey(privateKeyStr)
+	if err != nil {
+		return "", errors.New("unable to parse private key for domain > " + err.Error())
+	}
+	// client is copied, so that the shared client keeps using http-01 challenge
+	client := s.client
+	client.ChallengeSolvers = map[string]acmez.Solver{
+		acme.ChallengeTypeDNS01: dns01Solver{
+			provider: provider,
+		},
+	}
+	certs, err := client.ObtainCertificate(s.ctx, s.account, privateKey, []string{domain})
+	if err != nil {
+		return "", errors.New("unable to obtain certificate > " + err.Error())
+	}
+	return string(certs[0].ChainPEM), nil
+}
@@ -2,6 +2,8 @@ package Manager
 
 import (
 	"context"
+	"time"
+
 	"github.com/mholt/acmez"
 	"github.com/mholt/acmez/acme"
 	"gorm.io/gorm"
@@ -19,12 +21,71 @@ type ManagerOptions struct {
 	IsStaging         bool
 	Email             string
 	AccountPrivateKey string
+	// DirectoryURL : ACME directory of the CA, Let's Encrypt is used if empty
+	DirectoryURL string
+	// EABKeyID, EABHMACKey : External Account Binding credentials, required by some CAs (e.g. ZeroSSL)
+	EABKeyID   string
+	EABHMACKey string // base64url encoded
+	// CACertificate : PEM encoded root certificate to trust the ACME server of a private CA (e.g. step-ca, Pebble)
+	CACertificate string
 }
 
+const (
+	letsEncryptDirectoryURL        = "https://acme-v02.api.letsencrypt.org/directory"
+	letsEncryptStagingDirectoryURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+)
+
 type http01Solver struct {
 	dbClient gorm.DB
 }
 
+// DNS01Provider : manage the TXT records required for dns-01 challenge
+// fqdn is the record name with trailing dot, e.g. _acme-challenge.example.com.
+type DNS01Provider interface {
+	Present(ctx context.Context, fqdn string, value string) error
+	CleanUp(ctx context.Context, fqdn string, value string) error
+}
+
+type dns01Solver struct {
+	provider DNS01Provider
+}
+
+const (
+	defaultDNS01RecordTTL         uint32 = 120
+	defaultDNSQueryTimeout               = 10 * time.Second
+	defaultWebhookTimeout                = 30 * time.Second
+	dns01PropagationTimeout              = 2 * time.Minute
+	dns01PropagationCheckInterval        = 5 * time.Second
+)
+
+// RFC2136Provider : dns provider using dynamic updates (RFC2136) secured with TSIG
+type RFC2136Provider struct {
+	// Nameserver : primary nameserver of the zone, port 53 is used if not specified
+	Nameserver string
+	// TSIGKeyName, TSIGSecret : key to sign the updates, updates are unsigned if empty
+	TSIGKeyName string
+	TSIGSecret  string // base64 encoded
+	// TSIGAlgorithm : e.g. hmac-sha256, hmac-sha512 [default: hmac-sha256]
+	TSIGAlgorithm string
+	TTL           uint32
+	Timeout       time.Duration
+}
+
+// WebhookDNSProvider : dns provider which sends the record changes to a http endpoint
+// Request : POST <URL> {"action": "present" | "cleanup", "fqdn": "...", "value": "..."}
+type WebhookDNSProvider struct {
+	URL string
+	// Secret : sent as `Authorization: Bearer <secret>` header, if not empty
+	Secret  string
+	Timeout time.Duration
+}
+
+type webhookDNSRequest struct {
+	Action string `json:"action"`
+	FQDN   string `json:"fqdn"`
+	Value  string `json:"value"`
+}
+
 // GORM Models
 type KeyAuthorizationToken struct {
 	Token              string `gorm:"primaryKey"`
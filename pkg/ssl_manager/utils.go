@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"net/url"
 )
 
 // Decode the private key from a private key string
@@ -24,10 +25,23 @@ func decodePrivateKey(key string) (*rsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
+// FetchIssuerName : name of the CA
 func (s Manager) FetchIssuerName() string {
+	if s.options.DirectoryURL != "" {
+		directoryURL, err := url.Parse(s.options.DirectoryURL)
+		if err != nil || directoryURL.Hostname() == "" {
+			return s.options.DirectoryURL
+		}
+		return directoryURL.Hostname()
+	}
 	if s.options.IsStaging {
 		return "Let's Encrypt (Staging)"
 	} else {
 		return "Let's Encrypt"
 	}
 }
+
+// FetchDirectoryURL : ACME directory url of the CA in use
+func (s Manager) FetchDirectoryURL() string {
+	return s.options.Directory()
+}
@@ -0,0 +1,84 @@
+package task_queue
+
+import (
+	"sync"
+	"time"
+)
+
+// ConcurrencyKeyed can be implemented by the argument type of a queue to serialize its tasks
+// At most one task of a concurrency key is running at a time, across all the queues, workers and instances
+// A task whose key is held by another running task is postponed by concurrencyKeyBusyDelay, without counting it as a failed attempt
+// Empty key means the task is not serialized
+type ConcurrencyKeyed interface {
+	ConcurrencyKey() string
+}
+
+const (
+	// concurrencyKeyBusyDelay : delay before the task with a busy concurrency key is attempted again
+	concurrencyKeyBusyDelay = 2 * time.Second
+	// concurrencyKeyLockTTL : lock of a key held by a lost worker is released after this duration
+	// Lock is refreshed while the task is running, so it only expires if the worker is gone
+	concurrencyKeyLockTTL = 1 * time.Minute
+)
+
+// concurrencyKeyOf returns the concurrency key of the argument, or empty string if it's not keyed
+func concurrencyKeyOf(argument ArgumentType) string {
+	if keyed, ok := argument.(ConcurrencyKeyed); ok {
+		return keyed.ConcurrencyKey()
+	}
+	return ""
+}
+
+// concurrencyKeyLocks : keys held by the running tasks of this process
+type concurrencyKeyLocks struct {
+	mutex *sync.Mutex
+	keys  map[string]struct{}
+}
+
+func newConcurrencyKeyLocks() *concurrencyKeyLocks {
+	return &concurrencyKeyLocks{
+		mutex: &sync.Mutex{},
+		keys:  make(map[string]struct{}),
+	}
+}
+
+// tryLock acquires the key if it's not held by any other task of this process
+func (c *concurrencyKeyLocks) tryLock(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.keys[key]; ok {
+		return false
+	}
+	c.keys[key] = struct{}{}
+	return true
+}
+
+func (c *concurrencyKeyLocks) unlock(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.keys, key)
+}
+
+// keepLockAlive calls refresh periodically till the returned function is called
+// The returned function waits for the running refresh, so the lock can be released safely after it
+func keepLockAlive(refresh func()) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(concurrencyKeyLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
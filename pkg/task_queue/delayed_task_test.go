@@ -0,0 +1,216 @@
+package task_queue
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type DelayedTaskTestArgument struct {
+	Name string `json:"name"`
+}
+
+// DelayedTaskTestWorker reports the time of consumption of the tasks
+type DelayedTaskTestWorker struct {
+	consumed chan time.Time
+}
+
+func (w DelayedTaskTestWorker) Consume(_ DelayedTaskTestArgument, _ context.Context, _ context.CancelFunc) error {
+	if w.consumed != nil {
+		w.consumed <- time.Now()
+	}
+	return nil
+}
+
+const delayedTaskTestQueueName = "delayed_task_test"
+
+func TestMain(m *testing.M) {
+	// set before any consumer is started, as consumers of a test keep running after it
+	redisDelayedTasksPollInterval = time.Second
+	os.Exit(m.Run())
+}
+
+func newDelayedTaskTestConsumer() (WorkerFunctionType, <-chan time.Time) {
+	consumed := make(chan time.Time, 10)
+	return DelayedTaskTestWorker{consumed: consumed}.Consume, consumed
+}
+
+func newLocalTestClient(t *testing.T, db *gorm.DB) Client {
+	client, err := NewClient(Options{
+		Type:                Local,
+		NoOfWorkersPerQueue: 1,
+		MaxMessagesPerQueue: 10,
+		DbClient:            db,
+	})
+	assert.NoError(t, err)
+	return client
+}
+
+func newRedisTestClient(t *testing.T, address string) Client {
+	client, err := NewClient(Options{
+		Type:                Remote,
+		NoOfWorkersPerQueue: 1,
+		RemoteQueueType:     RedisQueue,
+		RedisClient:         redis.NewClient(&redis.Options{Addr: address}),
+	})
+	assert.NoError(t, err)
+	return client
+}
+
+// newAmqpTestClient : client of the broker at SWIFTWAVE_TEST_AMQP_URI, the test is skipped if it's not set
+func newAmqpTestClient(t *testing.T) Client {
+	amqpUri := os.Getenv("SWIFTWAVE_TEST_AMQP_URI")
+	if amqpUri == "" {
+		t.Skip("SWIFTWAVE_TEST_AMQP_URI is not set")
+	}
+	client, err := NewClient(Options{
+		Type:                Remote,
+		NoOfWorkersPerQueue: 1,
+		RemoteQueueType:     AmqpQueue,
+		AMQPUri:             amqpUri,
+		AMQPClientName:      "swiftwave_test",
+	})
+	assert.NoError(t, err)
+	return client
+}
+
+func TestLocalDelayedTaskSurvivesRestart(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tasks.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&EnqueuedTask{}))
+
+	// enqueue the task and stop before it's due
+	client := newLocalTestClient(t, db)
+	assert.NoError(t, client.RegisterFunction(delayedTaskTestQueueName, DelayedTaskTestWorker{}.Consume))
+	scheduledAt := time.Now().Add(2 * time.Second)
+	assert.NoError(t, client.EnqueueTaskAt(delayedTaskTestQueueName, DelayedTaskTestArgument{Name: "restart"}, scheduledAt))
+
+	// start again with the same database
+	restartedClient := newLocalTestClient(t, db)
+	consumer, consumed := newDelayedTaskTestConsumer()
+	assert.NoError(t, restartedClient.RegisterFunction(delayedTaskTestQueueName, consumer))
+	assert.NoError(t, restartedClient.StartConsumers(true))
+	assert.NoError(t, restartedClient.EnqueueProcessingQueueExpiredTask())
+
+	select {
+	case consumedAt := <-consumed:
+		assert.False(t, consumedAt.Before(scheduledAt), "task consumed before its scheduled time")
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled task was not consumed after restart")
+	}
+	assert.Eventually(t, func() bool {
+		messages, err := restartedClient.ListMessages(delayedTaskTestQueueName)
+		return err == nil && len(messages) == 0
+	}, 2*time.Second, 100*time.Millisecond)
+}
+
+func TestLocalDueTaskIsEnqueuedImmediately(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tasks.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&EnqueuedTask{}))
+
+	client := newLocalTestClient(t, db)
+	consumer, consumed := newDelayedTaskTestConsumer()
+	assert.NoError(t, client.RegisterFunction(delayedTaskTestQueueName, consumer))
+	assert.NoError(t, client.StartConsumers(true))
+	assert.NoError(t, client.EnqueueTaskAfter(delayedTaskTestQueueName, DelayedTaskTestArgument{Name: "now"}, -time.Second))
+
+	select {
+	case <-consumed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("due task was not consumed")
+	}
+}
+
+func TestLocalDuplicateDelayedTaskIsConsumedOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tasks.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&EnqueuedTask{}))
+
+	client := newLocalTestClient(t, db)
+	consumer, consumed := newDelayedTaskTestConsumer()
+	assert.NoError(t, client.RegisterFunction(delayedTaskTestQueueName, consumer))
+	// task is waiting in the queue, so the same task is not scheduled again
+	assert.NoError(t, client.EnqueueTask(delayedTaskTestQueueName, DelayedTaskTestArgument{Name: "duplicate"}))
+	assert.NoError(t, client.EnqueueTaskAfter(delayedTaskTestQueueName, DelayedTaskTestArgument{Name: "duplicate"}, 500*time.Millisecond))
+	time.Sleep(time.Second)
+	assert.NoError(t, client.StartConsumers(true))
+
+	select {
+	case <-consumed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("task was not consumed")
+	}
+	select {
+	case <-consumed:
+		t.Fatal("duplicate task was consumed again")
+	case <-time.After(2 * time.Second):
+	}
+}
+
+func TestRedisDelayedTaskSurvivesRestart(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+
+	// enqueue the task and stop before it's due
+	client := newRedisTestClient(t, redisServer.Addr())
+	assert.NoError(t, client.RegisterFunction(delayedTaskTestQueueName, DelayedTaskTestWorker{}.Consume))
+	scheduledAt := time.Now().Add(2 * time.Second)
+	assert.NoError(t, client.EnqueueTaskAt(delayedTaskTestQueueName, DelayedTaskTestArgument{Name: "restart"}, scheduledAt))
+	messages, err := client.ListMessages(delayedTaskTestQueueName)
+	assert.NoError(t, err)
+	assert.Empty(t, messages, "delayed task should not be in the queue before its time")
+
+	// start again with the same redis server
+	restartedClient := newRedisTestClient(t, redisServer.Addr())
+	consumer, consumed := newDelayedTaskTestConsumer()
+	assert.NoError(t, restartedClient.RegisterFunction(delayedTaskTestQueueName, consumer))
+	assert.NoError(t, restartedClient.StartConsumers(true))
+
+	select {
+	case consumedAt := <-consumed:
+		assert.False(t, consumedAt.Before(scheduledAt), "task consumed before its scheduled time")
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled task was not consumed after restart")
+	}
+}
+
+func TestDelayBucket(t *testing.T) {
+	assert.Equal(t, time.Second, delayBucket(200*time.Millisecond))
+	assert.Equal(t, time.Second, delayBucket(1500*time.Millisecond))
+	assert.Equal(t, 4*time.Second, delayBucket(7*time.Second))
+	assert.Equal(t, 64*time.Second, delayBucket(90*time.Second))
+	// capped at the largest bucket
+	assert.Equal(t, time.Duration(1<<amqpMaxDelayBucketExponent)*time.Second, delayBucket(30*24*time.Hour))
+}
+
+// TestAmqpDelayedTaskIsConsumedAfterItsTime : run against a local rabbitmq instance, see newAmqpTestClient
+func TestAmqpDelayedTaskIsConsumedAfterItsTime(t *testing.T) {
+	client := newAmqpTestClient(t)
+	consumer, consumed := newDelayedTaskTestConsumer()
+	assert.NoError(t, client.RegisterFunction(delayedTaskTestQueueName, consumer))
+	assert.NoError(t, client.StartConsumers(true))
+	// delay is not a power of two, so the message passes through more than one delay queue
+	scheduledAt := time.Now().Add(3 * time.Second)
+	assert.NoError(t, client.EnqueueTaskAt(delayedTaskTestQueueName, DelayedTaskTestArgument{Name: "amqp"}, scheduledAt))
+
+	select {
+	case consumedAt := <-consumed:
+		assert.False(t, consumedAt.Before(scheduledAt), "task consumed before its scheduled time")
+	case <-time.After(10 * time.Second):
+		t.Fatal("scheduled task was not consumed")
+	}
+}
@@ -2,8 +2,10 @@ package task_queue
 
 import (
 	"errors"
+	"github.com/nats-io/nats.go/jetstream"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"sync"
+	"time"
 )
 
 func NewClient(options Options) (Client, error) {
@@ -35,6 +37,10 @@ func createLocalTaskQueueClient(options Options) (Client, error) {
 		maxMessagesPerQueue:         options.MaxMessagesPerQueue,
 		NoOfWorkersPerQueue:         options.NoOfWorkersPerQueue,
 		consumersWaitGroup:          &sync.WaitGroup{},
+		mutexScheduledTasks:         &sync.Mutex{},
+		scheduledTasks:              make(map[string]*time.Timer),
+		concurrencyKeyLocks:         newConcurrencyKeyLocks(),
+		taskRunRecorder:             newTaskRunRecorder(options),
 	}, nil
 }
 
@@ -63,6 +69,8 @@ func createRemoteTaskQueueClient(options Options) (Client, error) {
 			amqpConfig:                  amqpConfig,
 			amqpClientName:              options.AMQPClientName,
 			consumersWaitGroup:          &sync.WaitGroup{},
+			concurrencyKeyLocks:         newConcurrencyKeyLocks(),
+			taskRunRecorder:             newTaskRunRecorder(options),
 		}, nil
 	} else if options.RemoteQueueType == RedisQueue {
 		if options.RedisClient == nil {
@@ -75,8 +83,33 @@ func createRemoteTaskQueueClient(options Options) (Client, error) {
 			queueToFunctionMapping:      functionsMapping,
 			redisClient:                 options.RedisClient,
 			consumersWaitGroup:          &sync.WaitGroup{},
+			concurrencyKeyLocks:         newConcurrencyKeyLocks(),
+			taskRunRecorder:             newTaskRunRecorder(options),
 		}, nil
 
+	} else if options.RemoteQueueType == NatsQueue {
+		if options.NatsConnection == nil {
+			return nil, errors.New("nats connection is nil")
+		}
+		js, err := jetstream.New(options.NatsConnection)
+		if err != nil {
+			return nil, err
+		}
+		locks, err := declareLocksBucketUsingNATS(js)
+		if err != nil {
+			return nil, err
+		}
+		return &remoteTaskQueue{
+			queueType:                   NatsQueue,
+			mutexQueueToFunctionMapping: mutex,
+			NoOfWorkersPerQueue:         options.NoOfWorkersPerQueue,
+			queueToFunctionMapping:      functionsMapping,
+			natsJetStream:               js,
+			natsLocks:                   locks,
+			consumersWaitGroup:          &sync.WaitGroup{},
+			concurrencyKeyLocks:         newConcurrencyKeyLocks(),
+			taskRunRecorder:             newTaskRunRecorder(options),
+		}, nil
 	} else {
 		return nil, errors.New("invalid remote queue type")
 	}
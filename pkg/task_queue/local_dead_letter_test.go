@@ -0,0 +1,123 @@
+package task_queue
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type DeadLetterTestArgument struct {
+	Name string `json:"name"`
+}
+
+// DeadLetterTestWorker fails all the tasks and reports the attempts
+type DeadLetterTestWorker struct {
+	attempted chan DeadLetterTestArgument
+}
+
+func (w DeadLetterTestWorker) Consume(argument DeadLetterTestArgument, _ context.Context, _ context.CancelFunc) error {
+	w.attempted <- argument
+	return errors.New("failed to process " + argument.Name)
+}
+
+const deadLetterTestQueueName = "dead_letter_test"
+
+var deadLetterTestRetryPolicy = RetryPolicy{
+	MaxAttempts:    2,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     50 * time.Millisecond,
+	Multiplier:     1,
+}
+
+func newDeadLetterTestDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tasks.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&EnqueuedTask{}))
+	return db
+}
+
+func waitForDeadLetterTask(t *testing.T, client Client) DeadLetterTask {
+	t.Helper()
+	var tasks []DeadLetterTask
+	assert.Eventually(t, func() bool {
+		var err error
+		tasks, err = client.ListDeadLetterTasks(deadLetterTestQueueName)
+		return err == nil && len(tasks) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+	if len(tasks) != 1 {
+		t.FailNow()
+	}
+	return tasks[0]
+}
+
+func TestLocalFailedTaskMovesToDeadLetterQueue(t *testing.T) {
+	client := newLocalTestClient(t, newDeadLetterTestDb(t))
+	attempted := make(chan DeadLetterTestArgument, 10)
+	assert.NoError(t, client.RegisterFunctionWithRetryPolicy(deadLetterTestQueueName, DeadLetterTestWorker{attempted: attempted}.Consume, deadLetterTestRetryPolicy))
+	assert.NoError(t, client.StartConsumers(true))
+	assert.NoError(t, client.EnqueueTask(deadLetterTestQueueName, DeadLetterTestArgument{Name: "first"}))
+
+	task := waitForDeadLetterTask(t, client)
+	assert.Equal(t, uint(2), task.Attempts)
+	assert.Equal(t, "failed to process first", task.FailureReason)
+	assert.Len(t, attempted, 2)
+	messages, err := client.ListMessages(deadLetterTestQueueName)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	// requeued task gets all the attempts again
+	assert.NoError(t, client.RequeueDeadLetterTask(deadLetterTestQueueName, task.ID))
+	assert.Eventually(t, func() bool {
+		return len(attempted) == 4
+	}, 5*time.Second, 20*time.Millisecond)
+	task = waitForDeadLetterTask(t, client)
+	assert.Equal(t, uint(2), task.Attempts)
+
+	assert.NoError(t, client.DropDeadLetterTask(deadLetterTestQueueName, task.ID))
+	tasks, err := client.ListDeadLetterTasks(deadLetterTestQueueName)
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+	assert.Error(t, client.DropDeadLetterTask(deadLetterTestQueueName, task.ID))
+}
+
+func TestLocalTaskWithoutRetryMovesToDeadLetterQueue(t *testing.T) {
+	client := newLocalTestClient(t, newDeadLetterTestDb(t))
+	attempted := make(chan DeadLetterTestArgument, 10)
+	assert.NoError(t, client.RegisterFunctionWithRetryPolicy(deadLetterTestQueueName, DeadLetterTestWorker{attempted: attempted}.Consume, NoRetryPolicy()))
+	assert.NoError(t, client.StartConsumers(true))
+	assert.NoError(t, client.EnqueueTask(deadLetterTestQueueName, DeadLetterTestArgument{Name: "once"}))
+
+	task := waitForDeadLetterTask(t, client)
+	assert.Equal(t, uint(1), task.Attempts)
+	assert.Len(t, attempted, 1)
+}
+
+func TestLocalExpiredTaskKeepsAttempts(t *testing.T) {
+	db := newDeadLetterTestDb(t)
+	// task failed once before the restart
+	body := `{"name":"restart"}`
+	_, err := addTaskToDb(db, deadLetterTestQueueName, body, nil)
+	assert.NoError(t, err)
+	attempts, err := recordTaskFailureInDb(db, deadLetterTestQueueName, body, "failed to process restart")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), attempts)
+
+	client := newLocalTestClient(t, db)
+	attempted := make(chan DeadLetterTestArgument, 10)
+	assert.NoError(t, client.RegisterFunctionWithRetryPolicy(deadLetterTestQueueName, DeadLetterTestWorker{attempted: attempted}.Consume, deadLetterTestRetryPolicy))
+	assert.NoError(t, client.StartConsumers(true))
+	assert.NoError(t, client.EnqueueProcessingQueueExpiredTask())
+
+	// only the remaining attempt is made
+	task := waitForDeadLetterTask(t, client)
+	assert.Equal(t, uint(2), task.Attempts)
+	assert.Len(t, attempted, 1)
+}
@@ -4,11 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
-	"reflect"
+	"strconv"
 	"sync"
+	"time"
 )
 
 func (l *localTaskQueue) RegisterFunction(queueName string, function WorkerFunctionType) error {
+	return l.RegisterFunctionWithRetryPolicy(queueName, function, DefaultRetryPolicy())
+}
+
+func (l *localTaskQueue) RegisterFunctionWithRetryPolicy(queueName string, function WorkerFunctionType, retryPolicy RetryPolicy) error {
+	// validate retry policy
+	if err := retryPolicy.Validate(); err != nil {
+		return err
+	}
+
 	// acquire lock
 	l.mutexQueueToFunctionMapping.Lock()
 	// release lock when function returns
@@ -30,6 +40,7 @@ func (l *localTaskQueue) RegisterFunction(queueName string, function WorkerFunct
 	if err != nil {
 		return err
 	}
+	metadata.retryPolicy = retryPolicy
 
 	// add function to mapping
 	l.queueToFunctionMapping[queueName] = metadata
@@ -56,7 +67,7 @@ func (l *localTaskQueue) EnqueueTask(queueName string, argument ArgumentType) er
 	if err != nil {
 		return err
 	}
-	err = addTaskToDb(l.db, queueName, string(jsonBytes))
+	_, err = addTaskToDb(l.db, queueName, string(jsonBytes), nil)
 	if err != nil {
 		return err
 	}
@@ -68,7 +79,50 @@ func (l *localTaskQueue) EnqueueTask(queueName string, argument ArgumentType) er
 	return nil
 }
 
+func (l *localTaskQueue) EnqueueTaskAt(queueName string, argument ArgumentType, at time.Time) error {
+	if !at.After(time.Now()) {
+		return l.EnqueueTask(queueName, argument)
+	}
+	// fetch function by queue name
+	functionMetadata, err := l.getFunction(queueName)
+	if err != nil {
+		return err
+	}
+	// verify the argument type
+	if functionMetadata.argumentTypeName != getTypeName(argument) {
+		return errors.New("invalid argument type for this queue, expected [" + functionMetadata.argumentTypeName + "]")
+	}
+
+	// persist the task with schedule
+	jsonBytes, err := json.Marshal(argument)
+	if err != nil {
+		return err
+	}
+	added, err := addTaskToDb(l.db, queueName, string(jsonBytes), &at)
+	if err != nil {
+		return err
+	}
+	// same task is already scheduled or waiting in the queue
+	if !added {
+		return nil
+	}
+	l.scheduleTask(queueName, taskHash(string(jsonBytes)), argument, at)
+	return nil
+}
+
+func (l *localTaskQueue) EnqueueTaskAfter(queueName string, argument ArgumentType, delay time.Duration) error {
+	return l.EnqueueTaskAt(queueName, argument, time.Now().Add(delay))
+}
+
 func (l *localTaskQueue) StartConsumers(nowait bool) error {
+	// tasks are consumed only by this instance, so the runs which are still running were left by the previous instance
+	if l.taskRunRecorder.db != nil {
+		err := MarkLostTaskRunsAsFailed(l.taskRunRecorder.db, time.Now())
+		if err != nil {
+			log.Println("error while marking lost task runs as failed", err)
+		}
+	}
+
 	// copy the queue names to a new slice
 	queueNames := make([]string, 0, len(l.queueToChannelMapping))
 
@@ -107,29 +161,42 @@ func (l *localTaskQueue) WaitForConsumers() {
 
 func (l *localTaskQueue) EnqueueProcessingQueueExpiredTask() error {
 	for queueName := range l.queueToChannelMapping {
-		tasks, err := getTasksFromDb(l.db, queueName, true)
+		// tasks scheduled for future are kept in database, and consumed once their time comes
+		scheduledTasks, err := getScheduledTasksFromDb(l.db, queueName)
+		if err != nil {
+			log.Println(err)
+		}
+		for _, task := range scheduledTasks {
+			functionMetadata, err := l.getFunction(queueName)
+			if err != nil {
+				continue
+			}
+			argument, err := unmarshalArgument(functionMetadata, task.Body)
+			if err != nil {
+				log.Println("error while unmarshalling argument for queue ["+queueName+"]", err)
+				continue
+			}
+			l.scheduleTask(queueName, task.Hash, argument, *task.ScheduledAt)
+		}
+		// due tasks are pushed again as they are, so the attempts made so far are kept
+		tasks, err := getDueTasksFromDb(l.db, queueName)
 		if err != nil {
 			log.Println(err)
 			continue
 		}
-		for _, task := range *tasks {
+		for _, task := range tasks {
 			functionMetadata, err := l.getFunction(queueName)
 			if err != nil {
 				log.Println("error while fetching function for queue [" + queueName + "]")
 				log.Println("error: " + err.Error())
 				continue
 			}
-			// create a new object of an argument type
-			argument := reflect.New(functionMetadata.argumentType).Interface()
-			err = json.Unmarshal([]byte(task.Body), &argument)
+			argument, err := unmarshalArgument(functionMetadata, task.Body)
 			if err != nil {
 				log.Println("error while unmarshalling argument for queue ["+queueName+"]", err)
 				continue
 			}
-			// argument is a pointer, dereference it
-			argument = reflect.ValueOf(argument).Elem().Interface()
-			// enqueue task
-			err = l.EnqueueTask(queueName, argument)
+			err = l.pushToChannel(queueName, argument)
 			if err != nil {
 				log.Println("error while enqueueing task for queue ["+queueName+"]", err)
 			}
@@ -154,7 +221,136 @@ func (l *localTaskQueue) ListMessages(queueName string) ([]string, error) {
 	return result, nil
 }
 
+func (l *localTaskQueue) ListDeadLetterTasks(queueName string) ([]DeadLetterTask, error) {
+	tasks, err := getDeadLetterTasksFromDb(l.db, queueName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]DeadLetterTask, 0, len(tasks))
+	for _, task := range tasks {
+		deadLetterTask := DeadLetterTask{
+			ID:            task.Hash,
+			QueueName:     task.QueueName,
+			Body:          task.Body,
+			Attempts:      task.Attempts,
+			FailureReason: task.FailureReason,
+		}
+		if task.FailedAt != nil {
+			deadLetterTask.FailedAt = *task.FailedAt
+		}
+		result = append(result, deadLetterTask)
+	}
+	return result, nil
+}
+
+// RequeueDeadLetterTask moves the task back to the queue
+// If the queue has no consumer in this process, the task is picked up by EnqueueProcessingQueueExpiredTask of the running service
+func (l *localTaskQueue) RequeueDeadLetterTask(queueName string, taskId string) error {
+	err := requeueDeadLetterTaskInDb(l.db, queueName, taskId)
+	if err != nil {
+		return err
+	}
+	functionMetadata, err := l.getFunction(queueName)
+	if err != nil {
+		return nil
+	}
+	tasks, err := getTasksFromDb(l.db, queueName, false)
+	if err != nil {
+		return err
+	}
+	for _, task := range *tasks {
+		if task.Hash != taskId {
+			continue
+		}
+		argument, err := unmarshalArgument(functionMetadata, task.Body)
+		if err != nil {
+			return err
+		}
+		return l.pushToChannel(queueName, argument)
+	}
+	return nil
+}
+
+func (l *localTaskQueue) DropDeadLetterTask(queueName string, taskId string) error {
+	return removeDeadLetterTaskFromDb(l.db, queueName, taskId)
+}
+
 // private function
+// handleFailedTask retries the task after backoff, or moves it to the dead-letter queue if the attempts are exhausted
+func (l *localTaskQueue) handleFailedTask(queueName string, retryPolicy RetryPolicy, argument ArgumentType, content string, taskErr error) {
+	attempts, err := recordTaskFailureInDb(l.db, queueName, content, taskErr.Error())
+	if err != nil {
+		log.Println("error while recording failure of task for queue ["+queueName+"]", err)
+		return
+	}
+	if !retryPolicy.ShouldRetry(attempts) {
+		log.Println("moving task to dead-letter queue for queue [" + queueName + "] after " + strconv.Itoa(int(attempts)) + " attempts")
+		err = moveTaskToDeadLetterInDb(l.db, queueName, content)
+		if err != nil {
+			log.Println("error while moving task to dead-letter queue for queue ["+queueName+"]", err)
+		}
+		return
+	}
+	// persist the time of next attempt, so that the retry survives restart
+	nextAttemptAt := time.Now().Add(retryPolicy.Backoff(attempts))
+	err = scheduleTaskInDb(l.db, queueName, content, nextAttemptAt)
+	if err != nil {
+		log.Println("error while scheduling retry of task for queue ["+queueName+"]", err)
+	}
+	l.scheduleTask(queueName, taskHash(content), argument, nextAttemptAt)
+}
+
+// postponeTask schedules the task again after concurrencyKeyBusyDelay, attempts of the task are not changed
+func (l *localTaskQueue) postponeTask(queueName string, argument ArgumentType, content string) {
+	at := time.Now().Add(concurrencyKeyBusyDelay)
+	err := scheduleTaskInDb(l.db, queueName, content, at)
+	if err != nil {
+		log.Println("error while postponing task for queue ["+queueName+"]", err)
+	}
+	l.scheduleTask(queueName, taskHash(content), argument, at)
+}
+
+// scheduleTask pushes the task to the channel at the given time, if it's still pending in the database by then
+// A task is scheduled only once in a process, even if it's found again by EnqueueProcessingQueueExpiredTask
+func (l *localTaskQueue) scheduleTask(queueName string, hash string, argument ArgumentType, at time.Time) {
+	key := queueName + ":" + hash
+	l.mutexScheduledTasks.Lock()
+	defer l.mutexScheduledTasks.Unlock()
+	if _, ok := l.scheduledTasks[key]; ok {
+		return
+	}
+	l.scheduledTasks[key] = time.AfterFunc(time.Until(at), func() {
+		l.mutexScheduledTasks.Lock()
+		delete(l.scheduledTasks, key)
+		l.mutexScheduledTasks.Unlock()
+		// task can be purged or dropped in the meantime
+		exists, err := existsTaskInDb(l.db, queueName, hash)
+		if err != nil || !exists {
+			return
+		}
+		err = l.pushToChannel(queueName, argument)
+		if err != nil {
+			// task is still in the database, it will be enqueued again by EnqueueProcessingQueueExpiredTask
+			log.Println("error while enqueueing scheduled task for queue ["+queueName+"]", err)
+		}
+	})
+}
+
+func (l *localTaskQueue) pushToChannel(queueName string, argument ArgumentType) error {
+	l.mutexQueueToChannelMapping.RLock()
+	channel, ok := l.queueToChannelMapping[queueName]
+	l.mutexQueueToChannelMapping.RUnlock()
+	if !ok {
+		return errors.New("no channel registered for this queue")
+	}
+	select {
+	case channel <- argument:
+		return nil
+	default:
+		return errors.New("queue is full, cannot enqueue task")
+	}
+}
+
 func (l *localTaskQueue) getFunction(queueName string) (functionMetadata, error) {
 	// acquire lock
 	l.mutexQueueToFunctionMapping.RLock()
@@ -211,14 +407,29 @@ func (l *localTaskQueue) listenForTasks(queueName string, wg *sync.WaitGroup) {
 			break
 		}
 
+		jsonBytes, marshalErr := json.Marshal(argument)
+		if marshalErr != nil {
+			log.Println("error while marshalling argument for queue ["+queueName+"]", marshalErr)
+			continue
+		}
+		// postpone the task if another task with the same concurrency key is running
+		concurrencyKey := concurrencyKeyOf(argument)
+		if concurrencyKey != "" {
+			if !l.concurrencyKeyLocks.tryLock(concurrencyKey) {
+				l.postponeTask(queueName, argument, string(jsonBytes))
+				continue
+			}
+		}
+		run := l.taskRunRecorder.start(queueName, argument, string(jsonBytes))
 		err := invokeFunction(functionMetadata.function, argument, functionMetadata.argumentType)
+		l.taskRunRecorder.finish(run, err)
+		if concurrencyKey != "" {
+			l.concurrencyKeyLocks.unlock(concurrencyKey)
+		}
 		if err != nil {
 			log.Println("error while invoking function for queue [" + queueName + "]")
+			l.handleFailedTask(queueName, functionMetadata.retryPolicy, argument, string(jsonBytes), err)
 		} else {
-			jsonBytes, err := json.Marshal(argument)
-			if err != nil {
-				log.Println("error while marshalling argument for queue ["+queueName+"] to remove", err)
-			}
 			err = removeTaskFromDb(l.db, queueName, string(jsonBytes))
 			if err != nil {
 				log.Println("error while removing task from DbClient for queue ["+queueName+"]", err)
@@ -1,56 +1,56 @@
 package task_queue
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"github.com/fatih/color"
 	"gorm.io/gorm"
+	"time"
 )
 
 // EnqueuedTask holds the task details
 // Add EnqueuedTask to gorm migration
 type EnqueuedTask struct {
-	ID        int `gorm:"primaryKey"`
-	QueueName string
-	Body      string
-	Hash      string
+	ID            int `gorm:"primaryKey"`
+	QueueName     string
+	Body          string
+	Hash          string
+	Attempts      uint       `gorm:"default:0"`
+	FailureReason string     // error of the last failed attempt
+	DeadLetter    bool       `gorm:"default:false"`
+	FailedAt      *time.Time // set when the task is moved to the dead-letter queue
+	ScheduledAt   *time.Time // task is not consumed before this time, nil for immediate tasks
 }
 
-func addTaskToDb(db *gorm.DB, queueName string, body string) error {
-	h := sha256.New()
-	_, err := h.Write([]byte(body))
-	if err != nil {
-		return err
-	}
-	hashSum := h.Sum(nil)
-	hashString := hex.EncodeToString(hashSum)
+// addTaskToDb returns false if the same task is already in the queue, then it's not added again
+func addTaskToDb(db *gorm.DB, queueName string, body string, scheduledAt *time.Time) (bool, error) {
+	hashString := taskHash(body)
 
 	exists, err := existsTaskInDb(db, queueName, hashString)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if exists {
-		return nil
+		return false, nil
 	}
 
 	task := &EnqueuedTask{
-		ID:        0,
-		QueueName: queueName,
-		Body:      body,
-		Hash:      hashString,
+		ID:          0,
+		QueueName:   queueName,
+		Body:        body,
+		Hash:        hashString,
+		ScheduledAt: scheduledAt,
 	}
 	result := db.Create(task)
 	if result.Error != nil {
 		color.Red(result.Error.Error())
-		return result.Error
+		return false, result.Error
 	}
-	return nil
+	return true, nil
 }
 
 func existsTaskInDb(db *gorm.DB, queueName string, hash string) (bool, error) {
 	var task EnqueuedTask
-	result := db.Where("queue_name = ? AND hash = ?", queueName, hash).First(&task)
+	result := db.Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, false).First(&task)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return false, nil
@@ -61,13 +61,8 @@ func existsTaskInDb(db *gorm.DB, queueName string, hash string) (bool, error) {
 }
 
 func removeTaskFromDb(db *gorm.DB, queueName string, content string) error {
-	h := sha256.New()
-	_, err := h.Write([]byte(content))
-	if err != nil {
-		return err
-	}
-	hash := hex.EncodeToString(h.Sum(nil))
-	result := db.Where("queue_name = ? AND hash = ?", queueName, hash).Delete(&EnqueuedTask{})
+	hash := taskHash(content)
+	result := db.Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, false).Delete(&EnqueuedTask{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -75,7 +70,7 @@ func removeTaskFromDb(db *gorm.DB, queueName string, content string) error {
 }
 
 func remoteTasksFromDb(db *gorm.DB, queueName string) error {
-	result := db.Where("queue_name = ?", queueName).Delete(&EnqueuedTask{})
+	result := db.Where("queue_name = ? AND dead_letter = ?", queueName, false).Delete(&EnqueuedTask{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -84,7 +79,7 @@ func remoteTasksFromDb(db *gorm.DB, queueName string) error {
 
 func getTasksFromDb(db *gorm.DB, queueName string, removeTasks bool) (*[]EnqueuedTask, error) {
 	var tasks []EnqueuedTask
-	result := db.Where("queue_name = ?", queueName).Find(&tasks)
+	result := db.Where("queue_name = ? AND dead_letter = ?", queueName, false).Find(&tasks)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -93,3 +88,90 @@ func getTasksFromDb(db *gorm.DB, queueName string, removeTasks bool) (*[]Enqueue
 	}
 	return &tasks, nil
 }
+
+// getDueTasksFromDb returns the tasks which are not scheduled for future
+func getDueTasksFromDb(db *gorm.DB, queueName string) ([]EnqueuedTask, error) {
+	var tasks []EnqueuedTask
+	result := db.Where("queue_name = ? AND dead_letter = ? AND (scheduled_at IS NULL OR scheduled_at <= ?)", queueName, false, time.Now()).Find(&tasks)
+	return tasks, result.Error
+}
+
+// getScheduledTasksFromDb returns the tasks which are scheduled for future
+func getScheduledTasksFromDb(db *gorm.DB, queueName string) ([]EnqueuedTask, error) {
+	var tasks []EnqueuedTask
+	result := db.Where("queue_name = ? AND dead_letter = ? AND scheduled_at > ?", queueName, false, time.Now()).Find(&tasks)
+	return tasks, result.Error
+}
+
+func scheduleTaskInDb(db *gorm.DB, queueName string, content string, scheduledAt time.Time) error {
+	hash := taskHash(content)
+	result := db.Model(&EnqueuedTask{}).Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, false).Update("scheduled_at", scheduledAt)
+	return result.Error
+}
+
+// recordTaskFailureInDb increments the attempts of the task and returns the attempts made so far
+func recordTaskFailureInDb(db *gorm.DB, queueName string, content string, failureReason string) (uint, error) {
+	hash := taskHash(content)
+	var task EnqueuedTask
+	result := db.Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, false).First(&task)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	task.Attempts = task.Attempts + 1
+	task.FailureReason = failureReason
+	result = db.Model(&task).Select("attempts", "failure_reason").Updates(&task)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return task.Attempts, nil
+}
+
+func moveTaskToDeadLetterInDb(db *gorm.DB, queueName string, content string) error {
+	hash := taskHash(content)
+	result := db.Model(&EnqueuedTask{}).Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, false).Updates(map[string]interface{}{
+		"dead_letter": true,
+		"failed_at":   time.Now(),
+	})
+	return result.Error
+}
+
+func getDeadLetterTasksFromDb(db *gorm.DB, queueName string) ([]EnqueuedTask, error) {
+	var tasks []EnqueuedTask
+	result := db.Where("queue_name = ? AND dead_letter = ?", queueName, true).Order("id asc").Find(&tasks)
+	return tasks, result.Error
+}
+
+// requeueDeadLetterTaskInDb moves the task back to the queue
+// If the same task is already pending in the queue, the dead-letter task is just removed
+func requeueDeadLetterTaskInDb(db *gorm.DB, queueName string, hash string) error {
+	var task EnqueuedTask
+	result := db.Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, true).First(&task)
+	if result.Error != nil {
+		return result.Error
+	}
+	exists, err := existsTaskInDb(db, queueName, hash)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return db.Delete(&task).Error
+	}
+	return db.Model(&task).Updates(map[string]interface{}{
+		"dead_letter":    false,
+		"attempts":       0,
+		"failure_reason": "",
+		"failed_at":      nil,
+		"scheduled_at":   nil,
+	}).Error
+}
+
+func removeDeadLetterTaskFromDb(db *gorm.DB, queueName string, hash string) error {
+	result := db.Where("queue_name = ? AND hash = ? AND dead_letter = ?", queueName, hash, true).Delete(&EnqueuedTask{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
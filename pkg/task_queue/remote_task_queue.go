@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/nats-io/nats.go"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"log"
 	"reflect"
@@ -14,6 +15,14 @@ import (
 )
 
 func (r *remoteTaskQueue) RegisterFunction(queueName string, function WorkerFunctionType) error {
+	return r.RegisterFunctionWithRetryPolicy(queueName, function, DefaultRetryPolicy())
+}
+
+func (r *remoteTaskQueue) RegisterFunctionWithRetryPolicy(queueName string, function WorkerFunctionType, retryPolicy RetryPolicy) error {
+	// validate retry policy
+	if err := retryPolicy.Validate(); err != nil {
+		return err
+	}
 	// acquire lock
 	r.mutexQueueToFunctionMapping.Lock()
 	// release lock when function returns
@@ -28,6 +37,7 @@ func (r *remoteTaskQueue) RegisterFunction(queueName string, function WorkerFunc
 	if err != nil {
 		return err
 	}
+	metadata.retryPolicy = retryPolicy
 	// establish connection
 	err = r.establishConnection()
 	if err != nil {
@@ -65,31 +75,9 @@ func (r *remoteTaskQueue) EnqueueTask(queueName string, argument ArgumentType) e
 
 	// push to queue
 	if r.queueType == AmqpQueue {
-		dConfirmation, err := r.amqpChannel.PublishWithDeferredConfirmWithContext(
-			context.Background(),
-			"",
-			queueName,
-			true,
-			false,
-			amqp.Publishing{
-				Headers:         amqp.Table{},
-				ContentType:     "text/plain",
-				ContentEncoding: "",
-				DeliveryMode:    amqp.Persistent,
-				Priority:        0,
-				Body:            jsonBytes,
-			},
-		)
+		err = r.publishUsingAMQP(queueName, amqp.Table{}, "", jsonBytes)
 		if err != nil {
-			log.Println("error while publishing message to queue [" + queueName + "]")
-			log.Println(err.Error())
-			return errors.New("error while publishing message to queue")
-		}
-		// Check acknowledgement
-		ack := dConfirmation.Wait()
-		if !ack {
-			log.Println("error while publishing message to queue [" + queueName + "] publish ack > false")
-			return errors.New("error while publishing message to queue")
+			return err
 		}
 	} else if r.queueType == RedisQueue {
 		// push to redis
@@ -99,6 +87,11 @@ func (r *remoteTaskQueue) EnqueueTask(queueName string, argument ArgumentType) e
 			log.Println(err.Error())
 			return errors.New("error while pushing message to queue")
 		}
+	} else if r.queueType == NatsQueue {
+		err = r.publishUsingNATS(queueName, nats.Header{}, jsonBytes)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -150,7 +143,8 @@ func (r *remoteTaskQueue) WaitForConsumers() {
 }
 
 func (r *remoteTaskQueue) EnqueueProcessingQueueExpiredTask() error {
-	if r.queueType == AmqpQueue {
+	// unacknowledged messages are delivered again by the server itself
+	if r.queueType == AmqpQueue || r.queueType == NatsQueue {
 		return nil
 	}
 	for queueName := range r.queueToFunctionMapping {
@@ -179,7 +173,7 @@ func (r *remoteTaskQueue) getFunction(queueName string) (functionMetadata, error
 
 // establishConnection: connect connects to the AMQP server
 func (r *remoteTaskQueue) establishConnection() error {
-	if r.queueType == RedisQueue {
+	if r.queueType == RedisQueue || r.queueType == NatsQueue {
 		return nil
 	}
 	// if there is already a connection, return
@@ -207,11 +201,14 @@ func (r *remoteTaskQueue) establishConnection() error {
 	return nil
 }
 
-// declareQueue: create a queue
+// declareQueue: create a queue along with its dead-letter queue
 func (r *remoteTaskQueue) declareQueue(queueName string) error {
 	if r.queueType == RedisQueue {
 		return nil
 	}
+	if r.queueType == NatsQueue {
+		return r.declareStreamsUsingNATS(queueName)
+	}
 	if r.amqpConnection == nil || r.amqpChannel == nil {
 		return errors.New("connection not established")
 	}
@@ -224,15 +221,61 @@ func (r *remoteTaskQueue) declareQueue(queueName string) error {
 		false,     // noWait
 		nil,       // arguments
 	)
+	if err != nil {
+		return err
+	}
+	// create dead-letter queue
+	_, err = r.amqpChannel.QueueDeclare(
+		deadLetterQueueName(queueName), // name of the queue
+		true,                           // durable
+		false,                          // delete when unused
+		false,                          // exclusive
+		false,                          // noWait
+		nil,                            // arguments
+	)
 	return err
 }
 
+// publishUsingAMQP: publish a persistent message to a queue and wait for the confirmation
+func (r *remoteTaskQueue) publishUsingAMQP(queueName string, headers amqp.Table, expiration string, body []byte) error {
+	dConfirmation, err := r.amqpChannel.PublishWithDeferredConfirmWithContext(
+		context.Background(),
+		"",
+		queueName,
+		true,
+		false,
+		amqp.Publishing{
+			Headers:         headers,
+			ContentType:     "text/plain",
+			ContentEncoding: "",
+			DeliveryMode:    amqp.Persistent,
+			Priority:        0,
+			Expiration:      expiration,
+			Body:            body,
+		},
+	)
+	if err != nil {
+		log.Println("error while publishing message to queue [" + queueName + "]")
+		log.Println(err.Error())
+		return errors.New("error while publishing message to queue")
+	}
+	// Check acknowledgement
+	ack := dConfirmation.Wait()
+	if !ack {
+		log.Println("error while publishing message to queue [" + queueName + "] publish ack > false")
+		return errors.New("error while publishing message to queue")
+	}
+	return nil
+}
+
 // listenForTasks: listen for tasks on a queue
 func (r *remoteTaskQueue) listenForTasks(queueName string, wg *sync.WaitGroup) {
 	if r.queueType == RedisQueue {
 		r.listenForTasksUsingRQ(queueName, wg)
 	} else if r.queueType == AmqpQueue {
 		r.listenForTasksUsingAMQP(queueName, wg)
+	} else if r.queueType == NatsQueue {
+		r.listenForTasksUsingNATS(queueName, wg)
 	}
 }
 
@@ -248,7 +291,9 @@ func (r *remoteTaskQueue) listenForTasksUsingRQ(queueName string, _ *sync.WaitGr
 	log.Println("starting consumer for redis queue [" + queueName + "]")
 
 	for {
-		stringCmd := r.redisClient.BLMove(context.Background(), queueName, queueName+"_processing", "right", "left", 1*time.Minute)
+		// move the delayed tasks whose time has come to the queue
+		r.enqueueDueTasksUsingRQ(queueName)
+		stringCmd := r.redisClient.BLMove(context.Background(), queueName, queueName+"_processing", "right", "left", redisDelayedTasksPollInterval)
 		if stringCmd.Err() != nil {
 			if strings.Contains(stringCmd.Err().Error(), "redis: nil") {
 				continue
@@ -280,15 +325,32 @@ func (r *remoteTaskQueue) listenForTasksUsingRQ(queueName string, _ *sync.WaitGr
 			log.Println("error: " + err.Error())
 			continue
 		}
+		// postpone the task if another task with the same concurrency key is running
+		releaseConcurrencyKey, acquired := r.acquireConcurrencyKey(argument)
+		if !acquired {
+			err = r.scheduleTaskUsingRQ(queueName, content, time.Now().Add(concurrencyKeyBusyDelay))
+			if err != nil {
+				// keep it in processing queue, it will be enqueued again by EnqueueProcessingQueueExpiredTask
+				log.Println("error while postponing task for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+				continue
+			}
+			r.redisClient.LRem(context.Background(), queueName+"_processing", 0, content)
+			continue
+		}
 		// execute function
+		run := r.taskRunRecorder.start(queueName, argument, string(content))
 		err = invokeFunction(functionMetadata.function, argument, functionMetadata.argumentType)
+		r.taskRunRecorder.finish(run, err)
+		releaseConcurrencyKey()
 		// remove from processing queue
 		r.redisClient.LRem(context.Background(), queueName+"_processing", 0, content)
 		if err != nil {
 			log.Println("error while executing function for queue [" + queueName + "]")
 			log.Println("error: " + err.Error())
-			// enqueue to original queue
-			r.redisClient.LPush(context.Background(), queueName, content)
+			r.handleFailedTaskUsingRQ(queueName, functionMetadata.retryPolicy, content, err)
+		} else {
+			r.redisClient.HDel(context.Background(), attemptsKeyName(queueName), taskHash(string(content)))
 		}
 	}
 }
@@ -331,6 +393,19 @@ func (r *remoteTaskQueue) listenForTasksUsingAMQP(queueName string, wg *sync.Wai
 		// fetch the content
 		content := delivery.Body
 
+		// delayed message can arrive before its time, publish it again for the remaining delay
+		if scheduledAt, ok := amqpScheduledAt(delivery.Headers); ok && time.Now().Before(scheduledAt) {
+			err := r.publishDelayedUsingAMQP(queueName, delivery.Headers, scheduledAt, content)
+			if err != nil {
+				log.Println("error while delaying message for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+				nackMessage(delivery)
+				continue
+			}
+			ackMessage(delivery)
+			continue
+		}
+
 		// create a new object of an argument type
 		argument := reflect.New(functionMetadata.argumentType).Interface()
 
@@ -353,13 +428,34 @@ func (r *remoteTaskQueue) listenForTasksUsingAMQP(queueName string, wg *sync.Wai
 			ackMessage(delivery)
 			continue
 		}
+		// postpone the task if another task with the same concurrency key is running
+		releaseConcurrencyKey, acquired := r.acquireConcurrencyKey(argument)
+		if !acquired {
+			err = r.publishDelayedUsingAMQP(queueName, delivery.Headers, time.Now().Add(concurrencyKeyBusyDelay), content)
+			if err != nil {
+				log.Println("error while postponing task for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+				nackMessage(delivery)
+				continue
+			}
+			ackMessage(delivery)
+			continue
+		}
 		// execute function
+		run := r.taskRunRecorder.start(queueName, argument, string(content))
 		err = invokeFunction(functionMetadata.function, argument, functionMetadata.argumentType)
+		r.taskRunRecorder.finish(run, err)
+		releaseConcurrencyKey()
 		if err != nil {
 			log.Println("error while executing function for queue [" + queueName + "]")
 			log.Println("error: " + err.Error())
-			nackMessage(delivery)
-			continue
+			err = r.handleFailedTaskUsingAMQP(queueName, functionMetadata.retryPolicy, delivery, err)
+			if err != nil {
+				log.Println("error while handling failed task for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+				nackMessage(delivery)
+				continue
+			}
 		}
 		// acknowledge message
 		ackMessage(delivery)
@@ -385,11 +481,7 @@ func nackMessage(delivery amqp.Delivery) {
 
 func (r *remoteTaskQueue) PurgeQueue(queueName string) error {
 	if r.queueType == RedisQueue {
-		err := r.redisClient.Del(context.Background(), queueName).Err()
-		if err != nil {
-			return err
-		}
-		return r.redisClient.Del(context.Background(), queueName+"_processing").Err()
+		return r.redisClient.Del(context.Background(), queueName, queueName+"_processing", delayedQueueName(queueName), attemptsKeyName(queueName)).Err()
 	}
 	if r.queueType == AmqpQueue {
 		if r.amqpChannel == nil {
@@ -398,8 +490,18 @@ func (r *remoteTaskQueue) PurgeQueue(queueName string) error {
 				return fmt.Errorf("error while establishing connection to AMQP server: %s", err.Error())
 			}
 		}
-		_, err := r.amqpChannel.QueuePurge(queueName, true)
-		return err
+		err := r.declareQueue(queueName)
+		if err != nil {
+			return err
+		}
+		_, err = r.amqpChannel.QueuePurge(queueName, true)
+		if err != nil {
+			return err
+		}
+		return r.purgeDelayedQueuesUsingAMQP(queueName)
+	}
+	if r.queueType == NatsQueue {
+		return r.purgeQueueUsingNATS(queueName)
 	}
 	return errors.New("invalid queue type")
 }
@@ -411,6 +513,9 @@ func (r *remoteTaskQueue) ListMessages(queueName string) ([]string, error) {
 	if r.queueType == AmqpQueue {
 		return r.inspectQueueUsingAMQP(queueName)
 	}
+	if r.queueType == NatsQueue {
+		return r.inspectQueueUsingNATS(queueName)
+	}
 	return nil, errors.New("invalid queue type")
 }
 
@@ -0,0 +1,169 @@
+package task_queue
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go/jetstream"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"log"
+)
+
+const (
+	redisConcurrencyKeyLockPrefix = "task_queue_lock:"
+	amqpConcurrencyKeyLockPrefix  = "task_queue_lock."
+	natsConcurrencyKeyLockBucket  = "task_queue_locks"
+)
+
+// redisReleaseLockScript deletes the lock only if it's still held by the same owner
+var redisReleaseLockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// redisRefreshLockScript extends the expiry of the lock only if it's still held by the same owner
+var redisRefreshLockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+
+// acquireConcurrencyKey takes the lock of the concurrency key of the argument in this instance and across the instances
+// Returns false if the key is held by another task, otherwise the returned function should be called to release the key
+func (r *remoteTaskQueue) acquireConcurrencyKey(argument ArgumentType) (func(), bool) {
+	key := concurrencyKeyOf(argument)
+	if key == "" {
+		return func() {}, true
+	}
+	if !r.concurrencyKeyLocks.tryLock(key) {
+		return nil, false
+	}
+	var release func()
+	var err error
+	if r.queueType == RedisQueue {
+		release, err = r.acquireConcurrencyKeyUsingRQ(key)
+	} else if r.queueType == AmqpQueue {
+		release, err = r.acquireConcurrencyKeyUsingAMQP(key)
+	} else if r.queueType == NatsQueue {
+		release, err = r.acquireConcurrencyKeyUsingNATS(key)
+	} else {
+		err = errors.New("invalid queue type")
+	}
+	if err != nil {
+		r.concurrencyKeyLocks.unlock(key)
+		return nil, false
+	}
+	return func() {
+		release()
+		r.concurrencyKeyLocks.unlock(key)
+	}, true
+}
+
+// acquireConcurrencyKeyUsingRQ: lock is a redis key with expiry, which is extended while the task is running
+func (r *remoteTaskQueue) acquireConcurrencyKeyUsingRQ(key string) (func(), error) {
+	ctx := context.Background()
+	lockKey := redisConcurrencyKeyLockPrefix + key
+	owner := uuid.NewString()
+	acquired, err := r.redisClient.SetNX(ctx, lockKey, owner, concurrencyKeyLockTTL).Result()
+	if err != nil {
+		log.Println("error while acquiring lock of concurrency key [" + key + "]")
+		log.Println("error: " + err.Error())
+		return nil, err
+	}
+	if !acquired {
+		return nil, errors.New("concurrency key is held by another task")
+	}
+	stopRefresh := keepLockAlive(func() {
+		err := r.redisClient.Eval(ctx, redisRefreshLockScript, []string{lockKey}, owner, concurrencyKeyLockTTL.Milliseconds()).Err()
+		if err != nil {
+			log.Println("error while refreshing lock of concurrency key [" + key + "]")
+			log.Println("error: " + err.Error())
+		}
+	})
+	return func() {
+		stopRefresh()
+		err := r.redisClient.Eval(ctx, redisReleaseLockScript, []string{lockKey}, owner).Err()
+		if err != nil {
+			log.Println("error while releasing lock of concurrency key [" + key + "]")
+			log.Println("error: " + err.Error())
+		}
+	}, nil
+}
+
+// acquireConcurrencyKeyUsingAMQP: lock is an exclusive queue, which can be declared by only one connection at a time
+// Broker deletes the queue if the connection is lost, so the lock is never held by a lost worker
+func (r *remoteTaskQueue) acquireConcurrencyKeyUsingAMQP(key string) (func(), error) {
+	// declaration of a locked queue closes the channel, so a separate channel is used for each lock
+	channel, err := r.amqpConnection.Channel()
+	if err != nil {
+		log.Println("error while opening channel for lock of concurrency key [" + key + "]")
+		log.Println("error: " + err.Error())
+		return nil, err
+	}
+	lockQueueName := amqpConcurrencyKeyLockPrefix + key
+	_, err = channel.QueueDeclare(
+		lockQueueName, // name of the queue
+		false,         // durable
+		true,          // delete when unused
+		true,          // exclusive
+		false,         // noWait
+		nil,           // arguments
+	)
+	if err != nil {
+		var amqpErr *amqp.Error
+		if !errors.As(err, &amqpErr) || amqpErr.Code != amqp.ResourceLocked {
+			log.Println("error while acquiring lock of concurrency key [" + key + "]")
+			log.Println("error: " + err.Error())
+		}
+		_ = channel.Close()
+		return nil, err
+	}
+	return func() {
+		_, err := channel.QueueDelete(lockQueueName, false, false, false)
+		if err != nil {
+			log.Println("error while releasing lock of concurrency key [" + key + "]")
+			log.Println("error: " + err.Error())
+		}
+		_ = channel.Close()
+	}, nil
+}
+
+// declareLocksBucketUsingNATS: create the key-value bucket of concurrency key locks
+// Entries expire after the ttl, unless they are updated by the running task
+func declareLocksBucketUsingNATS(js jetstream.JetStream) (jetstream.KeyValue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	return js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket: natsConcurrencyKeyLockBucket,
+		TTL:    concurrencyKeyLockTTL,
+	})
+}
+
+// acquireConcurrencyKeyUsingNATS: lock is an entry of the key-value bucket, which can be created only if it doesn't exist
+func (r *remoteTaskQueue) acquireConcurrencyKeyUsingNATS(key string) (func(), error) {
+	// key-value keys can't contain all the characters
+	lockKey := base64.RawURLEncoding.EncodeToString([]byte(key))
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	revision, err := r.natsLocks.Create(ctx, lockKey, []byte(key))
+	cancel()
+	if err != nil {
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			log.Println("error while acquiring lock of concurrency key [" + key + "]")
+			log.Println("error: " + err.Error())
+		}
+		return nil, err
+	}
+	stopRefresh := keepLockAlive(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+		defer cancel()
+		newRevision, err := r.natsLocks.Update(ctx, lockKey, []byte(key), revision)
+		if err == nil {
+			revision = newRevision
+		}
+	})
+	return func() {
+		stopRefresh()
+		ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+		defer cancel()
+		// lock is deleted only if it's not taken by another task after expiry
+		err := r.natsLocks.Delete(ctx, lockKey, jetstream.LastRevision(revision))
+		if err != nil {
+			log.Println("error while releasing lock of concurrency key [" + key + "]")
+			log.Println("error: " + err.Error())
+		}
+	}, nil
+}
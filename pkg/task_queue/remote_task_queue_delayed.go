@@ -0,0 +1,186 @@
+package task_queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/nats-io/nats.go"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"log"
+	"math/bits"
+	"strconv"
+	"time"
+)
+
+// redisDelayedTasksPollInterval : consumers move the delayed tasks whose time has come to the queue at least once in this interval
+var redisDelayedTasksPollInterval = 10 * time.Second
+
+const (
+	amqpScheduledAtHeader = "x-scheduled-at"
+	// amqpMaxDelayBucketExponent : delayed queues are created for 2^0 to 2^20 seconds (~12 days), longer delays hop through the largest one
+	amqpMaxDelayBucketExponent = 20
+	// amqpDelayedQueueIdleExpiry : delayed queue is deleted by the broker once it's unused for its ttl + this duration
+	amqpDelayedQueueIdleExpiry = 10 * time.Minute
+)
+
+func (r *remoteTaskQueue) EnqueueTaskAt(queueName string, argument ArgumentType, at time.Time) error {
+	if !at.After(time.Now()) {
+		return r.EnqueueTask(queueName, argument)
+	}
+	// marshal argument to json
+	jsonBytes, err := json.Marshal(argument)
+	if err != nil {
+		return errors.New("error while marshalling argument to json")
+	}
+
+	// check if queueName is registered
+	_, err = r.getFunction(queueName)
+	if err != nil {
+		return err
+	}
+
+	// establish connection if not already established
+	err = r.establishConnection()
+	if err != nil {
+		return errors.New("error while establishing connection to AMQP server")
+	}
+
+	if r.queueType == AmqpQueue {
+		return r.publishDelayedUsingAMQP(queueName, amqp.Table{}, at, jsonBytes)
+	} else if r.queueType == RedisQueue {
+		err = r.scheduleTaskUsingRQ(queueName, jsonBytes, at)
+		if err != nil {
+			log.Println("error while scheduling message for queue [" + queueName + "]")
+			log.Println(err.Error())
+			return errors.New("error while scheduling message for queue")
+		}
+	} else if r.queueType == NatsQueue {
+		return r.publishDelayedUsingNATS(queueName, nats.Header{}, at, jsonBytes)
+	}
+	return nil
+}
+
+func (r *remoteTaskQueue) EnqueueTaskAfter(queueName string, argument ArgumentType, delay time.Duration) error {
+	return r.EnqueueTaskAt(queueName, argument, time.Now().Add(delay))
+}
+
+// delayedQueueName returns the name of the redis sorted set which holds the delayed tasks, scored by their time in unix milliseconds
+// Identical tasks are stored once in the set
+func delayedQueueName(queueName string) string {
+	return queueName + "_delayed"
+}
+
+// scheduleTaskUsingRQ: add the task to the delayed tasks set
+func (r *remoteTaskQueue) scheduleTaskUsingRQ(queueName string, content []byte, at time.Time) error {
+	return r.redisClient.ZAdd(context.Background(), delayedQueueName(queueName), &redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: content,
+	}).Err()
+}
+
+// enqueueDueTasksUsingRQ: move the tasks whose time has come from the delayed tasks set to the queue
+func (r *remoteTaskQueue) enqueueDueTasksUsingRQ(queueName string) {
+	ctx := context.Background()
+	contents, err := r.redisClient.ZRangeByScore(ctx, delayedQueueName(queueName), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return
+	}
+	for _, content := range contents {
+		// only the consumer which removes the task from the sorted set enqueues it
+		removed, err := r.redisClient.ZRem(ctx, delayedQueueName(queueName), content).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+		r.redisClient.RPush(ctx, queueName, content)
+	}
+}
+
+// delayBucket returns the largest power of two seconds which is not more than the delay
+func delayBucket(delay time.Duration) time.Duration {
+	seconds := uint64(delay / time.Second)
+	if seconds <= 1 {
+		return time.Second
+	}
+	exponent := bits.Len64(seconds) - 1
+	if exponent > amqpMaxDelayBucketExponent {
+		exponent = amqpMaxDelayBucketExponent
+	}
+	return time.Duration(1<<exponent) * time.Second
+}
+
+// delayedBucketQueueName returns the name of the amqp queue which holds the messages for the delay bucket
+func delayedBucketQueueName(queueName string, bucket time.Duration) string {
+	return fmt.Sprintf("%s_delayed_%ds", queueName, int64(bucket/time.Second))
+}
+
+// declareDelayedQueue: create the delayed queue of a bucket
+// All the messages of the queue have the same ttl, so they expire in order and are routed back to the queue
+func (r *remoteTaskQueue) declareDelayedQueue(queueName string, bucket time.Duration) (string, error) {
+	name := delayedBucketQueueName(queueName, bucket)
+	_, err := r.amqpChannel.QueueDeclare(
+		name,  // name of the queue
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // noWait
+		amqp.Table{
+			"x-message-ttl":             bucket.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queueName,
+			"x-expires":                 (bucket + amqpDelayedQueueIdleExpiry).Milliseconds(),
+		}, // arguments
+	)
+	return name, err
+}
+
+// publishDelayedUsingAMQP: publish the message to the delayed queue of the largest bucket which fits in the delay
+// The consumer publishes it again for the remaining delay, so the message hops through smaller buckets till its time comes
+func (r *remoteTaskQueue) publishDelayedUsingAMQP(queueName string, headers amqp.Table, at time.Time, body []byte) error {
+	delay := time.Until(at)
+	if delay <= 0 {
+		return r.publishUsingAMQP(queueName, headers, "", body)
+	}
+	delayedHeaders := amqp.Table{}
+	for key, value := range headers {
+		// death history of the previous hops is not required
+		if key == "x-death" {
+			continue
+		}
+		delayedHeaders[key] = value
+	}
+	delayedHeaders[amqpScheduledAtHeader] = at.UnixMilli()
+	name, err := r.declareDelayedQueue(queueName, delayBucket(delay))
+	if err != nil {
+		return err
+	}
+	return r.publishUsingAMQP(name, delayedHeaders, "", body)
+}
+
+// amqpScheduledAt returns the time before which the message should not be consumed
+func amqpScheduledAt(headers amqp.Table) (time.Time, bool) {
+	value, ok := headers[amqpScheduledAtHeader].(int64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(value), true
+}
+
+// purgeDelayedQueuesUsingAMQP: purge the delayed queues of all the buckets
+func (r *remoteTaskQueue) purgeDelayedQueuesUsingAMQP(queueName string) error {
+	for exponent := 0; exponent <= amqpMaxDelayBucketExponent; exponent++ {
+		name, err := r.declareDelayedQueue(queueName, time.Duration(1<<exponent)*time.Second)
+		if err != nil {
+			return err
+		}
+		_, err = r.amqpChannel.QueuePurge(name, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package task_queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// natsConsumerName : durable consumer of a queue, shared by the workers of all the instances
+	natsConsumerName = "workers"
+	// natsAckWait : message is delivered again if it's not acknowledged in this duration
+	// Consumer keeps extending it while the task is running, so it only expires if the worker is gone
+	natsAckWait             = 1 * time.Minute
+	natsOperationTimeout    = 10 * time.Second
+	natsAttemptsHeader      = "Swiftwave-Attempts"
+	natsFailureReasonHeader = "Swiftwave-Failure-Reason"
+	natsFailedAtHeader      = "Swiftwave-Failed-At"
+	natsScheduledAtHeader   = "Swiftwave-Scheduled-At"
+)
+
+// declareStreamsUsingNATS: create the work queue stream of a queue along with its consumer and dead-letter stream
+// Stream and subject are named after the queue
+func (r *remoteTaskQueue) declareStreamsUsingNATS(queueName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	_, err := r.natsJetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      queueName,
+		Subjects:  []string{queueName},
+		Retention: jetstream.WorkQueuePolicy,
+		Storage:   jetstream.FileStorage,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.natsJetStream.CreateOrUpdateConsumer(ctx, queueName, jetstream.ConsumerConfig{
+		Durable:   natsConsumerName,
+		AckPolicy: jetstream.AckExplicitPolicy,
+		AckWait:   natsAckWait,
+		// retries are handled by the retry policy
+		MaxDeliver: -1,
+		// delayed messages stay pending till their time comes, so don't limit them
+		MaxAckPending: -1,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.natsJetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      deadLetterQueueName(queueName),
+		Subjects:  []string{deadLetterQueueName(queueName)},
+		Retention: jetstream.LimitsPolicy,
+		Storage:   jetstream.FileStorage,
+	})
+	return err
+}
+
+// publishUsingNATS: publish a message to a stream and wait for the acknowledgement
+func (r *remoteTaskQueue) publishUsingNATS(subject string, header nats.Header, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	_, err := r.natsJetStream.PublishMsg(ctx, &nats.Msg{
+		Subject: subject,
+		Header:  header,
+		Data:    body,
+	})
+	if err != nil {
+		log.Println("error while publishing message to queue [" + subject + "]")
+		log.Println(err.Error())
+		return errors.New("error while publishing message to queue")
+	}
+	return nil
+}
+
+// publishDelayedUsingNATS: publish the message with its scheduled time
+// Consumer delays the redelivery of the message till its time comes
+func (r *remoteTaskQueue) publishDelayedUsingNATS(queueName string, header nats.Header, at time.Time, body []byte) error {
+	if header == nil {
+		header = nats.Header{}
+	}
+	header.Set(natsScheduledAtHeader, strconv.FormatInt(at.UnixMilli(), 10))
+	return r.publishUsingNATS(queueName, header, body)
+}
+
+// natsScheduledAt returns the time before which the message should not be consumed
+func natsScheduledAt(header nats.Header) (time.Time, bool) {
+	value, err := strconv.ParseInt(header.Get(natsScheduledAtHeader), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(value), true
+}
+
+func natsAttempts(header nats.Header) uint {
+	value, err := strconv.ParseUint(header.Get(natsAttemptsHeader), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(value)
+}
+
+func (r *remoteTaskQueue) listenForTasksUsingNATS(queueName string, wg *sync.WaitGroup) {
+	defer wg.Done()
+	// fetch function by queue name
+	functionMetadata, err := r.getFunction(queueName)
+	if err != nil {
+		log.Println("error while fetching function for queue [" + queueName + "]")
+		log.Println("error: " + err.Error())
+	}
+
+	// log message
+	log.Println("starting consumer for nats queue [" + queueName + "]")
+
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	consumer, err := r.natsJetStream.Consumer(ctx, queueName, natsConsumerName)
+	cancel()
+	if err != nil {
+		log.Println(err.Error())
+		panic("error while listening for queue [" + queueName + "], maybe some connection error")
+	}
+	messages, err := consumer.Messages(jetstream.PullMaxMessages(1))
+	if err != nil {
+		log.Println(err.Error())
+		panic("error while listening for queue [" + queueName + "], maybe some connection error")
+	}
+
+	for {
+		msg, err := messages.Next()
+		if err != nil {
+			if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				break
+			}
+			log.Println("error while fetching message from queue [" + queueName + "]")
+			log.Println("error: " + err.Error())
+			time.Sleep(time.Second)
+			continue
+		}
+
+		// fetch the content
+		content := msg.Data()
+
+		// delayed message is delivered again once its time comes
+		if scheduledAt, ok := natsScheduledAt(msg.Headers()); ok && time.Now().Before(scheduledAt) {
+			err = msg.NakWithDelay(time.Until(scheduledAt))
+			if err != nil {
+				log.Println("error while delaying message for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+			}
+			continue
+		}
+
+		// create a new object of an argument type
+		argument := reflect.New(functionMetadata.argumentType).Interface()
+
+		// string to json unmarshal
+		err = json.Unmarshal(content, &argument)
+		if err != nil {
+			log.Println(err)
+			_ = msg.Term()
+			continue
+		}
+
+		// argument is a pointer, dereference it
+		argument = reflect.ValueOf(argument).Elem().Interface()
+
+		// postpone the task if another task with the same concurrency key is running
+		releaseConcurrencyKey, acquired := r.acquireConcurrencyKey(argument)
+		if !acquired {
+			err = msg.NakWithDelay(concurrencyKeyBusyDelay)
+			if err != nil {
+				log.Println("error while postponing task for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+			}
+			continue
+		}
+
+		// keep the message in progress while the task is running
+		done := make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(natsAckWait / 3)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					_ = msg.InProgress()
+				}
+			}
+		}()
+		// execute function
+		run := r.taskRunRecorder.start(queueName, argument, string(content))
+		err = invokeFunction(functionMetadata.function, argument, functionMetadata.argumentType)
+		r.taskRunRecorder.finish(run, err)
+		close(done)
+		releaseConcurrencyKey()
+		if err != nil {
+			log.Println("error while executing function for queue [" + queueName + "]")
+			log.Println("error: " + err.Error())
+			err = r.handleFailedTaskUsingNATS(queueName, functionMetadata.retryPolicy, msg, err)
+			if err != nil {
+				log.Println("error while handling failed task for queue [" + queueName + "]")
+				log.Println("error: " + err.Error())
+				_ = msg.Nak()
+				continue
+			}
+		}
+		// acknowledge message
+		err = msg.Ack()
+		if err != nil {
+			log.Println("error while acknowledging message for queue [" + queueName + "]")
+			log.Println("error: " + err.Error())
+		}
+	}
+}
+
+// handleFailedTaskUsingNATS: publish the task again with the backoff as delay, or to the dead-letter stream
+// The failed message should be acknowledged by the caller once the task is published
+func (r *remoteTaskQueue) handleFailedTaskUsingNATS(queueName string, retryPolicy RetryPolicy, msg jetstream.Msg, taskErr error) error {
+	attempts := natsAttempts(msg.Headers()) + 1
+	header := nats.Header{}
+	header.Set(natsAttemptsHeader, strconv.FormatUint(uint64(attempts), 10))
+	if retryPolicy.ShouldRetry(attempts) {
+		return r.publishDelayedUsingNATS(queueName, header, time.Now().Add(retryPolicy.Backoff(attempts)), msg.Data())
+	}
+	// header value can't span multiple lines
+	header.Set(natsFailureReasonHeader, strings.Join(strings.Fields(taskErr.Error()), " "))
+	header.Set(natsFailedAtHeader, time.Now().Format(time.RFC3339))
+	return r.publishUsingNATS(deadLetterQueueName(queueName), header, msg.Data())
+}
+
+// fetchStreamMessagesUsingNATS: get all the messages of a stream without consuming them
+func (r *remoteTaskQueue) fetchStreamMessagesUsingNATS(streamName string) ([]*jetstream.RawStreamMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	stream, err := r.natsJetStream.Stream(ctx, streamName)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			return []*jetstream.RawStreamMsg{}, nil
+		}
+		return nil, err
+	}
+	info, err := stream.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]*jetstream.RawStreamMsg, 0, info.State.Msgs)
+	if info.State.Msgs == 0 {
+		return messages, nil
+	}
+	for sequence := info.State.FirstSeq; sequence <= info.State.LastSeq; sequence++ {
+		msg, err := stream.GetMsg(ctx, sequence)
+		if err != nil {
+			// acknowledged and deleted messages leave gaps in the sequence
+			if errors.Is(err, jetstream.ErrMsgNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func (r *remoteTaskQueue) inspectQueueUsingNATS(queueName string) ([]string, error) {
+	messages, err := r.fetchStreamMessagesUsingNATS(queueName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		result = append(result, string(msg.Data))
+	}
+	return result, nil
+}
+
+func (r *remoteTaskQueue) purgeQueueUsingNATS(queueName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+	defer cancel()
+	stream, err := r.natsJetStream.Stream(ctx, queueName)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			return nil
+		}
+		return err
+	}
+	return stream.Purge(ctx)
+}
+
+func (r *remoteTaskQueue) listDeadLetterTasksUsingNATS(queueName string) ([]DeadLetterTask, []uint64, error) {
+	messages, err := r.fetchStreamMessagesUsingNATS(deadLetterQueueName(queueName))
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks := make([]DeadLetterTask, 0, len(messages))
+	sequences := make([]uint64, 0, len(messages))
+	for _, msg := range messages {
+		task := DeadLetterTask{
+			ID:            taskHash(string(msg.Data)),
+			QueueName:     queueName,
+			Body:          string(msg.Data),
+			Attempts:      natsAttempts(msg.Header),
+			FailureReason: msg.Header.Get(natsFailureReasonHeader),
+		}
+		task.FailedAt, _ = time.Parse(time.RFC3339, msg.Header.Get(natsFailedAtHeader))
+		tasks = append(tasks, task)
+		sequences = append(sequences, msg.Sequence)
+	}
+	return tasks, sequences, nil
+}
+
+// removeDeadLetterTaskUsingNATS: delete the task from the dead-letter stream, and publish it to the queue if requeue is true
+func (r *remoteTaskQueue) removeDeadLetterTaskUsingNATS(queueName string, taskId string, requeue bool) error {
+	tasks, sequences, err := r.listDeadLetterTasksUsingNATS(queueName)
+	if err != nil {
+		return err
+	}
+	for i, task := range tasks {
+		if task.ID != taskId {
+			continue
+		}
+		if requeue {
+			err = r.publishUsingNATS(queueName, nats.Header{}, []byte(task.Body))
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), natsOperationTimeout)
+		defer cancel()
+		stream, err := r.natsJetStream.Stream(ctx, deadLetterQueueName(queueName))
+		if err != nil {
+			return err
+		}
+		return stream.DeleteMsg(ctx, sequences[i])
+	}
+	return errors.New("task not found in dead-letter queue")
+}
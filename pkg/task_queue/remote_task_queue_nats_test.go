@@ -0,0 +1,168 @@
+package task_queue
+
+import (
+	"context"
+	"errors"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type NatsTaskTestArgument struct {
+	Name string `json:"name"`
+}
+
+// NatsTaskTestWorker reports the consumed tasks, and fails them if fail is set
+type NatsTaskTestWorker struct {
+	consumed chan NatsTaskTestArgument
+	fail     bool
+}
+
+func (w NatsTaskTestWorker) Consume(argument NatsTaskTestArgument, _ context.Context, _ context.CancelFunc) error {
+	if w.consumed != nil {
+		w.consumed <- argument
+	}
+	if w.fail {
+		return errors.New("failed to process " + argument.Name)
+	}
+	return nil
+}
+
+func runNatsTestServer(t *testing.T) *server.Server {
+	natsServer, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	assert.NoError(t, err)
+	go natsServer.Start()
+	if !natsServer.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(natsServer.Shutdown)
+	return natsServer
+}
+
+func newNatsTestClient(t *testing.T, natsServer *server.Server) Client {
+	natsConnection, err := nats.Connect(natsServer.ClientURL())
+	assert.NoError(t, err)
+	t.Cleanup(natsConnection.Close)
+	client, err := NewClient(Options{
+		Type:                Remote,
+		NoOfWorkersPerQueue: 1,
+		RemoteQueueType:     NatsQueue,
+		NatsConnection:      natsConnection,
+	})
+	assert.NoError(t, err)
+	return client
+}
+
+func TestNatsEnqueueAndConsume(t *testing.T) {
+	natsServer := runNatsTestServer(t)
+	client := newNatsTestClient(t, natsServer)
+	consumed := make(chan NatsTaskTestArgument, 10)
+	assert.NoError(t, client.RegisterFunction("nats_test", NatsTaskTestWorker{consumed: consumed}.Consume))
+	assert.NoError(t, client.EnqueueTask("nats_test", NatsTaskTestArgument{Name: "first"}))
+	assert.NoError(t, client.StartConsumers(true))
+
+	select {
+	case argument := <-consumed:
+		assert.Equal(t, "first", argument.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("task was not consumed")
+	}
+	// acknowledged task is removed from the queue
+	assert.Eventually(t, func() bool {
+		messages, err := client.ListMessages("nats_test")
+		return err == nil && len(messages) == 0
+	}, 2*time.Second, 100*time.Millisecond)
+}
+
+func TestNatsListAndPurgeQueue(t *testing.T) {
+	natsServer := runNatsTestServer(t)
+	client := newNatsTestClient(t, natsServer)
+	assert.NoError(t, client.RegisterFunction("nats_test", NatsTaskTestWorker{}.Consume))
+	assert.NoError(t, client.EnqueueTask("nats_test", NatsTaskTestArgument{Name: "first"}))
+	assert.NoError(t, client.EnqueueTask("nats_test", NatsTaskTestArgument{Name: "second"}))
+
+	messages, err := client.ListMessages("nats_test")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"name":"first"}`, `{"name":"second"}`}, messages)
+
+	assert.NoError(t, client.PurgeQueue("nats_test"))
+	messages, err = client.ListMessages("nats_test")
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestNatsDelayedTaskSurvivesRestart(t *testing.T) {
+	natsServer := runNatsTestServer(t)
+
+	// enqueue the task and stop before it's due
+	client := newNatsTestClient(t, natsServer)
+	assert.NoError(t, client.RegisterFunction("nats_test", NatsTaskTestWorker{}.Consume))
+	scheduledAt := time.Now().Add(2 * time.Second)
+	assert.NoError(t, client.EnqueueTaskAt("nats_test", NatsTaskTestArgument{Name: "delayed"}, scheduledAt))
+
+	// start again with the same nats server
+	restartedClient := newNatsTestClient(t, natsServer)
+	consumed := make(chan NatsTaskTestArgument, 10)
+	assert.NoError(t, restartedClient.RegisterFunction("nats_test", NatsTaskTestWorker{consumed: consumed}.Consume))
+	assert.NoError(t, restartedClient.StartConsumers(true))
+	assert.NoError(t, restartedClient.EnqueueProcessingQueueExpiredTask())
+
+	select {
+	case <-consumed:
+		assert.False(t, time.Now().Before(scheduledAt), "task consumed before its scheduled time")
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled task was not consumed after restart")
+	}
+}
+
+func TestNatsFailedTaskMovesToDeadLetterQueue(t *testing.T) {
+	natsServer := runNatsTestServer(t)
+	client := newNatsTestClient(t, natsServer)
+	consumed := make(chan NatsTaskTestArgument, 10)
+	assert.NoError(t, client.RegisterFunctionWithRetryPolicy("nats_test", NatsTaskTestWorker{consumed: consumed, fail: true}.Consume, RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     100 * time.Millisecond,
+		Multiplier:     1,
+	}))
+	assert.NoError(t, client.EnqueueTask("nats_test", NatsTaskTestArgument{Name: "failing"}))
+	assert.NoError(t, client.StartConsumers(true))
+
+	var deadLetterTasks []DeadLetterTask
+	assert.Eventually(t, func() bool {
+		var err error
+		deadLetterTasks, err = client.ListDeadLetterTasks("nats_test")
+		return err == nil && len(deadLetterTasks) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+	if len(deadLetterTasks) != 1 {
+		return
+	}
+	assert.Len(t, consumed, 2)
+	assert.Equal(t, uint(2), deadLetterTasks[0].Attempts)
+	assert.Equal(t, "failed to process failing", deadLetterTasks[0].FailureReason)
+	assert.Equal(t, `{"name":"failing"}`, deadLetterTasks[0].Body)
+
+	// requeue the task, it fails again till it's back in the dead-letter queue
+	assert.NoError(t, client.RequeueDeadLetterTask("nats_test", deadLetterTasks[0].ID))
+	assert.Eventually(t, func() bool {
+		return len(consumed) == 4
+	}, 5*time.Second, 100*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		tasks, err := client.ListDeadLetterTasks("nats_test")
+		return err == nil && len(tasks) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	assert.NoError(t, client.DropDeadLetterTask("nats_test", deadLetterTasks[0].ID))
+	tasks, err := client.ListDeadLetterTasks("nats_test")
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+}
@@ -0,0 +1,238 @@
+package task_queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+const (
+	amqpAttemptsHeader      = "x-attempts"
+	amqpFailureReasonHeader = "x-failure-reason"
+	amqpFailedAtHeader      = "x-failed-at"
+)
+
+// deadLetterQueueName returns the name of the dead-letter queue of a queue
+func deadLetterQueueName(queueName string) string {
+	return queueName + "_dead_letter"
+}
+
+// attemptsKeyName returns the name of the redis hash which holds the failed attempts of the tasks
+func attemptsKeyName(queueName string) string {
+	return queueName + "_attempts"
+}
+
+// handleFailedTaskUsingRQ: schedule the retry of the task in the delayed tasks set, or move it to the dead-letter queue
+func (r *remoteTaskQueue) handleFailedTaskUsingRQ(queueName string, retryPolicy RetryPolicy, content []byte, taskErr error) {
+	ctx := context.Background()
+	hash := taskHash(string(content))
+	attempts, err := r.redisClient.HIncrBy(ctx, attemptsKeyName(queueName), hash, 1).Result()
+	if err != nil {
+		// can't track the attempts, enqueue to original queue
+		r.redisClient.LPush(ctx, queueName, content)
+		return
+	}
+	if retryPolicy.ShouldRetry(uint(attempts)) {
+		err = r.scheduleTaskUsingRQ(queueName, content, time.Now().Add(retryPolicy.Backoff(uint(attempts))))
+		if err != nil {
+			r.redisClient.LPush(ctx, queueName, content)
+		}
+		return
+	}
+	deadLetterTask := DeadLetterTask{
+		ID:            hash,
+		QueueName:     queueName,
+		Body:          string(content),
+		Attempts:      uint(attempts),
+		FailureReason: taskErr.Error(),
+		FailedAt:      time.Now(),
+	}
+	data, err := json.Marshal(deadLetterTask)
+	if err != nil {
+		r.redisClient.LPush(ctx, queueName, content)
+		return
+	}
+	r.redisClient.RPush(ctx, deadLetterQueueName(queueName), data)
+	r.redisClient.HDel(ctx, attemptsKeyName(queueName), hash)
+}
+
+// handleFailedTaskUsingAMQP: publish the task to the delayed queues till the backoff is over, or to the dead-letter queue
+// The failed delivery should be acknowledged by the caller once the task is published
+func (r *remoteTaskQueue) handleFailedTaskUsingAMQP(queueName string, retryPolicy RetryPolicy, delivery amqp.Delivery, taskErr error) error {
+	attempts := amqpAttempts(delivery.Headers) + 1
+	var err error
+	if retryPolicy.ShouldRetry(attempts) {
+		err = r.publishDelayedUsingAMQP(queueName, amqp.Table{
+			amqpAttemptsHeader: int64(attempts),
+		}, time.Now().Add(retryPolicy.Backoff(attempts)), delivery.Body)
+	} else {
+		err = r.publishUsingAMQP(deadLetterQueueName(queueName), amqp.Table{
+			amqpAttemptsHeader:      int64(attempts),
+			amqpFailureReasonHeader: taskErr.Error(),
+			amqpFailedAtHeader:      time.Now().Format(time.RFC3339),
+		}, "", delivery.Body)
+	}
+	return err
+}
+
+func amqpAttempts(headers amqp.Table) uint {
+	switch value := headers[amqpAttemptsHeader].(type) {
+	case int64:
+		return uint(value)
+	case int32:
+		return uint(value)
+	case int:
+		return uint(value)
+	default:
+		return 0
+	}
+}
+
+func (r *remoteTaskQueue) ListDeadLetterTasks(queueName string) ([]DeadLetterTask, error) {
+	if r.queueType == RedisQueue {
+		tasks, _, err := r.fetchDeadLetterTasksUsingRQ(queueName)
+		return tasks, err
+	}
+	if r.queueType == AmqpQueue {
+		tasks, deliveries, err := r.fetchDeadLetterTasksUsingAMQP(queueName)
+		if err != nil {
+			return nil, err
+		}
+		for _, delivery := range deliveries {
+			nackMessage(delivery)
+		}
+		return tasks, nil
+	}
+	if r.queueType == NatsQueue {
+		tasks, _, err := r.listDeadLetterTasksUsingNATS(queueName)
+		return tasks, err
+	}
+	return nil, errors.New("invalid queue type")
+}
+
+func (r *remoteTaskQueue) RequeueDeadLetterTask(queueName string, taskId string) error {
+	return r.removeDeadLetterTask(queueName, taskId, true)
+}
+
+func (r *remoteTaskQueue) DropDeadLetterTask(queueName string, taskId string) error {
+	return r.removeDeadLetterTask(queueName, taskId, false)
+}
+
+// removeDeadLetterTask: remove the task from the dead-letter queue, and push it back to the queue if requeue is true
+func (r *remoteTaskQueue) removeDeadLetterTask(queueName string, taskId string, requeue bool) error {
+	if r.queueType == RedisQueue {
+		ctx := context.Background()
+		tasks, entries, err := r.fetchDeadLetterTasksUsingRQ(queueName)
+		if err != nil {
+			return err
+		}
+		for i, task := range tasks {
+			if task.ID != taskId {
+				continue
+			}
+			removed, err := r.redisClient.LRem(ctx, deadLetterQueueName(queueName), 1, entries[i]).Result()
+			if err != nil {
+				return err
+			}
+			if removed == 0 || !requeue {
+				return nil
+			}
+			return r.redisClient.RPush(ctx, queueName, task.Body).Err()
+		}
+		return errors.New("task not found in dead-letter queue")
+	}
+	if r.queueType == AmqpQueue {
+		tasks, deliveries, err := r.fetchDeadLetterTasksUsingAMQP(queueName)
+		if err != nil {
+			return err
+		}
+		isFound := false
+		for i, task := range tasks {
+			if isFound || task.ID != taskId {
+				nackMessage(deliveries[i])
+				continue
+			}
+			isFound = true
+			if requeue {
+				err = r.publishUsingAMQP(queueName, amqp.Table{}, "", deliveries[i].Body)
+				if err != nil {
+					nackMessage(deliveries[i])
+					continue
+				}
+			}
+			ackMessage(deliveries[i])
+		}
+		if !isFound {
+			return errors.New("task not found in dead-letter queue")
+		}
+		return err
+	}
+	if r.queueType == NatsQueue {
+		return r.removeDeadLetterTaskUsingNATS(queueName, taskId, requeue)
+	}
+	return errors.New("invalid queue type")
+}
+
+// fetchDeadLetterTasksUsingRQ: returns the tasks along with the raw entries of the list
+func (r *remoteTaskQueue) fetchDeadLetterTasksUsingRQ(queueName string) ([]DeadLetterTask, []string, error) {
+	entries, err := r.redisClient.LRange(context.Background(), deadLetterQueueName(queueName), 0, -1).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks := make([]DeadLetterTask, 0, len(entries))
+	for _, entry := range entries {
+		var task DeadLetterTask
+		err = json.Unmarshal([]byte(entry), &task)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid entry in dead-letter queue: %s", err.Error())
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, entries, nil
+}
+
+// fetchDeadLetterTasksUsingAMQP: get all the messages of the dead-letter queue without acknowledging them
+// Caller should ack or nack each of the returned deliveries
+func (r *remoteTaskQueue) fetchDeadLetterTasksUsingAMQP(queueName string) ([]DeadLetterTask, []amqp.Delivery, error) {
+	err := r.establishConnection()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while establishing connection to AMQP server: %s", err.Error())
+	}
+	err = r.declareQueue(queueName)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks := make([]DeadLetterTask, 0)
+	deliveries := make([]amqp.Delivery, 0)
+	for {
+		// unacknowledged messages are not delivered again on the same channel, so all the messages are fetched once
+		delivery, ok, err := r.amqpChannel.Get(deadLetterQueueName(queueName), false)
+		if err != nil {
+			for _, d := range deliveries {
+				nackMessage(d)
+			}
+			return nil, nil, err
+		}
+		if !ok {
+			break
+		}
+		task := DeadLetterTask{
+			ID:        taskHash(string(delivery.Body)),
+			QueueName: queueName,
+			Body:      string(delivery.Body),
+			Attempts:  amqpAttempts(delivery.Headers),
+		}
+		if reason, ok := delivery.Headers[amqpFailureReasonHeader].(string); ok {
+			task.FailureReason = reason
+		}
+		if failedAt, ok := delivery.Headers[amqpFailedAtHeader].(string); ok {
+			task.FailedAt, _ = time.Parse(time.RFC3339, failedAt)
+		}
+		tasks = append(tasks, task)
+		deliveries = append(deliveries, delivery)
+	}
+	return tasks, deliveries, nil
+}
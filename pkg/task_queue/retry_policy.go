@@ -0,0 +1,65 @@
+package task_queue
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"time"
+)
+
+// DefaultRetryPolicy returns the retry policy used by RegisterFunction
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		Multiplier:     2,
+	}
+}
+
+// NoRetryPolicy returns the retry policy for the tasks which should not be attempted again automatically
+// Failed task is moved to the dead-letter queue right away
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 1,
+		Multiplier:  1,
+	}
+}
+
+// Validate checks that the retry policy is usable
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts == 0 {
+		return errors.New("max attempts of retry policy should be at least 1")
+	}
+	if p.Multiplier < 1 {
+		return errors.New("multiplier of retry policy should be at least 1")
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		return errors.New("max backoff of retry policy should not be less than initial backoff")
+	}
+	return nil
+}
+
+// Backoff returns the delay before the next attempt, after the given number of failed attempts
+func (p RetryPolicy) Backoff(failedAttempts uint) time.Duration {
+	if failedAttempts == 0 {
+		return 0
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(failedAttempts-1))
+	if backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
+
+// ShouldRetry checks if the task should be attempted again after the given number of failed attempts
+func (p RetryPolicy) ShouldRetry(failedAttempts uint) bool {
+	return failedAttempts < p.MaxAttempts
+}
+
+// taskHash returns the sha256 hash of the task body, it's used as the id of the task
+func taskHash(body string) string {
+	h := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(h[:])
+}
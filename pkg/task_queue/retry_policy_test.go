@@ -0,0 +1,38 @@
+package task_queue
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Minute,
+		Multiplier:     2,
+	}
+	assert.Equal(t, time.Duration(0), policy.Backoff(0))
+	assert.Equal(t, 10*time.Second, policy.Backoff(1))
+	assert.Equal(t, 20*time.Second, policy.Backoff(2))
+	assert.Equal(t, 40*time.Second, policy.Backoff(3))
+	// capped at max backoff
+	assert.Equal(t, time.Minute, policy.Backoff(4))
+	assert.Equal(t, time.Minute, policy.Backoff(10))
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Multiplier: 1}
+	assert.True(t, policy.ShouldRetry(2))
+	assert.False(t, policy.ShouldRetry(3))
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert.NoError(t, DefaultRetryPolicy().Validate())
+	assert.NoError(t, NoRetryPolicy().Validate())
+	assert.False(t, NoRetryPolicy().ShouldRetry(1))
+	assert.Error(t, RetryPolicy{MaxAttempts: 0, Multiplier: 2}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 1, Multiplier: 0.5}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 1, Multiplier: 2, InitialBackoff: time.Minute, MaxBackoff: time.Second}.Validate())
+}
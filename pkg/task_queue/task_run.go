@@ -0,0 +1,170 @@
+package task_queue
+
+import (
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+type TaskRunStatus string
+
+const lostTaskRunError = "worker was lost before the run finished"
+
+const (
+	TaskRunRunning   TaskRunStatus = "running"
+	TaskRunSucceeded TaskRunStatus = "succeeded"
+	TaskRunFailed    TaskRunStatus = "failed"
+)
+
+// TaskRun holds the history of a run of a task, written by the consumers of both local and remote task queue
+// Add TaskRun to gorm migration
+type TaskRun struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	QueueName string        `json:"queue_name" gorm:"index"`
+	TaskID    string        `json:"task_id"` // sha256 hash of the body, same as the id of the dead-letter task
+	Body      string        `json:"body"`
+	Label     string        `json:"label" gorm:"index"` // see TaskRunLabeled
+	Status    TaskRunStatus `json:"status"`
+	Error     string        `json:"error"` // error of the failed run
+	StartedAt time.Time     `json:"started_at" gorm:"index"`
+	// FinishedAt : nil while the task is running, or if the worker was lost in between
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// TaskRunLabeled can be implemented by the argument type of a queue to label the runs of its tasks, so that the history can be filtered by it
+// Concurrency key of the argument is used as the label, if it's not implemented
+type TaskRunLabeled interface {
+	TaskRunLabel() string
+}
+
+// TaskRunFilter : filter for FindTaskRuns, empty fields are ignored
+type TaskRunFilter struct {
+	QueueName string
+	Label     string
+	Status    TaskRunStatus
+	Limit     int // latest 100 runs are returned if not provided
+}
+
+// Duration returns the time taken by the run, or the time elapsed so far if it's running
+func (t TaskRun) Duration() time.Duration {
+	if t.FinishedAt == nil {
+		return time.Since(t.StartedAt)
+	}
+	return t.FinishedAt.Sub(t.StartedAt)
+}
+
+// Matches checks if the run matches the filter, used to filter the notified runs
+func (f TaskRunFilter) Matches(run TaskRun) bool {
+	if f.QueueName != "" && run.QueueName != f.QueueName {
+		return false
+	}
+	if f.Label != "" && run.Label != f.Label {
+		return false
+	}
+	if f.Status != "" && run.Status != f.Status {
+		return false
+	}
+	return true
+}
+
+// FindTaskRuns returns the runs matching the filter, latest first
+func FindTaskRuns(db *gorm.DB, filter TaskRunFilter) ([]TaskRun, error) {
+	var runs []TaskRun
+	tx := db.Model(&TaskRun{})
+	if filter.QueueName != "" {
+		tx = tx.Where("queue_name = ?", filter.QueueName)
+	}
+	if filter.Label != "" {
+		tx = tx.Where("label = ?", filter.Label)
+	}
+	if filter.Status != "" {
+		tx = tx.Where("status = ?", filter.Status)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	tx = tx.Order("started_at DESC").Order("id DESC").Limit(limit).Find(&runs)
+	return runs, tx.Error
+}
+
+// DeleteTaskRunsOlderThan deletes the runs started before the given time
+func DeleteTaskRunsOlderThan(db *gorm.DB, before time.Time) error {
+	return db.Where("started_at < ?", before).Delete(&TaskRun{}).Error
+}
+
+// MarkLostTaskRunsAsFailed marks the runs started before the given time which are still running as failed
+// Those runs were left by a worker which has been lost in between, result of a run which finishes later still replaces it
+func MarkLostTaskRunsAsFailed(db *gorm.DB, startedBefore time.Time) error {
+	return db.Model(&TaskRun{}).Where("status = ? AND started_at < ?", TaskRunRunning, startedBefore).Updates(map[string]interface{}{
+		"status":      TaskRunFailed,
+		"error":       lostTaskRunError,
+		"finished_at": time.Now(),
+	}).Error
+}
+
+// taskRunRecorder writes the runs to the database and notifies the listener on every change of a run
+// Runs are not recorded if there is no database
+type taskRunRecorder struct {
+	db       *gorm.DB
+	listener func(run TaskRun)
+}
+
+func newTaskRunRecorder(options Options) *taskRunRecorder {
+	return &taskRunRecorder{
+		db:       options.DbClient,
+		listener: options.OnTaskRunUpdate,
+	}
+}
+
+func taskRunLabelOf(argument ArgumentType) string {
+	if labeled, ok := argument.(TaskRunLabeled); ok {
+		return labeled.TaskRunLabel()
+	}
+	return concurrencyKeyOf(argument)
+}
+
+// start records the run of a task which is about to be executed
+func (t *taskRunRecorder) start(queueName string, argument ArgumentType, body string) *TaskRun {
+	run := &TaskRun{
+		QueueName: queueName,
+		TaskID:    taskHash(body),
+		Body:      body,
+		Label:     taskRunLabelOf(argument),
+		Status:    TaskRunRunning,
+		StartedAt: time.Now(),
+	}
+	if t.db != nil {
+		err := t.db.Create(run).Error
+		if err != nil {
+			log.Println("error while recording run of task for queue ["+queueName+"]", err)
+		}
+	}
+	t.notify(run)
+	return run
+}
+
+// finish records the result of the run
+func (t *taskRunRecorder) finish(run *TaskRun, taskErr error) {
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = TaskRunSucceeded
+	if taskErr != nil {
+		run.Status = TaskRunFailed
+		run.Error = taskErr.Error()
+	}
+	// run is not in database if it couldn't be created
+	if t.db != nil && run.ID != 0 {
+		err := t.db.Model(run).Select("status", "error", "finished_at").Updates(run).Error
+		if err != nil {
+			log.Println("error while recording result of task for queue ["+run.QueueName+"]", err)
+		}
+	}
+	t.notify(run)
+}
+
+func (t *taskRunRecorder) notify(run *TaskRun) {
+	if t.listener != nil {
+		t.listener(*run)
+	}
+}
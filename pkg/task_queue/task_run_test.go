@@ -0,0 +1,165 @@
+package task_queue
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type TaskRunTestArgument struct {
+	Name string `json:"name"`
+	Fail bool   `json:"fail"`
+}
+
+func (a TaskRunTestArgument) TaskRunLabel() string {
+	return "application:" + a.Name
+}
+
+type TaskRunTestWorker struct{}
+
+func (w TaskRunTestWorker) Consume(argument TaskRunTestArgument, _ context.Context, _ context.CancelFunc) error {
+	if argument.Fail {
+		return errors.New("failed to process " + argument.Name)
+	}
+	return nil
+}
+
+func newTaskRunTestDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tasks.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&EnqueuedTask{}, &TaskRun{}))
+	return db
+}
+
+// assertTaskRunsRecorded runs a succeeding and a failing task, and checks their history
+func assertTaskRunsRecorded(t *testing.T, client Client, db *gorm.DB, updates <-chan TaskRun) {
+	assert.NoError(t, client.RegisterFunctionWithRetryPolicy("task_run_test", TaskRunTestWorker{}.Consume, RetryPolicy{
+		MaxAttempts:    1,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second,
+		Multiplier:     1,
+	}))
+	assert.NoError(t, client.StartConsumers(true))
+	assert.NoError(t, client.EnqueueTask("task_run_test", TaskRunTestArgument{Name: "ok"}))
+	assert.NoError(t, client.EnqueueTask("task_run_test", TaskRunTestArgument{Name: "broken", Fail: true}))
+
+	// every run is notified when it starts and when it finishes
+	finished := make(map[string]TaskRun)
+	timeout := time.After(5 * time.Second)
+	for len(finished) < 2 {
+		select {
+		case run := <-updates:
+			if run.Status != TaskRunRunning {
+				finished[run.Label] = run
+			}
+		case <-timeout:
+			t.Fatal("task runs were not finished")
+		}
+	}
+	assert.Equal(t, TaskRunSucceeded, finished["application:ok"].Status)
+	assert.Equal(t, TaskRunFailed, finished["application:broken"].Status)
+	assert.Equal(t, "failed to process broken", finished["application:broken"].Error)
+	assert.True(t, TaskRunFilter{Label: "application:broken", Status: TaskRunFailed}.Matches(finished["application:broken"]))
+	assert.False(t, TaskRunFilter{Label: "application:broken"}.Matches(finished["application:ok"]))
+
+	runs, err := FindTaskRuns(db, TaskRunFilter{QueueName: "task_run_test"})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	for _, run := range runs {
+		assert.NotNil(t, run.FinishedAt)
+		assert.Equal(t, taskHash(run.Body), run.TaskID)
+	}
+	runs, err = FindTaskRuns(db, TaskRunFilter{Label: "application:broken"})
+	assert.NoError(t, err)
+	if assert.Len(t, runs, 1) {
+		assert.Equal(t, TaskRunFailed, runs[0].Status)
+		assert.Equal(t, `{"name":"broken","fail":true}`, runs[0].Body)
+	}
+	runs, err = FindTaskRuns(db, TaskRunFilter{Status: TaskRunSucceeded})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	// prune the history
+	assert.NoError(t, DeleteTaskRunsOlderThan(db, time.Now().Add(-time.Hour)))
+	runs, err = FindTaskRuns(db, TaskRunFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.NoError(t, DeleteTaskRunsOlderThan(db, time.Now().Add(time.Second)))
+	runs, err = FindTaskRuns(db, TaskRunFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestLocalTaskRunsRecorded(t *testing.T) {
+	db := newTaskRunTestDb(t)
+	updates := make(chan TaskRun, 10)
+	client, err := NewClient(Options{
+		Type:                Local,
+		NoOfWorkersPerQueue: 1,
+		MaxMessagesPerQueue: 10,
+		DbClient:            db,
+		OnTaskRunUpdate: func(run TaskRun) {
+			updates <- run
+		},
+	})
+	assert.NoError(t, err)
+	assertTaskRunsRecorded(t, client, db, updates)
+}
+
+func TestRedisTaskRunsRecorded(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	db := newTaskRunTestDb(t)
+	updates := make(chan TaskRun, 10)
+	client, err := NewClient(Options{
+		Type:                Remote,
+		NoOfWorkersPerQueue: 1,
+		RemoteQueueType:     RedisQueue,
+		RedisClient:         redis.NewClient(&redis.Options{Addr: redisServer.Addr()}),
+		DbClient:            db,
+		OnTaskRunUpdate: func(run TaskRun) {
+			updates <- run
+		},
+	})
+	assert.NoError(t, err)
+	assertTaskRunsRecorded(t, client, db, updates)
+}
+
+func TestLostTaskRunsMarkedAsFailed(t *testing.T) {
+	db := newTaskRunTestDb(t)
+	lost := TaskRun{QueueName: "task_run_test", Status: TaskRunRunning, StartedAt: time.Now().Add(-2 * time.Hour)}
+	recent := TaskRun{QueueName: "task_run_test", Status: TaskRunRunning, StartedAt: time.Now()}
+	assert.NoError(t, db.Create(&lost).Error)
+	assert.NoError(t, db.Create(&recent).Error)
+
+	assert.NoError(t, MarkLostTaskRunsAsFailed(db, time.Now().Add(-time.Hour)))
+	runs, err := FindTaskRuns(db, TaskRunFilter{Status: TaskRunFailed})
+	assert.NoError(t, err)
+	if assert.Len(t, runs, 1) {
+		assert.Equal(t, lost.ID, runs[0].ID)
+		assert.Equal(t, lostTaskRunError, runs[0].Error)
+		assert.NotNil(t, runs[0].FinishedAt)
+	}
+
+	// local queue is consumed only by one instance, so all the running runs are lost on start
+	client, err := NewClient(Options{
+		Type:                Local,
+		NoOfWorkersPerQueue: 1,
+		MaxMessagesPerQueue: 10,
+		DbClient:            db,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, client.StartConsumers(true))
+	runs, err = FindTaskRuns(db, TaskRunFilter{Status: TaskRunRunning})
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+}
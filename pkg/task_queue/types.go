@@ -2,20 +2,31 @@ package task_queue
 
 import (
 	"github.com/go-redis/redis/v8"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"gorm.io/gorm"
 	"reflect"
 	"sync"
+	"time"
 )
 
 type WorkerFunctionType interface{}
 type ArgumentType interface{}
 
 type Client interface {
-	// RegisterFunction registers a consumer function for a queue
+	// RegisterFunction registers a consumer function for a queue with the default retry policy
 	RegisterFunction(queueName string, function WorkerFunctionType) error
+	// RegisterFunctionWithRetryPolicy registers a consumer function for a queue
+	// Failed tasks are retried with backoff as per the policy, and moved to the dead-letter queue after the last attempt
+	RegisterFunctionWithRetryPolicy(queueName string, function WorkerFunctionType, retryPolicy RetryPolicy) error
 	// EnqueueTask enqueues a task to a queue
 	EnqueueTask(queueName string, argument ArgumentType) error
+	// EnqueueTaskAt enqueues a task to a queue, the task will not be consumed before the given time
+	// Scheduled tasks are persisted, so they survive restart of the service
+	EnqueueTaskAt(queueName string, argument ArgumentType, at time.Time) error
+	// EnqueueTaskAfter enqueues a task to a queue, the task will not be consumed before the given delay
+	EnqueueTaskAfter(queueName string, argument ArgumentType, delay time.Duration) error
 	// StartConsumers is a blocking function that starts the consumers for all the registered queues
 	StartConsumers(nowait bool) error
 	// WaitForConsumers is a blocking function that waits for all the consumers to finish
@@ -27,6 +38,31 @@ type Client interface {
 	// ListMessages returns the messages of a queue
 	// Note: Should be called when no consumers are running
 	ListMessages(queueName string) ([]string, error)
+	// ListDeadLetterTasks returns the tasks of the dead-letter queue of a queue
+	ListDeadLetterTasks(queueName string) ([]DeadLetterTask, error)
+	// RequeueDeadLetterTask moves a task from the dead-letter queue back to the queue, attempts are counted from zero again
+	RequeueDeadLetterTask(queueName string, taskId string) error
+	// DropDeadLetterTask removes a task from the dead-letter queue
+	DropDeadLetterTask(queueName string, taskId string) error
+}
+
+// RetryPolicy decides how many times a failed task is attempted and the delay between the attempts
+// Delay grows exponentially from InitialBackoff by Multiplier, and is capped at MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts    uint // including the first attempt
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+}
+
+// DeadLetterTask is a task which has failed in all the attempts of the retry policy
+type DeadLetterTask struct {
+	ID            string    `json:"id"` // sha256 hash of the body
+	QueueName     string    `json:"queue_name"`
+	Body          string    `json:"body"`
+	Attempts      uint      `json:"attempts"`
+	FailureReason string    `json:"failure_reason"`
+	FailedAt      time.Time `json:"failed_at"`
 }
 
 type localTaskQueue struct {
@@ -38,6 +74,10 @@ type localTaskQueue struct {
 	NoOfWorkersPerQueue         int
 	consumersWaitGroup          *sync.WaitGroup
 	db                          *gorm.DB
+	mutexScheduledTasks         *sync.Mutex
+	scheduledTasks              map[string]*time.Timer // map between queue name + task hash <---> timer of the scheduled task
+	concurrencyKeyLocks         *concurrencyKeyLocks
+	taskRunRecorder             *taskRunRecorder
 }
 
 type RemoteQueueType string
@@ -45,6 +85,7 @@ type RemoteQueueType string
 const (
 	AmqpQueue       RemoteQueueType = "amqp"
 	RedisQueue      RemoteQueueType = "redis"
+	NatsQueue       RemoteQueueType = "nats"
 	NoneRemoteQueue RemoteQueueType = "none"
 )
 
@@ -54,6 +95,8 @@ type remoteTaskQueue struct {
 	consumersWaitGroup          *sync.WaitGroup
 	NoOfWorkersPerQueue         int
 	queueType                   RemoteQueueType
+	concurrencyKeyLocks         *concurrencyKeyLocks // keys held by the tasks of this instance, backend lock is taken across instances
+	taskRunRecorder             *taskRunRecorder
 	// redis specific
 	redisClient *redis.Client
 	// amqp specific
@@ -62,6 +105,9 @@ type remoteTaskQueue struct {
 	amqpClientName string
 	amqpConnection *amqp.Connection
 	amqpChannel    *amqp.Channel
+	// nats specific
+	natsJetStream jetstream.JetStream
+	natsLocks     jetstream.KeyValue // bucket of concurrency key locks
 }
 
 type functionMetadata struct {
@@ -69,6 +115,7 @@ type functionMetadata struct {
 	functionName     string
 	argumentType     reflect.Type
 	argumentTypeName string
+	retryPolicy      RetryPolicy
 }
 
 type ServiceType string
@@ -82,7 +129,9 @@ type Options struct {
 	Type                ServiceType
 	NoOfWorkersPerQueue int
 	MaxMessagesPerQueue int      // only applicable for local task queue
-	DbClient            *gorm.DB // only applicable for local task queue
+	DbClient            *gorm.DB // tasks of local task queue are stored in it, history of task runs is written to it for both
+	// OnTaskRunUpdate is called when a task starts running and when it finishes, optional
+	OnTaskRunUpdate func(run TaskRun)
 	// Extra options for remote task queue
 	RemoteQueueType RemoteQueueType
 	// Redis specific options
@@ -91,4 +140,6 @@ type Options struct {
 	AMQPUri        string
 	AMQPVhost      string
 	AMQPClientName string
+	// NATS specific options, JetStream should be enabled on the server
+	NatsConnection *nats.Conn
 }
@@ -2,6 +2,7 @@ package task_queue
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"strings"
 )
@@ -15,6 +16,18 @@ func getTypeName(object interface{}) string {
 	}
 }
 
+// unmarshalArgument creates the argument of the function from the json body of the task
+func unmarshalArgument(metadata functionMetadata, body string) (ArgumentType, error) {
+	// create a new object of an argument type
+	argument := reflect.New(metadata.argumentType).Interface()
+	err := json.Unmarshal([]byte(body), &argument)
+	if err != nil {
+		return nil, err
+	}
+	// argument is a pointer, dereference it
+	return reflect.ValueOf(argument).Elem().Interface(), nil
+}
+
 func invokeFunction(function interface{}, argument interface{}, argumentType ArgumentType) error {
 	// create context with cancel
 	ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+)
+
+const (
+	// default duration covered by a sample
+	defaultIntervalSeconds = 60
+	// samples older than this are rejected, those would be downsampled already
+	maxSampleAge = 6 * time.Hour
+	// samples from future are accepted within this limit to tolerate clock drift
+	maxClockDrift = 5 * time.Minute
+)
+
+// Initialize : Initialize the server and its routes
+func (server *Server) Initialize() {
+	server.EchoServer.POST("/service/analytics", server.ingestStats)
+}
+
+// Handler to ingest the server and application resource stats pushed by the agents
+// Request should be authenticated with the analytics service token of the server
+// Authorization: Bearer <id>:<token>
+func (server *Server) ingestStats(c echo.Context) error {
+	ctx := c.Request().Context()
+	db := server.ServiceManager.DbClient
+	// verify token
+	tokenId, token, ok := parseAnalyticsToken(c.Request().Header.Get(echo.HeaderAuthorization))
+	if !ok {
+		return c.String(http.StatusUnauthorized, "Missing or malformed analytics token")
+	}
+	verified, hostname, err := core.ValidateAnalyticsServiceToken(ctx, db, tokenId, token)
+	if err != nil || !verified {
+		return c.String(http.StatusUnauthorized, "Invalid analytics token")
+	}
+	serverId, err := core.FetchServerIDByHostName(&db, hostname)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Server not found")
+	}
+	// parse payload
+	var data StatsData
+	if err := c.Bind(&data); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid payload")
+	}
+	recordedAt := time.Unix(int64(data.TimeStamp), 0).Truncate(time.Minute)
+	if data.TimeStamp == 0 || time.Since(recordedAt) > maxSampleAge || time.Until(recordedAt) > maxClockDrift {
+		return c.String(http.StatusBadRequest, "Timestamp is out of the accepted range")
+	}
+	intervalSeconds := data.IntervalSeconds
+	if intervalSeconds == 0 {
+		intervalSeconds = defaultIntervalSeconds
+	}
+	// store server stats
+	diskStats := make(core.ServerDiskStats, 0, len(data.SystemStat.DiskStats))
+	for _, disk := range data.SystemStat.DiskStats {
+		diskStats = append(diskStats, core.ServerDiskStat{
+			Path:       disk.Path,
+			MountPoint: disk.MountPoint,
+			TotalGB:    disk.TotalGB,
+			UsedGB:     disk.UsedGB,
+		})
+	}
+	serverStat := &core.ServerResourceStat{
+		ServerID:        serverId,
+		CpuUsagePercent: data.SystemStat.CpuUsagePercent,
+		MemStat: core.ServerMemoryStat{
+			TotalGB:  data.SystemStat.MemStat.TotalGB,
+			UsedGB:   data.SystemStat.MemStat.UsedGB,
+			CachedGB: data.SystemStat.MemStat.CachedGB,
+		},
+		DiskStats: diskStats,
+		NetStat: core.ServerNetStat{
+			SentKB:   data.SystemStat.NetStat.SentKB,
+			RecvKB:   data.SystemStat.NetStat.RecvKB,
+			SentKBPS: data.SystemStat.NetStat.SentKB / intervalSeconds,
+			RecvKBPS: data.SystemStat.NetStat.RecvKB / intervalSeconds,
+		},
+		RecordedAt: recordedAt,
+	}
+	err = serverStat.Create(ctx, db)
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to store server resource stats of", hostname, err.Error())
+		return c.String(http.StatusInternalServerError, "Failed to store server stats")
+	}
+	// store application stats
+	appStats := make([]*core.ApplicationServiceResourceStat, 0, len(data.ServiceStats))
+	for serviceName, stats := range data.ServiceStats {
+		if stats == nil {
+			continue
+		}
+		application := &core.Application{}
+		err = application.FindByName(ctx, db, serviceName)
+		if err != nil {
+			// ignore services which are not managed by swiftwave
+			continue
+		}
+		appStats = append(appStats, &core.ApplicationServiceResourceStat{
+			ApplicationID:        application.ID,
+			ServiceCpuTime:       stats.ServiceCpuTime,
+			SystemCpuTime:        stats.SystemCpuTime,
+			CpuUsagePercent:      stats.CpuUsagePercent,
+			ReportingServerCount: 1,
+			UsedMemoryMB:         stats.UsedMemoryMB,
+			NetStat: core.ApplicationServiceNetStat{
+				SentKB:   stats.NetStat.SentKB,
+				RecvKB:   stats.NetStat.RecvKB,
+				SentKBPS: stats.NetStat.SentKB / intervalSeconds,
+				RecvKBPS: stats.NetStat.RecvKB / intervalSeconds,
+			},
+			RecordedAt: recordedAt,
+		})
+	}
+	err = core.CreateApplicationServiceResourceStat(ctx, db, appStats)
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to store application resource stats reported by", hostname, err.Error())
+		return c.String(http.StatusInternalServerError, "Failed to store application stats")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseAnalyticsToken : split `Bearer <id>:<token>` into id and token
+func parseAnalyticsToken(header string) (string, string, bool) {
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(header), "Bearer "))
+	tokenId, token, found := strings.Cut(value, ":")
+	if !found || tokenId == "" || token == "" {
+		return "", "", false
+	}
+	return tokenId, token, true
+}
@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+)
+
+// Server : hold references to other components of service
+type Server struct {
+	EchoServer     *echo.Echo
+	Config         *config.Config
+	ServiceManager *service_manager.ServiceManager
+}
+
+// StatsData : payload pushed by the agent of a server
+type StatsData struct {
+	SystemStat HostStats `json:"system"`
+	// ServiceStats : stats of the containers grouped by the service name
+	ServiceStats map[string]*ServiceStats `json:"services"`
+	// TimeStamp : unix timestamp (in seconds) of the sample
+	TimeStamp uint64 `json:"timestamp"`
+	// IntervalSeconds : duration covered by the sample, network and cpu counters are deltas of this duration
+	IntervalSeconds uint64 `json:"interval_seconds"`
+}
+
+type HostStats struct {
+	CpuUsagePercent uint8      `json:"cpu_usage_percent"`
+	MemStat         MemoryStat `json:"memory"`
+	DiskStats       []DiskStat `json:"disks"`
+	NetStat         NetStat    `json:"network"`
+}
+
+type MemoryStat struct {
+	TotalGB  float32 `json:"total_gb"`
+	UsedGB   float32 `json:"used_gb"`
+	CachedGB float32 `json:"cached_gb"`
+}
+
+type DiskStat struct {
+	Path       string  `json:"path"`
+	MountPoint string  `json:"mount_point"`
+	TotalGB    float32 `json:"total_gb"`
+	UsedGB     float32 `json:"used_gb"`
+}
+
+type NetStat struct {
+	SentKB uint64 `json:"sent_kb"`
+	RecvKB uint64 `json:"recv_kb"`
+}
+
+type ServiceStats struct {
+	ServiceCpuTime  uint64  `json:"service_cpu_time"`
+	SystemCpuTime   uint64  `json:"system_cpu_time"`
+	CpuUsagePercent uint8   `json:"cpu_usage_percent"`
+	UsedMemoryMB    uint64  `json:"used_memory_mb"`
+	NetStat         NetStat `json:"network"`
+}
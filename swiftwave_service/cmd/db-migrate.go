@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/db"
 )
 
@@ -21,8 +23,19 @@ var dbMigrateCmd = &cobra.Command{
 		err = db.MigrateDatabase(client)
 		if err != nil {
 			printError("Failed to migrate the database")
-		} else {
-			printSuccess("Successfully migrated the database")
+			return
+		}
+		printSuccess("Successfully migrated the database")
+		// encrypt the secrets stored before the encryption was enabled
+		if keyring := secretmanager.DefaultKeyring(); keyring != nil {
+			count, err := encryptSecrets(client, keyring)
+			if err != nil {
+				printError("Failed to encrypt secrets: " + err.Error())
+				return
+			}
+			if count > 0 {
+				printSuccess(fmt.Sprintf("Encrypted %d secrets", count))
+			}
 		}
 	},
 }
@@ -95,6 +95,10 @@ var initCmd = &cobra.Command{
 		currentLocalImageRegistryUser := ""
 		currentLocalImageRegistryPassword := ""
 
+		currentMetricsToken := ""
+		// secrets stored in database can be decrypted only with the same master keys
+		currentSecretsConfig := local_config.SecretsConfig{}
+
 		if config != nil && config.LocalConfig != nil {
 			currentPostgresHost = config.LocalConfig.PostgresqlConfig.Host
 			currentPostgresPort = config.LocalConfig.PostgresqlConfig.Port
@@ -107,6 +111,8 @@ var initCmd = &cobra.Command{
 			currentLocalImageRegistryPort = config.LocalConfig.LocalImageRegistryConfig.Port
 			currentLocalImageRegistryUser = config.LocalConfig.LocalImageRegistryConfig.Username
 			currentLocalImageRegistryPassword = config.LocalConfig.LocalImageRegistryConfig.Password
+			currentMetricsToken = config.LocalConfig.ServiceConfig.MetricsToken
+			currentSecretsConfig = config.LocalConfig.SecretsConfig
 		}
 
 		// Create config
@@ -116,6 +122,7 @@ var initCmd = &cobra.Command{
 				UseTLS:                      false,
 				ManagementNodeAddress:       domainName,
 				AutoRenewManagementNodeCert: false,
+				MetricsToken:                currentMetricsToken,
 			},
 			PostgresqlConfig: local_config.PostgresqlConfig{
 				Host:             defaultString(currentPostgresHost, "127.0.0.1"),
@@ -133,6 +140,7 @@ var initCmd = &cobra.Command{
 				Username: defaultString(currentLocalImageRegistryUser, "user_"+generateRandomString(8)),
 				Password: defaultString(currentLocalImageRegistryPassword, generateRandomString(20)),
 			},
+			SecretsConfig: currentSecretsConfig,
 		}
 		err = local_config.FillDefaults(newConfig)
 		if err != nil {
@@ -3,6 +3,7 @@ package cmd
 import (
 	_ "embed"
 	"fmt"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
 	swiftwave_config "github.com/swiftwave-org/swiftwave/swiftwave_service/config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config/local_config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config/system_config/bootstrap"
@@ -28,6 +29,9 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(autoUpdateCmd)
 	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(exportStateCmd)
+	rootCmd.AddCommand(applyStateCmd)
 }
 
 var rootCmd = &cobra.Command{
@@ -87,11 +91,22 @@ func Execute() {
 		} else {
 			printSuccess("Database migrated successfully")
 		}
+		// encrypt the secrets stored before the encryption was enabled
+		if keyring := secretmanager.DefaultKeyring(); keyring != nil {
+			count, err := encryptSecrets(dbClient, keyring)
+			if err != nil {
+				printError("Failed to encrypt secrets: " + err.Error())
+				os.Exit(1)
+			}
+			if count > 0 {
+				printSuccess(fmt.Sprintf("Encrypted %d secrets", count))
+			}
+		}
 
 		loadSystemConfig := false
 
 		// if it's start command, and system setup is required, don't load complete config
-		if len(os.Args) > 1 && (os.Args[1] == "start" || os.Args[1] == "localregistry" || os.Args[1] == "tq" || os.Args[1] == "tls") {
+		if len(os.Args) > 1 && (os.Args[1] == "start" || os.Args[1] == "localregistry" || os.Args[1] == "tq" || os.Args[1] == "tls" || os.Args[1] == "apply") {
 			setupRequired, err := bootstrap.IsSystemSetupRequired()
 			if err != nil {
 				printError("Failed to check if system setup is required: " + err.Error())
@@ -100,7 +115,7 @@ func Execute() {
 			if !setupRequired {
 				loadSystemConfig = true
 			} else {
-				if os.Args[1] == "tq" || os.Args[1] == "localregistry" || os.Args[1] == "tls" {
+				if os.Args[1] == "tq" || os.Args[1] == "localregistry" || os.Args[1] == "tls" || os.Args[1] == "apply" {
 					printError("System setup is required. Run 'swiftwave start' to setup system")
 					os.Exit(1)
 				}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/db"
+	"gorm.io/gorm"
+)
+
+func init() {
+	secretsRotateKeyCmd.Flags().Bool("remove-previous-keys", false, "Remove the previous master keys after re-encryption (stop swiftwave service before using it)")
+	secretsCmd.AddCommand(secretsRotateKeyCmd)
+}
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage encryption of secrets",
+	Long:  `Manage encryption of secrets (environment variables, credentials, backup config) stored in database`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// print help
+		err := cmd.Help()
+		if err != nil {
+			return
+		}
+	},
+}
+
+var secretsRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Rotate the master key of secrets",
+	Long: `Generate a new master key and re-encrypt all the secrets with it.
+The previous keys are kept to decrypt the values written by a running swiftwave service, unless --remove-previous-keys is used`,
+	Run: func(cmd *cobra.Command, args []string) {
+		removePreviousKeys, _ := cmd.Flags().GetBool("remove-previous-keys")
+		currentKeys, err := config.LocalConfig.SecretKeys()
+		if err != nil {
+			printError("Failed to load current master key: " + err.Error())
+			return
+		}
+		newKey, err := secretmanager.GenerateKey()
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		// store the new key along with the current keys first, so that nothing becomes unreadable if re-encryption fails midway
+		rotatedKeys := append([][]byte{newKey}, currentKeys...)
+		err = config.LocalConfig.UpdateSecretKeys(rotatedKeys)
+		if err != nil {
+			printError("Failed to store new master key: " + err.Error())
+			return
+		}
+		keyring, err := secretmanager.NewKeyring(newKey, currentKeys...)
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		dbClient, err := db.GetClient(config.LocalConfig, 2)
+		if err != nil {
+			printError("Failed to connect to database: " + err.Error())
+			return
+		}
+		count, err := encryptSecrets(dbClient, keyring)
+		if err != nil {
+			printError("Failed to re-encrypt secrets: " + err.Error())
+			printInfo("New master key is stored along with the previous keys, re-run the command after fixing the issue")
+			return
+		}
+		printSuccess(fmt.Sprintf("Re-encrypted %d secrets with the new master key", count))
+		if removePreviousKeys {
+			err = config.LocalConfig.UpdateSecretKeys([][]byte{newKey})
+			if err != nil {
+				printError("Failed to remove previous master keys: " + err.Error())
+				return
+			}
+			printSuccess("Removed previous master keys")
+		}
+		printInfo("Restart swiftwave service to use the new master key")
+		printWarning("Take a new snapshot, the previous snapshots can't decrypt the secrets without the previous master key")
+	},
+}
+
+// encryptSecrets : encrypt the secrets stored as plaintext or with a previous master key in a single transaction
+func encryptSecrets(dbClient *gorm.DB, keyring *secretmanager.Keyring) (int, error) {
+	tx := dbClient.Begin()
+	count, err := core.EncryptSecrets(context.Background(), *tx, keyring)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
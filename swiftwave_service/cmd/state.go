@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/config/system_config"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/db"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/declarative_state"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/worker"
+)
+
+func init() {
+	exportStateCmd.Flags().StringP("output", "o", "", "Write the document to file instead of stdout")
+	applyStateCmd.Flags().StringP("file", "f", "", "Document to apply")
+	applyStateCmd.Flags().Bool("dry-run", false, "Only show the changes, don't apply those")
+	applyStateCmd.Flags().Bool("prune", false, "Delete the applications, ingress rules and redirect rules which are not in the document")
+	_ = applyStateCmd.MarkFlagRequired("file")
+}
+
+var exportStateCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the state of swiftwave as YAML",
+	Long: `Export applications, application groups, domains, ingress rules, redirect rules, persistent volumes, access control lists and credential references as YAML document.
+Secrets of credentials, persistent volumes and dns providers are not exported`,
+	Example: `swiftwave export -o swiftwave.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		dbClient, err := db.GetClient(config.LocalConfig, 2)
+		if err != nil {
+			printError("Failed to connect to database: " + err.Error())
+			return
+		}
+		document, err := declarative_state.Export(context.Background(), *dbClient)
+		if err != nil {
+			printError("Failed to export state: " + err.Error())
+			return
+		}
+		content, err := document.Marshal()
+		if err != nil {
+			printError("Failed to serialize state: " + err.Error())
+			return
+		}
+		if output == "" {
+			_, _ = os.Stdout.Write(content)
+			return
+		}
+		err = os.WriteFile(output, content, 0600)
+		if err != nil {
+			printError("Failed to write file: " + err.Error())
+			return
+		}
+		printSuccess("Exported state to " + output)
+		printWarning("The document contains environment variables of the applications, keep it safe")
+	},
+}
+
+var applyStateCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply the YAML document to swiftwave",
+	Long: `Create and update the resources of the YAML document, and enqueue the tasks to bring those live.
+Applying is idempotent, the same document can be applied again to resume after a failure`,
+	Example: `swiftwave apply -f swiftwave.yaml --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+		content, err := os.ReadFile(file)
+		if err != nil {
+			printError("Failed to read file: " + err.Error())
+			return
+		}
+		document, err := declarative_state.Parse(content)
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		ctx := context.Background()
+		if dryRun {
+			dbClient, err := db.GetClient(config.LocalConfig, 2)
+			if err != nil {
+				printError("Failed to connect to database: " + err.Error())
+				return
+			}
+			plan, err := declarative_state.ComputePlan(ctx, *dbClient, document, prune)
+			if err != nil {
+				printError(err.Error())
+				return
+			}
+			fmt.Print(plan.String())
+			return
+		}
+		serviceManager := service_manager.ServiceManager{}
+		serviceManager.Load(*config)
+		workerManager := worker.NewManager(config, &serviceManager)
+		swarmManagerServer, err := core.FetchSwarmManager(&serviceManager.DbClient)
+		if err != nil {
+			printError("Failed to fetch swarm manager: " + err.Error())
+			return
+		}
+		dockerManager, err := manager.DockerClient(ctx, swarmManagerServer)
+		if err != nil {
+			printError("Failed to connect to docker: " + err.Error())
+			return
+		}
+		restrictedPorts := make([]int, 0)
+		for _, port := range config.SystemConfig.RestrictedPorts {
+			restrictedPorts = append(restrictedPorts, int(port))
+		}
+		plan, err := declarative_state.Apply(ctx, declarative_state.ApplyOptions{
+			DbClient:        serviceManager.DbClient,
+			DockerManager:   dockerManager,
+			TaskEnqueuer:    workerManager,
+			RestrictedPorts: restrictedPorts,
+			CodeTarballDir:  config.LocalConfig.ServiceConfig.TarballDirectoryPath,
+			Prune:           prune,
+		}, document)
+		if plan != nil {
+			fmt.Print(plan.String())
+		}
+		if err != nil {
+			printError(err.Error())
+			printInfo("Changes applied before the failure are kept, apply the document again after fixing the issue")
+			return
+		}
+		printSuccess("Applied successfully")
+		if plan.HasChanges() && config.SystemConfig.TaskQueueConfig.Mode == system_config.LocalTaskQueue {
+			printInfo("Tasks will be picked up by swiftwave service within an hour, restart the service to pick those up now")
+		}
+	},
+}
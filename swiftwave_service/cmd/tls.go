@@ -120,6 +120,10 @@ var generateCertificateCommand = &cobra.Command{
 			IsStaging:         config.SystemConfig.LetsEncryptConfig.Staging,
 			Email:             config.SystemConfig.LetsEncryptConfig.EmailID,
 			AccountPrivateKey: config.SystemConfig.LetsEncryptConfig.PrivateKey,
+			DirectoryURL:      config.SystemConfig.LetsEncryptConfig.DirectoryURL,
+			EABKeyID:          config.SystemConfig.LetsEncryptConfig.EABKeyID,
+			EABHMACKey:        config.SystemConfig.LetsEncryptConfig.EABHMACKey,
+			CACertificate:     config.SystemConfig.LetsEncryptConfig.CACertificate,
 		}
 		sslManager := SSL.Manager{}
 		err = sslManager.Init(context.Background(), *dbClient, options)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/swiftwave-org/swiftwave/pkg/task_queue"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config/system_config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/db"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
@@ -10,13 +11,20 @@ import (
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 )
 
 func init() {
 	taskQueueCmd.AddCommand(taskQueueListCmd)
 	taskQueueCmd.AddCommand(taskQueueInspectCmd)
 	taskQueueCmd.AddCommand(taskQueuePurgeCmd)
+	taskQueueCmd.AddCommand(taskQueueDeadLetterCmd)
+	taskQueueDeadLetterCmd.AddCommand(taskQueueDeadLetterListCmd)
+	taskQueueDeadLetterCmd.AddCommand(taskQueueDeadLetterInspectCmd)
+	taskQueueDeadLetterCmd.AddCommand(taskQueueDeadLetterRequeueCmd)
+	taskQueueDeadLetterCmd.AddCommand(taskQueueDeadLetterDropCmd)
 }
 
 var taskQueueCmd = &cobra.Command{
@@ -48,7 +56,7 @@ var taskQueuePurgeCmd = &cobra.Command{
 			printError("Failed to connect to database: " + err.Error())
 			return
 		}
-		taskQueueClient, err := service_manager.FetchTaskQueueClient(config, dbClient)
+		taskQueueClient, err := service_manager.FetchTaskQueueClient(config, dbClient, nil)
 		if err != nil {
 			printError("Failed to fetch task queue client: " + err.Error())
 			return
@@ -130,7 +138,7 @@ var taskQueueInspectCmd = &cobra.Command{
 			printError("Failed to connect to database: " + err.Error())
 			return
 		}
-		taskQueueClient, err := service_manager.FetchTaskQueueClient(config, dbClient)
+		taskQueueClient, err := service_manager.FetchTaskQueueClient(config, dbClient, nil)
 		if err != nil {
 			printError("Failed to fetch task queue client: " + err.Error())
 			return
@@ -168,6 +176,192 @@ var taskQueueInspectCmd = &cobra.Command{
 	},
 }
 
+var taskQueueDeadLetterCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Manage dead-lettered tasks",
+	Long:  `Manage the tasks which have exhausted their retry attempts`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// print help
+		err := cmd.Help()
+		if err != nil {
+			return
+		}
+	},
+}
+
+var taskQueueDeadLetterListCmd = &cobra.Command{
+	Use:     "ls",
+	Short:   "List dead-lettered tasks of a queue",
+	Long:    `List dead-lettered tasks of a queue or of all queues`,
+	Example: `swiftwave tq dlq ls <all|queue_name>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			printError("Queue name is required or use 'all' to list dead-lettered tasks of all queues")
+			printInfo(cmd.Example)
+			return
+		}
+		queues := worker.Queues()
+		if args[0] != "all" {
+			if !isExistsInList(queues, args[0]) {
+				printError("Queue does not exist")
+				return
+			}
+			queues = []string{args[0]}
+		}
+		sort.Strings(queues)
+		taskQueueClient, ok := fetchTaskQueueClientForCmd()
+		if !ok {
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 4, 1, 3, ' ', 0)
+		fs := "%s\t%s\t%s\t%s\t%s\n"
+		_, _ = fmt.Fprintf(w, fs, "QUEUE NAME", "TASK ID", "ATTEMPTS", "FAILED AT", "FAILURE REASON")
+		_, _ = fmt.Fprintf(w, fs, "----------", "-------", "--------", "---------", "--------------")
+		for _, queue := range queues {
+			tasks, err := taskQueueClient.ListDeadLetterTasks(queue)
+			if err != nil {
+				_ = w.Flush()
+				printError("Failed to list dead-lettered tasks of queue " + queue + ": " + err.Error())
+				return
+			}
+			for _, task := range tasks {
+				_, _ = fmt.Fprintf(w, fs, queue, truncateString(task.ID, 12), strconv.Itoa(int(task.Attempts)), formatDeadLetterTime(task.FailedAt), truncateString(task.FailureReason, 80))
+			}
+		}
+		_ = w.Flush()
+	},
+}
+
+var taskQueueDeadLetterInspectCmd = &cobra.Command{
+	Use:     "inspect",
+	Short:   "Inspect a dead-lettered task",
+	Long:    `Inspect a dead-lettered task, will print the failure details and the message`,
+	Example: `swiftwave tq dlq inspect <queue_name> <task_id>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, queueName, task, ok := findDeadLetterTaskForCmd(cmd, args)
+		if !ok {
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 4, 1, 3, ' ', 0)
+		fs := "%s\t%s\n"
+		_, _ = fmt.Fprintf(w, fs, "QUEUE NAME", queueName)
+		_, _ = fmt.Fprintf(w, fs, "TASK ID", task.ID)
+		_, _ = fmt.Fprintf(w, fs, "ATTEMPTS", strconv.Itoa(int(task.Attempts)))
+		_, _ = fmt.Fprintf(w, fs, "FAILED AT", formatDeadLetterTime(task.FailedAt))
+		_, _ = fmt.Fprintf(w, fs, "FAILURE REASON", task.FailureReason)
+		_, _ = fmt.Fprintf(w, fs, "MESSAGE", task.Body)
+		_ = w.Flush()
+	},
+}
+
+var taskQueueDeadLetterRequeueCmd = &cobra.Command{
+	Use:     "requeue",
+	Short:   "Requeue a dead-lettered task",
+	Long:    `Move a dead-lettered task back to its queue, the attempts are counted again from zero`,
+	Example: `swiftwave tq dlq requeue <queue_name> <task_id>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		taskQueueClient, queueName, task, ok := findDeadLetterTaskForCmd(cmd, args)
+		if !ok {
+			return
+		}
+		printInfo("Requeuing task ...")
+		err := taskQueueClient.RequeueDeadLetterTask(queueName, task.ID)
+		if err != nil {
+			printError("Failed to requeue task: " + err.Error())
+			return
+		}
+		printSuccess("Task requeued successfully")
+	},
+}
+
+var taskQueueDeadLetterDropCmd = &cobra.Command{
+	Use:     "drop",
+	Short:   "Drop a dead-lettered task",
+	Long:    `Remove a dead-lettered task permanently`,
+	Example: `swiftwave tq dlq drop <queue_name> <task_id>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		taskQueueClient, queueName, task, ok := findDeadLetterTaskForCmd(cmd, args)
+		if !ok {
+			return
+		}
+		printInfo("Dropping task ...")
+		err := taskQueueClient.DropDeadLetterTask(queueName, task.ID)
+		if err != nil {
+			printError("Failed to drop task: " + err.Error())
+			return
+		}
+		printSuccess("Task dropped successfully")
+	},
+}
+
+func fetchTaskQueueClientForCmd() (task_queue.Client, bool) {
+	dbClient, err := db.GetClient(config.LocalConfig, 2)
+	if err != nil {
+		printError("Failed to connect to database: " + err.Error())
+		return nil, false
+	}
+	taskQueueClient, err := service_manager.FetchTaskQueueClient(config, dbClient, nil)
+	if err != nil {
+		printError("Failed to fetch task queue client: " + err.Error())
+		return nil, false
+	}
+	return taskQueueClient, true
+}
+
+// findDeadLetterTaskForCmd finds the dead-lettered task by queue name and full or prefix of the task id
+func findDeadLetterTaskForCmd(cmd *cobra.Command, args []string) (task_queue.Client, string, task_queue.DeadLetterTask, bool) {
+	if len(args) < 2 {
+		printError("Queue name and task id are required")
+		printInfo(cmd.Example)
+		return nil, "", task_queue.DeadLetterTask{}, false
+	}
+	queueName, taskId := args[0], args[1]
+	if !isExistsInList(worker.Queues(), queueName) {
+		printError("Queue does not exist")
+		return nil, "", task_queue.DeadLetterTask{}, false
+	}
+	taskQueueClient, ok := fetchTaskQueueClientForCmd()
+	if !ok {
+		return nil, "", task_queue.DeadLetterTask{}, false
+	}
+	tasks, err := taskQueueClient.ListDeadLetterTasks(queueName)
+	if err != nil {
+		printError("Failed to list dead-lettered tasks: " + err.Error())
+		return nil, "", task_queue.DeadLetterTask{}, false
+	}
+	var matchedTasks []task_queue.DeadLetterTask
+	for _, task := range tasks {
+		if strings.HasPrefix(task.ID, taskId) {
+			matchedTasks = append(matchedTasks, task)
+		}
+	}
+	if len(matchedTasks) == 0 {
+		printError("Task not found in dead-letter queue")
+		return nil, "", task_queue.DeadLetterTask{}, false
+	}
+	if len(matchedTasks) > 1 && matchedTasks[0].ID != taskId {
+		printError("Multiple tasks found for the task id, provide more characters of the task id")
+		return nil, "", task_queue.DeadLetterTask{}, false
+	}
+	return taskQueueClient, queueName, matchedTasks[0], true
+}
+
+func formatDeadLetterTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC822)
+}
+
+// truncateString truncates the string to the given length and flattens it to a single line
+func truncateString(s string, length int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
+
 func isExistsInList(list []string, item string) bool {
 	for _, l := range list {
 		if l == item {
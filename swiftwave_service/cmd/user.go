@@ -17,6 +17,7 @@ func init() {
 	userManagementCmd.AddCommand(disableTotpCmd)
 	createUserCmd.Flags().StringP("username", "u", "", "userID")
 	createUserCmd.Flags().StringP("password", "p", "", "Password [Optional]")
+	createUserCmd.Flags().StringP("role", "r", string(core.AdministratorRole), "Role of the user [admin, manager]")
 	deleteUserCmd.Flags().StringP("username", "u", "", "userID")
 	disableTotpCmd.Flags().StringP("username", "u", "", "userID")
 }
@@ -40,6 +41,7 @@ var createUserCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		username := cmd.Flag("username").Value.String()
 		password := cmd.Flag("password").Value.String()
+		role := core.UserRole(cmd.Flag("role").Value.String())
 		if username == "" {
 			printError("userID is required")
 			err := cmd.Help()
@@ -48,6 +50,10 @@ var createUserCmd = &cobra.Command{
 			}
 			return
 		}
+		if !role.IsValid() {
+			printError("Invalid role, should be either admin or manager")
+			return
+		}
 		if password == "" {
 			// Ask for password
 			fmt.Print("Enter password: ")
@@ -86,6 +92,7 @@ var createUserCmd = &cobra.Command{
 		// Create user
 		user := core.User{
 			Username: username,
+			Role:     role,
 		}
 		err = user.SetPassword(password)
 		if err != nil {
@@ -25,6 +25,7 @@ var defaultPVBackupDirectoryPath = filepath.Join(defaultDataDirectory, "pvbackup
 var defaultPVRestoreDirectoryPath = filepath.Join(defaultDataDirectory, "pvrestore")
 var defaultTarballDirectoryPath = filepath.Join(defaultDataDirectory, "tarball")
 var defaultLocalPostgresDataDirectory = filepath.Join(defaultDataDirectory, "postgres")
+var defaultMasterKeyFilePath = filepath.Join(defaultDataDirectory, "master.key")
 var LocalConfigPath = filepath.Join(defaultDataDirectory, "config.yml")
 var LogDirectoryPath = "/var/log/swiftwave"
 var InfoLogFilePath = filepath.Join(LogDirectoryPath, "swiftwave.log")
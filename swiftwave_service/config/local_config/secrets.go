@@ -0,0 +1,70 @@
+package local_config
+
+import (
+	"errors"
+	"os"
+
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
+)
+
+// SecretKeys : master keys of the secrets, the first one is the primary key
+// The master key file is generated on the first start, if the default path is used
+func (config *Config) SecretKeys() ([][]byte, error) {
+	if config.SecretsConfig.MasterKeyFile != "" {
+		keys, err := secretmanager.ReadKeyFile(config.SecretsConfig.MasterKeyFile)
+		if err == nil {
+			return keys, nil
+		}
+		if !os.IsNotExist(err) || config.SecretsConfig.MasterKeyFile != defaultMasterKeyFilePath {
+			return nil, errors.New("failed to read master key file > " + err.Error())
+		}
+		key, err := secretmanager.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = [][]byte{key}
+		err = secretmanager.WriteKeyFile(config.SecretsConfig.MasterKeyFile, keys)
+		if err != nil {
+			return nil, errors.New("failed to write master key file > " + err.Error())
+		}
+		return keys, nil
+	}
+	if config.SecretsConfig.MasterKey == "" {
+		return nil, errors.New("master_key or master_key_file is required in secrets config")
+	}
+	keys := make([][]byte, 0, len(config.SecretsConfig.PreviousMasterKeys)+1)
+	for _, encodedKey := range append([]string{config.SecretsConfig.MasterKey}, config.SecretsConfig.PreviousMasterKeys...) {
+		key, err := secretmanager.DecodeKey(encodedKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SecretKeyring : keyring of the master keys of the secrets
+func (config *Config) SecretKeyring() (*secretmanager.Keyring, error) {
+	keys, err := config.SecretKeys()
+	if err != nil {
+		return nil, err
+	}
+	return secretmanager.NewKeyring(keys[0], keys[1:]...)
+}
+
+// UpdateSecretKeys : store the master keys, the first one is the primary key
+// Keys are written to the master key file if configured, otherwise to the config file
+func (config *Config) UpdateSecretKeys(keys [][]byte) error {
+	if len(keys) == 0 {
+		return errors.New("at least one master key is required")
+	}
+	if config.SecretsConfig.MasterKeyFile != "" {
+		return secretmanager.WriteKeyFile(config.SecretsConfig.MasterKeyFile, keys)
+	}
+	config.SecretsConfig.MasterKey = secretmanager.EncodeKey(keys[0])
+	config.SecretsConfig.PreviousMasterKeys = make([]string, 0, len(keys)-1)
+	for _, key := range keys[1:] {
+		config.SecretsConfig.PreviousMasterKeys = append(config.SecretsConfig.PreviousMasterKeys, secretmanager.EncodeKey(key))
+	}
+	return Update(config)
+}
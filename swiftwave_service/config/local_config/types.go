@@ -13,6 +13,7 @@ type Config struct {
 	LocalImageRegistryConfig       LocalImageRegistryConfig       `yaml:"local_image_registry"`
 	EnvironmentVariables           EnvironmentVariables           `yaml:"environment_variables"`
 	ManagementNodeTunnellingConfig ManagementNodeTunnellingConfig `yaml:"management_node_tunnelling"`
+	SecretsConfig                  SecretsConfig                  `yaml:"secrets"`
 }
 
 type ServiceConfig struct {
@@ -22,6 +23,7 @@ type ServiceConfig struct {
 	BindAddress                     string `yaml:"bind_address"`
 	BindPort                        int    `yaml:"bind_port"`
 	SSHTimeout                      int    `yaml:"ssh_timeout"`
+	MetricsToken                    string `yaml:"metrics_token"`
 	SocketPathDirectory             string `yaml:"-"`
 	DataDirectory                   string `yaml:"-"`
 	LocalPostgresDataDirectory      string `yaml:"-"`
@@ -79,3 +81,12 @@ type ManagementNodeTunnellingConfig struct {
 	LocalImageRegistryNodeAddress string `yaml:"local_image_registry_node_address"`
 	LocalImageRegistryNodePort    int    `yaml:"local_image_registry_node_port"`
 }
+
+type SecretsConfig struct {
+	// base64 encoded 32 bytes key, used only if master_key_file is not set
+	MasterKey string `yaml:"master_key,omitempty"`
+	// file with base64 encoded keys, one per line, the first one is the primary key and the rest are the previous keys
+	MasterKeyFile string `yaml:"master_key_file,omitempty"`
+	// keys used only to decrypt the values encrypted before a key rotation, used along with master_key
+	PreviousMasterKeys []string `yaml:"previous_master_keys,omitempty"`
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/labstack/gommon/random"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
 	"github.com/swiftwave-org/swiftwave/pkg/ssh_toolkit"
 	"golang.org/x/crypto/bcrypt"
 	"gopkg.in/yaml.v3"
@@ -85,6 +87,12 @@ func readConfigFile(path string) (*Config, error) {
 	// validate and set defaults
 	_ = FillDefaults(&config)
 	ssh_toolkit.UpdateTCPTimeout(config.ServiceConfig.SSHTimeout)
+	// load the master keys of the secrets
+	keyring, err := config.SecretKeyring()
+	if err != nil {
+		return nil, errors.New("failed to load master key of secrets > " + err.Error())
+	}
+	secretmanager.UseKeyring(keyring)
 	// write config
 	_ = Update(&config)
 	return &config, nil
@@ -100,6 +108,9 @@ func FillDefaults(config *Config) error {
 	if strings.Compare(config.ServiceConfig.ManagementNodeAddress, "") == 0 {
 		return errors.New("management_node_address is required in config")
 	}
+	if strings.Compare(config.ServiceConfig.MetricsToken, "") == 0 {
+		config.ServiceConfig.MetricsToken = random.String(32)
+	}
 	if config.LocalImageRegistryConfig.Port == 0 {
 		config.LocalImageRegistryConfig.Port = defaultImageRegistryPort
 	}
@@ -129,6 +140,9 @@ func FillDefaults(config *Config) error {
 	if config.ServiceConfig.SSHTimeout == 0 {
 		config.ServiceConfig.SSHTimeout = defaultSSHTimeout
 	}
+	if strings.Compare(config.SecretsConfig.MasterKey, "") == 0 && strings.Compare(config.SecretsConfig.MasterKeyFile, "") == 0 {
+		config.SecretsConfig.MasterKeyFile = defaultMasterKeyFilePath
+	}
 	if strings.Compare(config.EnvironmentVariables.SshAuthSock, "") == 0 {
 		config.EnvironmentVariables.SshAuthSock = os.Getenv("SSH_AUTH_SOCK")
 	}
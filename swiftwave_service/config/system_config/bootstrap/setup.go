@@ -115,6 +115,7 @@ func SystemSetupHandler(c echo.Context) error {
 	// Create the initial user
 	user := core.User{
 		Username: systemConfigReq.NewAdminCredential.Username,
+		Role:     core.AdministratorRole,
 	}
 	err = user.SetPassword(systemConfigReq.NewAdminCredential.Password)
 	if err != nil {
@@ -11,12 +11,14 @@ const (
 	RemoteRegistry  ImageRegistryType      = "remote"
 	LocalPubsub     PubsubType             = "local"
 	RemotePubsub    PubsubType             = "remote"
+	NatsPubsub      PubsubType             = "nats"
 	LocalTaskQueue  TaskQueueType          = "local"
 	RemoteTaskQueue TaskQueueType          = "remote"
 	AMQP            TaskQueueQueueProtocol = "amqp"
 	AMQPS           TaskQueueQueueProtocol = "amqps"
 	AmqpQueue       RemoteTaskQueueType    = "amqp"
 	RedisQueue      RemoteTaskQueueType    = "redis"
+	NatsQueue       RemoteTaskQueueType    = "nats"
 	NoneRemoteQueue RemoteTaskQueueType    = "none"
 )
 
@@ -36,9 +38,13 @@ type SystemConfigurationPayload struct {
 }
 
 type LetsEncryptConfig struct {
-	EmailAddress string `json:"email_address"`
-	StagingEnv   bool   `json:"staging_env"`
-	PrivateKey   string `json:"-"`
+	EmailAddress  string `json:"email_address"`
+	StagingEnv    bool   `json:"staging_env"`
+	PrivateKey    string `json:"-"`
+	DirectoryURL  string `json:"directory_url"`
+	EABKeyID      string `json:"eab_key_id"`
+	EABHMACKey    string `json:"eab_hmac_key"`
+	CACertificate string `json:"ca_certificate"`
 }
 
 type ImageRegistryConfig struct {
@@ -77,6 +83,7 @@ type PubsubConfig struct {
 	Type         PubsubType  `json:"type"`
 	BufferLength uint        `json:"buffer_length"`
 	RedisConfig  RedisConfig `json:"redis_config"`
+	NatsConfig   NatsConfig  `json:"nats_config"`
 }
 
 type RedisConfig struct {
@@ -93,6 +100,13 @@ type TaskQueueConfig struct {
 	NoOfWorkersPerQueue            uint                `json:"no_of_workers_per_queue"`
 	AmqpConfig                     AmqpConfig          `json:"amqp_config"`
 	RedisConfig                    RedisConfig         `json:"redis_config"`
+	NatsConfig                     NatsConfig          `json:"nats_config"`
+}
+
+type NatsConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
 }
 
 type AmqpConfig struct {
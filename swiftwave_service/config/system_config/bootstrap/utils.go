@@ -115,6 +115,12 @@ func payloadToDBRecord(payload SystemConfigurationPayload) (system_config.System
 	if isEmptyString(payload.LetsEncrypt.EmailAddress) {
 		return system_config.SystemConfig{}, errors.New("letsencrypt email address is required")
 	}
+	if !isEmptyString(payload.LetsEncrypt.DirectoryURL) && !strings.HasPrefix(strings.TrimSpace(payload.LetsEncrypt.DirectoryURL), "https://") {
+		return system_config.SystemConfig{}, errors.New("acme directory url should be a https url")
+	}
+	if isEmptyString(payload.LetsEncrypt.EABKeyID) != isEmptyString(payload.LetsEncrypt.EABHMACKey) {
+		return system_config.SystemConfig{}, errors.New("both eab key id and hmac key are required for external account binding")
+	}
 	if isEmptyString(payload.HAProxyConfig.Image) {
 		return system_config.SystemConfig{}, errors.New("haproxy image is required")
 	}
@@ -165,9 +171,13 @@ func payloadToDBRecord(payload SystemConfigurationPayload) (system_config.System
 		SshPrivateKey:   sshPrivateKey,
 		RestrictedPorts: portsStringToArray(payload.ExtraRestrictedPorts),
 		LetsEncryptConfig: system_config.LetsEncryptConfig{
-			EmailID:    payload.LetsEncrypt.EmailAddress,
-			Staging:    payload.LetsEncrypt.StagingEnv,
-			PrivateKey: letsEncryptPrivateKey,
+			EmailID:       payload.LetsEncrypt.EmailAddress,
+			Staging:       payload.LetsEncrypt.StagingEnv,
+			PrivateKey:    letsEncryptPrivateKey,
+			DirectoryURL:  strings.TrimSpace(payload.LetsEncrypt.DirectoryURL),
+			EABKeyID:      strings.TrimSpace(payload.LetsEncrypt.EABKeyID),
+			EABHMACKey:    strings.TrimSpace(payload.LetsEncrypt.EABHMACKey),
+			CACertificate: strings.TrimSpace(payload.LetsEncrypt.CACertificate),
 		},
 		HAProxyConfig: system_config.HAProxyConfig{
 			Image:    payload.HAProxyConfig.Image,
@@ -197,6 +207,11 @@ func payloadToDBRecord(payload SystemConfigurationPayload) (system_config.System
 				Password:   payload.PubsubConfig.RedisConfig.Password,
 				DatabaseID: payload.PubsubConfig.RedisConfig.Database,
 			},
+			NATSConfig: system_config.NATSConfig{
+				URL:      payload.PubsubConfig.NatsConfig.URL,
+				Username: payload.PubsubConfig.NatsConfig.Username,
+				Password: payload.PubsubConfig.NatsConfig.Password,
+			},
 		},
 		TaskQueueConfig: system_config.TaskQueueConfig{
 			Mode:                           system_config.TaskQueueMode(payload.TaskQueueConfig.Type),
@@ -217,6 +232,11 @@ func payloadToDBRecord(payload SystemConfigurationPayload) (system_config.System
 				Password:   payload.TaskQueueConfig.RedisConfig.Password,
 				DatabaseID: payload.TaskQueueConfig.RedisConfig.Database,
 			},
+			NATSConfig: system_config.NATSConfig{
+				URL:      payload.TaskQueueConfig.NatsConfig.URL,
+				Username: payload.TaskQueueConfig.NatsConfig.Username,
+				Password: payload.TaskQueueConfig.NatsConfig.Password,
+			},
 		},
 		ImageRegistryConfig: imageRegistryConfig,
 	}, nil
@@ -250,6 +270,16 @@ func dbRecordToPayload(record *system_config.SystemConfig) SystemConfigurationPa
 				Database: record.PubSubConfig.RedisConfig.DatabaseID,
 			},
 		}
+	} else if record.PubSubConfig.Mode == system_config.NatsPubSub {
+		pubsubConfig = PubsubConfig{
+			Type:         NatsPubsub,
+			BufferLength: record.PubSubConfig.BufferLength,
+			NatsConfig: NatsConfig{
+				URL:      record.PubSubConfig.NATSConfig.URL,
+				Username: record.PubSubConfig.NATSConfig.Username,
+				Password: record.PubSubConfig.NATSConfig.Password,
+			},
+		}
 	}
 	var taskQueueConfig = TaskQueueConfig{
 		Type:                           LocalTaskQueue,
@@ -277,14 +307,23 @@ func dbRecordToPayload(record *system_config.SystemConfig) SystemConfigurationPa
 				Password: record.TaskQueueConfig.RedisConfig.Password,
 				Database: record.TaskQueueConfig.RedisConfig.DatabaseID,
 			},
+			NatsConfig: NatsConfig{
+				URL:      record.TaskQueueConfig.NATSConfig.URL,
+				Username: record.TaskQueueConfig.NATSConfig.Username,
+				Password: record.TaskQueueConfig.NATSConfig.Password,
+			},
 		}
 	}
 	return SystemConfigurationPayload{
 		NetworkName:          record.NetworkName,
 		ExtraRestrictedPorts: portsArrayToString(record.RestrictedPorts),
 		LetsEncrypt: LetsEncryptConfig{
-			EmailAddress: record.LetsEncryptConfig.EmailID,
-			StagingEnv:   record.LetsEncryptConfig.Staging,
+			EmailAddress:  record.LetsEncryptConfig.EmailID,
+			StagingEnv:    record.LetsEncryptConfig.Staging,
+			DirectoryURL:  record.LetsEncryptConfig.DirectoryURL,
+			EABKeyID:      record.LetsEncryptConfig.EABKeyID,
+			EABHMACKey:    record.LetsEncryptConfig.EABHMACKey,
+			CACertificate: record.LetsEncryptConfig.CACertificate,
 		},
 		ImageRegistry: imageRegistry,
 		HAProxyConfig: HAProxyConfig{
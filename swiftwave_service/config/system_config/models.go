@@ -21,4 +21,6 @@ type SystemConfig struct {
 	PubSubConfig                 PubSubConfig                 `json:"pub_sub_config" gorm:"embedded;embeddedPrefix:pub_sub_config_"`
 	TaskQueueConfig              TaskQueueConfig              `json:"task_queue_config" gorm:"embedded;embeddedPrefix:task_queue_config_"`
 	ImageRegistryConfig          ImageRegistryConfig          `json:"image_registry_config" gorm:"embedded;embeddedPrefix:image_registry_config_"`
+	// secretsEncrypted - secret fields hold the encrypted values, between save and its completion
+	secretsEncrypted bool
 }
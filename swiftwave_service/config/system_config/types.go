@@ -6,6 +6,7 @@ type PubSubMode string
 const (
 	LocalPubSub  PubSubMode = "local"
 	RemotePubSub PubSubMode = "remote"
+	NatsPubSub   PubSubMode = "nats"
 )
 
 // AMQPProtocol : protocol for AMQP
@@ -29,6 +30,7 @@ type RemoteTaskQueueType string
 const (
 	AmqpQueue       RemoteTaskQueueType = "amqp"
 	RedisQueue      RemoteTaskQueueType = "redis"
+	NatsQueue       RemoteTaskQueueType = "nats"
 	NoneRemoteQueue RemoteTaskQueueType = "none"
 )
 
@@ -40,12 +42,20 @@ type ImageRegistryConfig struct {
 	Namespace string `json:"namespace"`
 }
 
-// LetsEncryptConfig : hold information about lets encrypt configuration
+// LetsEncryptConfig : hold information about ACME CA configuration
+// Let's Encrypt is used by default, any ACME CA can be used by setting DirectoryURL
 type LetsEncryptConfig struct {
 	ID         uint   `json:"id" gorm:"primaryKey"`
 	Staging    bool   `json:"staging" gorm:"default:false"`
 	EmailID    string `json:"email_id"`
 	PrivateKey string `json:"private_key"`
+	// DirectoryURL : ACME directory of the CA (e.g. ZeroSSL, step-ca)
+	DirectoryURL string `json:"directory_url"`
+	// EABKeyID, EABHMACKey : External Account Binding credentials
+	EABKeyID   string `json:"eab_key_id"`
+	EABHMACKey string `json:"eab_hmac_key" gorm:"column:eab_hmac_key"`
+	// CACertificate : root certificate of private CA, to trust its ACME server
+	CACertificate string `json:"ca_certificate"`
 }
 
 // FirewallConfig : hold information about firewall configuration
@@ -76,6 +86,7 @@ type PubSubConfig struct {
 	Mode         PubSubMode  `json:"mode" gorm:"default:'local'"`
 	BufferLength uint        `json:"buffer_length" gorm:"default:2000"`
 	RedisConfig  RedisConfig `json:"redis_config" gorm:"embedded;embeddedPrefix:redis_"`
+	NATSConfig   NATSConfig  `json:"nats_config" gorm:"embedded;embeddedPrefix:nats_"`
 }
 
 // TaskQueueConfig : configuration for task queue system
@@ -86,6 +97,7 @@ type TaskQueueConfig struct {
 	NoOfWorkersPerQueue            uint                `json:"no_of_workers_per_queue"`
 	AMQPConfig                     AMQPConfig          `json:"amqp_config" gorm:"embedded;embeddedPrefix:amqp_"`
 	RedisConfig                    RedisConfig         `json:"redis_config" gorm:"embedded;embeddedPrefix:redis_"`
+	NATSConfig                     NATSConfig          `json:"nats_config" gorm:"embedded;embeddedPrefix:nats_"`
 }
 
 // RedisConfig : configuration for Redis
@@ -106,6 +118,13 @@ type AMQPConfig struct {
 	VHost    string       `json:"vhost"`
 }
 
+// NATSConfig : configuration for NATS, JetStream should be enabled on the server
+type NATSConfig struct {
+	URL      string `json:"url"` // can contain multiple servers separated by comma
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 // HAProxyConfig : configuration for HAProxy
 type HAProxyConfig struct {
 	Image    string `json:"image"`
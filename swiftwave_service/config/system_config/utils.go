@@ -4,6 +4,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
 	"golang.org/x/crypto/ssh"
 	"gorm.io/gorm"
 	"strings"
@@ -115,3 +116,59 @@ func (config *SystemConfig) PublicSSHKey() (string, error) {
 	pubKeyComplete := fmt.Sprintf("%s swiftwave", pubKey)
 	return pubKeyComplete, nil
 }
+
+// Secret fields are encrypted with the master key by the GORM hooks before they are stored, and decrypted once they are loaded
+// Whether the fields hold the encrypted values is tracked by the record, as a plaintext value can look like an encrypted one
+
+func (config *SystemConfig) BeforeSave(_ *gorm.DB) error {
+	// already encrypted by a previous save which failed
+	if config.secretsEncrypted {
+		return nil
+	}
+	s3BackupConfig := &config.PersistentVolumeBackupConfig.S3BackupConfig
+	fields := []*string{&s3BackupConfig.AccessKeyID, &s3BackupConfig.SecretAccessKey}
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value, err := secretmanager.Encrypt(*field)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	for i, field := range fields {
+		*field = values[i]
+	}
+	config.secretsEncrypted = true
+	return nil
+}
+
+func (config *SystemConfig) AfterSave(_ *gorm.DB) error {
+	return config.decryptSecrets()
+}
+
+func (config *SystemConfig) AfterFind(_ *gorm.DB) error {
+	// loaded values are always encrypted
+	config.secretsEncrypted = true
+	return config.decryptSecrets()
+}
+
+func (config *SystemConfig) decryptSecrets() error {
+	if !config.secretsEncrypted {
+		return nil
+	}
+	s3BackupConfig := &config.PersistentVolumeBackupConfig.S3BackupConfig
+	fields := []*string{&s3BackupConfig.AccessKeyID, &s3BackupConfig.SecretAccessKey}
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value, err := secretmanager.Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	for i, field := range fields {
+		*field = values[i]
+	}
+	config.secretsEncrypted = false
+	return nil
+}
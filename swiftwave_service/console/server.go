@@ -12,18 +12,40 @@ import (
 	"github.com/swiftwave-org/swiftwave/pkg/ssh_toolkit"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/middleware"
 	"golang.org/x/net/websocket"
 )
 
 // Initialize : Initialize the server and its routes
 func (server *Server) Initialize() {
 	server.initiateAssetRoutes()
-	server.EchoServer.POST("/console/token/server/:id", server.generateAuthTokenForServer)
-	server.EchoServer.POST("/console/token/application/:id/:server_id", server.generateAuthTokenForApplication)
-	server.EchoServer.GET("/console/application/:id/servers", server.fetchServersForApplication)
+	// only admin can access console of servers
+	server.EchoServer.POST("/console/token/server/:id", server.generateAuthTokenForServer, requireRole(core.AdministratorRole))
+	server.EchoServer.POST("/console/token/application/:id/:server_id", server.generateAuthTokenForApplication, requireRole(core.ManagerRole))
+	server.EchoServer.GET("/console/application/:id/servers", server.fetchServersForApplication, requireRole(core.ManagerRole))
 	server.EchoServer.GET("/console/ws/:requestId/:token/:rows/:cols", server.consoleWebsocket)
 }
 
+// requireRole : middleware to allow only authenticated users with the required role
+func requireRole(role core.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authInfo, ok := c.Get("auth").(middleware.AuthInfo)
+			if !ok || !authInfo.IsAuthorized() {
+				return c.String(http.StatusUnauthorized, "Unauthenticated")
+			}
+			allowed, err := authInfo.HasRole(role)
+			if err != nil {
+				return c.String(http.StatusUnauthorized, "Unauthenticated")
+			}
+			if !allowed {
+				return c.String(http.StatusForbidden, "Forbidden, requires "+string(role)+" role")
+			}
+			return next(c)
+		}
+	}
+}
+
 // Handler for generate auth token
 func (server *Server) generateAuthTokenForServer(c echo.Context) error {
 	serverIdStr := c.Param("id")
@@ -0,0 +1,83 @@
+package console
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/middleware"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRequireRole(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&core.User{}, &core.UserSession{}))
+	ctx := context.Background()
+	createSession := func(username string, role core.UserRole) string {
+		user, err := core.CreateUser(ctx, *db, core.User{Username: username, Role: role, PasswordHash: "hash"})
+		assert.NoError(t, err)
+		sessionID, err := core.CreateSession(ctx, *db, user)
+		assert.NoError(t, err)
+		return sessionID
+	}
+	sessions := map[string]string{
+		"admin":           createSession("admin", core.AdministratorRole),
+		"manager":         createSession("manager", core.ManagerRole),
+		"unauthenticated": "",
+	}
+
+	e := echo.New()
+	e.Use(middleware.AuthResolverMiddleware(db))
+	ok := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+	e.GET("/admin", ok, requireRole(core.AdministratorRole))
+	e.GET("/manager", ok, requireRole(core.ManagerRole))
+
+	expectedStatusCodes := map[string]map[string]int{
+		"/admin": {
+			"admin":           http.StatusOK,
+			"manager":         http.StatusForbidden,
+			"unauthenticated": http.StatusUnauthorized,
+		},
+		"/manager": {
+			"admin":           http.StatusOK,
+			"manager":         http.StatusOK,
+			"unauthenticated": http.StatusUnauthorized,
+		},
+	}
+	for path, statusCodes := range expectedStatusCodes {
+		for name, expectedStatusCode := range statusCodes {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if sessions[name] != "" {
+				req.AddCookie(&http.Cookie{Name: "session_id", Value: sessions[name]})
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, expectedStatusCode, rec.Code, "%s requesting %s", name, path)
+			switch expectedStatusCode {
+			case http.StatusForbidden:
+				assert.Equal(t, "Forbidden, requires admin role", rec.Body.String())
+			case http.StatusUnauthorized:
+				assert.Equal(t, "Unauthenticated", rec.Body.String())
+			}
+		}
+	}
+
+	t.Run("missing auth info", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/admin", nil), httptest.NewRecorder())
+		rec := c.Response().Writer.(*httptest.ResponseRecorder)
+		assert.NoError(t, requireRole(core.ManagerRole)(ok)(c))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
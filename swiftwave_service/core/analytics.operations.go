@@ -108,3 +108,181 @@ func FetchApplicationServiceResourceAnalytics(_ context.Context, db gorm.DB, app
 	err := db.Where("application_id = ?", applicationId).Where("recorded_at > ?", time.Unix(int64(tillTime), 0)).Order("recorded_at desc").Find(&appStats).Error
 	return appStats, err
 }
+
+// DownsampleServerResourceStats merges the server resource stats recorded before `before` into one record per `interval`
+func DownsampleServerResourceStats(_ context.Context, db gorm.DB, before time.Time, interval time.Duration) error {
+	var serverIds []uint
+	err := db.Model(&ServerResourceStat{}).Where("recorded_at < ?", before).Distinct().Pluck("server_id", &serverIds).Error
+	if err != nil {
+		return err
+	}
+	for _, serverId := range serverIds {
+		var records []*ServerResourceStat
+		err = db.Where("server_id = ? AND recorded_at < ?", serverId, before).Order("recorded_at asc").Find(&records).Error
+		if err != nil {
+			return err
+		}
+		for bucket, bucketRecords := range groupServerResourceStats(records, interval) {
+			if !isDownsampleRequired(len(bucketRecords), bucketRecords[0].RecordedAt, bucket) {
+				continue
+			}
+			aggregated := aggregateServerResourceStats(bucketRecords, bucket, interval)
+			ids := make([]uint, 0, len(bucketRecords))
+			for _, record := range bucketRecords {
+				ids = append(ids, record.ID)
+			}
+			err = db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Where("id IN ?", ids).Delete(&ServerResourceStat{}).Error; err != nil {
+					return err
+				}
+				return tx.Create(aggregated).Error
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DownsampleApplicationServiceResourceStats merges the application resource stats recorded before `before` into one record per `interval`
+func DownsampleApplicationServiceResourceStats(_ context.Context, db gorm.DB, before time.Time, interval time.Duration) error {
+	var applicationIds []string
+	err := db.Model(&ApplicationServiceResourceStat{}).Where("recorded_at < ?", before).Distinct().Pluck("application_id", &applicationIds).Error
+	if err != nil {
+		return err
+	}
+	for _, applicationId := range applicationIds {
+		var records []*ApplicationServiceResourceStat
+		err = db.Where("application_id = ? AND recorded_at < ?", applicationId, before).Order("recorded_at asc").Find(&records).Error
+		if err != nil {
+			return err
+		}
+		for bucket, bucketRecords := range groupApplicationServiceResourceStats(records, interval) {
+			if !isDownsampleRequired(len(bucketRecords), bucketRecords[0].RecordedAt, bucket) {
+				continue
+			}
+			aggregated := aggregateApplicationServiceResourceStats(bucketRecords, bucket, interval)
+			ids := make([]uint, 0, len(bucketRecords))
+			for _, record := range bucketRecords {
+				ids = append(ids, record.ID)
+			}
+			err = db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Where("id IN ?", ids).Delete(&ApplicationServiceResourceStat{}).Error; err != nil {
+					return err
+				}
+				return tx.Create(aggregated).Error
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteResourceStatsOlderThan deletes the server and application resource stats recorded before `before`
+func DeleteResourceStatsOlderThan(_ context.Context, db gorm.DB, before time.Time) error {
+	err := db.Where("recorded_at < ?", before).Delete(&ServerResourceStat{}).Error
+	if err != nil {
+		return err
+	}
+	return db.Where("recorded_at < ?", before).Delete(&ApplicationServiceResourceStat{}).Error
+}
+
+// private functions
+
+// isDownsampleRequired : a bucket is already downsampled if it has a single record at the start of the bucket
+func isDownsampleRequired(recordsCount int, firstRecordedAt time.Time, bucket time.Time) bool {
+	return recordsCount > 1 || !firstRecordedAt.Equal(bucket)
+}
+
+func groupServerResourceStats(records []*ServerResourceStat, interval time.Duration) map[time.Time][]*ServerResourceStat {
+	groups := make(map[time.Time][]*ServerResourceStat)
+	for _, record := range records {
+		bucket := record.RecordedAt.Truncate(interval)
+		groups[bucket] = append(groups[bucket], record)
+	}
+	return groups
+}
+
+func groupApplicationServiceResourceStats(records []*ApplicationServiceResourceStat, interval time.Duration) map[time.Time][]*ApplicationServiceResourceStat {
+	groups := make(map[time.Time][]*ApplicationServiceResourceStat)
+	for _, record := range records {
+		bucket := record.RecordedAt.Truncate(interval)
+		groups[bucket] = append(groups[bucket], record)
+	}
+	return groups
+}
+
+// aggregateServerResourceStats : average the usages and sum up the network transfer
+// records should be sorted by recorded_at, latest disk stats are kept
+func aggregateServerResourceStats(records []*ServerResourceStat, bucket time.Time, interval time.Duration) *ServerResourceStat {
+	count := len(records)
+	var cpuUsagePercent uint64
+	var memTotalGB, memUsedGB, memCachedGB float64
+	var sentKB, recvKB uint64
+	for _, record := range records {
+		cpuUsagePercent += uint64(record.CpuUsagePercent)
+		memTotalGB += float64(record.MemStat.TotalGB)
+		memUsedGB += float64(record.MemStat.UsedGB)
+		memCachedGB += float64(record.MemStat.CachedGB)
+		sentKB += record.NetStat.SentKB
+		recvKB += record.NetStat.RecvKB
+	}
+	intervalSeconds := uint64(interval.Seconds())
+	return &ServerResourceStat{
+		ServerID:        records[0].ServerID,
+		CpuUsagePercent: uint8(cpuUsagePercent / uint64(count)),
+		MemStat: ServerMemoryStat{
+			TotalGB:  float32(memTotalGB / float64(count)),
+			UsedGB:   float32(memUsedGB / float64(count)),
+			CachedGB: float32(memCachedGB / float64(count)),
+		},
+		DiskStats: records[count-1].DiskStats,
+		NetStat: ServerNetStat{
+			SentKB:   sentKB,
+			RecvKB:   recvKB,
+			SentKBPS: sentKB / intervalSeconds,
+			RecvKBPS: recvKB / intervalSeconds,
+		},
+		RecordedAt: bucket,
+	}
+}
+
+// aggregateApplicationServiceResourceStats : sum up the cpu time and network transfer, average the memory usage
+func aggregateApplicationServiceResourceStats(records []*ApplicationServiceResourceStat, bucket time.Time, interval time.Duration) *ApplicationServiceResourceStat {
+	count := len(records)
+	var serviceCpuTime, systemCpuTime, usedMemoryMB, sentKB, recvKB uint64
+	var reportingServerCount uint
+	for _, record := range records {
+		serviceCpuTime += record.ServiceCpuTime
+		systemCpuTime += record.SystemCpuTime
+		usedMemoryMB += record.UsedMemoryMB
+		sentKB += record.NetStat.SentKB
+		recvKB += record.NetStat.RecvKB
+		if record.ReportingServerCount > reportingServerCount {
+			reportingServerCount = record.ReportingServerCount
+		}
+	}
+	var cpuUsagePercent uint8 = 0
+	if systemCpuTime > 0 {
+		cpuUsagePercent = uint8(float64(serviceCpuTime) / float64(systemCpuTime) * 100)
+	}
+	intervalSeconds := uint64(interval.Seconds())
+	return &ApplicationServiceResourceStat{
+		ApplicationID:        records[0].ApplicationID,
+		ServiceCpuTime:       serviceCpuTime,
+		SystemCpuTime:        systemCpuTime,
+		CpuUsagePercent:      cpuUsagePercent,
+		ReportingServerCount: reportingServerCount,
+		UsedMemoryMB:         usedMemoryMB / uint64(count),
+		NetStat: ApplicationServiceNetStat{
+			SentKB:   sentKB,
+			RecvKB:   recvKB,
+			SentKBPS: sentKB / intervalSeconds,
+			RecvKBPS: recvKB / intervalSeconds,
+		},
+		RecordedAt: bucket,
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDownsampleServerResourceStats(t *testing.T) {
+	bucket := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	records := []*ServerResourceStat{
+		{ID: 1, ServerID: 1, CpuUsagePercent: 10, MemStat: ServerMemoryStat{TotalGB: 4, UsedGB: 1}, NetStat: ServerNetStat{SentKB: 600, RecvKB: 1200}, RecordedAt: bucket.Add(1 * time.Minute)},
+		{ID: 2, ServerID: 1, CpuUsagePercent: 30, MemStat: ServerMemoryStat{TotalGB: 4, UsedGB: 3}, NetStat: ServerNetStat{SentKB: 600, RecvKB: 2400}, RecordedAt: bucket.Add(59 * time.Minute),
+			DiskStats: ServerDiskStats{{Path: "/dev/sda", MountPoint: "/", TotalGB: 100, UsedGB: 10}}},
+		{ID: 3, ServerID: 1, CpuUsagePercent: 50, RecordedAt: bucket.Add(1 * time.Hour)},
+	}
+
+	t.Run("records are grouped by interval", func(t *testing.T) {
+		groups := groupServerResourceStats(records, time.Hour)
+		assert.Len(t, groups, 2)
+		assert.Len(t, groups[bucket], 2)
+		assert.Len(t, groups[bucket.Add(time.Hour)], 1)
+	})
+
+	t.Run("usages are averaged and network transfer is summed up", func(t *testing.T) {
+		aggregated := aggregateServerResourceStats(records[:2], bucket, time.Hour)
+		assert.Equal(t, uint8(20), aggregated.CpuUsagePercent)
+		assert.Equal(t, float32(2), aggregated.MemStat.UsedGB)
+		assert.Equal(t, uint64(1200), aggregated.NetStat.SentKB)
+		assert.Equal(t, uint64(3600), aggregated.NetStat.RecvKB)
+		assert.Equal(t, uint64(1), aggregated.NetStat.RecvKBPS)
+		assert.Equal(t, records[1].DiskStats, aggregated.DiskStats, "latest disk stats should be kept")
+		assert.Equal(t, bucket, aggregated.RecordedAt)
+	})
+
+	t.Run("already downsampled bucket is skipped", func(t *testing.T) {
+		assert.False(t, isDownsampleRequired(1, bucket, bucket))
+		assert.True(t, isDownsampleRequired(1, bucket.Add(time.Minute), bucket))
+		assert.True(t, isDownsampleRequired(2, bucket, bucket))
+	})
+}
+
+func TestDownsampleApplicationServiceResourceStats(t *testing.T) {
+	bucket := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	records := []*ApplicationServiceResourceStat{
+		{ApplicationID: "app", ServiceCpuTime: 10, SystemCpuTime: 100, ReportingServerCount: 1, UsedMemoryMB: 100, RecordedAt: bucket.Add(time.Minute)},
+		{ApplicationID: "app", ServiceCpuTime: 30, SystemCpuTime: 100, ReportingServerCount: 2, UsedMemoryMB: 300, RecordedAt: bucket.Add(2 * time.Minute)},
+	}
+	aggregated := aggregateApplicationServiceResourceStats(records, bucket, time.Hour)
+	assert.Equal(t, uint64(40), aggregated.ServiceCpuTime)
+	assert.Equal(t, uint64(200), aggregated.SystemCpuTime)
+	assert.Equal(t, uint8(20), aggregated.CpuUsagePercent)
+	assert.Equal(t, uint64(200), aggregated.UsedMemoryMB)
+	assert.Equal(t, uint(2), aggregated.ReportingServerCount)
+}
@@ -53,6 +53,13 @@ func FindAllApplications(_ context.Context, db gorm.DB, includeGroupedApplicatio
 	return applications, tx.Error
 }
 
+// FindApplicationsWithAutoSleepEnabled : fetch all the awake applications which have auto sleep enabled
+func FindApplicationsWithAutoSleepEnabled(_ context.Context, db gorm.DB) ([]*Application, error) {
+	var applications []*Application
+	tx := db.Where("auto_sleep_enabled = ? AND is_sleeping = ? AND is_deleted = ? AND deployment_mode = ?", true, false, false, DeploymentModeReplicated).Find(&applications)
+	return applications, tx.Error
+}
+
 type ApplicationDeploymentInfo struct {
 	ApplicationID string
 	DeploymentID  string
@@ -100,11 +107,9 @@ func (application *Application) Create(ctx context.Context, db gorm.DB, dockerMa
 		return errors.New("application name not available")
 	}
 	// check resource limits and reserved resource
-	if application.ResourceLimit.MemoryMB != 0 && application.ResourceLimit.MemoryMB < 6 {
-		return errors.New("memory limit should be at least 6 MB or 0 for unlimited")
-	}
-	if application.ReservedResource.MemoryMB != 0 && application.ReservedResource.MemoryMB < 6 {
-		return errors.New("reserved memory should be at least 6 MB or 0 for unlimited")
+	err = application.validateResources(dockerManager)
+	if err != nil {
+		return err
 	}
 	// Verify the PreferredServerHostnames
 	if len(application.PreferredServerHostnames) > 0 {
@@ -159,6 +164,29 @@ func (application *Application) Create(ctx context.Context, db gorm.DB, dockerMa
 	if application.DockerProxy.Enabled && len(application.PreferredServerHostnames) == 0 {
 		return errors.New("you need to select exactly one preferred server for getting access to docker socket proxy")
 	}
+	// Validate AutoSleep configuration
+	if err := application.AutoSleep.Validate(application.DeploymentMode); err != nil {
+		return err
+	}
+	// Validate AutoScaling configuration
+	if err := application.AutoScaling.Validate(application.DeploymentMode, application.ResourceLimit); err != nil {
+		return err
+	}
+	if application.AutoScaling.Enabled {
+		application.Replicas = application.AutoScaling.ClampReplicas(application.Replicas)
+	}
+	// Validate DeploymentStrategy configuration
+	if err := application.DeploymentStrategy.Validate(); err != nil {
+		return err
+	}
+	// Validate cron job configuration
+	if err := application.validateKind(); err != nil {
+		return err
+	}
+	// Validate release command
+	if _, err := application.ReleaseCommandArgs(); err != nil {
+		return err
+	}
 	// create application
 	createdApplication := Application{
 		ID:                       uuid.NewString(),
@@ -168,6 +196,7 @@ func (application *Application) Create(ctx context.Context, db gorm.DB, dockerMa
 		WebhookToken:             uuid.NewString(),
 		Hostname:                 application.Hostname,
 		Command:                  application.Command,
+		ReleaseCommand:           application.ReleaseCommand,
 		Capabilities:             application.Capabilities,
 		Sysctls:                  application.Sysctls,
 		ResourceLimit:            application.ResourceLimit,
@@ -176,6 +205,11 @@ func (application *Application) Create(ctx context.Context, db gorm.DB, dockerMa
 		DockerProxy:              application.DockerProxy,
 		PreferredServerHostnames: application.PreferredServerHostnames,
 		CustomHealthCheck:        application.CustomHealthCheck,
+		AutoSleep:                application.AutoSleep,
+		AutoScaling:              application.AutoScaling,
+		DeploymentStrategy:       application.DeploymentStrategy,
+		Kind:                     application.Kind,
+		CronJob:                  application.CronJob,
 	}
 	tx := db.Create(&createdApplication)
 	if tx.Error != nil {
@@ -185,9 +219,10 @@ func (application *Application) Create(ctx context.Context, db gorm.DB, dockerMa
 	createdEnvironmentVariables := make([]EnvironmentVariable, 0)
 	for _, environmentVariable := range application.EnvironmentVariables {
 		createdEnvironmentVariable := EnvironmentVariable{
-			ApplicationID: createdApplication.ID,
-			Key:           environmentVariable.Key,
-			Value:         environmentVariable.Value,
+			ApplicationID:  createdApplication.ID,
+			Key:            environmentVariable.Key,
+			Value:          environmentVariable.Value,
+			ExposeAsSecret: environmentVariable.ExposeAsSecret,
 		}
 		createdEnvironmentVariables = append(createdEnvironmentVariables, createdEnvironmentVariable)
 	}
@@ -302,7 +337,7 @@ func (application *Application) Create(ctx context.Context, db gorm.DB, dockerMa
 	return nil
 }
 
-func (application *Application) Update(ctx context.Context, db gorm.DB, _ containermanger.Manager) (*ApplicationUpdateResult, error) {
+func (application *Application) Update(ctx context.Context, db gorm.DB, dockerManager containermanger.Manager) (*ApplicationUpdateResult, error) {
 	var err error
 	// ensure that application is not deleted
 	isDeleted, err := application.IsApplicationDeleted(ctx, db)
@@ -313,11 +348,9 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		return nil, errors.New("application is deleted")
 	}
 	// check resource limits and reserved resource
-	if application.ResourceLimit.MemoryMB != 0 && application.ResourceLimit.MemoryMB < 6 {
-		return nil, errors.New("memory limit should be at least 6 MB or 0 for unlimited")
-	}
-	if application.ReservedResource.MemoryMB != 0 && application.ReservedResource.MemoryMB < 6 {
-		return nil, errors.New("reserved memory should be at least 6 MB or 0 for unlimited")
+	err = application.validateResources(dockerManager)
+	if err != nil {
+		return nil, err
 	}
 	// Verify the PreferredServerHostnames
 	if len(application.PreferredServerHostnames) > 0 {
@@ -335,6 +368,29 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 	if application.DockerProxy.Enabled && len(application.PreferredServerHostnames) != 1 {
 		return nil, errors.New("you must select preferred servers for deployment to get access to docker proxy")
 	}
+	// validate auto sleep configuration
+	if err := application.AutoSleep.Validate(application.DeploymentMode); err != nil {
+		return nil, err
+	}
+	// validate auto scaling configuration
+	if err := application.AutoScaling.Validate(application.DeploymentMode, application.ResourceLimit); err != nil {
+		return nil, err
+	}
+	if application.AutoScaling.Enabled {
+		application.Replicas = application.AutoScaling.ClampReplicas(application.Replicas)
+	}
+	// validate deployment strategy
+	if err := application.DeploymentStrategy.Validate(); err != nil {
+		return nil, err
+	}
+	// validate cron job configuration
+	if err := application.validateKind(); err != nil {
+		return nil, err
+	}
+	// validate release command
+	if _, err := application.ReleaseCommandArgs(); err != nil {
+		return nil, err
+	}
 	// status
 	isReloadRequired := false
 	// fetch application with environment variables and persistent volume bindings
@@ -353,6 +409,14 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		// reload application
 		isReloadRequired = true
 	}
+	// check if ReleaseCommand is changed
+	if applicationExistingFull.ReleaseCommand != application.ReleaseCommand {
+		// update release command, it will be used from the next deployment
+		err = db.Model(&applicationExistingFull).Update("release_command", application.ReleaseCommand).Error
+		if err != nil {
+			return nil, err
+		}
+	}
 	// check if Command is changed
 	if applicationExistingFull.Command != application.Command {
 		// update command
@@ -384,14 +448,21 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		isReloadRequired = true
 	}
 	// check if Resource limits or reservations are changed
-	if applicationExistingFull.ResourceLimit.MemoryMB != application.ResourceLimit.MemoryMB || applicationExistingFull.ReservedResource.MemoryMB != application.ReservedResource.MemoryMB {
+	if applicationExistingFull.ResourceLimit != application.ResourceLimit || applicationExistingFull.ReservedResource != application.ReservedResource {
 		// update resource limits
-		err = db.Model(&applicationExistingFull).Update("resource_limit_memory_mb", application.ResourceLimit.MemoryMB).Error
+		err = db.Model(&applicationExistingFull).Updates(map[string]interface{}{
+			"resource_limit_memory_mb":  application.ResourceLimit.MemoryMB,
+			"resource_limit_nano_cpus":  application.ResourceLimit.NanoCPUs,
+			"resource_limit_pids_limit": application.ResourceLimit.PidsLimit,
+		}).Error
 		if err != nil {
 			return nil, err
 		}
 		// update reserved resource
-		err = db.Model(&applicationExistingFull).Update("reserved_resource_memory_mb", application.ReservedResource.MemoryMB).Error
+		err = db.Model(&applicationExistingFull).Updates(map[string]interface{}{
+			"reserved_resource_memory_mb": application.ReservedResource.MemoryMB,
+			"reserved_resource_nano_cpus": application.ReservedResource.NanoCPUs,
+		}).Error
 		if err != nil {
 			return nil, err
 		}
@@ -399,19 +470,20 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		isReloadRequired = true
 	}
 	// create array of environment variables
-	var newEnvironmentVariableMap = make(map[string]string)
+	var newEnvironmentVariableMap = make(map[string]EnvironmentVariable)
 	for _, environmentVariable := range application.EnvironmentVariables {
-		newEnvironmentVariableMap[environmentVariable.Key] = environmentVariable.Value
+		newEnvironmentVariableMap[environmentVariable.Key] = environmentVariable
 	}
 	// update environment variables -- if required
 	if applicationExistingFull.EnvironmentVariables != nil {
 		for _, environmentVariable := range applicationExistingFull.EnvironmentVariables {
 			// check if environment variable is present in new environment variables
-			if _, ok := newEnvironmentVariableMap[environmentVariable.Key]; ok {
+			if newEnvironmentVariable, ok := newEnvironmentVariableMap[environmentVariable.Key]; ok {
 				// check if value is changed
-				if environmentVariable.Value != newEnvironmentVariableMap[environmentVariable.Key] {
+				if environmentVariable.Value != newEnvironmentVariable.Value || environmentVariable.ExposeAsSecret != newEnvironmentVariable.ExposeAsSecret {
 					// update environment variable
-					environmentVariable.Value = newEnvironmentVariableMap[environmentVariable.Key]
+					environmentVariable.Value = newEnvironmentVariable.Value
+					environmentVariable.ExposeAsSecret = newEnvironmentVariable.ExposeAsSecret
 					err = environmentVariable.Update(ctx, db)
 					if err != nil {
 						return nil, err
@@ -436,11 +508,12 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		}
 	}
 	// add new environment variables which are not present
-	for key, value := range newEnvironmentVariableMap {
+	for key, newEnvironmentVariable := range newEnvironmentVariableMap {
 		environmentVariable := EnvironmentVariable{
-			ApplicationID: application.ID,
-			Key:           key,
-			Value:         value,
+			ApplicationID:  application.ID,
+			Key:            key,
+			Value:          newEnvironmentVariable.Value,
+			ExposeAsSecret: newEnvironmentVariable.ExposeAsSecret,
 		}
 		err := environmentVariable.Create(ctx, db)
 		if err != nil {
@@ -592,6 +665,15 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		// reload application
 		isReloadRequired = true
 	}
+	// check if capabilities or sysctls are changed
+	if !isSameStringSet(application.Capabilities, applicationExistingFull.Capabilities) || !isSameStringSet(application.Sysctls, applicationExistingFull.Sysctls) {
+		err = db.Model(&applicationExistingFull).Select("capabilities", "sysctls").Updates(application).Error
+		if err != nil {
+			return nil, err
+		}
+		// reload application
+		isReloadRequired = true
+	}
 	// check for changes in docker proxy configuration
 	if !application.DockerProxy.Equal(&applicationExistingFull.DockerProxy) {
 		// store docker proxy configuration
@@ -623,6 +705,42 @@ func (application *Application) Update(ctx context.Context, db gorm.DB, _ contai
 		// reload application
 		isReloadRequired = true
 	}
+	// check for changes in auto sleep configuration
+	// no reload required, it's only used by the auto sleep cronjob and the wake-up handler
+	if !application.AutoSleep.Equal(&applicationExistingFull.AutoSleep) {
+		err = db.Model(&applicationExistingFull).Select("auto_sleep_enabled", "auto_sleep_idle_timeout_minutes").Updates(application).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	// check for changes in auto scaling configuration
+	// no reload required, it's only used by the auto scaling cronjob
+	if !application.AutoScaling.Equal(&applicationExistingFull.AutoScaling) {
+		err = db.Model(&applicationExistingFull).Select("auto_scaling_enabled", "auto_scaling_min_replicas", "auto_scaling_max_replicas", "auto_scaling_metric", "auto_scaling_target_percent", "auto_scaling_cooldown_seconds").Updates(application).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	// kind of application can't be changed, as the service and the runs are managed differently
+	if applicationKindOrDefault(application.Kind) != applicationKindOrDefault(applicationExistingFull.Kind) {
+		return nil, errors.New("kind of application can't be changed")
+	}
+	// check for changes in cron job configuration
+	// no reload required, it's used by the cron job scheduler
+	if application.IsCronJob() && !application.CronJob.Equal(&applicationExistingFull.CronJob) {
+		err = db.Model(&applicationExistingFull).Select("cron_job_schedule", "cron_job_concurrency_policy", "cron_job_history_limit").Updates(application).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	// check for changes in deployment strategy
+	// no reload required, it's used from the next deployment
+	if !application.DeploymentStrategy.Equal(&applicationExistingFull.DeploymentStrategy) {
+		err = db.Model(&applicationExistingFull).Select("deployment_strategy_type", "deployment_strategy_canary_weight_percent").Updates(application).Error
+		if err != nil {
+			return nil, err
+		}
+	}
 	// update deployment -- if required
 	currentDeploymentID, err := FindCurrentDeployedDeploymentIDByApplicationId(ctx, db, application.ID)
 	if err != nil {
@@ -692,16 +810,10 @@ func (application *Application) HardDelete(ctx context.Context, db gorm.DB, _ co
 		applicationGroup := &ApplicationGroup{
 			ID: *applicationGroupID,
 		}
-		isAnyApplicationAssociatedWithGroup, err := applicationGroup.IsAnyApplicationAssociatedWithGroup(ctx, db)
+		err = applicationGroup.deleteIfUnused(ctx, db)
 		if err != nil {
 			return err
 		}
-		if !isAnyApplicationAssociatedWithGroup {
-			err = applicationGroup.Delete(ctx, db)
-			if err != nil {
-				return err
-			}
-		}
 	}
 	return nil
 }
@@ -726,12 +838,9 @@ func (application *Application) RebuildApplication(ctx context.Context, db gorm.
 		return "", err
 	}
 	// create a new deployment from latest deployment
-	latestDeployment, err := FindCurrentDeployedDeploymentByApplicationId(ctx, db, application.ID)
+	latestDeployment, err := FindCurrentOrLatestDeploymentByApplicationId(ctx, db, application.ID)
 	if err != nil {
-		latestDeployment, err = FindLatestDeploymentByApplicationId(ctx, db, application.ID)
-		if err != nil {
-			return "", errors.New("failed to fetch latest deployment")
-		}
+		return "", errors.New("failed to fetch latest deployment")
 	}
 
 	// fetch build args
@@ -798,6 +907,17 @@ func (application *Application) MarkAsWake(ctx context.Context, db gorm.DB) erro
 	return tx.Error
 }
 
+// WakeUpIfSleeping : mark the application as wake only if it's sleeping
+// Returns true only for the caller which has actually woken up the application
+// so that concurrent callers don't trigger multiple deployments
+func (application *Application) WakeUpIfSleeping(_ context.Context, db gorm.DB) (bool, error) {
+	tx := db.Model(&Application{}).Where("id = ? AND is_sleeping = ?", application.ID, true).Update("is_sleeping", false)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
+
 func (application *Application) UpdateGroup(ctx context.Context, db gorm.DB, groupId *string) error {
 	err := application.FindById(ctx, db, application.ID)
 	if err != nil {
@@ -818,16 +938,97 @@ func (application *Application) UpdateGroup(ctx context.Context, db gorm.DB, gro
 		group := &ApplicationGroup{
 			ID: oldApplicationGroupID,
 		}
-		isAnyApplicationAssociatedWithGroup, err := group.IsAnyApplicationAssociatedWithGroup(ctx, db)
+		err = group.deleteIfUnused(ctx, db)
 		if err != nil {
 			return err
 		}
-		if !isAnyApplicationAssociatedWithGroup {
-			err = group.Delete(ctx, db)
-			if err != nil {
-				return err
+	}
+	return nil
+}
+
+// validateResources : verify the resource limits and reservations
+// If preferred servers are provided, the replicas should fit in the reservations of those servers
+func (application *Application) validateResources(dockerManager containermanger.Manager) error {
+	if application.ResourceLimit.MemoryMB != 0 && application.ResourceLimit.MemoryMB < 6 {
+		return errors.New("memory limit should be at least 6 MB or 0 for unlimited")
+	}
+	if application.ReservedResource.MemoryMB != 0 && application.ReservedResource.MemoryMB < 6 {
+		return errors.New("reserved memory should be at least 6 MB or 0 for unlimited")
+	}
+	// docker doesn't allow less than 0.01 cpu
+	if application.ResourceLimit.NanoCPUs != 0 && application.ResourceLimit.NanoCPUs < 1e7 {
+		return errors.New("cpu limit should be at least 0.01 CPU or 0 for unlimited")
+	}
+	if application.ReservedResource.NanoCPUs < 0 {
+		return errors.New("reserved cpu should be positive or 0 for no reservation")
+	}
+	if application.ResourceLimit.NanoCPUs != 0 && application.ReservedResource.NanoCPUs > application.ResourceLimit.NanoCPUs {
+		return errors.New("reserved cpu should not be more than the cpu limit")
+	}
+	if application.ResourceLimit.PidsLimit < 0 {
+		return errors.New("pids limit should be positive or 0 for unlimited")
+	}
+	if len(application.PreferredServerHostnames) == 0 || (application.ReservedResource.MemoryMB == 0 && application.ReservedResource.NanoCPUs == 0) {
+		return nil
+	}
+	nodeResources, err := dockerManager.NodeResources()
+	if err != nil {
+		return err
+	}
+	// replicas of the application itself are replaced, so those reservations are not counted
+	reservedResources, err := dockerManager.NodeReservedResources(application.Name, application.CanaryServiceName())
+	if err != nil {
+		return err
+	}
+	serverResources := make([]containermanger.Resource, 0)
+	for _, hostname := range application.PreferredServerHostnames {
+		if resource, ok := nodeResources[hostname]; ok {
+			serverResources = append(serverResources, availableResource(resource, reservedResources[hostname]))
+		}
+	}
+	if len(serverResources) == 0 {
+		// preferred servers are not part of swarm cluster yet, nothing to verify
+		return nil
+	}
+	return application.verifyReplicasFitOnServers(serverResources)
+}
+
+// availableResource : resources of the server left after the reservations of other services
+func availableResource(total containermanger.Resource, reserved containermanger.Resource) containermanger.Resource {
+	available := containermanger.Resource{
+		MemoryMB: total.MemoryMB - reserved.MemoryMB,
+		NanoCPUs: total.NanoCPUs - reserved.NanoCPUs,
+	}
+	if available.MemoryMB < 0 {
+		available.MemoryMB = 0
+	}
+	if available.NanoCPUs < 0 {
+		available.NanoCPUs = 0
+	}
+	return available
+}
+
+// verifyReplicasFitOnServers : check if the replicas can be scheduled on the servers by the reservations
+func (application *Application) verifyReplicasFitOnServers(serverResources []containermanger.Resource) error {
+	var capacity uint = 0
+	for _, serverResource := range serverResources {
+		replicasOnServer := uint(0)
+		if application.ReservedResource.MemoryMB > 0 {
+			replicasOnServer = uint(serverResource.MemoryMB / application.ReservedResource.MemoryMB)
+		}
+		if application.ReservedResource.NanoCPUs > 0 {
+			replicasByCPU := uint(serverResource.NanoCPUs / application.ReservedResource.NanoCPUs)
+			if application.ReservedResource.MemoryMB == 0 || replicasByCPU < replicasOnServer {
+				replicasOnServer = replicasByCPU
 			}
 		}
+		if application.DeploymentMode == DeploymentModeGlobal && replicasOnServer == 0 {
+			return errors.New("reserved resources of application doesn't fit in one of the preferred servers")
+		}
+		capacity += replicasOnServer
+	}
+	if application.DeploymentMode == DeploymentModeReplicated && application.Replicas > capacity {
+		return fmt.Errorf("only %d replicas fit in the reserved resources of preferred servers, but %d replicas are requested", capacity, application.Replicas)
 	}
 	return nil
 }
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplicationAutoScalingDesiredReplicas(t *testing.T) {
+	autoScaling := &ApplicationAutoScaling{
+		Enabled:         true,
+		MinReplicas:     2,
+		MaxReplicas:     10,
+		Metric:          AutoScalingMetricCPU,
+		TargetPercent:   50,
+		CooldownSeconds: 300,
+	}
+
+	t.Run("usage near the target keeps the replicas", func(t *testing.T) {
+		assert.Equal(t, uint(4), autoScaling.DesiredReplicas(4, 54))
+		assert.Equal(t, uint(4), autoScaling.DesiredReplicas(4, 46))
+	})
+
+	t.Run("replicas are scaled proportionally to the usage", func(t *testing.T) {
+		assert.Equal(t, uint(6), autoScaling.DesiredReplicas(4, 75))
+		assert.Equal(t, uint(3), autoScaling.DesiredReplicas(4, 30))
+	})
+
+	t.Run("replicas are bounded", func(t *testing.T) {
+		assert.Equal(t, uint(10), autoScaling.DesiredReplicas(8, 100))
+		assert.Equal(t, uint(2), autoScaling.DesiredReplicas(4, 1))
+		assert.Equal(t, uint(2), autoScaling.DesiredReplicas(0, 0))
+	})
+}
+
+func TestApplicationAutoScalingValidate(t *testing.T) {
+	valid := ApplicationAutoScaling{
+		Enabled:         true,
+		MinReplicas:     1,
+		MaxReplicas:     3,
+		Metric:          AutoScalingMetricMemory,
+		TargetPercent:   70,
+		CooldownSeconds: 300,
+	}
+	resourceLimit := ApplicationResourceLimit{MemoryMB: 512}
+	assert.NoError(t, valid.Validate(DeploymentModeReplicated, resourceLimit))
+
+	disabled := ApplicationAutoScaling{}
+	assert.NoError(t, disabled.Validate(DeploymentModeGlobal, ApplicationResourceLimit{}))
+
+	testCases := map[string]func(a *ApplicationAutoScaling){
+		"min replicas above max replicas": func(a *ApplicationAutoScaling) { a.MinReplicas = 4 },
+		"zero min replicas":               func(a *ApplicationAutoScaling) { a.MinReplicas = 0 },
+		"unknown metric":                  func(a *ApplicationAutoScaling) { a.Metric = "network" },
+		"target above 100 percent":        func(a *ApplicationAutoScaling) { a.TargetPercent = 120 },
+		"short cooldown":                  func(a *ApplicationAutoScaling) { a.CooldownSeconds = 10 },
+	}
+	for name, modify := range testCases {
+		t.Run(name, func(t *testing.T) {
+			autoScaling := valid
+			modify(&autoScaling)
+			assert.Error(t, autoScaling.Validate(DeploymentModeReplicated, resourceLimit))
+		})
+	}
+	assert.Error(t, valid.Validate(DeploymentModeGlobal, resourceLimit))
+	assert.Error(t, valid.Validate(DeploymentModeReplicated, ApplicationResourceLimit{}))
+
+	byCpu := valid
+	byCpu.Metric = AutoScalingMetricCPU
+	assert.Error(t, byCpu.Validate(DeploymentModeReplicated, resourceLimit))
+	assert.NoError(t, byCpu.Validate(DeploymentModeReplicated, ApplicationResourceLimit{NanoCPUs: 500000000}))
+}
+
+func TestApplicationAutoScalingUsagePercent(t *testing.T) {
+	application := &Application{
+		Replicas: 2,
+		ResourceLimit: ApplicationResourceLimit{
+			MemoryMB: 512,
+			NanoCPUs: 500000000, // half cpu per replica
+		},
+	}
+
+	t.Run("cpu usage is relative to the cpu limit of the replicas", func(t *testing.T) {
+		application.AutoScaling.Metric = AutoScalingMetricCPU
+		// one cpu busy for the whole minute, the limit of both the replicas
+		stats := []*ApplicationServiceResourceStat{
+			{ServiceCpuTime: 60 * 1000000000, SystemCpuTime: 8 * 60 * 1000000000, CpuUsagePercent: 12},
+			{ServiceCpuTime: 30 * 1000000000, SystemCpuTime: 8 * 60 * 1000000000, CpuUsagePercent: 6},
+		}
+		assert.InDelta(t, 75, application.AutoScalingUsagePercent(stats), 0.01)
+	})
+
+	t.Run("memory usage is relative to the memory limit of the replicas", func(t *testing.T) {
+		application.AutoScaling.Metric = AutoScalingMetricMemory
+		stats := []*ApplicationServiceResourceStat{
+			{UsedMemoryMB: 512},
+			{UsedMemoryMB: 256},
+		}
+		assert.InDelta(t, 37.5, application.AutoScalingUsagePercent(stats), 0.01)
+	})
+
+	t.Run("no stats means no usage", func(t *testing.T) {
+		assert.Equal(t, float64(0), application.AutoScalingUsagePercent(nil))
+	})
+}
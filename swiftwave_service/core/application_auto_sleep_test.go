@@ -0,0 +1,25 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplicationAutoSleepValidate(t *testing.T) {
+	t.Run("disabled auto sleep is always valid", func(t *testing.T) {
+		autoSleep := ApplicationAutoSleep{Enabled: false, IdleTimeoutMinutes: 0}
+		assert.NoError(t, autoSleep.Validate(DeploymentModeGlobal))
+	})
+
+	t.Run("global deployment can not sleep", func(t *testing.T) {
+		autoSleep := ApplicationAutoSleep{Enabled: true, IdleTimeoutMinutes: 30}
+		assert.Error(t, autoSleep.Validate(DeploymentModeGlobal))
+	})
+
+	t.Run("idle timeout should not be less than minimum", func(t *testing.T) {
+		autoSleep := ApplicationAutoSleep{Enabled: true, IdleTimeoutMinutes: MinimumAutoSleepIdleTimeoutMinutes - 1}
+		assert.Error(t, autoSleep.Validate(DeploymentModeReplicated))
+		autoSleep.IdleTimeoutMinutes = MinimumAutoSleepIdleTimeoutMinutes
+		assert.NoError(t, autoSleep.Validate(DeploymentModeReplicated))
+	})
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplicationCronJobValidate(t *testing.T) {
+	t.Run("valid cron job", func(t *testing.T) {
+		cronJob := ApplicationCronJob{Schedule: "*/15 * * * *", ConcurrencyPolicy: CronJobConcurrencyForbid, HistoryLimit: 10}
+		assert.NoError(t, cronJob.Validate())
+		cronJob.Schedule = "@daily"
+		assert.NoError(t, cronJob.Validate())
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		cronJob := ApplicationCronJob{Schedule: "* * *", ConcurrencyPolicy: CronJobConcurrencyForbid, HistoryLimit: 10}
+		assert.Error(t, cronJob.Validate())
+	})
+
+	t.Run("unknown concurrency policy and empty history are invalid", func(t *testing.T) {
+		cronJob := ApplicationCronJob{Schedule: "0 * * * *", ConcurrencyPolicy: "queue", HistoryLimit: 10}
+		assert.Error(t, cronJob.Validate())
+		cronJob.ConcurrencyPolicy = CronJobConcurrencyReplace
+		cronJob.HistoryLimit = 0
+		assert.Error(t, cronJob.Validate())
+	})
+}
+
+func TestApplicationValidateKind(t *testing.T) {
+	cronJob := ApplicationCronJob{Schedule: "0 0 * * *", ConcurrencyPolicy: CronJobConcurrencyAllow, HistoryLimit: 5}
+
+	t.Run("service doesn't require cron job config", func(t *testing.T) {
+		application := Application{Kind: ApplicationKindService, DeploymentMode: DeploymentModeGlobal}
+		assert.NoError(t, application.validateKind())
+	})
+
+	t.Run("cron job should be replicated", func(t *testing.T) {
+		application := Application{Kind: ApplicationKindCronJob, DeploymentMode: DeploymentModeGlobal, CronJob: cronJob}
+		assert.Error(t, application.validateKind())
+		application.DeploymentMode = DeploymentModeReplicated
+		assert.NoError(t, application.validateKind())
+	})
+
+	t.Run("cron job doesn't support auto sleep and parallel deployments", func(t *testing.T) {
+		application := Application{Kind: ApplicationKindCronJob, DeploymentMode: DeploymentModeReplicated, CronJob: cronJob}
+		application.AutoSleep.Enabled = true
+		assert.Error(t, application.validateKind())
+		application.AutoSleep.Enabled = false
+		application.DeploymentStrategy.Type = DeploymentStrategyCanary
+		assert.Error(t, application.validateKind())
+	})
+}
+
+func TestCronJobRunServiceName(t *testing.T) {
+	// another application can be named `<name>-run-<id>`, so the name is derived from the application id
+	run := CronJobRun{ID: 7, ApplicationID: "0d2e3f1a-5b6c-4d7e-8f90-a1b2c3d4e5f6"}
+	assert.Equal(t, "0d2e3f1a-5b6c-4d7e-8f90-a1b2c3d4e5f6-run-7", run.ServiceName())
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplicationDeploymentStrategyValidate(t *testing.T) {
+	t.Run("rolling and blue/green ignore canary weight", func(t *testing.T) {
+		strategy := ApplicationDeploymentStrategy{Type: DeploymentStrategyRolling, CanaryWeightPercent: 0}
+		assert.NoError(t, strategy.Validate())
+		strategy.Type = DeploymentStrategyBlueGreen
+		assert.NoError(t, strategy.Validate())
+		assert.Equal(t, 0, strategy.InitialTrafficPercent())
+	})
+
+	t.Run("canary weight should be a partial share of the traffic", func(t *testing.T) {
+		strategy := ApplicationDeploymentStrategy{Type: DeploymentStrategyCanary, CanaryWeightPercent: 0}
+		assert.Error(t, strategy.Validate())
+		strategy.CanaryWeightPercent = 100
+		assert.Error(t, strategy.Validate())
+		strategy.CanaryWeightPercent = 20
+		assert.NoError(t, strategy.Validate())
+		assert.Equal(t, 20, strategy.InitialTrafficPercent())
+	})
+
+	t.Run("unknown strategy is invalid", func(t *testing.T) {
+		strategy := ApplicationDeploymentStrategy{Type: "recreate"}
+		assert.Error(t, strategy.Validate())
+	})
+}
+
+func TestApplicationCanaryServiceName(t *testing.T) {
+	// another application can be named after the canary service of this one, so the name is derived from the id
+	application := Application{ID: "0d2e3f1a-5b6c-4d7e-8f90-a1b2c3d4e5f6", Name: "api"}
+	assert.Equal(t, "0d2e3f1a-5b6c-4d7e-8f90-a1b2c3d4e5f6-canary", application.CanaryServiceName())
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+)
+
+// ApplicationEventHistoryLimit : number of runtime events kept per application
+const ApplicationEventHistoryLimit = 500
+
+// DefaultApplicationEventFetchLimit : number of latest events returned if the filter has no limit
+const DefaultApplicationEventFetchLimit = 100
+
+var cronJobRunServiceNameRegex = regexp.MustCompile(`^(.+)-run-\d+$`)
+
+// ApplicationEventsTopic : pubsub topic on which the new runtime events of the application are published
+func ApplicationEventsTopic(applicationId string) string {
+	return fmt.Sprintf("application-events-%s", applicationId)
+}
+
+// FindApplicationEventsByApplicationId : fetch the latest events of the application which match the filter
+func FindApplicationEventsByApplicationId(_ context.Context, db gorm.DB, applicationId string, filter ApplicationEventFilter) ([]*ApplicationEvent, error) {
+	var events []*ApplicationEvent
+	tx := db.Where("application_id = ?", applicationId)
+	if len(filter.Types) > 0 {
+		tx = tx.Where("type IN ?", filter.Types)
+	}
+	if filter.From != nil {
+		tx = tx.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		tx = tx.Where("created_at <= ?", *filter.To)
+	}
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultApplicationEventFetchLimit
+	}
+	tx = tx.Order("id desc").Limit(int(limit)).Find(&events)
+	return events, tx.Error
+}
+
+// FindApplicationIdByServiceName : map the swarm service to the application which owns it
+// Apart from the service of the application, it can be the canary service, docker proxy, release command or a cron job run
+func FindApplicationIdByServiceName(_ context.Context, db gorm.DB, serviceName string) (string, error) {
+	candidates := []*gorm.DB{db.Where("name = ?", serviceName)}
+	for _, suffix := range []string{"-canary", "-dp", "-release"} {
+		if strings.HasSuffix(serviceName, suffix) {
+			candidates = append(candidates, db.Where("id = ?", strings.TrimSuffix(serviceName, suffix)))
+		}
+	}
+	if matches := cronJobRunServiceNameRegex.FindStringSubmatch(serviceName); matches != nil {
+		candidates = append(candidates, db.Where("id = ?", matches[1]))
+	}
+	for _, candidate := range candidates {
+		var ids []string
+		tx := candidate.Model(&Application{}).Limit(1).Pluck("id", &ids)
+		if tx.Error != nil {
+			return "", tx.Error
+		}
+		if len(ids) > 0 {
+			return ids[0], nil
+		}
+	}
+	return "", gorm.ErrRecordNotFound
+}
+
+// Create : record the event and drop the events older than the history limit
+func (event *ApplicationEvent) Create(_ context.Context, db gorm.DB) error {
+	if event.ApplicationID == "" {
+		return errors.New("application id is required")
+	}
+	err := db.Create(event).Error
+	if err != nil {
+		return err
+	}
+	var ids []uint
+	tx := db.Model(&ApplicationEvent{}).Where("application_id = ?", event.ApplicationID).Order("id desc").Offset(ApplicationEventHistoryLimit).Pluck("id", &ids)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Where("id IN ?", ids).Delete(&ApplicationEvent{}).Error
+}
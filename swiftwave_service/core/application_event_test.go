@@ -0,0 +1,39 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestFindApplicationIdByServiceName(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "applications.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Exec("CREATE TABLE applications (id text primary key, name text)").Error)
+	assert.NoError(t, db.Exec("INSERT INTO applications (id, name) VALUES (?, ?)", "0d2e3f1a", "blog").Error)
+	application := &Application{ID: "0d2e3f1a", Name: "blog"}
+	ctx := context.Background()
+
+	serviceNames := []string{
+		application.Name,
+		application.CanaryServiceName(),
+		application.DockerProxyServiceName(),
+		application.ReleaseCommandServiceName(),
+		(&CronJobRun{ID: 7, ApplicationID: application.ID}).ServiceName(),
+	}
+	for _, serviceName := range serviceNames {
+		applicationId, err := FindApplicationIdByServiceName(ctx, *db, serviceName)
+		assert.NoError(t, err, serviceName)
+		assert.Equal(t, application.ID, applicationId, serviceName)
+	}
+
+	_, err = FindApplicationIdByServiceName(ctx, *db, "other-release")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
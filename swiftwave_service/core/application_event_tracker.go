@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+)
+
+// ApplicationEventTracker converts the container events of all the servers to application events
+// Swarm replaces a failed task with a new task in the same slot, so the last container of each slot is tracked to detect restarts and rescheduling
+// Containers which are killed before they die are stopped by swarm (update, scale down, removal), so those don't generate any event
+type ApplicationEventTracker struct {
+	mutex      *sync.Mutex
+	containers map[string]*trackedContainer // <container id> -> state
+	slots      map[string]*trackedSlot      // <service name>.<slot> -> last container
+}
+
+type trackedContainer struct {
+	serverHostName string
+	killed         bool
+	oomKilled      bool
+	unhealthy      bool
+}
+
+type trackedSlot struct {
+	serverHostName string
+	containerID    string
+	// set once the container has died
+	stopped   bool
+	stoppedAt time.Time
+	failed    bool
+	reason    string
+	// set if the server of the container is unreachable
+	lost bool
+}
+
+func NewApplicationEventTracker() *ApplicationEventTracker {
+	return &ApplicationEventTracker{
+		mutex:      &sync.Mutex{},
+		containers: make(map[string]*trackedContainer),
+		slots:      make(map[string]*trackedSlot),
+	}
+}
+
+// Track : process the container event received from the server, returns the application event if any
+// Application id of the returned event is not set, it should be resolved from the service name
+func (t *ApplicationEventTracker) Track(serverHostName string, event containermanger.ContainerEvent) *ApplicationEvent {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	applicationEvent := &ApplicationEvent{
+		ServerHostName: serverHostName,
+		ServiceName:    event.ServiceName,
+		TaskID:         event.TaskID,
+		ContainerID:    event.ContainerID,
+		CreatedAt:      event.Time,
+	}
+	slotKey := event.ServiceName + "." + event.TaskSlot
+	switch event.Action {
+	case containermanger.ContainerEventStart:
+		previous, ok := t.slots[slotKey]
+		t.slots[slotKey] = &trackedSlot{
+			serverHostName: serverHostName,
+			containerID:    event.ContainerID,
+		}
+		if !ok || previous.containerID == event.ContainerID {
+			return nil
+		}
+		if previous.failed {
+			if previous.serverHostName == serverHostName {
+				applicationEvent.Type = ApplicationEventTypeRestarted
+				applicationEvent.Message = fmt.Sprintf("task restarted after the previous container %s", previous.reason)
+			} else {
+				applicationEvent.Type = ApplicationEventTypeRescheduled
+				applicationEvent.Message = fmt.Sprintf("task rescheduled from server %s after the previous container %s", previous.serverHostName, previous.reason)
+			}
+			return applicationEvent
+		}
+		if previous.lost && !previous.stopped && previous.serverHostName != serverHostName {
+			applicationEvent.Type = ApplicationEventTypeRescheduled
+			applicationEvent.Message = fmt.Sprintf("task rescheduled from server %s as the server is unreachable", previous.serverHostName)
+			return applicationEvent
+		}
+		return nil
+	case containermanger.ContainerEventKill:
+		t.container(serverHostName, event.ContainerID).killed = true
+		return nil
+	case containermanger.ContainerEventOOM:
+		t.container(serverHostName, event.ContainerID).oomKilled = true
+		applicationEvent.Type = ApplicationEventTypeOOMKilled
+		applicationEvent.Message = "container killed as it ran out of memory"
+		return applicationEvent
+	case containermanger.ContainerEventUnhealthy:
+		t.container(serverHostName, event.ContainerID).unhealthy = true
+		applicationEvent.Type = ApplicationEventTypeUnhealthy
+		applicationEvent.Message = "health check of container failed"
+		return applicationEvent
+	case containermanger.ContainerEventDie:
+		state := t.container(serverHostName, event.ContainerID)
+		delete(t.containers, event.ContainerID)
+		slot, ok := t.slots[slotKey]
+		if !ok || slot.containerID != event.ContainerID {
+			// the slot has already been taken over by a new container
+			slot = &trackedSlot{}
+		}
+		slot.stopped = true
+		slot.stoppedAt = event.Time
+		switch {
+		case state.oomKilled:
+			slot.failed = true
+			slot.reason = "was killed as it ran out of memory"
+		case state.unhealthy:
+			slot.failed = true
+			slot.reason = "was unhealthy"
+		case state.killed:
+			// stopped by swarm
+		default:
+			slot.failed = true
+			slot.reason = fmt.Sprintf("exited with code %d", event.ExitCode)
+			if event.ExitCode != 0 {
+				applicationEvent.Type = ApplicationEventTypeCrashed
+				applicationEvent.ExitCode = event.ExitCode
+				applicationEvent.Message = fmt.Sprintf("container exited with code %d", event.ExitCode)
+				return applicationEvent
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// ServerDisconnected : mark the tasks of the server as lost, so that their replacement on another server is reported as rescheduling
+func (t *ApplicationEventTracker) ServerDisconnected(serverHostName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, slot := range t.slots {
+		if slot.serverHostName == serverHostName {
+			slot.lost = true
+		}
+	}
+	for containerID, state := range t.containers {
+		if state.serverHostName == serverHostName {
+			delete(t.containers, containerID)
+		}
+	}
+}
+
+// Prune : forget the slots whose container has died before the given time, those are not going to be replaced anymore
+func (t *ApplicationEventTracker) Prune(before time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, slot := range t.slots {
+		if slot.stopped && slot.stoppedAt.Before(before) {
+			delete(t.slots, key)
+		}
+	}
+}
+
+func (t *ApplicationEventTracker) container(serverHostName string, containerID string) *trackedContainer {
+	state, ok := t.containers[containerID]
+	if !ok {
+		state = &trackedContainer{serverHostName: serverHostName}
+		t.containers[containerID] = state
+	}
+	return state
+}
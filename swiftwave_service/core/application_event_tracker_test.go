@@ -0,0 +1,74 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"testing"
+	"time"
+)
+
+func containerEvent(action containermanger.ContainerEventAction, containerID string, slot string, exitCode int) containermanger.ContainerEvent {
+	return containermanger.ContainerEvent{
+		Action:      action,
+		ContainerID: containerID,
+		ServiceName: "app",
+		TaskID:      "task-" + containerID,
+		TaskSlot:    slot,
+		ExitCode:    exitCode,
+		Time:        time.Now(),
+	}
+}
+
+func TestApplicationEventTracker(t *testing.T) {
+	t.Run("crash and restart on the same server", func(t *testing.T) {
+		tracker := NewApplicationEventTracker()
+		assert.Nil(t, tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c1", "1", 0)))
+		event := tracker.Track("server-1", containerEvent(containermanger.ContainerEventDie, "c1", "1", 2))
+		assert.NotNil(t, event)
+		assert.Equal(t, ApplicationEventTypeCrashed, event.Type)
+		assert.Equal(t, 2, event.ExitCode)
+		event = tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c2", "1", 0))
+		assert.NotNil(t, event)
+		assert.Equal(t, ApplicationEventTypeRestarted, event.Type)
+	})
+
+	t.Run("oom kill and reschedule to another server", func(t *testing.T) {
+		tracker := NewApplicationEventTracker()
+		tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c1", "1", 0))
+		event := tracker.Track("server-1", containerEvent(containermanger.ContainerEventOOM, "c1", "1", 0))
+		assert.NotNil(t, event)
+		assert.Equal(t, ApplicationEventTypeOOMKilled, event.Type)
+		// die of oom killed container is already reported
+		assert.Nil(t, tracker.Track("server-1", containerEvent(containermanger.ContainerEventDie, "c1", "1", 137)))
+		event = tracker.Track("server-2", containerEvent(containermanger.ContainerEventStart, "c2", "1", 0))
+		assert.NotNil(t, event)
+		assert.Equal(t, ApplicationEventTypeRescheduled, event.Type)
+		assert.Equal(t, "server-2", event.ServerHostName)
+	})
+
+	t.Run("containers stopped by swarm are ignored", func(t *testing.T) {
+		tracker := NewApplicationEventTracker()
+		tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c1", "1", 0))
+		assert.Nil(t, tracker.Track("server-1", containerEvent(containermanger.ContainerEventKill, "c1", "1", 0)))
+		assert.Nil(t, tracker.Track("server-1", containerEvent(containermanger.ContainerEventDie, "c1", "1", 143)))
+		assert.Nil(t, tracker.Track("server-2", containerEvent(containermanger.ContainerEventStart, "c2", "1", 0)))
+	})
+
+	t.Run("task of unreachable server is rescheduled", func(t *testing.T) {
+		tracker := NewApplicationEventTracker()
+		tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c1", "1", 0))
+		assert.Nil(t, tracker.Track("server-2", containerEvent(containermanger.ContainerEventStart, "c2", "2", 0)))
+		tracker.ServerDisconnected("server-1")
+		event := tracker.Track("server-2", containerEvent(containermanger.ContainerEventStart, "c3", "1", 0))
+		assert.NotNil(t, event)
+		assert.Equal(t, ApplicationEventTypeRescheduled, event.Type)
+	})
+
+	t.Run("stopped slots are pruned", func(t *testing.T) {
+		tracker := NewApplicationEventTracker()
+		tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c1", "1", 0))
+		tracker.Track("server-1", containerEvent(containermanger.ContainerEventDie, "c1", "1", 1))
+		tracker.Prune(time.Now().Add(time.Minute))
+		assert.Nil(t, tracker.Track("server-1", containerEvent(containermanger.ContainerEventStart, "c2", "1", 0)))
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"github.com/dgryski/trifles/uuid"
 	"gorm.io/gorm"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // This file contains the operations for the ApplicationGroup model.
@@ -23,6 +25,15 @@ func FindAllApplicationGroups(_ context.Context, db gorm.DB) ([]*ApplicationGrou
 	return groups, err
 }
 
+func FindGitOpsEnabledApplicationGroups(_ context.Context, db gorm.DB) ([]*ApplicationGroup, error) {
+	var groups []*ApplicationGroup
+	err := db.Where("git_ops_enabled = ?", true).Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
 func FindApplicationsByApplicationGroupID(_ context.Context, db gorm.DB, groupId string) ([]*Application, error) {
 	var applications []*Application
 	err := db.Model(&Application{}).Where("application_group_id = ?", groupId).Scan(&applications).Error
@@ -46,6 +57,74 @@ func (applicationGroup *ApplicationGroup) Create(_ context.Context, db gorm.DB)
 	return db.Create(applicationGroup).Error
 }
 
+func (applicationGroup *ApplicationGroup) Update(_ context.Context, db gorm.DB) error {
+	if strings.Compare(applicationGroup.Name, "") == 0 {
+		return errors.New("name cannot be blank")
+	}
+	return db.Model(applicationGroup).Select("name", "logo").Updates(applicationGroup).Error
+}
+
+// UpdateGitOps : bind the group to the stack file of git repository, or unbind if disabled
+// Sync status is reset, so that the stack file is applied on next sync
+func (applicationGroup *ApplicationGroup) UpdateGitOps(ctx context.Context, db gorm.DB) error {
+	gitOps := &applicationGroup.GitOps
+	if gitOps.Enabled {
+		if strings.TrimSpace(gitOps.RepositoryURL) == "" {
+			return errors.New("repository url cannot be blank")
+		}
+		if strings.TrimSpace(gitOps.RepositoryBranch) == "" {
+			return errors.New("repository branch cannot be blank")
+		}
+		gitOps.StackFilePath = filepath.Clean(strings.TrimPrefix(strings.TrimSpace(gitOps.StackFilePath), "/"))
+		if gitOps.StackFilePath == "." || strings.HasPrefix(gitOps.StackFilePath, "..") {
+			return errors.New("invalid stack file path")
+		}
+		if gitOps.GitCredentialID != nil {
+			gitCredential := &GitCredential{}
+			err := gitCredential.FindById(ctx, db, *gitOps.GitCredentialID)
+			if err != nil {
+				return errors.New("invalid git credential provided")
+			}
+		}
+	}
+	gitOps.SyncStatus = GitOpsSyncStatusPending
+	gitOps.LastAppliedCommit = ""
+	gitOps.SyncError = ""
+	return db.Model(applicationGroup).Select("git_ops_enabled", "git_ops_git_credential_id", "git_ops_repository_url", "git_ops_repository_branch", "git_ops_stack_file_path", "git_ops_sync_status", "git_ops_last_applied_commit", "git_ops_sync_error").Updates(applicationGroup).Error
+}
+
+// MarkGitOpsSyncPending : mark the group as waiting for the sync, the last applied commit is kept
+func (applicationGroup *ApplicationGroup) MarkGitOpsSyncPending(_ context.Context, db gorm.DB) error {
+	applicationGroup.GitOps.SyncStatus = GitOpsSyncStatusPending
+	return db.Model(applicationGroup).Select("git_ops_sync_status").Updates(applicationGroup).Error
+}
+
+// MarkGitOpsSynced : record the applied commit and stack content, drift is recorded if any change was reverted
+func (applicationGroup *ApplicationGroup) MarkGitOpsSynced(_ context.Context, db gorm.DB, commitHash string, stackContent string, driftDetails string) error {
+	now := time.Now()
+	applicationGroup.StackContent = stackContent
+	applicationGroup.GitOps.SyncStatus = GitOpsSyncStatusSynced
+	applicationGroup.GitOps.LastAppliedCommit = commitHash
+	applicationGroup.GitOps.LastSyncedAt = &now
+	applicationGroup.GitOps.SyncError = ""
+	columns := []interface{}{"stack_content", "git_ops_sync_status", "git_ops_last_applied_commit", "git_ops_last_synced_at", "git_ops_sync_error"}
+	if driftDetails != "" {
+		applicationGroup.GitOps.DriftDetectedAt = &now
+		applicationGroup.GitOps.DriftDetails = driftDetails
+		columns = append(columns, "git_ops_drift_detected_at", "git_ops_drift_details")
+	}
+	return db.Model(applicationGroup).Select(columns[0], columns[1:]...).Updates(applicationGroup).Error
+}
+
+// MarkGitOpsSyncFailed : record the reason of failure, the last applied commit is kept
+func (applicationGroup *ApplicationGroup) MarkGitOpsSyncFailed(_ context.Context, db gorm.DB, reason string) error {
+	now := time.Now()
+	applicationGroup.GitOps.SyncStatus = GitOpsSyncStatusFailed
+	applicationGroup.GitOps.LastSyncedAt = &now
+	applicationGroup.GitOps.SyncError = reason
+	return db.Model(applicationGroup).Select("git_ops_sync_status", "git_ops_last_synced_at", "git_ops_sync_error").Updates(applicationGroup).Error
+}
+
 func (applicationGroup *ApplicationGroup) Delete(_ context.Context, db gorm.DB) error {
 	// check if no application is associated with this group
 	applications, err := FindApplicationsByApplicationGroupID(context.Background(), db, applicationGroup.ID)
@@ -59,6 +138,29 @@ func (applicationGroup *ApplicationGroup) Delete(_ context.Context, db gorm.DB)
 	return db.Delete(applicationGroup).Error
 }
 
+// deleteIfUnused : delete the group once the last application is removed from it
+// Groups bound to git repository are kept, as the applications come back on next sync
+func (applicationGroup *ApplicationGroup) deleteIfUnused(ctx context.Context, db gorm.DB) error {
+	isAnyApplicationAssociatedWithGroup, err := applicationGroup.IsAnyApplicationAssociatedWithGroup(ctx, db)
+	if err != nil {
+		return err
+	}
+	if isAnyApplicationAssociatedWithGroup {
+		return nil
+	}
+	err = applicationGroup.FindById(ctx, db, applicationGroup.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if applicationGroup.GitOps.Enabled {
+		return nil
+	}
+	return applicationGroup.Delete(ctx, db)
+}
+
 func (applicationGroup *ApplicationGroup) IsAnyApplicationAssociatedWithGroup(ctx context.Context, db gorm.DB) (bool, error) {
 	// verify from database
 	var count int64
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplicationReleaseCommandArgs(t *testing.T) {
+	t.Run("quoted arguments are kept together", func(t *testing.T) {
+		application := Application{ReleaseCommand: `python manage.py shell -c "from app import migrate; migrate()"`}
+		args, err := application.ReleaseCommandArgs()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"python", "manage.py", "shell", "-c", "from app import migrate; migrate()"}, args)
+	})
+
+	t.Run("single quotes and escapes are supported", func(t *testing.T) {
+		application := Application{ReleaseCommand: `bundle exec rails runner 'puts "done"' --path=/srv/my\ app`}
+		args, err := application.ReleaseCommandArgs()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bundle", "exec", "rails", "runner", `puts "done"`, "--path=/srv/my app"}, args)
+	})
+
+	t.Run("unterminated quote is invalid", func(t *testing.T) {
+		application := Application{ReleaseCommand: `npm run "migrate`}
+		_, err := application.ReleaseCommandArgs()
+		assert.Error(t, err)
+	})
+
+	t.Run("empty command has no arguments", func(t *testing.T) {
+		application := Application{}
+		args, err := application.ReleaseCommandArgs()
+		assert.NoError(t, err)
+		assert.Empty(t, args)
+	})
+}
+
+func TestApplicationReleaseCommandServiceName(t *testing.T) {
+	// another application can be named `<name>-release`, so the name is derived from the id
+	application := Application{ID: "0d2e3f1a-5b6c-4d7e-8f90-a1b2c3d4e5f6", Name: "api"}
+	assert.Equal(t, "0d2e3f1a-5b6c-4d7e-8f90-a1b2c3d4e5f6-release", application.ReleaseCommandServiceName())
+}
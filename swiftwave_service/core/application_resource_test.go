@@ -0,0 +1,52 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"testing"
+)
+
+func TestApplicationVerifyReplicasFitOnServers(t *testing.T) {
+	servers := []containermanger.Resource{
+		{MemoryMB: 4096, NanoCPUs: 2e9},
+		{MemoryMB: 2048, NanoCPUs: 4e9},
+	}
+
+	t.Run("replicas are limited by the scarce resource of each server", func(t *testing.T) {
+		application := &Application{
+			DeploymentMode:   DeploymentModeReplicated,
+			Replicas:         4,
+			ReservedResource: ApplicationReservedResource{MemoryMB: 1024, NanoCPUs: 1e9},
+		}
+		assert.NoError(t, application.verifyReplicasFitOnServers(servers))
+		application.Replicas = 5
+		assert.Error(t, application.verifyReplicasFitOnServers(servers))
+	})
+
+	t.Run("global application should fit in every server", func(t *testing.T) {
+		application := &Application{
+			DeploymentMode:   DeploymentModeGlobal,
+			ReservedResource: ApplicationReservedResource{NanoCPUs: 3e9},
+		}
+		assert.Error(t, application.verifyReplicasFitOnServers(servers))
+		application.ReservedResource.NanoCPUs = 2e9
+		assert.NoError(t, application.verifyReplicasFitOnServers(servers))
+	})
+
+	t.Run("reservations of other services are not available", func(t *testing.T) {
+		application := &Application{
+			DeploymentMode:   DeploymentModeReplicated,
+			Replicas:         4,
+			ReservedResource: ApplicationReservedResource{MemoryMB: 1024, NanoCPUs: 1e9},
+		}
+		availableServers := []containermanger.Resource{
+			availableResource(servers[0], containermanger.Resource{MemoryMB: 1024}),
+			availableResource(servers[1], containermanger.Resource{MemoryMB: 4096, NanoCPUs: 1e9}),
+		}
+		assert.Equal(t, containermanger.Resource{MemoryMB: 3072, NanoCPUs: 2e9}, availableServers[0])
+		assert.Equal(t, containermanger.Resource{MemoryMB: 0, NanoCPUs: 3e9}, availableServers[1])
+		assert.Error(t, application.verifyReplicasFitOnServers(availableServers))
+		application.Replicas = 2
+		assert.NoError(t, application.verifyReplicasFitOnServers(availableServers))
+	})
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// AutoScalingEventHistoryLimit : number of auto scaling events kept per application
+const AutoScalingEventHistoryLimit = 100
+
+// FindApplicationsWithAutoScalingEnabled : fetch all the awake applications which have auto scaling enabled
+func FindApplicationsWithAutoScalingEnabled(_ context.Context, db gorm.DB) ([]*Application, error) {
+	var applications []*Application
+	tx := db.Where("auto_scaling_enabled = ? AND is_sleeping = ? AND is_deleted = ? AND deployment_mode = ?", true, false, false, DeploymentModeReplicated).Find(&applications)
+	return applications, tx.Error
+}
+
+func FindAutoScalingEventsByApplicationId(_ context.Context, db gorm.DB, applicationId string) ([]*AutoScalingEvent, error) {
+	var events []*AutoScalingEvent
+	tx := db.Where("application_id = ?", applicationId).Order("id desc").Find(&events)
+	return events, tx.Error
+}
+
+// FindLatestAutoScalingEventByApplicationId : returns nil if the application has never been scaled
+func FindLatestAutoScalingEventByApplicationId(_ context.Context, db gorm.DB, applicationId string) (*AutoScalingEvent, error) {
+	var events []*AutoScalingEvent
+	tx := db.Where("application_id = ?", applicationId).Order("id desc").Limit(1).Find(&events)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return events[0], nil
+}
+
+// Create : record the event and drop the events older than the history limit
+func (event *AutoScalingEvent) Create(_ context.Context, db gorm.DB) error {
+	err := db.Create(event).Error
+	if err != nil {
+		return err
+	}
+	var ids []uint
+	tx := db.Model(&AutoScalingEvent{}).Where("application_id = ?", event.ApplicationID).Order("id desc").Offset(AutoScalingEventHistoryLimit).Pluck("id", &ids)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Where("id IN ?", ids).Delete(&AutoScalingEvent{}).Error
+}
+
+// UpdateReplicas : update the replicas without triggering a new deployment, used by auto scaling
+func (application *Application) UpdateReplicas(_ context.Context, db gorm.DB, replicas uint) error {
+	tx := db.Model(&application).Update("replicas", replicas)
+	return tx.Error
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// FindCronJobApplications : fetch all the cron job applications which are not deleted
+func FindCronJobApplications(_ context.Context, db gorm.DB) ([]*Application, error) {
+	var applications []*Application
+	tx := db.Where("kind = ? AND is_deleted = ?", ApplicationKindCronJob, false).Find(&applications)
+	return applications, tx.Error
+}
+
+func FindCronJobRunsByApplicationId(_ context.Context, db gorm.DB, applicationId string) ([]*CronJobRun, error) {
+	var runs []*CronJobRun
+	// logs are fetched only for a single run, as they can be large
+	tx := db.Omit("logs").Where("application_id = ?", applicationId).Order("id desc").Find(&runs)
+	return runs, tx.Error
+}
+
+// FindActiveCronJobRunsByApplicationId : fetch the runs which are pending or running
+func FindActiveCronJobRunsByApplicationId(_ context.Context, db gorm.DB, applicationId string) ([]*CronJobRun, error) {
+	var runs []*CronJobRun
+	tx := db.Omit("logs").Where("application_id = ? AND status IN ?", applicationId, []CronJobRunStatus{CronJobRunStatusPending, CronJobRunStatusRunning}).Order("id asc").Find(&runs)
+	return runs, tx.Error
+}
+
+// DeleteOldCronJobRuns : keep only the latest `historyLimit` finished runs of the application
+func DeleteOldCronJobRuns(_ context.Context, db gorm.DB, applicationId string, historyLimit uint) error {
+	var ids []uint
+	tx := db.Model(&CronJobRun{}).Where("application_id = ? AND status IN ?", applicationId, []CronJobRunStatus{CronJobRunStatusSucceeded, CronJobRunStatusFailed, CronJobRunStatusCancelled}).Order("id desc").Offset(int(historyLimit)).Pluck("id", &ids)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Where("id IN ?", ids).Delete(&CronJobRun{}).Error
+}
+
+func (run *CronJobRun) FindById(_ context.Context, db gorm.DB, id uint) error {
+	tx := db.Where("id = ?", id).First(&run)
+	return tx.Error
+}
+
+func (run *CronJobRun) Create(_ context.Context, db gorm.DB) error {
+	run.Status = CronJobRunStatusPending
+	run.CreatedAt = time.Now()
+	tx := db.Create(&run)
+	return tx.Error
+}
+
+// MarkAsRunning : the run is not updated if it has been cancelled meanwhile
+func (run *CronJobRun) MarkAsRunning(_ context.Context, db gorm.DB) error {
+	now := time.Now()
+	tx := db.Model(&CronJobRun{}).Where("id = ? AND status = ?", run.ID, CronJobRunStatusPending).Updates(map[string]interface{}{
+		"status":     CronJobRunStatusRunning,
+		"started_at": now,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected > 0 {
+		run.Status = CronJobRunStatusRunning
+		run.StartedAt = &now
+	}
+	return nil
+}
+
+// MarkAsFinished : store the final status, exit code and logs of the run
+// The run is not updated if it has been finished already (e.g. cancelled meanwhile)
+func (run *CronJobRun) MarkAsFinished(_ context.Context, db gorm.DB, status CronJobRunStatus, exitCode int, message string, logs string) error {
+	now := time.Now()
+	tx := db.Model(&CronJobRun{}).Where("id = ? AND status IN ?", run.ID, []CronJobRunStatus{CronJobRunStatusPending, CronJobRunStatusRunning}).Updates(map[string]interface{}{
+		"status":      status,
+		"exit_code":   exitCode,
+		"message":     message,
+		"logs":        logs,
+		"finished_at": now,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected > 0 {
+		run.Status = status
+		run.ExitCode = exitCode
+		run.Message = message
+		run.Logs = logs
+		run.FinishedAt = &now
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -33,6 +34,35 @@ func FindCurrentDeployedDeploymentByApplicationId(ctx context.Context, db gorm.D
 	return deployment, nil
 }
 
+// FindCurrentOrLatestDeploymentByApplicationId : fetch the deployed deployment, or the latest one if nothing is deployed
+// The latest deployment can be a failed deployment which has been rolled back, so it's used only as a fallback
+func FindCurrentOrLatestDeploymentByApplicationId(ctx context.Context, db gorm.DB, id string) (*Deployment, error) {
+	deployment, err := FindCurrentDeployedDeploymentByApplicationId(ctx, db, id)
+	if err == nil {
+		return deployment, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return FindLatestDeploymentByApplicationId(ctx, db, id)
+}
+
+// FindCanaryDeploymentByApplicationId : fetch the blue/green or canary deployment which is waiting for promotion
+func FindCanaryDeploymentByApplicationId(ctx context.Context, db gorm.DB, id string) (*Deployment, error) {
+	var deployment = &Deployment{}
+	tx := db.Where("application_id = ? AND status = ?", id, DeploymentStatusCanary).Order("created_at desc").First(&deployment)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return deployment, nil
+}
+
+// MarkCanaryDeploymentsAsStalled : mark the canary deployments of the application as stalled, used when a new canary replaces the running one
+func MarkCanaryDeploymentsAsStalled(ctx context.Context, db gorm.DB, applicationId string) error {
+	tx := db.Model(&Deployment{}).Where("application_id = ? AND status = ?", applicationId, DeploymentStatusCanary).Update("status", DeploymentStalled)
+	return tx.Error
+}
+
 func FindLatestDeploymentIDByApplicationId(ctx context.Context, db gorm.DB, id string) (string, error) {
 	var deployment = &Deployment{}
 	tx := db.Select("id").Where("application_id = ?", id).Order("created_at desc").First(&deployment)
@@ -101,7 +131,7 @@ func (deployment *Deployment) Update(ctx context.Context, db gorm.DB) (*Deployme
 	recreationRequired := false
 	// verify all params are same
 	if deployment.UpstreamType != latestDeployment.UpstreamType ||
-		deployment.GitCredentialID != latestDeployment.GitCredentialID ||
+		!isSameID(deployment.GitCredentialID, latestDeployment.GitCredentialID) ||
 		deployment.GitProvider != latestDeployment.GitProvider ||
 		deployment.GitType != latestDeployment.GitType ||
 		deployment.GitEndpoint != latestDeployment.GitEndpoint ||
@@ -113,7 +143,7 @@ func (deployment *Deployment) Update(ctx context.Context, db gorm.DB) (*Deployme
 		deployment.CodePath != latestDeployment.CodePath ||
 		deployment.SourceCodeCompressedFileName != latestDeployment.SourceCodeCompressedFileName ||
 		deployment.DockerImage != latestDeployment.DockerImage ||
-		deployment.ImageRegistryCredentialID != latestDeployment.ImageRegistryCredentialID ||
+		!isSameID(deployment.ImageRegistryCredentialID, latestDeployment.ImageRegistryCredentialID) ||
 		deployment.Dockerfile != latestDeployment.Dockerfile {
 		recreationRequired = true
 	}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestFindCurrentOrLatestDeploymentByApplicationId(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "deployments.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&Deployment{}))
+	ctx := context.Background()
+	createdAt := time.Now()
+	addDeployment := func(id string, status DeploymentStatus) {
+		createdAt = createdAt.Add(time.Second)
+		assert.NoError(t, db.Create(&Deployment{ID: id, ApplicationID: "app", Status: status, CreatedAt: createdAt}).Error)
+	}
+
+	_, err = FindCurrentOrLatestDeploymentByApplicationId(ctx, *db, "app")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// nothing is deployed yet
+	addDeployment("first", DeploymentStatusFailed)
+	deployment, err := FindCurrentOrLatestDeploymentByApplicationId(ctx, *db, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", deployment.ID)
+
+	// failed deployment which has been rolled back is not used
+	addDeployment("second", DeploymentStatusDeployed)
+	addDeployment("third", DeploymentStatusFailed)
+	deployment, err = FindCurrentOrLatestDeploymentByApplicationId(ctx, *db, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", deployment.ID)
+}
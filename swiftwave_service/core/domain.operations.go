@@ -23,6 +23,7 @@ func FindAllDomains(_ context.Context, db gorm.DB) ([]*Domain, error) {
 	return domains, tx.Error
 }
 
+// FetchDomainsThoseWillExpire : domains with auto renew enabled, custom certificates are not included
 func FetchDomainsThoseWillExpire(_ context.Context, db gorm.DB, daysToExpire int) ([]*Domain, error) {
 	var domains []*Domain
 	tx := db.Where("ssl_status = ?", DomainSSLStatusIssued).Where("ssl_auto_renew = ?", true).Where("ssl_expired_at < ?", time.Now().AddDate(0, 0, daysToExpire)).Find(&domains)
@@ -34,8 +35,21 @@ func (domain *Domain) FindById(_ context.Context, db gorm.DB, id uint) error {
 	return tx.Error
 }
 
+func (domain *Domain) FindByName(_ context.Context, db gorm.DB, name string) error {
+	tx := db.Where("name = ?", name).First(&domain)
+	return tx.Error
+}
+
 func (domain *Domain) Create(_ context.Context, db gorm.DB) error {
-	err := domain.validateAndFillSSLInfo()
+	err := domain.DNSProvider.Validate()
+	if err != nil {
+		return err
+	}
+	err = domain.validateName()
+	if err != nil {
+		return err
+	}
+	err = domain.validateAndFillSSLInfo()
 	if err != nil {
 		return err
 	}
@@ -44,7 +58,15 @@ func (domain *Domain) Create(_ context.Context, db gorm.DB) error {
 }
 
 func (domain *Domain) Update(_ context.Context, db gorm.DB) error {
-	err := domain.validateAndFillSSLInfo()
+	err := domain.DNSProvider.Validate()
+	if err != nil {
+		return err
+	}
+	err = domain.validateName()
+	if err != nil {
+		return err
+	}
+	err = domain.validateAndFillSSLInfo()
 	if err != nil {
 		return err
 	}
@@ -132,6 +154,8 @@ func (domain *Domain) validateAndFillSSLInfo() error {
 	var sslIssuer = "Unknown Issuer"
 	if len(cert.Issuer.Organization) > 0 {
 		sslIssuer = cert.Issuer.Organization[0]
+	} else if cert.Issuer.CommonName != "" {
+		sslIssuer = cert.Issuer.CommonName
 	}
 	domain.SSLIssuer = sslIssuer
 	return nil
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func generateTestCertificate(t *testing.T, dnsNames []string, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		Issuer:       pkix.Name{Organization: []string{"Test CA"}},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	fullChain := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+	return privateKey, fullChain
+}
+
+func TestDomainValidateCustomSSL(t *testing.T) {
+	validTill := time.Now().Add(90 * 24 * time.Hour)
+
+	t.Run("valid certificate", func(t *testing.T) {
+		privateKey, fullChain := generateTestCertificate(t, []string{"app.example.com"}, validTill)
+		domain := Domain{Name: "app.example.com"}
+		assert.NoError(t, domain.ValidateCustomSSL(privateKey, fullChain))
+	})
+
+	t.Run("wildcard certificate covers subdomain", func(t *testing.T) {
+		privateKey, fullChain := generateTestCertificate(t, []string{"*.example.com"}, validTill)
+		domain := Domain{Name: "app.example.com"}
+		assert.NoError(t, domain.ValidateCustomSSL(privateKey, fullChain))
+	})
+
+	t.Run("wildcard domain needs wildcard certificate", func(t *testing.T) {
+		privateKey, fullChain := generateTestCertificate(t, []string{"app.example.com"}, validTill)
+		domain := Domain{Name: "*.example.com"}
+		assert.Error(t, domain.ValidateCustomSSL(privateKey, fullChain))
+		privateKey, fullChain = generateTestCertificate(t, []string{"*.example.com"}, validTill)
+		assert.NoError(t, domain.ValidateCustomSSL(privateKey, fullChain))
+	})
+
+	t.Run("certificate of another domain", func(t *testing.T) {
+		privateKey, fullChain := generateTestCertificate(t, []string{"other.example.com"}, validTill)
+		domain := Domain{Name: "app.example.com"}
+		assert.Error(t, domain.ValidateCustomSSL(privateKey, fullChain))
+	})
+
+	t.Run("private key of another certificate", func(t *testing.T) {
+		_, fullChain := generateTestCertificate(t, []string{"app.example.com"}, validTill)
+		otherPrivateKey, _ := generateTestCertificate(t, []string{"app.example.com"}, validTill)
+		domain := Domain{Name: "app.example.com"}
+		assert.Error(t, domain.ValidateCustomSSL(otherPrivateKey, fullChain))
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		privateKey, fullChain := generateTestCertificate(t, []string{"app.example.com"}, time.Now().Add(-time.Minute))
+		domain := Domain{Name: "app.example.com"}
+		assert.Error(t, domain.ValidateCustomSSL(privateKey, fullChain))
+	})
+
+	t.Run("issuer and expiry are extracted", func(t *testing.T) {
+		privateKey, fullChain := generateTestCertificate(t, []string{"app.example.com"}, validTill)
+		domain := Domain{Name: "app.example.com", SSLPrivateKey: privateKey, SSLFullChain: fullChain}
+		assert.NoError(t, domain.validateAndFillSSLInfo())
+		assert.Equal(t, "app.example.com", domain.SSLIssuer)
+		assert.Equal(t, validTill.Unix(), domain.SSLExpiredAt.Unix())
+	})
+}
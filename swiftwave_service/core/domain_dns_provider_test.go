@@ -0,0 +1,85 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDomainDNSProviderValidate(t *testing.T) {
+	t.Run("none is valid", func(t *testing.T) {
+		p := DomainDNSProvider{Type: DNSProviderNone}
+		assert.NoError(t, p.Validate())
+		assert.False(t, p.IsConfigured())
+	})
+	t.Run("rfc2136 requires nameserver", func(t *testing.T) {
+		p := DomainDNSProvider{Type: DNSProviderRFC2136}
+		assert.Error(t, p.Validate())
+		p.RFC2136Nameserver = "ns1.example.com"
+		assert.NoError(t, p.Validate())
+	})
+	t.Run("rfc2136 requires both tsig key name and base64 secret", func(t *testing.T) {
+		p := DomainDNSProvider{Type: DNSProviderRFC2136, RFC2136Nameserver: "ns1.example.com", RFC2136TSIGKeyName: "swiftwave"}
+		assert.Error(t, p.Validate())
+		p.RFC2136TSIGSecret = "not base64!"
+		assert.Error(t, p.Validate())
+		p.RFC2136TSIGSecret = "c2VjcmV0"
+		assert.NoError(t, p.Validate())
+	})
+	t.Run("webhook requires http url", func(t *testing.T) {
+		p := DomainDNSProvider{Type: DNSProviderWebhook, WebhookURL: "ftp://example.com"}
+		assert.Error(t, p.Validate())
+		p.WebhookURL = "https://dns-hook.example.com/acme"
+		assert.NoError(t, p.Validate())
+	})
+	t.Run("unknown provider is invalid", func(t *testing.T) {
+		p := DomainDNSProvider{Type: "route53"}
+		assert.Error(t, p.Validate())
+	})
+}
+
+func TestDomainValidateName(t *testing.T) {
+	rfc2136 := DomainDNSProvider{Type: DNSProviderRFC2136, RFC2136Nameserver: "ns1.example.com"}
+	t.Run("wildcard domain with dns provider", func(t *testing.T) {
+		domain := Domain{Name: "*.example.com", DNSProvider: rfc2136}
+		assert.True(t, domain.IsWildcard())
+		assert.NoError(t, domain.validateName())
+	})
+	t.Run("wildcard domain without dns provider", func(t *testing.T) {
+		domain := Domain{Name: "*.example.com"}
+		assert.Error(t, domain.validateName())
+	})
+	t.Run("wildcard in the middle", func(t *testing.T) {
+		domain := Domain{Name: "app.*.example.com", DNSProvider: rfc2136}
+		assert.Error(t, domain.validateName())
+	})
+	t.Run("wildcard of top level domain", func(t *testing.T) {
+		domain := Domain{Name: "*.com", DNSProvider: rfc2136}
+		assert.Error(t, domain.validateName())
+	})
+	t.Run("regular domain", func(t *testing.T) {
+		domain := Domain{Name: "app.example.com"}
+		assert.False(t, domain.IsWildcard())
+		assert.NoError(t, domain.validateName())
+	})
+}
+
+func TestDomainMatchesHost(t *testing.T) {
+	t.Run("regular domain", func(t *testing.T) {
+		domain := Domain{Name: "app.example.com"}
+		assert.True(t, domain.MatchesHost("app.example.com"))
+		assert.True(t, domain.MatchesHost("App.Example.com"))
+		assert.True(t, domain.MatchesHost("app.example.com."))
+		assert.False(t, domain.MatchesHost("sub.app.example.com"))
+		assert.False(t, domain.MatchesHost("example.com"))
+		assert.False(t, domain.MatchesHost(""))
+	})
+	t.Run("wildcard domain", func(t *testing.T) {
+		domain := Domain{Name: "*.example.com"}
+		assert.True(t, domain.MatchesHost("app.example.com"))
+		assert.True(t, domain.MatchesHost("API.example.com"))
+		assert.True(t, domain.MatchesHost("v1.api.example.com"))
+		assert.False(t, domain.MatchesHost("example.com"))
+		assert.False(t, domain.MatchesHost("app.notexample.com"))
+		assert.False(t, domain.MatchesHost("app.example.org"))
+	})
+}
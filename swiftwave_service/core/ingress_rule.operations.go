@@ -85,6 +85,9 @@ func (ingressRule *IngressRule) IsValidNewIngressRule(ctx context.Context, db go
 		if err != nil {
 			return err
 		}
+		if application.IsCronJob() {
+			return errors.New("cron job can't be exposed by ingress rule")
+		}
 	}
 
 	// validation
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// StatusCount : no of records having the status
+type StatusCount struct {
+	Status string
+	Count  int64
+}
+
+// CountDeploymentsByStatus : no of deployments grouped by status
+func CountDeploymentsByStatus(_ context.Context, db gorm.DB) ([]StatusCount, error) {
+	var counts []StatusCount
+	err := db.Model(&Deployment{}).Select("status, count(*) as count").Group("status").Scan(&counts).Error
+	return counts, err
+}
+
+// CountIngressRulesByStatus : no of ingress rules grouped by status
+func CountIngressRulesByStatus(_ context.Context, db gorm.DB) ([]StatusCount, error) {
+	var counts []StatusCount
+	err := db.Model(&IngressRule{}).Select("status, count(*) as count").Group("status").Scan(&counts).Error
+	return counts, err
+}
+
+// FetchDomainsWithIssuedSSL : domains which have a ssl certificate issued
+func FetchDomainsWithIssuedSSL(_ context.Context, db gorm.DB) ([]*Domain, error) {
+	var domains []*Domain
+	err := db.Select("id", "name", "ssl_status", "ssl_expired_at").Where("ssl_status = ?", DomainSSLStatusIssued).Find(&domains).Error
+	return domains, err
+}
+
+// FetchLatestApplicationServiceResourceStats : latest resource stats of each application
+func FetchLatestApplicationServiceResourceStats(_ context.Context, db gorm.DB) ([]*ApplicationServiceResourceStat, error) {
+	var appStats []*ApplicationServiceResourceStat
+	err := db.Raw("SELECT DISTINCT ON (application_id) * FROM application_service_resource_stats ORDER BY application_id, recorded_at DESC").Scan(&appStats).Error
+	return appStats, err
+}
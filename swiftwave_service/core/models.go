@@ -39,7 +39,7 @@ type ServerLog struct {
 type User struct {
 	ID           uint          `json:"id" gorm:"primaryKey"`
 	Username     string        `json:"username" gorm:"unique"`
-	Role         UserRole      `json:"role" gorm:"default:'user'"`
+	Role         UserRole      `json:"role" gorm:"default:'admin'"`
 	PasswordHash string        `json:"password_hash"`
 	TotpEnabled  bool          `json:"totp_enabled" gorm:"default:false"`
 	TotpSecret   string        `json:"totp_secret"`
@@ -89,6 +89,8 @@ type GitCredential struct {
 	SshPublicKey  string  `json:"ssh_public_key"`
 
 	Deployments []Deployment `json:"deployments" gorm:"foreignKey:GitCredentialID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" `
+	// secretsEncrypted - secret fields hold the encrypted values, between save and its completion
+	secretsEncrypted bool
 }
 
 // ImageRegistryCredential credential for docker image registry
@@ -98,21 +100,25 @@ type ImageRegistryCredential struct {
 	Username    string       `json:"username"`
 	Password    string       `json:"password"`
 	Deployments []Deployment `json:"deployments" gorm:"foreignKey:ImageRegistryCredentialID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// secretsEncrypted - secret fields hold the encrypted values, between save and its completion
+	secretsEncrypted bool
 }
 
 // Domain hold information about domain
 type Domain struct {
-	ID            uint            `json:"id" gorm:"primaryKey"`
-	Name          string          `json:"name" gorm:"unique"`
-	SSLStatus     DomainSSLStatus `json:"ssl_status"`
-	SSLPrivateKey string          `json:"ssl_private_key"`
-	SSLFullChain  string          `json:"ssl_full_chain"`
-	SSLIssuedAt   time.Time       `json:"ssl_issued_at"`
-	SSLExpiredAt  time.Time       `json:"ssl_expired_at"`
-	SSLIssuer     string          `json:"ssl_issuer"`
-	SslAutoRenew  bool            `json:"ssl_auto_renew" gorm:"default:false"`
-	IngressRules  []IngressRule   `json:"ingress_rules" gorm:"foreignKey:DomainID"`
-	RedirectRules []RedirectRule  `json:"redirect_rules" gorm:"foreignKey:DomainID"`
+	ID               uint              `json:"id" gorm:"primaryKey"`
+	Name             string            `json:"name" gorm:"unique"`
+	SSLStatus        DomainSSLStatus   `json:"ssl_status"`
+	SSLPrivateKey    string            `json:"ssl_private_key"`
+	SSLFullChain     string            `json:"ssl_full_chain"`
+	SSLIssuedAt      time.Time         `json:"ssl_issued_at"`
+	SSLExpiredAt     time.Time         `json:"ssl_expired_at"`
+	SSLIssuer        string            `json:"ssl_issuer"`
+	SSLACMEDirectory string            `json:"ssl_acme_directory" gorm:"column:ssl_acme_directory"` // CA which issued the certificate, empty for custom certificates
+	SslAutoRenew     bool              `json:"ssl_auto_renew" gorm:"default:false"`
+	DNSProvider      DomainDNSProvider `json:"dns_provider" gorm:"embedded;embeddedPrefix:dns_provider_"`
+	IngressRules     []IngressRule     `json:"ingress_rules" gorm:"foreignKey:DomainID"`
+	RedirectRules    []RedirectRule    `json:"redirect_rules" gorm:"foreignKey:DomainID"`
 }
 
 // IngressRuleAuthentication hold information about ingress rule authentication
@@ -159,6 +165,8 @@ type PersistentVolume struct {
 	PersistentVolumeBindings []PersistentVolumeBinding `json:"persistent_volume_bindings" gorm:"foreignKey:PersistentVolumeID"`
 	PersistentVolumeBackups  []PersistentVolumeBackup  `json:"persistent_volume_backups" gorm:"foreignKey:PersistentVolumeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	PersistentVolumeRestores []PersistentVolumeRestore `json:"persistent_volume_restores" gorm:"foreignKey:PersistentVolumeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// secretsEncrypted - secret fields hold the encrypted values, between save and its completion
+	secretsEncrypted bool
 }
 
 // PersistentVolumeBinding hold information about persistent volume binding
@@ -197,7 +205,11 @@ type EnvironmentVariable struct {
 	ID            uint   `json:"id" gorm:"primaryKey"`
 	ApplicationID string `json:"application_id"`
 	Key           string `json:"key"`
-	Value         string `json:"value"`
+	Value         string `json:"value"` // encrypted at rest
+	// ExposeAsSecret - mount the value as docker swarm secret at /run/secrets/<key> instead of passing it as environment variable
+	ExposeAsSecret bool `json:"expose_as_secret" gorm:"default:false"`
+	// secretsEncrypted - secret fields hold the encrypted values, between save and its completion
+	secretsEncrypted bool
 }
 
 // BuildArg hold information about build args
@@ -222,11 +234,13 @@ type ConfigMount struct {
 
 // ApplicationGroup hold information about application-group
 type ApplicationGroup struct {
-	ID           string        `json:"id" gorm:"primaryKey"`
-	Name         string        `json:"name"`
-	Logo         string        `json:"logo"`
-	StackContent string        `json:"stack_content"`
-	Applications []Application `json:"applications" gorm:"foreignKey:ApplicationGroupID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ID           string `json:"id" gorm:"primaryKey"`
+	Name         string `json:"name"`
+	Logo         string `json:"logo"`
+	StackContent string `json:"stack_content"`
+	// GitOps - if enabled, applications of the group are reconciled with the stack file of the git repository
+	GitOps       ApplicationGroupGitOps `json:"git_ops" gorm:"embedded;embeddedPrefix:git_ops_"`
+	Applications []Application          `json:"applications" gorm:"foreignKey:ApplicationGroupID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 }
 
 // Application hold information about application
@@ -257,6 +271,8 @@ type Application struct {
 	IngressRules []IngressRule `json:"ingress_rules" gorm:"foreignKey:ApplicationID"`
 	// Command
 	Command string `json:"command"`
+	// ReleaseCommand - if set, it runs as a one-off task with the image of the new deployment before the deployment is rolled out (e.g. database migrations)
+	ReleaseCommand string `json:"release_command"`
 	// Capabilities
 	Capabilities pq.StringArray `json:"capabilities" gorm:"type:text[]"`
 	// Sysctls
@@ -273,6 +289,22 @@ type Application struct {
 	WebhookToken string `json:"webhook_token"`
 	// Sleeping
 	IsSleeping bool `json:"is_sleeping" gorm:"default:false"`
+	// AutoSleep - if enabled, application will be put to sleep after being idle
+	AutoSleep ApplicationAutoSleep `json:"auto_sleep" gorm:"embedded;embeddedPrefix:auto_sleep_"`
+	// AutoScaling - if enabled, replicas of the application are scaled by the resource usage
+	AutoScaling ApplicationAutoScaling `json:"auto_scaling" gorm:"embedded;embeddedPrefix:auto_scaling_"`
+	// AutoScalingEvents - history of the replicas changed by auto scaling
+	AutoScalingEvents []AutoScalingEvent `json:"auto_scaling_events" gorm:"foreignKey:ApplicationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	// Events - timeline of the runtime events of the containers (crash, oom kill, restart, ...)
+	Events []ApplicationEvent `json:"events" gorm:"foreignKey:ApplicationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	// DeploymentStrategy - rolling update (default), blue/green or canary
+	DeploymentStrategy ApplicationDeploymentStrategy `json:"deployment_strategy" gorm:"embedded;embeddedPrefix:deployment_strategy_"`
+	// Kind - long-running service (default) or cron job
+	Kind ApplicationKind `json:"kind" gorm:"default:'service'"`
+	// CronJob - schedule of the application, only used if Kind is cron job
+	CronJob ApplicationCronJob `json:"cron_job" gorm:"embedded;embeddedPrefix:cron_job_"`
+	// CronJobRuns - runs of the cron job
+	CronJobRuns []CronJobRun `json:"cron_job_runs" gorm:"foreignKey:ApplicationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	// Resource Stats
 	ResourceStats []ApplicationServiceResourceStat `json:"resource_stats" gorm:"foreignKey:ApplicationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	// PreferredServerHostnames - if set, we will schedule deployments to this server
@@ -283,6 +315,47 @@ type Application struct {
 	DockerProxy DockerProxyConfig `json:"docker_proxy" gorm:"embedded;embeddedPrefix:docker_proxy_"`
 }
 
+// CronJobRun hold information about a run of cron job application
+type CronJobRun struct {
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	ApplicationID string            `json:"application_id"`
+	DeploymentID  string            `json:"deployment_id"`
+	Trigger       CronJobRunTrigger `json:"trigger"`
+	Status        CronJobRunStatus  `json:"status"`
+	ExitCode      int               `json:"exit_code"`
+	Message       string            `json:"message"`
+	Logs          string            `json:"logs"`
+	CreatedAt     time.Time         `json:"created_at"`
+	StartedAt     *time.Time        `json:"started_at"`
+	FinishedAt    *time.Time        `json:"finished_at"`
+}
+
+// AutoScalingEvent hold information about a change of replicas by auto scaling
+type AutoScalingEvent struct {
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	ApplicationID string            `json:"application_id"`
+	FromReplicas  uint              `json:"from_replicas"`
+	ToReplicas    uint              `json:"to_replicas"`
+	Metric        AutoScalingMetric `json:"metric"`
+	UsagePercent  uint              `json:"usage_percent"`
+	TargetPercent uint              `json:"target_percent"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// ApplicationEvent hold information about a runtime event of a container of application
+type ApplicationEvent struct {
+	ID             uint                 `json:"id" gorm:"primaryKey"`
+	ApplicationID  string               `json:"application_id" gorm:"index"`
+	Type           ApplicationEventType `json:"type"`
+	ServerHostName string               `json:"server_host_name"`
+	ServiceName    string               `json:"service_name"`
+	TaskID         string               `json:"task_id"`
+	ContainerID    string               `json:"container_id"`
+	ExitCode       int                  `json:"exit_code"`
+	Message        string               `json:"message"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
 // Deployment hold information about deployment of application
 type Deployment struct {
 	ID            string       `json:"id" gorm:"primaryKey"`
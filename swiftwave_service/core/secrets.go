@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
+	"gorm.io/gorm"
+)
+
+// Secret fields are encrypted with the master key by the GORM hooks before they are stored, and decrypted once they are loaded
+// Raw column updates (e.g. db.Update("password", value)) bypass the hooks, so update the secret fields through the model only
+// Whether the fields hold the encrypted values is tracked by the record, as a plaintext value can look like an encrypted one
+
+func (e *EnvironmentVariable) BeforeSave(_ *gorm.DB) error {
+	return encryptSecretFields(&e.secretsEncrypted, &e.Value)
+}
+
+func (e *EnvironmentVariable) AfterSave(_ *gorm.DB) error {
+	return decryptSecretFields(&e.secretsEncrypted, &e.Value)
+}
+
+func (e *EnvironmentVariable) AfterFind(_ *gorm.DB) error {
+	// loaded values are always encrypted
+	e.secretsEncrypted = true
+	return decryptSecretFields(&e.secretsEncrypted, &e.Value)
+}
+
+func (gitCredential *GitCredential) BeforeSave(_ *gorm.DB) error {
+	return encryptSecretFields(&gitCredential.secretsEncrypted, &gitCredential.Password, &gitCredential.SshPrivateKey)
+}
+
+func (gitCredential *GitCredential) AfterSave(_ *gorm.DB) error {
+	return decryptSecretFields(&gitCredential.secretsEncrypted, &gitCredential.Password, &gitCredential.SshPrivateKey)
+}
+
+func (gitCredential *GitCredential) AfterFind(_ *gorm.DB) error {
+	// loaded values are always encrypted
+	gitCredential.secretsEncrypted = true
+	return decryptSecretFields(&gitCredential.secretsEncrypted, &gitCredential.Password, &gitCredential.SshPrivateKey)
+}
+
+func (imageRegistryCredential *ImageRegistryCredential) BeforeSave(_ *gorm.DB) error {
+	return encryptSecretFields(&imageRegistryCredential.secretsEncrypted, &imageRegistryCredential.Password)
+}
+
+func (imageRegistryCredential *ImageRegistryCredential) AfterSave(_ *gorm.DB) error {
+	return decryptSecretFields(&imageRegistryCredential.secretsEncrypted, &imageRegistryCredential.Password)
+}
+
+func (imageRegistryCredential *ImageRegistryCredential) AfterFind(_ *gorm.DB) error {
+	// loaded values are always encrypted
+	imageRegistryCredential.secretsEncrypted = true
+	return decryptSecretFields(&imageRegistryCredential.secretsEncrypted, &imageRegistryCredential.Password)
+}
+
+func (persistentVolume *PersistentVolume) BeforeSave(_ *gorm.DB) error {
+	return encryptSecretFields(&persistentVolume.secretsEncrypted, &persistentVolume.CIFSConfig.Password)
+}
+
+func (persistentVolume *PersistentVolume) AfterSave(_ *gorm.DB) error {
+	return decryptSecretFields(&persistentVolume.secretsEncrypted, &persistentVolume.CIFSConfig.Password)
+}
+
+func (persistentVolume *PersistentVolume) AfterFind(_ *gorm.DB) error {
+	// loaded values are always encrypted
+	persistentVolume.secretsEncrypted = true
+	return decryptSecretFields(&persistentVolume.secretsEncrypted, &persistentVolume.CIFSConfig.Password)
+}
+
+// secretColumn : column of a table which holds a secret
+type secretColumn struct {
+	table  string
+	column string
+}
+
+// secretColumns : all the columns encrypted at rest, keep it in sync with the hooks
+var secretColumns = []secretColumn{
+	{table: "environment_variables", column: "value"},
+	{table: "git_credentials", column: "password"},
+	{table: "git_credentials", column: "ssh_private_key"},
+	{table: "image_registry_credentials", column: "password"},
+	{table: "persistent_volumes", column: "cifs_config_password"},
+	{table: "system_configs", column: "persistent_volume_backup_config_s3_backup_access_key_id"},
+	{table: "system_configs", column: "persistent_volume_backup_config_s3_backup_secret_access_key"},
+}
+
+// EncryptSecrets : encrypt the secrets stored as plaintext or with a previous master key with the primary key of the keyring
+// It's run after the database migration to encrypt the existing rows, and on master key rotation
+// Returns the no of values encrypted
+func EncryptSecrets(_ context.Context, db gorm.DB, keyring *secretmanager.Keyring) (int, error) {
+	count := 0
+	for _, secret := range secretColumns {
+		var rows []struct {
+			ID    uint
+			Value string
+		}
+		err := db.Table(secret.table).Select("id, " + secret.column + " AS value").Where(secret.column + " IS NOT NULL AND " + secret.column + " <> ''").Scan(&rows).Error
+		if err != nil {
+			return count, err
+		}
+		for _, row := range rows {
+			if keyring.IsEncryptedWithPrimaryKey(row.Value) {
+				continue
+			}
+			plaintext, err := keyring.Decrypt(row.Value)
+			if err != nil {
+				return count, fmt.Errorf("failed to decrypt %s.%s of record %d > %s", secret.table, secret.column, row.ID, err.Error())
+			}
+			encrypted, err := keyring.Encrypt(plaintext)
+			if err != nil {
+				return count, err
+			}
+			err = db.Table(secret.table).Where("id = ?", row.ID).UpdateColumn(secret.column, encrypted).Error
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// encryptSecretFields : encrypt the fields, unless they are already encrypted by a previous save which failed
+func encryptSecretFields(encrypted *bool, fields ...*string) error {
+	if *encrypted {
+		return nil
+	}
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value, err := secretmanager.Encrypt(*field)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	for i, field := range fields {
+		*field = values[i]
+	}
+	*encrypted = true
+	return nil
+}
+
+// decryptSecretFields : decrypt the fields, if they hold the encrypted values
+func decryptSecretFields(encrypted *bool, fields ...*string) error {
+	if !*encrypted {
+		return nil
+	}
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value, err := secretmanager.Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	for i, field := range fields {
+		*field = values[i]
+	}
+	*encrypted = false
+	return nil
+}
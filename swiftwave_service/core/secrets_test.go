@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newSecretsTestKeyring(t *testing.T, previous ...[]byte) (*secretmanager.Keyring, []byte) {
+	key, err := secretmanager.GenerateKey()
+	assert.NoError(t, err)
+	keyring, err := secretmanager.NewKeyring(key, previous...)
+	assert.NoError(t, err)
+	return keyring, key
+}
+
+func newSecretsTestDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "secrets.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&EnvironmentVariable{}, &GitCredential{}, &ImageRegistryCredential{}, &PersistentVolume{}))
+	// system config belongs to another package, only its secret columns are required
+	assert.NoError(t, db.Exec("CREATE TABLE system_configs (id integer primary key, persistent_volume_backup_config_s3_backup_access_key_id text, persistent_volume_backup_config_s3_backup_secret_access_key text)").Error)
+	return db
+}
+
+func rawColumnValue(t *testing.T, db *gorm.DB, table string, column string, id uint) string {
+	var value string
+	assert.NoError(t, db.Table(table).Select(column).Where("id = ?", id).Row().Scan(&value))
+	return value
+}
+
+func TestSecretFieldHooks(t *testing.T) {
+	keyring, _ := newSecretsTestKeyring(t)
+	secretmanager.UseKeyring(keyring)
+	defer secretmanager.UseKeyring(nil)
+	db := newSecretsTestDb(t)
+
+	t.Run("values are encrypted at rest and decrypted on load", func(t *testing.T) {
+		variable := &EnvironmentVariable{ApplicationID: "app", Key: "DB_PASSWORD", Value: "s3cr3t"}
+		assert.NoError(t, db.Create(variable).Error)
+		assert.Equal(t, "s3cr3t", variable.Value, "value should be decrypted back after save")
+		stored := rawColumnValue(t, db, "environment_variables", "value", variable.ID)
+		assert.True(t, keyring.IsEncryptedWithPrimaryKey(stored))
+		assert.NotContains(t, stored, "s3cr3t")
+
+		var loaded EnvironmentVariable
+		assert.NoError(t, db.First(&loaded, variable.ID).Error)
+		assert.Equal(t, "s3cr3t", loaded.Value)
+
+		loaded.Value = "updated"
+		assert.NoError(t, db.Save(&loaded).Error)
+		var reloaded []EnvironmentVariable
+		assert.NoError(t, db.Where("id = ?", variable.ID).Find(&reloaded).Error)
+		if assert.Len(t, reloaded, 1) {
+			assert.Equal(t, "updated", reloaded[0].Value)
+		}
+	})
+
+	t.Run("plaintext which looks encrypted is encrypted too", func(t *testing.T) {
+		credential := &GitCredential{Name: "git", Password: "enc:v1:not-really-encrypted", SshPrivateKey: ""}
+		assert.NoError(t, db.Create(credential).Error)
+		stored := rawColumnValue(t, db, "git_credentials", "password", credential.ID)
+		assert.NotEqual(t, "enc:v1:not-really-encrypted", stored)
+		var loaded GitCredential
+		assert.NoError(t, db.First(&loaded, credential.ID).Error)
+		assert.Equal(t, "enc:v1:not-really-encrypted", loaded.Password)
+		assert.Equal(t, "", loaded.SshPrivateKey)
+	})
+
+	t.Run("values are encrypted once if the save is retried", func(t *testing.T) {
+		credential := &ImageRegistryCredential{Url: "registry.local", Password: "s3cr3t"}
+		// save failed after the values were encrypted
+		assert.NoError(t, credential.BeforeSave(db))
+		assert.NoError(t, db.Create(credential).Error)
+		assert.Equal(t, "s3cr3t", credential.Password)
+		var loaded ImageRegistryCredential
+		assert.NoError(t, db.First(&loaded, credential.ID).Error)
+		assert.Equal(t, "s3cr3t", loaded.Password)
+	})
+
+	t.Run("embedded secret field is encrypted", func(t *testing.T) {
+		volume := &PersistentVolume{Name: "data", Type: PersistentVolumeTypeCIFS, CIFSConfig: CIFSConfig{Password: "s3cr3t"}}
+		assert.NoError(t, db.Create(volume).Error)
+		assert.True(t, keyring.IsEncryptedWithPrimaryKey(rawColumnValue(t, db, "persistent_volumes", "cifs_config_password", volume.ID)))
+		var loaded PersistentVolume
+		assert.NoError(t, db.First(&loaded, volume.ID).Error)
+		assert.Equal(t, "s3cr3t", loaded.CIFSConfig.Password)
+	})
+}
+
+func TestEncryptSecrets(t *testing.T) {
+	db := newSecretsTestDb(t)
+	// rows stored before the encryption was enabled
+	assert.NoError(t, db.Create(&EnvironmentVariable{ApplicationID: "app", Key: "TOKEN", Value: "plain-token"}).Error)
+	assert.NoError(t, db.Create(&GitCredential{Name: "git", Password: "plain-password", SshPrivateKey: "plain-key"}).Error)
+	assert.NoError(t, db.Exec("INSERT INTO system_configs (id, persistent_volume_backup_config_s3_backup_access_key_id, persistent_volume_backup_config_s3_backup_secret_access_key) VALUES (1, 'access-key', '')").Error)
+
+	oldKeyring, oldKey := newSecretsTestKeyring(t)
+	count, err := EncryptSecrets(context.Background(), *db, oldKeyring)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count, "empty values should be skipped")
+	assert.True(t, oldKeyring.IsEncryptedWithPrimaryKey(rawColumnValue(t, db, "git_credentials", "password", 1)))
+	assert.Equal(t, "", rawColumnValue(t, db, "system_configs", "persistent_volume_backup_config_s3_backup_secret_access_key", 1))
+
+	// already encrypted values are skipped
+	count, err = EncryptSecrets(context.Background(), *db, oldKeyring)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// rotation re-encrypts with the new primary key
+	newKeyring, _ := newSecretsTestKeyring(t, oldKey)
+	count, err = EncryptSecrets(context.Background(), *db, newKeyring)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	secretmanager.UseKeyring(newKeyring)
+	defer secretmanager.UseKeyring(nil)
+	var variable EnvironmentVariable
+	assert.NoError(t, db.First(&variable).Error)
+	assert.Equal(t, "plain-token", variable.Value)
+	var credential GitCredential
+	assert.NoError(t, db.First(&credential).Error)
+	assert.Equal(t, "plain-password", credential.Password)
+	assert.Equal(t, "plain-key", credential.SshPrivateKey)
+	assert.True(t, newKeyring.IsEncryptedWithPrimaryKey(rawColumnValue(t, db, "system_configs", "persistent_volume_backup_config_s3_backup_access_key_id", 1)))
+}
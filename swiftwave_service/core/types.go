@@ -3,6 +3,7 @@ package core
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 )
 
 // ************************************************************************************* //
@@ -20,6 +21,24 @@ const (
 	ManagerRole UserRole = "manager"
 )
 
+// IsValid : check if the role is a known role
+func (r UserRole) IsValid() bool {
+	return r == AdministratorRole || r == ManagerRole
+}
+
+// HasPermission : check if the role is allowed to perform an operation that requires the given role
+// Administrator can perform any operation, manager can perform only operations that require manager role
+func (r UserRole) HasPermission(requiredRole UserRole) bool {
+	switch r {
+	case AdministratorRole:
+		return true
+	case ManagerRole:
+		return requiredRole == ManagerRole
+	default:
+		return false
+	}
+}
+
 // ServerStatus : status of the server
 type ServerStatus string
 
@@ -76,6 +95,15 @@ const (
 	DomainSSLStatusIssued  DomainSSLStatus = "issued"
 )
 
+// DNSProviderType : provider to manage the TXT records for dns-01 challenge
+type DNSProviderType string
+
+const (
+	DNSProviderNone    DNSProviderType = "none"
+	DNSProviderRFC2136 DNSProviderType = "rfc2136"
+	DNSProviderWebhook DNSProviderType = "webhook"
+)
+
 // DeploymentStatus : status of the deployment
 type DeploymentStatus string
 
@@ -86,6 +114,8 @@ const (
 	DeploymentStatusStopped       DeploymentStatus = "stopped"
 	DeploymentStatusFailed        DeploymentStatus = "failed"
 	DeploymentStalled             DeploymentStatus = "stalled"
+	// DeploymentStatusCanary : running in parallel of the deployed deployment (blue/green or canary), waiting to be promoted or aborted
+	DeploymentStatusCanary DeploymentStatus = "canary"
 )
 
 // GitType type of git credential
@@ -150,6 +180,87 @@ const (
 	DeploymentModeGlobal     DeploymentMode = "global"
 )
 
+// ApplicationKind : kind of application
+type ApplicationKind string
+
+const (
+	// ApplicationKindService : long-running replicated or global service
+	ApplicationKindService ApplicationKind = "service"
+	// ApplicationKindCronJob : runs to completion on a cron schedule
+	ApplicationKindCronJob ApplicationKind = "cron_job"
+)
+
+// CronJobConcurrencyPolicy : what to do when a run is due while the previous run is still running
+type CronJobConcurrencyPolicy string
+
+const (
+	// CronJobConcurrencyAllow : start the new run along with the running one
+	CronJobConcurrencyAllow CronJobConcurrencyPolicy = "allow"
+	// CronJobConcurrencyForbid : skip the new run
+	CronJobConcurrencyForbid CronJobConcurrencyPolicy = "forbid"
+	// CronJobConcurrencyReplace : cancel the running one and start the new run
+	CronJobConcurrencyReplace CronJobConcurrencyPolicy = "replace"
+)
+
+// CronJobRunStatus : status of a run of cron job
+type CronJobRunStatus string
+
+const (
+	CronJobRunStatusPending   CronJobRunStatus = "pending"
+	CronJobRunStatusRunning   CronJobRunStatus = "running"
+	CronJobRunStatusSucceeded CronJobRunStatus = "succeeded"
+	CronJobRunStatusFailed    CronJobRunStatus = "failed"
+	CronJobRunStatusCancelled CronJobRunStatus = "cancelled"
+)
+
+// ApplicationEventType : runtime event of the containers of application
+type ApplicationEventType string
+
+const (
+	ApplicationEventTypeCrashed     ApplicationEventType = "crashed"     // container exited with non-zero exit code
+	ApplicationEventTypeOOMKilled   ApplicationEventType = "oom_killed"  // container killed as it ran out of memory
+	ApplicationEventTypeUnhealthy   ApplicationEventType = "unhealthy"   // health check of container failed
+	ApplicationEventTypeRestarted   ApplicationEventType = "restarted"   // failed task replaced on the same server
+	ApplicationEventTypeRescheduled ApplicationEventType = "rescheduled" // failed or lost task replaced on another server
+)
+
+// ApplicationEventFilter : filter the application events, zero values are ignored
+type ApplicationEventFilter struct {
+	Types []ApplicationEventType
+	From  *time.Time
+	To    *time.Time
+	Limit uint
+}
+
+// CronJobRunTrigger : what started the run
+type CronJobRunTrigger string
+
+const (
+	CronJobRunTriggerSchedule CronJobRunTrigger = "schedule"
+	CronJobRunTriggerManual   CronJobRunTrigger = "manual"
+)
+
+// DeploymentStrategyType : how a new deployment replaces the deployed one
+type DeploymentStrategyType string
+
+const (
+	// DeploymentStrategyRolling : update the service in place
+	DeploymentStrategyRolling DeploymentStrategyType = "rolling"
+	// DeploymentStrategyBlueGreen : start the new deployment in parallel without any traffic, switch all traffic on promotion
+	DeploymentStrategyBlueGreen DeploymentStrategyType = "blue_green"
+	// DeploymentStrategyCanary : start the new deployment in parallel and route a percentage of the traffic to it till promotion
+	DeploymentStrategyCanary DeploymentStrategyType = "canary"
+)
+
+// GitOpsSyncStatus : status of the last sync of application group from git repository
+type GitOpsSyncStatus string
+
+const (
+	GitOpsSyncStatusPending GitOpsSyncStatus = "pending"
+	GitOpsSyncStatusSynced  GitOpsSyncStatus = "synced"
+	GitOpsSyncStatusFailed  GitOpsSyncStatus = "failed"
+)
+
 // ApplicationUpdateResult : result of application update
 type ApplicationUpdateResult struct {
 	RebuildRequired bool
@@ -207,21 +318,21 @@ const (
 
 // NFSConfig : configuration for NFS Storage
 type NFSConfig struct {
-	Host    string `json:"host,omitempty"`
-	Path    string `json:"path,omitempty"`
-	Version int    `json:"version,omitempty"`
+	Host    string `json:"host,omitempty" yaml:"host,omitempty"`
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Version int    `json:"version,omitempty" yaml:"version,omitempty"`
 }
 
 // CIFSConfig : configuration for CIFS Storage
 type CIFSConfig struct {
-	Share    string `json:"share"`
-	Host     string `json:"host"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	FileMode string `json:"file_mode"`
-	DirMode  string `json:"dir_mode"`
-	Uid      int    `json:"uid" gorm:"default:0"`
-	Gid      int    `json:"gid" gorm:"default:0"`
+	Share    string `json:"share" yaml:"share"`
+	Host     string `json:"host" yaml:"host"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password,omitempty"`
+	FileMode string `json:"file_mode" yaml:"file_mode"`
+	DirMode  string `json:"dir_mode" yaml:"dir_mode"`
+	Uid      int    `json:"uid" yaml:"uid" gorm:"default:0"`
+	Gid      int    `json:"gid" yaml:"gid" gorm:"default:0"`
 }
 
 var RequiredServerDependencies = []string{
@@ -339,21 +450,115 @@ type ApplicationServiceNetStat struct {
 // ************************************************************************************* //
 
 type ApplicationResourceLimit struct {
-	MemoryMB int `json:"memory_mb" gorm:"default:0"`
+	MemoryMB int `json:"memory_mb" yaml:"memory_mb" gorm:"default:0"`
+	// NanoCPUs - cpu limit in units of 10^-9 CPUs, 0 for unlimited
+	NanoCPUs int64 `json:"nano_cpus" yaml:"nano_cpus" gorm:"default:0"`
+	// PidsLimit - maximum number of processes in the container, 0 for unlimited
+	PidsLimit int64 `json:"pids_limit" yaml:"pids_limit" gorm:"default:0"`
 }
 
 type ApplicationReservedResource struct {
-	MemoryMB int `json:"memory_mb" gorm:"default:0"`
+	MemoryMB int `json:"memory_mb" yaml:"memory_mb" gorm:"default:0"`
+	// NanoCPUs - reserved cpu in units of 10^-9 CPUs, 0 for no reservation
+	NanoCPUs int64 `json:"nano_cpus" yaml:"nano_cpus" gorm:"default:0"`
+}
+
+// ApplicationAutoSleep - put the application to sleep once it has not received any request for IdleTimeoutMinutes
+// The application will be woken up automatically on the next http request
+type ApplicationAutoSleep struct {
+	Enabled            bool   `json:"enabled" yaml:"enabled" gorm:"default:false"`
+	IdleTimeoutMinutes uint64 `json:"idle_timeout_minutes" yaml:"idle_timeout_minutes" gorm:"default:30"`
+}
+
+// AutoScalingMetric - resource usage used to decide the replicas of the application
+type AutoScalingMetric string
+
+const (
+	// AutoScalingMetricCPU - average cpu usage of the replicas, in percentage of the cpu limit
+	AutoScalingMetricCPU AutoScalingMetric = "cpu"
+	// AutoScalingMetricMemory - average memory usage of the replicas, in percentage of the memory limit
+	AutoScalingMetricMemory AutoScalingMetric = "memory"
+)
+
+// ApplicationAutoScaling - scale the replicas between MinReplicas and MaxReplicas to keep the average usage near TargetPercent
+// After scaling, the application is not scaled again till CooldownSeconds has elapsed
+type ApplicationAutoScaling struct {
+	Enabled         bool              `json:"enabled" yaml:"enabled" gorm:"default:false"`
+	MinReplicas     uint              `json:"min_replicas" yaml:"min_replicas" gorm:"default:1"`
+	MaxReplicas     uint              `json:"max_replicas" yaml:"max_replicas" gorm:"default:1"`
+	Metric          AutoScalingMetric `json:"metric" yaml:"metric" gorm:"default:'cpu'"`
+	TargetPercent   uint              `json:"target_percent" yaml:"target_percent" gorm:"default:70"`
+	CooldownSeconds uint64            `json:"cooldown_seconds" yaml:"cooldown_seconds" gorm:"default:300"`
+}
+
+// ApplicationDeploymentStrategy - strategy used to roll out new deployments of the application
+type ApplicationDeploymentStrategy struct {
+	Type DeploymentStrategyType `json:"type" yaml:"type" gorm:"default:'rolling'"`
+	// CanaryWeightPercent - percentage of the traffic routed to the new deployment, only used by canary strategy
+	CanaryWeightPercent uint `json:"canary_weight_percent" yaml:"canary_weight_percent" gorm:"default:10"`
+}
+
+// ApplicationCronJob - schedule of the cron job application
+type ApplicationCronJob struct {
+	// Schedule - standard 5-field cron expression, evaluated in the timezone of the management server
+	Schedule          string                   `json:"schedule" yaml:"schedule"`
+	ConcurrencyPolicy CronJobConcurrencyPolicy `json:"concurrency_policy" yaml:"concurrency_policy" gorm:"default:'forbid'"`
+	// HistoryLimit - no of finished runs to keep
+	HistoryLimit uint `json:"history_limit" yaml:"history_limit" gorm:"default:10"`
 }
 
+// ApplicationGroupGitOps - git repository of the stack file, applications of the group are continuously reconciled with it
+type ApplicationGroupGitOps struct {
+	Enabled          bool   `json:"enabled" gorm:"default:false"`
+	GitCredentialID  *uint  `json:"git_credential_id"`
+	RepositoryURL    string `json:"repository_url"`
+	RepositoryBranch string `json:"repository_branch"`
+	// StackFilePath - path of the stack file, relative to the root of the repository
+	StackFilePath string           `json:"stack_file_path"`
+	SyncStatus    GitOpsSyncStatus `json:"sync_status" gorm:"default:'pending'"`
+	// LastAppliedCommit - commit hash of the stack file which is applied last time
+	LastAppliedCommit string     `json:"last_applied_commit"`
+	LastSyncedAt      *time.Time `json:"last_synced_at"`
+	// SyncError - reason of the last failed sync
+	SyncError string `json:"sync_error"`
+	// DriftDetectedAt - last time the applications were found modified outside of the repository, the changes are reverted by the sync
+	DriftDetectedAt *time.Time `json:"drift_detected_at"`
+	// DriftDetails - reverted changes of the last drift
+	DriftDetails string `json:"drift_details"`
+}
+
+// DomainDNSProvider - credentials of the dns provider of the domain
+// If configured, SSL certificate is issued by dns-01 challenge instead of http-01
+// It's required for wildcard domains
+type DomainDNSProvider struct {
+	Type DNSProviderType `json:"type" yaml:"type" gorm:"default:'none'"`
+	// RFC2136 (dynamic updates)
+	RFC2136Nameserver    string `json:"rfc2136_nameserver" yaml:"rfc2136_nameserver"`
+	RFC2136TSIGKeyName   string `json:"rfc2136_tsig_key_name" yaml:"rfc2136_tsig_key_name"`
+	RFC2136TSIGSecret    string `json:"rfc2136_tsig_secret" yaml:"rfc2136_tsig_secret,omitempty"`
+	RFC2136TSIGAlgorithm string `json:"rfc2136_tsig_algorithm" yaml:"rfc2136_tsig_algorithm"`
+	// Webhook
+	WebhookURL    string `json:"webhook_url" yaml:"webhook_url"`
+	WebhookSecret string `json:"webhook_secret" yaml:"webhook_secret,omitempty"`
+}
+
+// MinimumAutoSleepIdleTimeoutMinutes : idle activity is sampled every minute, so keep some room for the sampling delay
+const MinimumAutoSleepIdleTimeoutMinutes = 5
+
+// MinimumAutoScalingCooldownSeconds : resource stats are reported every minute, so scaling faster than that has no effect
+const MinimumAutoScalingCooldownSeconds = 60
+
+// applicationStatsIntervalSeconds : cpu time of the application stats is the usage of this interval
+const applicationStatsIntervalSeconds = 60
+
 type ApplicationCustomHealthCheck struct {
-	Enabled              bool   `json:"enabled" gorm:"default:false"`
-	TestCommand          string `json:"test_command"`
-	IntervalSeconds      uint64 `json:"interval_seconds" gorm:"default:10"`      // Time between running the check in seconds
-	TimeoutSeconds       uint64 `json:"timeout_seconds" gorm:"default:5"`        // Maximum time to allow one check to run in seconds
-	StartPeriodSeconds   uint64 `json:"start_period_seconds" gorm:"default:5"`   // Start period for the container to initialize before counting retries towards unstable
-	StartIntervalSeconds uint64 `json:"start_interval_seconds" gorm:"default:5"` // Time between running the check during the start period
-	Retries              uint64 `json:"retries" gorm:"default:0"`                // Consecutive failures needed to report unhealthy
+	Enabled              bool   `json:"enabled" yaml:"enabled" gorm:"default:false"`
+	TestCommand          string `json:"test_command" yaml:"test_command"`
+	IntervalSeconds      uint64 `json:"interval_seconds" yaml:"interval_seconds" gorm:"default:10"`            // Time between running the check in seconds
+	TimeoutSeconds       uint64 `json:"timeout_seconds" yaml:"timeout_seconds" gorm:"default:5"`               // Maximum time to allow one check to run in seconds
+	StartPeriodSeconds   uint64 `json:"start_period_seconds" yaml:"start_period_seconds" gorm:"default:5"`     // Start period for the container to initialize before counting retries towards unstable
+	StartIntervalSeconds uint64 `json:"start_interval_seconds" yaml:"start_interval_seconds" gorm:"default:5"` // Time between running the check during the start period
+	Retries              uint64 `json:"retries" yaml:"retries" gorm:"default:0"`                               // Consecutive failures needed to report unhealthy
 }
 
 // ************************************************************************************* //
@@ -361,8 +566,8 @@ type ApplicationCustomHealthCheck struct {
 // ************************************************************************************* //
 
 type DockerProxyConfig struct {
-	Enabled    bool                  `json:"enabled" gorm:"default:false"`
-	Permission DockerProxyPermission `json:"permissions" gorm:"embedded;embeddedPrefix:permission_"`
+	Enabled    bool                  `json:"enabled" yaml:"enabled" gorm:"default:false"`
+	Permission DockerProxyPermission `json:"permissions" yaml:"permissions" gorm:"embedded;embeddedPrefix:permission_"`
 }
 
 type DockerProxyPermissionType string
@@ -377,27 +582,27 @@ const (
 )
 
 type DockerProxyPermission struct {
-	Ping         DockerProxyPermissionType `json:"ping" gorm:"default:read"`
-	Version      DockerProxyPermissionType `json:"version" gorm:"default:none"`
-	Info         DockerProxyPermissionType `json:"info" gorm:"default:none"`
-	Events       DockerProxyPermissionType `json:"events" gorm:"default:none"`
-	Auth         DockerProxyPermissionType `json:"auth" gorm:"default:none"`
-	Secrets      DockerProxyPermissionType `json:"secrets" gorm:"default:none"`
-	Build        DockerProxyPermissionType `json:"build" gorm:"default:none"`
-	Commit       DockerProxyPermissionType `json:"commit" gorm:"default:none"`
-	Configs      DockerProxyPermissionType `json:"configs" gorm:"default:none"`
-	Containers   DockerProxyPermissionType `json:"containers" gorm:"default:none"`
-	Distribution DockerProxyPermissionType `json:"distribution" gorm:"default:none"`
-	Exec         DockerProxyPermissionType `json:"exec" gorm:"default:none"`
-	Grpc         DockerProxyPermissionType `json:"grpc" gorm:"default:none"`
-	Images       DockerProxyPermissionType `json:"images" gorm:"default:none"`
-	Networks     DockerProxyPermissionType `json:"networks" gorm:"default:none"`
-	Nodes        DockerProxyPermissionType `json:"nodes" gorm:"default:none"`
-	Plugins      DockerProxyPermissionType `json:"plugins" gorm:"default:none"`
-	Services     DockerProxyPermissionType `json:"services" gorm:"default:none"`
-	Session      DockerProxyPermissionType `json:"session" gorm:"default:none"`
-	Swarm        DockerProxyPermissionType `json:"swarm" gorm:"default:none"`
-	System       DockerProxyPermissionType `json:"system" gorm:"default:none"`
-	Tasks        DockerProxyPermissionType `json:"tasks" gorm:"default:none"`
-	Volumes      DockerProxyPermissionType `json:"volumes" gorm:"default:none"`
+	Ping         DockerProxyPermissionType `json:"ping" yaml:"ping" gorm:"default:read"`
+	Version      DockerProxyPermissionType `json:"version" yaml:"version" gorm:"default:none"`
+	Info         DockerProxyPermissionType `json:"info" yaml:"info" gorm:"default:none"`
+	Events       DockerProxyPermissionType `json:"events" yaml:"events" gorm:"default:none"`
+	Auth         DockerProxyPermissionType `json:"auth" yaml:"auth" gorm:"default:none"`
+	Secrets      DockerProxyPermissionType `json:"secrets" yaml:"secrets" gorm:"default:none"`
+	Build        DockerProxyPermissionType `json:"build" yaml:"build" gorm:"default:none"`
+	Commit       DockerProxyPermissionType `json:"commit" yaml:"commit" gorm:"default:none"`
+	Configs      DockerProxyPermissionType `json:"configs" yaml:"configs" gorm:"default:none"`
+	Containers   DockerProxyPermissionType `json:"containers" yaml:"containers" gorm:"default:none"`
+	Distribution DockerProxyPermissionType `json:"distribution" yaml:"distribution" gorm:"default:none"`
+	Exec         DockerProxyPermissionType `json:"exec" yaml:"exec" gorm:"default:none"`
+	Grpc         DockerProxyPermissionType `json:"grpc" yaml:"grpc" gorm:"default:none"`
+	Images       DockerProxyPermissionType `json:"images" yaml:"images" gorm:"default:none"`
+	Networks     DockerProxyPermissionType `json:"networks" yaml:"networks" gorm:"default:none"`
+	Nodes        DockerProxyPermissionType `json:"nodes" yaml:"nodes" gorm:"default:none"`
+	Plugins      DockerProxyPermissionType `json:"plugins" yaml:"plugins" gorm:"default:none"`
+	Services     DockerProxyPermissionType `json:"services" yaml:"services" gorm:"default:none"`
+	Session      DockerProxyPermissionType `json:"session" yaml:"session" gorm:"default:none"`
+	Swarm        DockerProxyPermissionType `json:"swarm" yaml:"swarm" gorm:"default:none"`
+	System       DockerProxyPermissionType `json:"system" yaml:"system" gorm:"default:none"`
+	Tasks        DockerProxyPermissionType `json:"tasks" yaml:"tasks" gorm:"default:none"`
+	Volumes      DockerProxyPermissionType `json:"volumes" yaml:"volumes" gorm:"default:none"`
 }
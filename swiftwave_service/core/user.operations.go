@@ -41,6 +41,12 @@ func CreateUser(ctx context.Context, db gorm.DB, user User) (User, error) {
 	if user.PasswordHash == "" {
 		return User{}, errors.New("password cannot be empty")
 	}
+	if user.Role == "" {
+		user.Role = AdministratorRole
+	}
+	if !user.Role.IsValid() {
+		return User{}, errors.New("invalid role")
+	}
 	err := db.Create(&user).Error
 	return user, err
 }
@@ -55,6 +61,32 @@ func DeleteUser(ctx context.Context, db gorm.DB, id uint) error {
 	return err
 }
 
+// ChangeUserRole : change role of the user
+// At least one administrator should be there in the system
+func ChangeUserRole(ctx context.Context, db gorm.DB, id uint, role UserRole) error {
+	if !role.IsValid() {
+		return errors.New("invalid role")
+	}
+	user, err := FindUserByID(ctx, db, id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.Role == role {
+		return nil
+	}
+	if user.Role == AdministratorRole {
+		var adminCount int64
+		err = db.Model(&User{}).Where("role = ?", AdministratorRole).Count(&adminCount).Error
+		if err != nil {
+			return err
+		}
+		if adminCount <= 1 {
+			return errors.New("at least one admin user is required")
+		}
+	}
+	return db.Model(&user).Update("role", role).Error
+}
+
 // ChangePassword : change user password
 func ChangePassword(ctx context.Context, db gorm.DB, username string, oldPassword string, newPassword string) error {
 	// Fetch user
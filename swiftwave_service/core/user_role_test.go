@@ -0,0 +1,25 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUserRolePermission(t *testing.T) {
+	t.Run("admin can perform any operation", func(t *testing.T) {
+		assert.True(t, AdministratorRole.HasPermission(AdministratorRole), "admin should be allowed to perform admin operations")
+		assert.True(t, AdministratorRole.HasPermission(ManagerRole), "admin should be allowed to perform manager operations")
+	})
+
+	t.Run("manager can perform only manager operations", func(t *testing.T) {
+		assert.False(t, ManagerRole.HasPermission(AdministratorRole), "manager should not be allowed to perform admin operations")
+		assert.True(t, ManagerRole.HasPermission(ManagerRole), "manager should be allowed to perform manager operations")
+	})
+
+	t.Run("unknown role can not perform any operation", func(t *testing.T) {
+		unknownRole := UserRole("user")
+		assert.False(t, unknownRole.IsValid(), "unknown role should not be valid")
+		assert.False(t, unknownRole.HasPermission(AdministratorRole), "unknown role should not be allowed to perform admin operations")
+		assert.False(t, unknownRole.HasPermission(ManagerRole), "unknown role should not be allowed to perform manager operations")
+	})
+}
@@ -1,9 +1,18 @@
 package core
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"github.com/anmitsu/go-shlex"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hashicorp/go-set"
+	cronschedule "github.com/swiftwave-org/swiftwave/pkg/cron_schedule"
 	"golang.org/x/crypto/bcrypt"
+	"math"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -55,6 +64,74 @@ func (domain *Domain) IsIPv4() bool {
 	return regex.MatchString(domain.Name)
 }
 
+// IsWildcard : check if the domain is a wildcard domain (e.g. *.example.com)
+func (domain *Domain) IsWildcard() bool {
+	return strings.HasPrefix(domain.Name, "*.")
+}
+
+// MatchesHost : check if requests for the hostname are routed to the domain
+// wildcard domain matches its subdomains at any depth, like the host condition of haproxy (e.g. *.example.com matches app.example.com)
+func (domain *Domain) MatchesHost(hostname string) bool {
+	name := strings.ToLower(strings.TrimSpace(domain.Name))
+	hostname = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+	if hostname == "" {
+		return false
+	}
+	if domain.IsWildcard() {
+		return strings.HasSuffix(hostname, strings.TrimPrefix(name, "*"))
+	}
+	return name == hostname
+}
+
+// validateName : `*` is only allowed as the first label of the domain
+func (domain *Domain) validateName() error {
+	name := strings.TrimPrefix(domain.Name, "*.")
+	if strings.Contains(name, "*") {
+		return errors.New("wildcard is only allowed as the first label of the domain, e.g. *.example.com")
+	}
+	if domain.IsWildcard() && !strings.Contains(name, ".") {
+		return errors.New("wildcard domain should have at least two labels after *, e.g. *.example.com")
+	}
+	if domain.IsWildcard() && !domain.DNSProvider.IsConfigured() {
+		return errors.New("dns provider is required for wildcard domain, as SSL certificate can be issued only by dns-01 challenge")
+	}
+	return nil
+}
+
+// IsConfigured : check if dns-01 challenge should be used for the domain
+func (p *DomainDNSProvider) IsConfigured() bool {
+	return p.Type != "" && p.Type != DNSProviderNone
+}
+
+// Validate : validate the credentials of the dns provider
+func (p *DomainDNSProvider) Validate() error {
+	switch p.Type {
+	case "", DNSProviderNone:
+		return nil
+	case DNSProviderRFC2136:
+		if strings.TrimSpace(p.RFC2136Nameserver) == "" {
+			return errors.New("nameserver is required for rfc2136 dns provider")
+		}
+		if (p.RFC2136TSIGKeyName == "") != (p.RFC2136TSIGSecret == "") {
+			return errors.New("both tsig key name and secret are required for rfc2136 dns provider")
+		}
+		if p.RFC2136TSIGSecret != "" {
+			if _, err := base64.StdEncoding.DecodeString(p.RFC2136TSIGSecret); err != nil {
+				return errors.New("tsig secret should be base64 encoded")
+			}
+		}
+		return nil
+	case DNSProviderWebhook:
+		webhookURL, err := url.Parse(p.WebhookURL)
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			return errors.New("valid http(s) url is required for webhook dns provider")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported dns provider %s", p.Type)
+	}
+}
+
 // IsLocalhost check if the domain is localhost
 func (server *Server) IsLocalhost() bool {
 	// if `localhost` or `127.0.0.1` or `0.0.0.0`
@@ -98,6 +175,288 @@ func (c *ApplicationCustomHealthCheck) Equal(other *ApplicationCustomHealthCheck
 		c.Retries == other.Retries
 }
 
+func (a *ApplicationAutoSleep) Equal(other *ApplicationAutoSleep) bool {
+	return a.Enabled == other.Enabled && a.IdleTimeoutMinutes == other.IdleTimeoutMinutes
+}
+
+// Validate : validate auto sleep configuration of application
+func (a *ApplicationAutoSleep) Validate(deploymentMode DeploymentMode) error {
+	if !a.Enabled {
+		return nil
+	}
+	if deploymentMode == DeploymentModeGlobal {
+		return errors.New("auto sleep is not supported for global deployment")
+	}
+	if a.IdleTimeoutMinutes < MinimumAutoSleepIdleTimeoutMinutes {
+		return fmt.Errorf("idle timeout for auto sleep should be at least %d minutes", MinimumAutoSleepIdleTimeoutMinutes)
+	}
+	return nil
+}
+
+func (a *ApplicationAutoScaling) Equal(other *ApplicationAutoScaling) bool {
+	return a.Enabled == other.Enabled &&
+		a.MinReplicas == other.MinReplicas &&
+		a.MaxReplicas == other.MaxReplicas &&
+		a.Metric == other.Metric &&
+		a.TargetPercent == other.TargetPercent &&
+		a.CooldownSeconds == other.CooldownSeconds
+}
+
+// Validate : validate auto scaling configuration of application
+func (a *ApplicationAutoScaling) Validate(deploymentMode DeploymentMode, resourceLimit ApplicationResourceLimit) error {
+	if !a.Enabled {
+		return nil
+	}
+	if deploymentMode != DeploymentModeReplicated {
+		return errors.New("auto scaling is only supported for replicated deployment")
+	}
+	if a.MinReplicas == 0 {
+		return errors.New("minimum replicas for auto scaling should be at least 1")
+	}
+	if a.MaxReplicas < a.MinReplicas {
+		return errors.New("maximum replicas for auto scaling should not be less than minimum replicas")
+	}
+	switch a.Metric {
+	case AutoScalingMetricCPU:
+		if resourceLimit.NanoCPUs == 0 {
+			return errors.New("cpu limit is required to auto scale by cpu usage")
+		}
+	case AutoScalingMetricMemory:
+		if resourceLimit.MemoryMB == 0 {
+			return errors.New("memory limit is required to auto scale by memory usage")
+		}
+	default:
+		return fmt.Errorf("unsupported auto scaling metric %s", a.Metric)
+	}
+	if a.TargetPercent == 0 || a.TargetPercent > 100 {
+		return errors.New("target usage for auto scaling should be between 1 and 100 percent")
+	}
+	if a.CooldownSeconds < MinimumAutoScalingCooldownSeconds {
+		return fmt.Errorf("cooldown for auto scaling should be at least %d seconds", MinimumAutoScalingCooldownSeconds)
+	}
+	return nil
+}
+
+// ClampReplicas : bound the replicas by minimum and maximum replicas
+func (a *ApplicationAutoScaling) ClampReplicas(replicas uint) uint {
+	if replicas < a.MinReplicas {
+		return a.MinReplicas
+	}
+	if replicas > a.MaxReplicas {
+		return a.MaxReplicas
+	}
+	return replicas
+}
+
+// autoScalingTolerance : changes of usage within 10% of the target are ignored, so that replicas don't flap
+const autoScalingTolerance = 0.1
+
+// DesiredReplicas : replicas required to bring the average usage of replicas to the target
+func (a *ApplicationAutoScaling) DesiredReplicas(currentReplicas uint, usagePercent float64) uint {
+	if currentReplicas == 0 {
+		return a.ClampReplicas(1)
+	}
+	ratio := usagePercent / float64(a.TargetPercent)
+	if math.Abs(ratio-1) <= autoScalingTolerance {
+		return a.ClampReplicas(currentReplicas)
+	}
+	return a.ClampReplicas(uint(math.Ceil(float64(currentReplicas) * ratio)))
+}
+
+// AutoScalingUsagePercent : average usage of the replicas by the metric of auto scaling, in percentage of the resource limit
+func (application *Application) AutoScalingUsagePercent(stats []*ApplicationServiceResourceStat) float64 {
+	if len(stats) == 0 || application.Replicas == 0 {
+		return 0
+	}
+	var usagePercent float64 = 0
+	for _, stat := range stats {
+		switch application.AutoScaling.Metric {
+		case AutoScalingMetricCPU:
+			// cpu time is the total of all the containers, and the limit is per container
+			if application.ResourceLimit.NanoCPUs > 0 {
+				usagePercent += float64(stat.ServiceCpuTime) / (float64(applicationStatsIntervalSeconds) * float64(application.Replicas) * float64(application.ResourceLimit.NanoCPUs)) * 100
+			}
+		case AutoScalingMetricMemory:
+			// used memory is the total of all the containers
+			if application.ResourceLimit.MemoryMB > 0 {
+				usagePercent += float64(stat.UsedMemoryMB) / float64(uint64(application.Replicas)*uint64(application.ResourceLimit.MemoryMB)) * 100
+			}
+		}
+	}
+	return usagePercent / float64(len(stats))
+}
+
 func (application *Application) DockerProxyServiceName() string {
 	return application.ID + "-dp"
 }
+
+// IsCronJob : check if the application runs on a cron schedule instead of a long-running service
+func (application *Application) IsCronJob() bool {
+	return application.Kind == ApplicationKindCronJob
+}
+
+// ServiceName : name of the swarm job service of the run
+// Application id is used instead of name, so that the run can't collide with the service of another application
+func (run *CronJobRun) ServiceName() string {
+	return fmt.Sprintf("%s-run-%d", run.ApplicationID, run.ID)
+}
+
+// IsFinished : check if the run has reached a final status
+func (run *CronJobRun) IsFinished() bool {
+	return run.Status == CronJobRunStatusSucceeded || run.Status == CronJobRunStatusFailed || run.Status == CronJobRunStatusCancelled
+}
+
+// Validate : validate cron job configuration of application
+func (c *ApplicationCronJob) Validate() error {
+	if _, err := cronschedule.Parse(c.Schedule); err != nil {
+		return errors.New("invalid cron schedule > " + err.Error())
+	}
+	switch c.ConcurrencyPolicy {
+	case CronJobConcurrencyAllow, CronJobConcurrencyForbid, CronJobConcurrencyReplace:
+	default:
+		return fmt.Errorf("unsupported concurrency policy %s", c.ConcurrencyPolicy)
+	}
+	if c.HistoryLimit == 0 {
+		return errors.New("history limit should be at least 1")
+	}
+	return nil
+}
+
+func (c *ApplicationCronJob) Equal(other *ApplicationCronJob) bool {
+	return strings.Compare(c.Schedule, other.Schedule) == 0 && c.ConcurrencyPolicy == other.ConcurrencyPolicy && c.HistoryLimit == other.HistoryLimit
+}
+
+func applicationKindOrDefault(kind ApplicationKind) ApplicationKind {
+	if kind == "" {
+		return ApplicationKindService
+	}
+	return kind
+}
+
+// validateKind : cron job applications run to completion, so the features of long-running services are not supported
+func (application *Application) validateKind() error {
+	switch application.Kind {
+	case "", ApplicationKindService:
+		return nil
+	case ApplicationKindCronJob:
+		if application.DeploymentMode != DeploymentModeReplicated {
+			return errors.New("cron job should use replicated deployment mode")
+		}
+		if application.AutoSleep.Enabled {
+			return errors.New("auto sleep is not supported for cron job")
+		}
+		if application.AutoScaling.Enabled {
+			return errors.New("auto scaling is not supported for cron job")
+		}
+		if application.DeploymentStrategy.IsParallel() {
+			return errors.New("blue/green and canary deployment strategies are not supported for cron job")
+		}
+		return application.CronJob.Validate()
+	default:
+		return fmt.Errorf("unsupported application kind %s", application.Kind)
+	}
+}
+
+// CanaryServiceName : name of the service which runs the blue/green or canary deployment in parallel
+// It's derived from the id, as any name with the suffix can be taken by another application
+func (application *Application) CanaryServiceName() string {
+	return application.ID + "-canary"
+}
+
+// ReleaseCommandServiceName : name of the one-off service which runs the release command of the application
+func (application *Application) ReleaseCommandServiceName() string {
+	return application.ID + "-release"
+}
+
+// ReleaseCommandArgs : split the release command into arguments as a shell would, quoted arguments are kept together
+func (application *Application) ReleaseCommandArgs() ([]string, error) {
+	args, err := shlex.Split(application.ReleaseCommand, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release command > %s", err.Error())
+	}
+	return args, nil
+}
+
+func (s *ApplicationDeploymentStrategy) Equal(other *ApplicationDeploymentStrategy) bool {
+	return s.Type == other.Type && s.CanaryWeightPercent == other.CanaryWeightPercent
+}
+
+// IsParallel : new deployments run in parallel of the deployed one till promotion
+func (s *ApplicationDeploymentStrategy) IsParallel() bool {
+	return s.Type == DeploymentStrategyBlueGreen || s.Type == DeploymentStrategyCanary
+}
+
+// InitialTrafficPercent : percentage of the traffic routed to a new parallel deployment
+func (s *ApplicationDeploymentStrategy) InitialTrafficPercent() int {
+	if s.Type == DeploymentStrategyCanary {
+		return int(s.CanaryWeightPercent)
+	}
+	return 0
+}
+
+// Validate : validate deployment strategy of application
+func (s *ApplicationDeploymentStrategy) Validate() error {
+	switch s.Type {
+	case "", DeploymentStrategyRolling, DeploymentStrategyBlueGreen:
+		return nil
+	case DeploymentStrategyCanary:
+		if s.CanaryWeightPercent < 1 || s.CanaryWeightPercent > 99 {
+			return errors.New("canary weight should be between 1 and 99 percent")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported deployment strategy %s", s.Type)
+	}
+}
+
+// ValidateCustomSSL : validate the uploaded certificate before using it for the domain
+// - private key should match the certificate
+// - certificate should cover the domain
+// - certificate should not be expired
+func (domain *Domain) ValidateCustomSSL(privateKey string, fullChain string) error {
+	keyPair, err := tls.X509KeyPair([]byte(fullChain), []byte(privateKey))
+	if err != nil {
+		return errors.New("private key does not match the certificate or the PEM data is invalid")
+	}
+	if len(keyPair.Certificate) == 0 {
+		return errors.New("no certificate found in the full chain")
+	}
+	// first certificate of the chain is the leaf certificate
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return errors.New("failed to parse the certificate")
+	}
+	if !isCertificateCoversDomain(leaf, domain.Name) {
+		return fmt.Errorf("certificate is not valid for %s", domain.Name)
+	}
+	if time.Now().After(leaf.NotAfter) {
+		return errors.New("certificate has expired")
+	}
+	return nil
+}
+
+func isCertificateCoversDomain(cert *x509.Certificate, name string) bool {
+	// wildcard domain needs a wildcard SAN, it can't be verified as a hostname
+	if strings.HasPrefix(name, "*.") {
+		for _, dnsName := range cert.DNSNames {
+			if strings.EqualFold(dnsName, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return cert.VerifyHostname(name) == nil
+}
+
+// isSameID : compare optional foreign keys by value, pointers of two records are never the same
+func isSameID(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// isSameStringSet : compare two lists ignoring the order of the items
+func isSameStringSet(a []string, b []string) bool {
+	return set.From[string](a).Equal(set.From[string](b))
+}
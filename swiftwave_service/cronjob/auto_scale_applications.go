@@ -0,0 +1,76 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+)
+
+// autoScalingStatsWindow : resource stats of this window are averaged to decide the replicas
+const autoScalingStatsWindow = 5 * time.Minute
+
+func (m Manager) AutoScaleApplications() {
+	logger.CronJobLogger.Println("Starting auto scale applications [cronjob]")
+	for {
+		m.autoScaleApplications()
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+func (m Manager) autoScaleApplications() {
+	ctx := context.Background()
+	applications, err := core.FindApplicationsWithAutoScalingEnabled(ctx, m.ServiceManager.DbClient)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to fetch applications with auto scaling enabled", err.Error())
+		return
+	}
+	for _, application := range applications {
+		err = m.autoScaleApplication(ctx, application)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to auto scale application", application.Name, err.Error())
+		}
+	}
+}
+
+// autoScaleApplication : decide the replicas of the application, the scaling is done by the worker along with the other tasks of the application
+func (m Manager) autoScaleApplication(ctx context.Context, application *core.Application) error {
+	db := m.ServiceManager.DbClient
+	now := time.Now()
+	statsFrom := now.Add(-autoScalingStatsWindow)
+	latestEvent, err := core.FindLatestAutoScalingEventByApplicationId(ctx, db, application.ID)
+	if err != nil {
+		return err
+	}
+	if latestEvent != nil {
+		if now.Sub(latestEvent.CreatedAt) < time.Duration(application.AutoScaling.CooldownSeconds)*time.Second {
+			return nil
+		}
+		// stats recorded before the last scaling don't reflect the current replicas
+		if latestEvent.CreatedAt.After(statsFrom) {
+			statsFrom = latestEvent.CreatedAt
+		}
+	}
+	// don't interfere with an ongoing deployment
+	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, db, application.ID)
+	if err != nil {
+		return err
+	}
+	if latestDeployment.Status != core.DeploymentStatusDeployed {
+		return nil
+	}
+	stats, err := core.FetchApplicationServiceResourceAnalytics(ctx, db, application.ID, uint(statsFrom.Unix()))
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+	usagePercent := application.AutoScalingUsagePercent(stats)
+	replicas := application.AutoScaling.DesiredReplicas(application.Replicas, usagePercent)
+	if replicas == application.Replicas {
+		return nil
+	}
+	return m.WorkerManager.EnqueueScaleApplicationRequest(application.ID, application.Replicas, replicas, uint(usagePercent))
+}
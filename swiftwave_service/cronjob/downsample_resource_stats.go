@@ -0,0 +1,46 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+)
+
+const (
+	// resource stats are kept at full resolution (1 minute) for this duration
+	resourceStatsRawRetention = 24 * time.Hour
+	// after that, stats are merged into one record per this interval
+	resourceStatsDownsampleInterval = 1 * time.Hour
+	// resource stats older than this are deleted
+	resourceStatsRetention = 30 * 24 * time.Hour
+)
+
+func (m Manager) DownsampleResourceStats() {
+	logger.CronJobLogger.Println("Starting downsample resource stats [cronjob]")
+	for {
+		m.downsampleResourceStats()
+		time.Sleep(1 * time.Hour)
+	}
+}
+
+func (m Manager) downsampleResourceStats() {
+	ctx := context.Background()
+	now := time.Now()
+	// delete expired stats
+	err := core.DeleteResourceStatsOlderThan(ctx, m.ServiceManager.DbClient, now.Add(-resourceStatsRetention))
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to delete expired resource stats", err.Error())
+	}
+	// downsample stats, only complete intervals are considered
+	before := now.Add(-resourceStatsRawRetention).Truncate(resourceStatsDownsampleInterval)
+	err = core.DownsampleServerResourceStats(ctx, m.ServiceManager.DbClient, before, resourceStatsDownsampleInterval)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to downsample server resource stats", err.Error())
+	}
+	err = core.DownsampleApplicationServiceResourceStats(ctx, m.ServiceManager.DbClient, before, resourceStatsDownsampleInterval)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to downsample application resource stats", err.Error())
+	}
+}
@@ -43,6 +43,20 @@ func (m Manager) Start(nowait bool) {
 	}
 	m.wg.Add(1)
 	go m.EnqueueTimedoutTasks()
+	m.wg.Add(1)
+	go m.SleepIdleApplications()
+	m.wg.Add(1)
+	go m.AutoScaleApplications()
+	m.wg.Add(1)
+	go m.WatchApplicationEvents()
+	m.wg.Add(1)
+	go m.DownsampleResourceStats()
+	m.wg.Add(1)
+	go m.ScheduleCronJobApplications()
+	m.wg.Add(1)
+	go m.SyncGitOpsApplicationGroups()
+	m.wg.Add(1)
+	go m.PruneTaskRuns()
 	if !nowait {
 		m.wg.Wait()
 	}
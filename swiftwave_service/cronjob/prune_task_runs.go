@@ -0,0 +1,35 @@
+package cronjob
+
+import (
+	"time"
+
+	"github.com/swiftwave-org/swiftwave/pkg/task_queue"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+)
+
+const (
+	// task runs older than this are deleted
+	taskRunsRetention = 7 * 24 * time.Hour
+	// task runs still running after this are considered to be left by a lost worker
+	// remote queues are shared by the instances, so a run can't be marked as failed on restart of an instance
+	taskRunsLostAfter = 24 * time.Hour
+)
+
+func (m Manager) PruneTaskRuns() {
+	logger.CronJobLogger.Println("Starting prune task runs [cronjob]")
+	for {
+		m.pruneTaskRuns()
+		time.Sleep(1 * time.Hour)
+	}
+}
+
+func (m Manager) pruneTaskRuns() {
+	err := task_queue.MarkLostTaskRunsAsFailed(&m.ServiceManager.DbClient, time.Now().Add(-taskRunsLostAfter))
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to mark lost task runs as failed", err.Error())
+	}
+	err = task_queue.DeleteTaskRunsOlderThan(&m.ServiceManager.DbClient, time.Now().Add(-taskRunsRetention))
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to delete expired task runs", err.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	cronschedule "github.com/swiftwave-org/swiftwave/pkg/cron_schedule"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+)
+
+func (m Manager) ScheduleCronJobApplications() {
+	logger.CronJobLogger.Println("Starting schedule cron job applications [cronjob]")
+	for {
+		// wake up at the start of every minute
+		now := time.Now()
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		m.scheduleCronJobApplications(time.Now().Truncate(time.Minute))
+	}
+}
+
+func (m Manager) scheduleCronJobApplications(now time.Time) {
+	ctx := context.Background()
+	applications, err := core.FindCronJobApplications(ctx, m.ServiceManager.DbClient)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to fetch cron job applications", err.Error())
+		return
+	}
+	for _, application := range applications {
+		if application.IsSleeping {
+			continue
+		}
+		schedule, err := cronschedule.Parse(application.CronJob.Schedule)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Invalid schedule of cron job", application.Name, err.Error())
+			continue
+		}
+		if !schedule.Matches(now) {
+			continue
+		}
+		run, err := m.WorkerManager.TriggerCronJob(ctx, application, core.CronJobRunTriggerSchedule)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to trigger cron job", application.Name, err.Error())
+			continue
+		}
+		if run == nil {
+			logger.CronJobLogger.Println("Skipping run of cron job", application.Name, "as the previous run is still running")
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+// applicationActivity : last observed traffic of an application
+type applicationActivity struct {
+	totalSessions uint64
+	lastActiveAt  time.Time
+}
+
+func (m Manager) SleepIdleApplications() {
+	logger.CronJobLogger.Println("Starting sleep idle applications [cronjob]")
+	// activities are kept in memory
+	// after a restart, every application gets a fresh idle timeout
+	activities := make(map[string]*applicationActivity)
+	for {
+		m.sleepIdleApplications(activities)
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+func (m Manager) sleepIdleApplications(activities map[string]*applicationActivity) {
+	ctx := context.Background()
+	applications, err := core.FindApplicationsWithAutoSleepEnabled(ctx, m.ServiceManager.DbClient)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to fetch applications with auto sleep enabled", err.Error())
+		return
+	}
+	// forget the applications which are sleeping or have auto sleep disabled
+	// so that they will get a fresh idle timeout once they are back
+	observedApplications := make(map[string]bool)
+	for _, application := range applications {
+		observedApplications[application.ID] = true
+	}
+	for applicationId := range activities {
+		if !observedApplications[applicationId] {
+			delete(activities, applicationId)
+		}
+	}
+	if len(applications) == 0 {
+		return
+	}
+	// fetch haproxy managers
+	proxyServers, err := core.FetchProxyActiveServers(&m.ServiceManager.DbClient)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to fetch proxy servers", err.Error())
+		return
+	}
+	if len(proxyServers) == 0 {
+		return
+	}
+	haproxyManagers, err := manager.HAProxyClients(ctx, proxyServers)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to create haproxy clients", err.Error())
+		return
+	}
+	now := time.Now()
+	for _, application := range applications {
+		totalSessions, isFound, err := fetchApplicationTotalSessions(ctx, m.ServiceManager.DbClient, haproxyManagers, application)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to fetch traffic stats of application", application.Name, err.Error())
+			continue
+		}
+		// without any ingress rule, there is no way to know if the application is idle
+		if !isFound {
+			delete(activities, application.ID)
+			continue
+		}
+		if !isIdleApplication(activities, application, totalSessions, now) {
+			continue
+		}
+		// put the application to sleep
+		err = m.sleepApplication(ctx, application)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to put idle application", application.Name, "to sleep", err.Error())
+			continue
+		}
+		delete(activities, application.ID)
+		logger.CronJobLogger.Println("Application", application.Name, "has been idle for", application.AutoSleep.IdleTimeoutMinutes, "minutes, put to sleep")
+	}
+}
+
+// isIdleApplication : record the observed sessions of the application and check if it has been idle for its idle timeout
+func isIdleApplication(activities map[string]*applicationActivity, application *core.Application, totalSessions uint64, now time.Time) bool {
+	activity, ok := activities[application.ID]
+	if !ok {
+		activities[application.ID] = &applicationActivity{
+			totalSessions: totalSessions,
+			lastActiveAt:  now,
+		}
+		return false
+	}
+	// counters are reset on haproxy reload, so any change is considered as activity
+	if activity.totalSessions != totalSessions {
+		activity.totalSessions = totalSessions
+		activity.lastActiveAt = now
+		return false
+	}
+	return now.Sub(activity.lastActiveAt) >= time.Duration(application.AutoSleep.IdleTimeoutMinutes)*time.Minute
+}
+
+// fetchApplicationTotalSessions : sum of the sessions of all the backends of the application across all the proxies
+func fetchApplicationTotalSessions(ctx context.Context, db gorm.DB, haproxyManagers []*haproxymanager.Manager, application *core.Application) (uint64, bool, error) {
+	ingressRules, err := core.FetchIngressRulesWithTargetPortAndProtocolOnly(ctx, db, application.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	var totalSessions uint64 = 0
+	isFound := false
+	for _, ingressRule := range ingressRules {
+		var backendProtocol haproxymanager.BackendProtocol
+		if ingressRule.Protocol == core.HTTPProtocol || ingressRule.Protocol == core.HTTPSProtocol {
+			backendProtocol = haproxymanager.HTTPBackend
+		} else if ingressRule.Protocol == core.TCPProtocol {
+			backendProtocol = haproxymanager.TCPBackend
+		} else {
+			continue
+		}
+		for _, haproxyManager := range haproxyManagers {
+			backendName := haproxyManager.GenerateBackendName(backendProtocol, application.Name, int(ingressRule.TargetPort))
+			stats, err := haproxyManager.FetchBackendStats(backendName)
+			if err != nil {
+				return 0, false, err
+			}
+			totalSessions += stats.TotalSessions
+			isFound = true
+		}
+	}
+	return totalSessions, isFound, nil
+}
+
+func (m Manager) sleepApplication(ctx context.Context, application *core.Application) error {
+	tx := m.ServiceManager.DbClient.Begin()
+	err := application.MarkAsSleeping(ctx, *tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	latestDeployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, m.ServiceManager.DbClient, application.ID)
+	if err != nil {
+		return err
+	}
+	return m.WorkerManager.EnqueueDeployApplicationRequest(application.ID, latestDeployment.ID)
+}
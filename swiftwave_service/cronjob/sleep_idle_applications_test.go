@@ -0,0 +1,58 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+)
+
+func TestIsIdleApplication(t *testing.T) {
+	application := &core.Application{
+		ID: "app",
+		AutoSleep: core.ApplicationAutoSleep{
+			Enabled:            true,
+			IdleTimeoutMinutes: 10,
+		},
+	}
+	start := time.Now()
+
+	t.Run("first observation is not idle", func(t *testing.T) {
+		activities := make(map[string]*applicationActivity)
+		assert.False(t, isIdleApplication(activities, application, 0, start))
+		assert.Equal(t, start, activities[application.ID].lastActiveAt)
+	})
+
+	t.Run("idle after timeout without new sessions", func(t *testing.T) {
+		activities := make(map[string]*applicationActivity)
+		assert.False(t, isIdleApplication(activities, application, 5, start))
+		assert.False(t, isIdleApplication(activities, application, 5, start.Add(9*time.Minute)))
+		assert.True(t, isIdleApplication(activities, application, 5, start.Add(10*time.Minute)))
+	})
+
+	t.Run("new sessions reset the idle timeout", func(t *testing.T) {
+		activities := make(map[string]*applicationActivity)
+		assert.False(t, isIdleApplication(activities, application, 5, start))
+		assert.False(t, isIdleApplication(activities, application, 6, start.Add(9*time.Minute)))
+		assert.False(t, isIdleApplication(activities, application, 6, start.Add(18*time.Minute)))
+		assert.True(t, isIdleApplication(activities, application, 6, start.Add(19*time.Minute)))
+	})
+
+	t.Run("counter reset by haproxy reload is considered as activity", func(t *testing.T) {
+		activities := make(map[string]*applicationActivity)
+		assert.False(t, isIdleApplication(activities, application, 100, start))
+		assert.False(t, isIdleApplication(activities, application, 0, start.Add(10*time.Minute)))
+		assert.Equal(t, uint64(0), activities[application.ID].totalSessions)
+		assert.True(t, isIdleApplication(activities, application, 0, start.Add(20*time.Minute)))
+	})
+
+	t.Run("applications are tracked separately", func(t *testing.T) {
+		activities := make(map[string]*applicationActivity)
+		otherApplication := &core.Application{ID: "other", AutoSleep: application.AutoSleep}
+		assert.False(t, isIdleApplication(activities, application, 1, start))
+		assert.False(t, isIdleApplication(activities, otherApplication, 1, start.Add(5*time.Minute)))
+		assert.True(t, isIdleApplication(activities, application, 1, start.Add(10*time.Minute)))
+		assert.False(t, isIdleApplication(activities, otherApplication, 1, start.Add(10*time.Minute)))
+	})
+}
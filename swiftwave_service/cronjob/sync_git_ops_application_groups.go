@@ -0,0 +1,66 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/declarative_state"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+)
+
+func (m Manager) SyncGitOpsApplicationGroups() {
+	logger.CronJobLogger.Println("Starting sync git ops application groups [cronjob]")
+	for {
+		m.syncGitOpsApplicationGroups()
+		time.Sleep(2 * time.Minute)
+	}
+}
+
+func (m Manager) syncGitOpsApplicationGroups() {
+	ctx := context.Background()
+	groups, err := core.FindGitOpsEnabledApplicationGroups(ctx, m.ServiceManager.DbClient)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to fetch git ops enabled application groups", err.Error())
+		return
+	}
+	if len(groups) == 0 {
+		return
+	}
+	swarmManagerServer, err := core.FetchSwarmManager(&m.ServiceManager.DbClient)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to fetch swarm manager", err.Error())
+		return
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManagerServer)
+	if err != nil {
+		logger.CronJobLoggerError.Println("Failed to create docker client", err.Error())
+		return
+	}
+	restrictedPorts := make([]int, 0)
+	for _, port := range m.Config.SystemConfig.RestrictedPorts {
+		restrictedPorts = append(restrictedPorts, int(port))
+	}
+	options := declarative_state.GitOpsSyncOptions{
+		ApplyOptions: declarative_state.ApplyOptions{
+			DbClient:        m.ServiceManager.DbClient,
+			DockerManager:   dockerManager,
+			TaskEnqueuer:    m.WorkerManager,
+			RestrictedPorts: restrictedPorts,
+			CodeTarballDir:  m.Config.LocalConfig.ServiceConfig.TarballDirectoryPath,
+		},
+		ServiceManager:   *m.ServiceManager,
+		SwiftwaveVersion: m.Config.LocalConfig.Version,
+	}
+	for _, group := range groups {
+		plan, err := declarative_state.SyncApplicationGroup(ctx, options, group)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to sync application group", group.Name, err.Error())
+			continue
+		}
+		if plan.HasChanges() {
+			logger.CronJobLogger.Println("Synced application group", group.Name, "to commit", group.GitOps.LastAppliedCommit, "\n"+plan.String())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package cronjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"github.com/swiftwave-org/swiftwave/pkg/pubsub"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+// applicationEventSlotRetention : a failed task is replaced by swarm within this duration, after that the slot is forgotten
+const applicationEventSlotRetention = 30 * time.Minute
+
+func (m Manager) WatchApplicationEvents() {
+	logger.CronJobLogger.Println("Starting application events watcher [cronjob]")
+	tracker := core.NewApplicationEventTracker()
+	// <server hostname> -> watcher is running
+	watchers := make(map[string]bool)
+	// <server hostname> -> time of the last event of the server, the watcher resumes from it after reconnecting
+	lastEventTimes := make(map[string]time.Time)
+	mutex := &sync.Mutex{}
+	for {
+		servers, err := core.FetchAllOnlineServers(&m.ServiceManager.DbClient)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to fetch online servers", err.Error())
+		} else {
+			for _, server := range servers {
+				mutex.Lock()
+				isWatching := watchers[server.HostName]
+				watchers[server.HostName] = true
+				since := lastEventTimes[server.HostName]
+				mutex.Unlock()
+				if isWatching {
+					continue
+				}
+				go func(server core.Server, since time.Time) {
+					lastEventTime, err := m.watchApplicationEventsOnServer(server, tracker, since)
+					logger.CronJobLoggerError.Println("Application events watcher stopped for server", server.HostName, err)
+					tracker.ServerDisconnected(server.HostName)
+					mutex.Lock()
+					delete(watchers, server.HostName)
+					lastEventTimes[server.HostName] = lastEventTime
+					mutex.Unlock()
+				}(server, since)
+			}
+		}
+		tracker.Prune(time.Now().Add(-applicationEventSlotRetention))
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// watchApplicationEventsOnServer : stream the container events of the server after the given time till the connection is lost
+// Returns the time of the last event received, to resume from it on the next connection
+func (m Manager) watchApplicationEventsOnServer(server core.Server, tracker *core.ApplicationEventTracker, since time.Time) (time.Time, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dockerManager, err := manager.DockerClient(ctx, server)
+	if err != nil {
+		return since, err
+	}
+	defer func(dockerManager *containermanger.Manager) {
+		_ = dockerManager.Close()
+	}(dockerManager)
+	// events older than the slots of the tracker are not replayed
+	if minimumSince := time.Now().Add(-applicationEventSlotRetention); since.Before(minimumSince) && !since.IsZero() {
+		since = minimumSince
+	}
+	lastEventTime := since
+	if lastEventTime.IsZero() {
+		lastEventTime = time.Now()
+	}
+	containerEvents, errs := dockerManager.WatchContainerEvents(since)
+	logger.CronJobLogger.Println("Watching application events on server", server.HostName)
+	for containerEvent := range containerEvents {
+		// event of the last time can be streamed again while resuming
+		if !containerEvent.Time.After(since) && !since.IsZero() {
+			continue
+		}
+		if containerEvent.Time.After(lastEventTime) {
+			lastEventTime = containerEvent.Time
+		}
+		event := tracker.Track(server.HostName, containerEvent)
+		if event == nil {
+			continue
+		}
+		err = recordApplicationEvent(ctx, m.ServiceManager.DbClient, m.ServiceManager.PubSubClient, event)
+		if err != nil {
+			logger.CronJobLoggerError.Println("Failed to record application event of service", event.ServiceName, err.Error())
+		}
+	}
+	return lastEventTime, <-errs
+}
+
+// recordApplicationEvent : persist the event in the timeline of the application and publish it to the subscribers
+func recordApplicationEvent(ctx context.Context, db gorm.DB, pubSubClient pubsub.Client, event *core.ApplicationEvent) error {
+	applicationId, err := core.FindApplicationIdByServiceName(ctx, db, event.ServiceName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// not a service of any application
+			return nil
+		}
+		return err
+	}
+	event.ApplicationID = applicationId
+	err = event.Create(ctx, db)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return pubSubClient.Publish(core.ApplicationEventsTopic(applicationId), string(data))
+}
@@ -0,0 +1,2 @@
+-- reverse: modify "users" table
+ALTER TABLE "public"."users" ALTER COLUMN "role" SET DEFAULT 'user';
@@ -0,0 +1,4 @@
+-- modify "users" table
+ALTER TABLE "public"."users" ALTER COLUMN "role" SET DEFAULT 'admin';
+-- existing users had no effective role, keep their access by promoting them to admin
+UPDATE "public"."users" SET "role" = 'admin' WHERE "role" IS NULL OR "role" NOT IN ('admin', 'manager');
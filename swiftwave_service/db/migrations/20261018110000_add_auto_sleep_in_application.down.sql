@@ -0,0 +1,2 @@
+-- reverse: modify "applications" table
+ALTER TABLE "public"."applications" DROP COLUMN "auto_sleep_idle_timeout_minutes", DROP COLUMN "auto_sleep_enabled";
@@ -0,0 +1,2 @@
+-- modify "applications" table
+ALTER TABLE "public"."applications" ADD COLUMN "auto_sleep_enabled" boolean NULL DEFAULT false, ADD COLUMN "auto_sleep_idle_timeout_minutes" bigint NULL DEFAULT 30;
@@ -0,0 +1,2 @@
+-- reverse: modify "domains" table
+ALTER TABLE "public"."domains" DROP COLUMN "dns_provider_webhook_secret", DROP COLUMN "dns_provider_webhook_url", DROP COLUMN "dns_provider_rfc2136_tsig_algorithm", DROP COLUMN "dns_provider_rfc2136_tsig_secret", DROP COLUMN "dns_provider_rfc2136_tsig_key_name", DROP COLUMN "dns_provider_rfc2136_nameserver", DROP COLUMN "dns_provider_type";
@@ -0,0 +1,2 @@
+-- modify "domains" table
+ALTER TABLE "public"."domains" ADD COLUMN "dns_provider_type" text NULL DEFAULT 'none', ADD COLUMN "dns_provider_rfc2136_nameserver" text NULL, ADD COLUMN "dns_provider_rfc2136_tsig_key_name" text NULL, ADD COLUMN "dns_provider_rfc2136_tsig_secret" text NULL, ADD COLUMN "dns_provider_rfc2136_tsig_algorithm" text NULL, ADD COLUMN "dns_provider_webhook_url" text NULL, ADD COLUMN "dns_provider_webhook_secret" text NULL;
@@ -0,0 +1,4 @@
+-- reverse: modify "system_configs" table
+ALTER TABLE "public"."system_configs" DROP COLUMN "lets_encrypt_config_ca_certificate", DROP COLUMN "lets_encrypt_config_eab_hmac_key", DROP COLUMN "lets_encrypt_config_eab_key_id", DROP COLUMN "lets_encrypt_config_directory_url";
+-- reverse: modify "domains" table
+ALTER TABLE "public"."domains" DROP COLUMN "ssl_acme_directory";
@@ -0,0 +1,4 @@
+-- modify "domains" table
+ALTER TABLE "public"."domains" ADD COLUMN "ssl_acme_directory" text NULL;
+-- modify "system_configs" table
+ALTER TABLE "public"."system_configs" ADD COLUMN "lets_encrypt_config_directory_url" text NULL, ADD COLUMN "lets_encrypt_config_eab_key_id" text NULL, ADD COLUMN "lets_encrypt_config_eab_hmac_key" text NULL, ADD COLUMN "lets_encrypt_config_ca_certificate" text NULL;
@@ -0,0 +1,2 @@
+-- reverse: modify "applications" table
+ALTER TABLE "public"."applications" DROP COLUMN "deployment_strategy_canary_weight_percent", DROP COLUMN "deployment_strategy_type";
@@ -0,0 +1,2 @@
+-- modify "applications" table
+ALTER TABLE "public"."applications" ADD COLUMN "deployment_strategy_type" text NULL DEFAULT 'rolling', ADD COLUMN "deployment_strategy_canary_weight_percent" bigint NULL DEFAULT 10;
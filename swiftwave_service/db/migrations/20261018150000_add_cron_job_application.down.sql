@@ -0,0 +1,4 @@
+-- reverse: create "cron_job_runs" table
+DROP TABLE "public"."cron_job_runs";
+-- reverse: modify "applications" table
+ALTER TABLE "public"."applications" DROP COLUMN "cron_job_history_limit", DROP COLUMN "cron_job_concurrency_policy", DROP COLUMN "cron_job_schedule", DROP COLUMN "kind";
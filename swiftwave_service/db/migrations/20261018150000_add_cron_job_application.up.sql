@@ -0,0 +1,18 @@
+-- modify "applications" table
+ALTER TABLE "public"."applications" ADD COLUMN "kind" text NULL DEFAULT 'service', ADD COLUMN "cron_job_schedule" text NULL, ADD COLUMN "cron_job_concurrency_policy" text NULL DEFAULT 'forbid', ADD COLUMN "cron_job_history_limit" bigint NULL DEFAULT 10;
+-- create "cron_job_runs" table
+CREATE TABLE "public"."cron_job_runs" (
+  "id" bigserial NOT NULL,
+  "application_id" text NULL,
+  "deployment_id" text NULL,
+  "trigger" text NULL,
+  "status" text NULL,
+  "exit_code" bigint NULL,
+  "message" text NULL,
+  "logs" text NULL,
+  "created_at" timestamptz NULL,
+  "started_at" timestamptz NULL,
+  "finished_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_applications_cron_job_runs" FOREIGN KEY ("application_id") REFERENCES "public"."applications" ("id") ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- reverse: modify "environment_variables" table
+ALTER TABLE "public"."environment_variables" DROP COLUMN "expose_as_secret";
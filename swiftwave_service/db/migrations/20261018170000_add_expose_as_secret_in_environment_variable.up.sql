@@ -0,0 +1,2 @@
+-- modify "environment_variables" table
+ALTER TABLE "public"."environment_variables" ADD COLUMN "expose_as_secret" boolean NULL DEFAULT false;
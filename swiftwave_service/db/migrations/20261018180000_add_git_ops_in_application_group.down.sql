@@ -0,0 +1,2 @@
+-- reverse: modify "application_groups" table
+ALTER TABLE "public"."application_groups" DROP COLUMN "git_ops_drift_details", DROP COLUMN "git_ops_drift_detected_at", DROP COLUMN "git_ops_sync_error", DROP COLUMN "git_ops_last_synced_at", DROP COLUMN "git_ops_last_applied_commit", DROP COLUMN "git_ops_sync_status", DROP COLUMN "git_ops_stack_file_path", DROP COLUMN "git_ops_repository_branch", DROP COLUMN "git_ops_repository_url", DROP COLUMN "git_ops_git_credential_id", DROP COLUMN "git_ops_enabled";
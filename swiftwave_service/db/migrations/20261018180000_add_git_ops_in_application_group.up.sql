@@ -0,0 +1,2 @@
+-- modify "application_groups" table
+ALTER TABLE "public"."application_groups" ADD COLUMN "git_ops_enabled" boolean NULL DEFAULT false, ADD COLUMN "git_ops_git_credential_id" bigint NULL, ADD COLUMN "git_ops_repository_url" text NULL, ADD COLUMN "git_ops_repository_branch" text NULL, ADD COLUMN "git_ops_stack_file_path" text NULL, ADD COLUMN "git_ops_sync_status" text NULL DEFAULT 'pending', ADD COLUMN "git_ops_last_applied_commit" text NULL, ADD COLUMN "git_ops_last_synced_at" timestamptz NULL, ADD COLUMN "git_ops_sync_error" text NULL, ADD COLUMN "git_ops_drift_detected_at" timestamptz NULL, ADD COLUMN "git_ops_drift_details" text NULL;
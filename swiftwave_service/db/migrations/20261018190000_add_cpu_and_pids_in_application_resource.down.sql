@@ -0,0 +1,2 @@
+-- reverse: modify "applications" table
+ALTER TABLE "public"."applications" DROP COLUMN "reserved_resource_nano_cpus", DROP COLUMN "resource_limit_pids_limit", DROP COLUMN "resource_limit_nano_cpus";
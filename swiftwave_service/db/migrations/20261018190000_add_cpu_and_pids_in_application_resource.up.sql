@@ -0,0 +1,2 @@
+-- modify "applications" table
+ALTER TABLE "public"."applications" ADD COLUMN "resource_limit_nano_cpus" bigint NULL DEFAULT 0, ADD COLUMN "resource_limit_pids_limit" bigint NULL DEFAULT 0, ADD COLUMN "reserved_resource_nano_cpus" bigint NULL DEFAULT 0;
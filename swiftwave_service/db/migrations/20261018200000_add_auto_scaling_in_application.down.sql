@@ -0,0 +1,4 @@
+-- reverse: create "auto_scaling_events" table
+DROP TABLE "public"."auto_scaling_events";
+-- reverse: modify "applications" table
+ALTER TABLE "public"."applications" DROP COLUMN "auto_scaling_cooldown_seconds", DROP COLUMN "auto_scaling_target_percent", DROP COLUMN "auto_scaling_metric", DROP COLUMN "auto_scaling_max_replicas", DROP COLUMN "auto_scaling_min_replicas", DROP COLUMN "auto_scaling_enabled";
@@ -0,0 +1,15 @@
+-- modify "applications" table
+ALTER TABLE "public"."applications" ADD COLUMN "auto_scaling_enabled" boolean NULL DEFAULT false, ADD COLUMN "auto_scaling_min_replicas" bigint NULL DEFAULT 1, ADD COLUMN "auto_scaling_max_replicas" bigint NULL DEFAULT 1, ADD COLUMN "auto_scaling_metric" text NULL DEFAULT 'cpu', ADD COLUMN "auto_scaling_target_percent" bigint NULL DEFAULT 70, ADD COLUMN "auto_scaling_cooldown_seconds" bigint NULL DEFAULT 300;
+-- create "auto_scaling_events" table
+CREATE TABLE "public"."auto_scaling_events" (
+  "id" bigserial NOT NULL,
+  "application_id" text NULL,
+  "from_replicas" bigint NULL,
+  "to_replicas" bigint NULL,
+  "metric" text NULL,
+  "usage_percent" bigint NULL,
+  "target_percent" bigint NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_applications_auto_scaling_events" FOREIGN KEY ("application_id") REFERENCES "public"."applications" ("id") ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- reverse: create index "idx_application_events_application_id" to table: "application_events"
+DROP INDEX "public"."idx_application_events_application_id";
+-- reverse: create "application_events" table
+DROP TABLE "public"."application_events";
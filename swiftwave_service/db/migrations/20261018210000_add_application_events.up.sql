@@ -0,0 +1,17 @@
+-- create "application_events" table
+CREATE TABLE "public"."application_events" (
+  "id" bigserial NOT NULL,
+  "application_id" text NULL,
+  "type" text NULL,
+  "server_host_name" text NULL,
+  "service_name" text NULL,
+  "task_id" text NULL,
+  "container_id" text NULL,
+  "exit_code" bigint NULL,
+  "message" text NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_applications_events" FOREIGN KEY ("application_id") REFERENCES "public"."applications" ("id") ON UPDATE CASCADE ON DELETE CASCADE
+);
+-- create index "idx_application_events_application_id" to table: "application_events"
+CREATE INDEX "idx_application_events_application_id" ON "public"."application_events" ("application_id");
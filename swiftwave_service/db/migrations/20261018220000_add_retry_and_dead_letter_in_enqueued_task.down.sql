@@ -0,0 +1,2 @@
+-- reverse: modify "enqueued_tasks" table
+ALTER TABLE "public"."enqueued_tasks" DROP COLUMN "failed_at", DROP COLUMN "dead_letter", DROP COLUMN "failure_reason", DROP COLUMN "attempts";
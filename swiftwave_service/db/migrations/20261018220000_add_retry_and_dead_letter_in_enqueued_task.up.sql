@@ -0,0 +1,2 @@
+-- modify "enqueued_tasks" table
+ALTER TABLE "public"."enqueued_tasks" ADD COLUMN "attempts" bigint NULL DEFAULT 0, ADD COLUMN "failure_reason" text NULL, ADD COLUMN "dead_letter" boolean NULL DEFAULT false, ADD COLUMN "failed_at" timestamptz NULL;
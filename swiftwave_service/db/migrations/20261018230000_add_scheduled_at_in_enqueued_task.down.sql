@@ -0,0 +1,2 @@
+-- reverse: modify "enqueued_tasks" table
+ALTER TABLE "public"."enqueued_tasks" DROP COLUMN "scheduled_at";
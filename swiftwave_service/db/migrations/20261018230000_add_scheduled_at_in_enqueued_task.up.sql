@@ -0,0 +1,2 @@
+-- modify "enqueued_tasks" table
+ALTER TABLE "public"."enqueued_tasks" ADD COLUMN "scheduled_at" timestamptz NULL;
@@ -0,0 +1,2 @@
+-- reverse: modify "system_configs" table
+ALTER TABLE "public"."system_configs" DROP COLUMN "task_queue_config_nats_password", DROP COLUMN "task_queue_config_nats_username", DROP COLUMN "task_queue_config_nats_url", DROP COLUMN "pub_sub_config_nats_password", DROP COLUMN "pub_sub_config_nats_username", DROP COLUMN "pub_sub_config_nats_url";
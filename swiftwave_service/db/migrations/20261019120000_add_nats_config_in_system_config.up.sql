@@ -0,0 +1,2 @@
+-- modify "system_configs" table
+ALTER TABLE "public"."system_configs" ADD COLUMN "pub_sub_config_nats_url" text NULL, ADD COLUMN "pub_sub_config_nats_username" text NULL, ADD COLUMN "pub_sub_config_nats_password" text NULL, ADD COLUMN "task_queue_config_nats_url" text NULL, ADD COLUMN "task_queue_config_nats_username" text NULL, ADD COLUMN "task_queue_config_nats_password" text NULL;
@@ -0,0 +1,8 @@
+-- reverse: create index "idx_task_runs_started_at" to table: "task_runs"
+DROP INDEX "public"."idx_task_runs_started_at";
+-- reverse: create index "idx_task_runs_queue_name" to table: "task_runs"
+DROP INDEX "public"."idx_task_runs_queue_name";
+-- reverse: create index "idx_task_runs_label" to table: "task_runs"
+DROP INDEX "public"."idx_task_runs_label";
+-- reverse: create "task_runs" table
+DROP TABLE "public"."task_runs";
@@ -0,0 +1,19 @@
+-- create "task_runs" table
+CREATE TABLE "public"."task_runs" (
+  "id" bigserial NOT NULL,
+  "queue_name" text NULL,
+  "task_id" text NULL,
+  "body" text NULL,
+  "label" text NULL,
+  "status" text NULL,
+  "error" text NULL,
+  "started_at" timestamptz NULL,
+  "finished_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_task_runs_label" to table: "task_runs"
+CREATE INDEX "idx_task_runs_label" ON "public"."task_runs" ("label");
+-- create index "idx_task_runs_queue_name" to table: "task_runs"
+CREATE INDEX "idx_task_runs_queue_name" ON "public"."task_runs" ("queue_name");
+-- create index "idx_task_runs_started_at" to table: "task_runs"
+CREATE INDEX "idx_task_runs_started_at" ON "public"."task_runs" ("started_at");
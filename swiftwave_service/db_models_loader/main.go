@@ -42,6 +42,7 @@ func main() {
 		&core.AppBasicAuthAccessControlList{},
 		&core.AppBasicAuthAccessControlUser{},
 		&task_queue.EnqueuedTask{},
+		&task_queue.TaskRun{},
 	)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "failed to load gorm schema: %v\n", err)
@@ -0,0 +1,198 @@
+package declarative_state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/stack_parser"
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyApplicationGroup : reconcile only the applications of the group, rest of the state is kept as it is
+// Applications of the group which are not in the list are deleted along with the ingress rules pointing to them.
+// Given ingress rules are created or updated, other ingress rules of the applications are kept as it is.
+// Returns the applied changes.
+func ApplyApplicationGroup(ctx context.Context, options ApplyOptions, group ApplicationGroup, applications []Application, ingressRules []IngressRule) (*Plan, error) {
+	current, err := exportDocument(ctx, options.DbClient, true)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := current.withApplicationGroup(group, applications, ingressRules)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := computePlan(current, desired, true)
+	if err != nil {
+		return nil, err
+	}
+	return applyPlan(ctx, options, plan)
+}
+
+// withApplicationGroup : copy of the document in which the applications of the group are replaced by the given applications
+func (d *Document) withApplicationGroup(group ApplicationGroup, applications []Application, ingressRules []IngressRule) (*Document, error) {
+	desired := *d
+	desired.ApplicationGroups = []ApplicationGroup{group}
+	for _, applicationGroup := range d.ApplicationGroups {
+		if applicationGroup.Name != group.Name {
+			desired.ApplicationGroups = append(desired.ApplicationGroups, applicationGroup)
+		}
+	}
+	applicationNames := make(map[string]bool)
+	for _, application := range applications {
+		applicationNames[application.Name] = true
+	}
+	removedApplicationNames := make(map[string]bool)
+	desired.Applications = make([]Application, 0)
+	for _, application := range d.Applications {
+		if application.Group == group.Name {
+			if !applicationNames[application.Name] {
+				removedApplicationNames[application.Name] = true
+			}
+			continue
+		}
+		if applicationNames[application.Name] {
+			return nil, fmt.Errorf("application %s already exists outside of application group %s", application.Name, group.Name)
+		}
+		desired.Applications = append(desired.Applications, application)
+	}
+	for _, application := range applications {
+		application.Group = group.Name
+		desired.Applications = append(desired.Applications, application)
+	}
+	// ingress rules of the removed applications are deleted as well
+	desired.IngressRules = make([]IngressRule, 0)
+	for _, ingressRule := range d.IngressRules {
+		if ingressRule.TargetType == core.ApplicationIngressRule && removedApplicationNames[ingressRule.Application] {
+			continue
+		}
+		desired.IngressRules = append(desired.IngressRules, ingressRule)
+	}
+	for _, ingressRule := range ingressRules {
+		if !applicationNames[ingressRule.Application] {
+			return nil, fmt.Errorf("ingress rule %s points to unknown application %s", ingressRule.key(), ingressRule.Application)
+		}
+		isReplaced := false
+		for i, existingIngressRule := range desired.IngressRules {
+			if existingIngressRule.key() != ingressRule.key() {
+				continue
+			}
+			if existingIngressRule.TargetType != core.ApplicationIngressRule || !applicationNames[existingIngressRule.Application] {
+				return nil, fmt.Errorf("ingress rule %s is already in use", ingressRule.key())
+			}
+			// options configured from dashboard are kept
+			ingressRule.HttpsRedirect = existingIngressRule.HttpsRedirect
+			ingressRule.AccessControlList = existingIngressRule.AccessControlList
+			desired.IngressRules[i] = ingressRule
+			isReplaced = true
+			break
+		}
+		if !isReplaced {
+			desired.IngressRules = append(desired.IngressRules, ingressRule)
+		}
+	}
+	desired.fillDefaults()
+	desired.sort()
+	return &desired, nil
+}
+
+// ApplicationsFromStack : convert the services of the stack to applications, variables of the stack should be filled already
+func ApplicationsFromStack(stack *stack_parser.Stack) ([]Application, error) {
+	applications := make([]Application, 0)
+	for serviceName, service := range stack.Services {
+		application := Application{
+			Name: serviceName,
+			Kind: core.ApplicationKindService,
+			Source: ApplicationSource{
+				UpstreamType: core.UpstreamTypeImage,
+				DockerImage:  service.Image,
+			},
+			DeploymentMode:           core.DeploymentMode(service.Deploy.Mode),
+			Replicas:                 service.Deploy.Replicas,
+			Hostname:                 service.Hostname,
+			Capabilities:             service.CapAdd,
+			PreferredServerHostnames: service.PreferredServerHostnames,
+			ResourceLimit: core.ApplicationResourceLimit{
+				MemoryMB:  service.Deploy.Resources.Limits.MemoryMB,
+				NanoCPUs:  service.Deploy.Resources.Limits.NanoCPUs,
+				PidsLimit: service.Deploy.Resources.Limits.Pids,
+			},
+			ReservedResource: core.ApplicationReservedResource{
+				MemoryMB: service.Deploy.Resources.Reservations.MemoryMB,
+				NanoCPUs: service.Deploy.Resources.Reservations.NanoCPUs,
+			},
+		}
+		if service.Command != nil {
+			application.Command = service.Command.String()
+		}
+		for key, value := range service.Sysctls {
+			application.Sysctls = append(application.Sysctls, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(application.Sysctls)
+		for key, value := range service.Environment {
+			application.EnvironmentVariables = append(application.EnvironmentVariables, EnvironmentVariable{
+				Key:   key,
+				Value: value,
+			})
+		}
+		for key, value := range service.SecretEnvironment {
+			application.EnvironmentVariables = append(application.EnvironmentVariables, EnvironmentVariable{
+				Key:            key,
+				Value:          value,
+				ExposeAsSecret: true,
+			})
+		}
+		for _, config := range service.Configs {
+			application.ConfigMounts = append(application.ConfigMounts, ConfigMount{
+				MountingPath: config.MountingPath,
+				Content:      config.Content,
+				Uid:          config.Uid,
+				Gid:          config.Gid,
+			})
+		}
+		for _, volume := range service.Volumes {
+			application.PersistentVolumeBindings = append(application.PersistentVolumeBindings, PersistentVolumeBinding{
+				PersistentVolume: volume.Name,
+				MountingPath:     volume.MountingPoint,
+			})
+		}
+		// health check and docker proxy config of stack share the same yaml format
+		err := convertByYaml(service.CustomHealthCheck, &application.CustomHealthCheck)
+		if err != nil {
+			return nil, err
+		}
+		err = convertByYaml(service.DockerProxyConfig, &application.DockerProxy)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, nil
+}
+
+// IngressRulesFromStack : convert the published ports of the services to ingress rules, variables of the stack should be filled already
+func IngressRulesFromStack(stack *stack_parser.Stack) []IngressRule {
+	ingressRules := make([]IngressRule, 0)
+	for serviceName, service := range stack.Services {
+		for _, port := range service.Ports {
+			ingressRules = append(ingressRules, IngressRule{
+				Protocol:    core.ProtocolType(port.IngressProtocol()),
+				Domain:      port.Domain,
+				Port:        port.Published,
+				TargetType:  core.ApplicationIngressRule,
+				Application: serviceName,
+				TargetPort:  port.Target,
+			})
+		}
+	}
+	return ingressRules
+}
+
+func convertByYaml(source interface{}, destination interface{}) error {
+	content, err := yaml.Marshal(source)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(content, destination)
+}
@@ -0,0 +1,120 @@
+package declarative_state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/stack_parser"
+)
+
+const groupDocument = `
+version: v1
+application_groups:
+  - name: blog
+applications:
+  - name: blog_web
+    group: blog
+    source:
+      upstream_type: image
+      docker_image: ghost:latest
+    replicas: 1
+  - name: blog_db
+    group: blog
+    source:
+      upstream_type: image
+      docker_image: mysql:8
+    replicas: 1
+  - name: api
+    source:
+      upstream_type: image
+      docker_image: api:latest
+    replicas: 1
+ingress_rules:
+  - protocol: tcp
+    port: 3306
+    application: blog_db
+    target_port: 3306
+`
+
+func TestWithApplicationGroup(t *testing.T) {
+	current := mustParse(t, groupDocument)
+	web := current.Applications[2]
+
+	t.Run("only the applications of the group are pruned", func(t *testing.T) {
+		desired, err := current.withApplicationGroup(ApplicationGroup{Name: "blog"}, []Application{web}, nil)
+		assert.NoError(t, err)
+		plan, err := computePlan(current, desired, true)
+		assert.NoError(t, err)
+		if assert.Len(t, plan.Changes, 2) {
+			assert.Equal(t, ChangeActionDelete, plan.Changes[0].Action)
+			assert.Equal(t, ingressRuleKind, plan.Changes[0].Kind)
+			assert.Equal(t, applicationKind, plan.Changes[1].Kind)
+			assert.Equal(t, "blog_db", plan.Changes[1].Name)
+		}
+	})
+
+	t.Run("applications outside of the group can't be taken over", func(t *testing.T) {
+		api := current.Applications[0]
+		api.Group = ""
+		_, err := current.withApplicationGroup(ApplicationGroup{Name: "blog"}, []Application{api}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("ingress rules of the stack are added or replaced", func(t *testing.T) {
+		db := current.Applications[1]
+		ingressRules := []IngressRule{
+			{Protocol: core.TCPProtocol, Port: 3306, TargetType: core.ApplicationIngressRule, Application: "blog_db", TargetPort: 3306},
+			{Protocol: core.TCPProtocol, Port: 2368, TargetType: core.ApplicationIngressRule, Application: "blog_web", TargetPort: 2368},
+		}
+		desired, err := current.withApplicationGroup(ApplicationGroup{Name: "blog"}, []Application{web, db}, ingressRules)
+		assert.NoError(t, err)
+		plan, err := computePlan(current, desired, true)
+		assert.NoError(t, err)
+		if assert.Len(t, plan.Changes, 1) {
+			assert.Equal(t, ChangeActionCreate, plan.Changes[0].Action)
+			assert.Equal(t, ingressRuleKind, plan.Changes[0].Kind)
+		}
+	})
+
+	t.Run("ingress rules of other applications can't be taken over", func(t *testing.T) {
+		document := mustParse(t, groupDocument)
+		document.IngressRules = append(document.IngressRules, IngressRule{Protocol: core.TCPProtocol, Port: 8080, TargetType: core.ApplicationIngressRule, Application: "api", TargetPort: 8080})
+		ingressRules := []IngressRule{
+			{Protocol: core.TCPProtocol, Port: 8080, TargetType: core.ApplicationIngressRule, Application: "blog_web", TargetPort: 8080},
+		}
+		_, err := document.withApplicationGroup(ApplicationGroup{Name: "blog"}, []Application{web}, ingressRules)
+		assert.Error(t, err)
+	})
+}
+
+func TestApplicationsFromStack(t *testing.T) {
+	stack, err := stack_parser.ParseStackYaml(`
+services:
+  web:
+    image: nginx:latest
+    environment:
+      MODE: production
+    sysctls:
+      net.core.somaxconn: "1024"
+    custom_health_check:
+      enabled: true
+      test_command: curl -f http://localhost
+`, "develop")
+	assert.NoError(t, err)
+	applications, err := ApplicationsFromStack(&stack)
+	assert.NoError(t, err)
+	if assert.Len(t, applications, 1) {
+		application := applications[0]
+		assert.Equal(t, "{{STACK_NAME}}_web", application.Name)
+		assert.Equal(t, core.UpstreamTypeImage, application.Source.UpstreamType)
+		assert.Equal(t, "nginx:latest", application.Source.DockerImage)
+		assert.Equal(t, core.DeploymentModeReplicated, application.DeploymentMode)
+		assert.Equal(t, uint(1), application.Replicas)
+		assert.Equal(t, []string{"net.core.somaxconn=1024"}, application.Sysctls)
+		assert.Equal(t, []EnvironmentVariable{{Key: "MODE", Value: "production"}}, application.EnvironmentVariables)
+		assert.True(t, application.CustomHealthCheck.Enabled)
+		assert.Equal(t, "curl -f http://localhost", application.CustomHealthCheck.TestCommand)
+		assert.Equal(t, core.DockerProxyNoPermission, application.DockerProxy.Permission.Exec)
+	}
+}
@@ -0,0 +1,605 @@
+package declarative_state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+// ApplyOptions : dependencies required to apply the document
+type ApplyOptions struct {
+	DbClient      gorm.DB
+	DockerManager *containermanger.Manager
+	TaskEnqueuer  TaskEnqueuer
+	// RestrictedPorts - ports which can't be used by ingress rules
+	RestrictedPorts []int
+	// CodeTarballDir - directory of the uploaded source code of applications
+	CodeTarballDir string
+	// Prune - delete the applications, ingress rules and redirect rules which are not in the document
+	Prune bool
+}
+
+// Apply : reconcile the database with the document and enqueue the tasks to bring the changes live
+// Changes are applied one by one, if a change fails the already applied changes are kept.
+// Applying is idempotent, so the document can be applied again after fixing the issue.
+// Returns the applied changes.
+func Apply(ctx context.Context, options ApplyOptions, desired *Document) (*Plan, error) {
+	plan, err := ComputePlan(ctx, options.DbClient, desired, options.Prune)
+	if err != nil {
+		return nil, err
+	}
+	return applyPlan(ctx, options, plan)
+}
+
+// applyPlan : apply the changes one by one, returns the applied changes
+func applyPlan(ctx context.Context, options ApplyOptions, plan *Plan) (*Plan, error) {
+	applied := &Plan{
+		Changes:  make([]Change, 0),
+		Warnings: plan.Warnings,
+	}
+	for _, change := range plan.Changes {
+		err := applyChange(ctx, options, change, applied)
+		if err != nil {
+			return applied, fmt.Errorf("failed to %s %s %s > %s", change.Action, change.Kind, change.Name, err.Error())
+		}
+		applied.Changes = append(applied.Changes, change)
+	}
+	return applied, nil
+}
+
+func applyChange(ctx context.Context, options ApplyOptions, change Change, applied *Plan) error {
+	switch resource := change.resource.(type) {
+	case PersistentVolume:
+		return applyPersistentVolume(ctx, options, resource)
+	case AccessControlList:
+		return applyAccessControlList(ctx, options, change.Action, resource)
+	case Domain:
+		return applyDomain(ctx, options, change.Action, resource)
+	case ApplicationGroup:
+		return applyApplicationGroup(ctx, options, change.Action, resource)
+	case Application:
+		return applyApplication(ctx, options, change.Action, resource)
+	case IngressRule:
+		return applyIngressRule(ctx, options, change.Action, resource, applied)
+	case RedirectRule:
+		return applyRedirectRule(ctx, options, change.Action, resource)
+	}
+	return errors.New("unknown kind of resource")
+}
+
+// applyPersistentVolume : persistent volumes can only be created
+func applyPersistentVolume(ctx context.Context, options ApplyOptions, resource PersistentVolume) error {
+	record := &core.PersistentVolume{
+		Name: resource.Name,
+		Type: resource.Type,
+	}
+	if resource.NFSConfig != nil {
+		record.NFSConfig = *resource.NFSConfig
+	}
+	if resource.CIFSConfig != nil {
+		record.CIFSConfig = *resource.CIFSConfig
+	}
+	return record.Create(ctx, options.DbClient, manager.DockerClient)
+}
+
+// applyAccessControlList : the changes are applied on the proxies first and then saved, so no database transaction is kept open while talking to the proxies
+func applyAccessControlList(ctx context.Context, options ApplyOptions, action ChangeAction, resource AccessControlList) error {
+	db := options.DbClient
+	record := &core.AppBasicAuthAccessControlList{}
+	var haproxyActions []func(transactionId string, haproxyManager *haproxymanager.Manager) error
+	var dbActions []func(tx *gorm.DB) error
+	existingUsers := make(map[string]*core.AppBasicAuthAccessControlUser)
+	if action == ChangeActionCreate {
+		// the generated name of the user list is required by the proxies, so the record is created upfront
+		record.Name = resource.Name
+		err := record.Create(ctx, &db)
+		if err != nil {
+			return err
+		}
+		haproxyActions = append(haproxyActions, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+			return haproxyManager.AddUserList(transactionId, record.GeneratedName)
+		})
+	} else {
+		err := db.Where("name = ?", resource.Name).First(record).Error
+		if err != nil {
+			return err
+		}
+		users, err := core.FetchAppBasicAuthAccessControlUsers(ctx, &db, record.ID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			existingUsers[user.Username] = user
+		}
+	}
+	desiredUsers := make(map[string]bool)
+	for _, user := range resource.Users {
+		desiredUsers[user.Username] = true
+		username, encryptedPassword := user.Username, user.EncryptedPassword
+		existingUser, ok := existingUsers[username]
+		if !ok {
+			dbActions = append(dbActions, func(tx *gorm.DB) error {
+				return (&core.AppBasicAuthAccessControlUser{
+					Username:                        username,
+					EncryptedPassword:               encryptedPassword,
+					AppBasicAuthAccessControlListID: record.ID,
+				}).Create(ctx, tx)
+			})
+			haproxyActions = append(haproxyActions, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+				return haproxyManager.AddUserInUserList(transactionId, record.GeneratedName, username, encryptedPassword)
+			})
+		} else if existingUser.EncryptedPassword != encryptedPassword {
+			dbActions = append(dbActions, func(tx *gorm.DB) error {
+				existingUser.EncryptedPassword = encryptedPassword
+				return existingUser.Update(ctx, tx)
+			})
+			haproxyActions = append(haproxyActions, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+				return haproxyManager.ChangeUserPasswordInUserList(transactionId, record.GeneratedName, username, encryptedPassword)
+			})
+		}
+	}
+	for username, existingUser := range existingUsers {
+		if desiredUsers[username] {
+			continue
+		}
+		username, existingUser := username, existingUser
+		dbActions = append(dbActions, func(tx *gorm.DB) error {
+			return existingUser.Delete(ctx, tx)
+		})
+		haproxyActions = append(haproxyActions, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+			return haproxyManager.DeleteUserFromUserList(transactionId, record.GeneratedName, username)
+		})
+	}
+	err := manager.RunActionsInAllHAProxyNodes(ctx, db, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+		for _, haproxyAction := range haproxyActions {
+			err := haproxyAction(transactionId, haproxyManager)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if action == ChangeActionCreate {
+			deleteErr := record.Delete(ctx, &db)
+			if deleteErr != nil {
+				log.Println("failed to delete access control list "+record.Name, deleteErr)
+			}
+		}
+		return err
+	}
+	tx := db.Begin()
+	defer tx.Rollback()
+	for _, dbAction := range dbActions {
+		err = dbAction(tx)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+func applyDomain(ctx context.Context, options ApplyOptions, action ChangeAction, resource Domain) error {
+	record := &core.Domain{}
+	if action == ChangeActionUpdate {
+		err := options.DbClient.Where("name = ?", resource.Name).First(record).Error
+		if err != nil {
+			return err
+		}
+	}
+	record.Name = resource.Name
+	record.DNSProvider = core.DomainDNSProvider{
+		Type: core.DNSProviderNone,
+	}
+	if resource.DNSProvider != nil {
+		record.DNSProvider = *resource.DNSProvider
+	}
+	if action == ChangeActionUpdate {
+		return record.Update(ctx, options.DbClient)
+	}
+	err := record.Create(ctx, options.DbClient)
+	if err != nil {
+		return err
+	}
+	// issue the certificate like a domain added from the dashboard
+	return options.TaskEnqueuer.EnqueueSSLGenerateRequest(record.ID)
+}
+
+func applyApplicationGroup(ctx context.Context, options ApplyOptions, action ChangeAction, resource ApplicationGroup) error {
+	record := &core.ApplicationGroup{}
+	if action == ChangeActionUpdate {
+		err := options.DbClient.Where("name = ?", resource.Name).First(record).Error
+		if err != nil {
+			return err
+		}
+		record.Logo = resource.Logo
+		return record.Update(ctx, options.DbClient)
+	}
+	record.Name = resource.Name
+	record.Logo = resource.Logo
+	return record.Create(ctx, options.DbClient)
+}
+
+func applyApplication(ctx context.Context, options ApplyOptions, action ChangeAction, resource Application) error {
+	db := options.DbClient
+	if action == ChangeActionDelete {
+		record := &core.Application{}
+		err := record.FindByName(ctx, db, resource.Name)
+		if err != nil {
+			return err
+		}
+		err = record.SoftDelete(ctx, db, *options.DockerManager)
+		if err != nil {
+			return err
+		}
+		return options.TaskEnqueuer.EnqueueDeleteApplicationRequest(record.ID)
+	}
+	application, err := toCoreApplication(ctx, db, resource)
+	if err != nil {
+		return err
+	}
+	if action == ChangeActionCreate {
+		tx := db.Begin()
+		err = application.Create(ctx, *tx, *options.DockerManager, options.CodeTarballDir)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		err = tx.Commit().Error
+		if err != nil {
+			return err
+		}
+		latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, db, application.ID)
+		if err != nil {
+			return err
+		}
+		return options.TaskEnqueuer.EnqueueBuildApplicationRequest(application.ID, latestDeployment.ID)
+	}
+	record := &core.Application{}
+	err = record.FindByName(ctx, db, resource.Name)
+	if err != nil {
+		return err
+	}
+	application.ID = record.ID
+	application.LatestDeployment.ApplicationID = record.ID
+	// keep the built commit if the repository is not changed, the build fetches the latest commit otherwise
+	sourceDeployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, db, record.ID)
+	if err != nil {
+		return err
+	}
+	if application.LatestDeployment.UpstreamType == core.UpstreamTypeGit && application.LatestDeployment.GitRepositoryURL() == sourceDeployment.GitRepositoryURL() && application.LatestDeployment.RepositoryBranch == sourceDeployment.RepositoryBranch {
+		application.LatestDeployment.CommitHash = sourceDeployment.CommitHash
+	}
+	result, err := application.Update(ctx, db, *options.DockerManager)
+	if err != nil {
+		return err
+	}
+	if !isSameOptionalString(record.ApplicationGroupID, application.ApplicationGroupID) {
+		err = record.UpdateGroup(ctx, db, application.ApplicationGroupID)
+		if err != nil {
+			return err
+		}
+	}
+	if result.RebuildRequired {
+		return options.TaskEnqueuer.EnqueueBuildApplicationRequest(record.ID, result.DeploymentId)
+	}
+	if result.ReloadRequired {
+		return options.TaskEnqueuer.EnqueueDeployApplicationRequest(record.ID, result.DeploymentId)
+	}
+	return nil
+}
+
+// toCoreApplication : convert the application of document to database object, references are resolved to ids
+func toCoreApplication(ctx context.Context, db gorm.DB, resource Application) (*core.Application, error) {
+	application := &core.Application{
+		Name:                     resource.Name,
+		Kind:                     resource.Kind,
+		DeploymentMode:           resource.DeploymentMode,
+		Replicas:                 resource.Replicas,
+		Hostname:                 resource.Hostname,
+		Command:                  resource.Command,
+		ReleaseCommand:           resource.ReleaseCommand,
+		Capabilities:             resource.Capabilities,
+		Sysctls:                  resource.Sysctls,
+		PreferredServerHostnames: resource.PreferredServerHostnames,
+		ResourceLimit:            resource.ResourceLimit,
+		ReservedResource:         resource.ReservedResource,
+		CustomHealthCheck:        resource.CustomHealthCheck,
+		DockerProxy:              resource.DockerProxy,
+		AutoSleep:                resource.AutoSleep,
+		AutoScaling:              resource.AutoScaling,
+		DeploymentStrategy:       resource.DeploymentStrategy,
+		EnvironmentVariables:     make([]core.EnvironmentVariable, 0),
+		ConfigMounts:             make([]core.ConfigMount, 0),
+		PersistentVolumeBindings: make([]core.PersistentVolumeBinding, 0),
+	}
+	if resource.CronJob != nil {
+		application.CronJob = *resource.CronJob
+	}
+	if resource.Group != "" {
+		group := &core.ApplicationGroup{}
+		err := db.Where("name = ?", resource.Group).First(group).Error
+		if err != nil {
+			return nil, fmt.Errorf("application group %s not found", resource.Group)
+		}
+		application.ApplicationGroupID = &group.ID
+	}
+	for _, environmentVariable := range resource.EnvironmentVariables {
+		application.EnvironmentVariables = append(application.EnvironmentVariables, core.EnvironmentVariable{
+			Key:            environmentVariable.Key,
+			Value:          environmentVariable.Value,
+			ExposeAsSecret: environmentVariable.ExposeAsSecret,
+		})
+	}
+	for _, configMount := range resource.ConfigMounts {
+		application.ConfigMounts = append(application.ConfigMounts, core.ConfigMount{
+			MountingPath: configMount.MountingPath,
+			Content:      configMount.Content,
+			Uid:          configMount.Uid,
+			Gid:          configMount.Gid,
+		})
+	}
+	for _, persistentVolumeBinding := range resource.PersistentVolumeBindings {
+		persistentVolume := &core.PersistentVolume{}
+		err := persistentVolume.FindByName(ctx, db, persistentVolumeBinding.PersistentVolume)
+		if err != nil {
+			return nil, fmt.Errorf("persistent volume %s not found", persistentVolumeBinding.PersistentVolume)
+		}
+		application.PersistentVolumeBindings = append(application.PersistentVolumeBindings, core.PersistentVolumeBinding{
+			PersistentVolumeID: persistentVolume.ID,
+			MountingPath:       persistentVolumeBinding.MountingPath,
+		})
+	}
+	source := resource.Source
+	application.LatestDeployment = core.Deployment{
+		UpstreamType:                 source.UpstreamType,
+		GitProvider:                  source.GitProvider,
+		GitType:                      source.GitType,
+		GitEndpoint:                  source.GitEndpoint,
+		GitSshUser:                   source.GitSshUser,
+		RepositoryOwner:              source.RepositoryOwner,
+		RepositoryName:               source.RepositoryName,
+		RepositoryBranch:             source.RepositoryBranch,
+		CodePath:                     source.CodePath,
+		SourceCodeCompressedFileName: source.SourceCodeCompressedFileName,
+		DockerImage:                  source.DockerImage,
+		Dockerfile:                   source.Dockerfile,
+		BuildArgs:                    make([]core.BuildArg, 0),
+	}
+	if source.GitCredential != "" {
+		gitCredential := &core.GitCredential{}
+		err := db.Where("name = ?", source.GitCredential).First(gitCredential).Error
+		if err != nil {
+			return nil, fmt.Errorf("git credential %s not found", source.GitCredential)
+		}
+		application.LatestDeployment.GitCredentialID = &gitCredential.ID
+	}
+	if source.ImageRegistryCredential != nil {
+		imageRegistryCredential := &core.ImageRegistryCredential{}
+		err := db.Where("url = ? AND username = ?", source.ImageRegistryCredential.Url, source.ImageRegistryCredential.Username).First(imageRegistryCredential).Error
+		if err != nil {
+			return nil, fmt.Errorf("image registry credential %s@%s not found", source.ImageRegistryCredential.Username, source.ImageRegistryCredential.Url)
+		}
+		application.LatestDeployment.ImageRegistryCredentialID = &imageRegistryCredential.ID
+	}
+	for _, buildArg := range source.BuildArgs {
+		application.LatestDeployment.BuildArgs = append(application.LatestDeployment.BuildArgs, core.BuildArg{
+			Key:   buildArg.Key,
+			Value: buildArg.Value,
+		})
+	}
+	return application, nil
+}
+
+func applyIngressRule(ctx context.Context, options ApplyOptions, action ChangeAction, resource IngressRule, applied *Plan) error {
+	db := options.DbClient
+	if action == ChangeActionCreate {
+		record := &core.IngressRule{
+			Protocol:        resource.Protocol,
+			Port:            resource.Port,
+			TargetType:      resource.TargetType,
+			TargetPort:      resource.TargetPort,
+			ExternalService: resource.ExternalService,
+		}
+		if record.TargetType == core.ExternalServiceIngressRule && strings.Compare(record.ExternalService, "") == 0 {
+			return errors.New("external service is required")
+		}
+		if resource.Domain != "" {
+			domain, err := findDomain(db, resource.Domain)
+			if err != nil {
+				return err
+			}
+			record.DomainID = &domain.ID
+		}
+		if record.TargetType == core.ApplicationIngressRule {
+			application := &core.Application{}
+			err := application.FindByName(ctx, db, resource.Application)
+			if err != nil {
+				return fmt.Errorf("application %s not found", resource.Application)
+			}
+			record.ApplicationID = &application.ID
+		}
+		err := record.Create(ctx, db, options.RestrictedPorts)
+		if err != nil {
+			return err
+		}
+		if resource.HttpsRedirect || resource.AccessControlList != "" {
+			applied.Warnings = append(applied.Warnings, fmt.Sprintf("https redirect and authentication of ingress rule %s can be configured once it's active, apply the document again after a while", resource.key()))
+		}
+		return options.TaskEnqueuer.EnqueueIngressRuleApplyRequest(record.ID)
+	}
+	record, err := findIngressRule(db, resource)
+	if err != nil {
+		return err
+	}
+	if action == ChangeActionDelete {
+		err = record.Delete(ctx, db, false)
+		if err != nil && !errors.Is(err, core.IngressRuleDeletingError) {
+			return err
+		}
+		return options.TaskEnqueuer.EnqueueIngressRuleDeleteRequest(record.ID)
+	}
+	// https redirect and authentication can be configured only once the ingress rule is active
+	if record.Status != core.IngressRuleStatusApplied {
+		applied.Warnings = append(applied.Warnings, fmt.Sprintf("ingress rule %s is not active yet, apply the document again after a while", resource.key()))
+		return nil
+	}
+	if record.HttpsRedirect != resource.HttpsRedirect {
+		if resource.HttpsRedirect {
+			isValid, err := record.ValidateForHttpsRedirectEnableRequest(ctx, db)
+			if !isValid {
+				return err
+			}
+		}
+		err = options.TaskEnqueuer.EnqueueIngressRuleHttpsRedirectRequest(record.ID, resource.HttpsRedirect)
+		if err != nil {
+			return err
+		}
+	}
+	return applyIngressRuleAuthentication(ctx, options, record, resource)
+}
+
+// applyIngressRuleAuthentication : switch the basic auth protection of the ingress rule to the access control list of the document
+// The proxies are updated first and then the ingress rule is saved, so no database transaction is kept open while talking to the proxies
+func applyIngressRuleAuthentication(ctx context.Context, options ApplyOptions, record *core.IngressRule, resource IngressRule) error {
+	db := options.DbClient
+	var currentAccessControlList, desiredAccessControlList *core.AppBasicAuthAccessControlList
+	if record.Authentication.AuthType == core.IngressRuleBasicAuthentication && record.Authentication.AppBasicAuthAccessControlListID != nil {
+		currentAccessControlList = &core.AppBasicAuthAccessControlList{}
+		err := currentAccessControlList.FindById(ctx, &db, *record.Authentication.AppBasicAuthAccessControlListID)
+		if err != nil {
+			return err
+		}
+	}
+	if resource.AccessControlList != "" {
+		desiredAccessControlList = &core.AppBasicAuthAccessControlList{}
+		err := db.Where("name = ?", resource.AccessControlList).First(desiredAccessControlList).Error
+		if err != nil {
+			return fmt.Errorf("access control list %s not found", resource.AccessControlList)
+		}
+	}
+	if currentAccessControlList == nil && desiredAccessControlList == nil {
+		return nil
+	}
+	if currentAccessControlList != nil && desiredAccessControlList != nil && currentAccessControlList.ID == desiredAccessControlList.ID {
+		return nil
+	}
+	if record.DomainID == nil {
+		return errors.New("basic authentication is supported only for ingress rules with domain")
+	}
+	if desiredAccessControlList != nil && (record.Protocol == core.TCPProtocol || record.Protocol == core.UDPProtocol) {
+		return errors.New("basic authentication is not supported for TCP/UDP mode")
+	}
+	domain := &core.Domain{}
+	err := domain.FindById(ctx, db, *record.DomainID)
+	if err != nil {
+		return err
+	}
+	port := int(record.Port)
+	err = manager.RunActionsInAllHAProxyNodes(ctx, db, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+		if currentAccessControlList != nil {
+			err := haproxyManager.RemoveBasicAuthentication(transactionId, haproxymanager.HTTPMode, port, domain.Name, currentAccessControlList.GeneratedName)
+			if err != nil {
+				return err
+			}
+		}
+		if desiredAccessControlList != nil {
+			return haproxyManager.SetupBasicAuthentication(transactionId, haproxymanager.HTTPMode, port, domain.Name, desiredAccessControlList.GeneratedName)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	tx := db.Begin()
+	defer tx.Rollback()
+	if currentAccessControlList != nil {
+		err = record.DisableAuthentication(ctx, *tx)
+		if err != nil {
+			return err
+		}
+		record.Authentication.AuthType = core.IngressRuleNoAuthentication
+		record.Authentication.AppBasicAuthAccessControlListID = nil
+	}
+	if desiredAccessControlList != nil {
+		err = record.ProtectUsingBasicAuth(ctx, *tx, desiredAccessControlList.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+func applyRedirectRule(ctx context.Context, options ApplyOptions, action ChangeAction, resource RedirectRule) error {
+	db := options.DbClient
+	domain, err := findDomain(db, resource.Domain)
+	if err != nil {
+		return err
+	}
+	if action == ChangeActionDelete {
+		record := &core.RedirectRule{}
+		err = db.Where("domain_id = ? AND protocol = ? AND status != ?", domain.ID, resource.Protocol, core.RedirectRuleStatusDeleting).First(record).Error
+		if err != nil {
+			return err
+		}
+		err = record.Delete(ctx, db, false)
+		if err != nil {
+			return err
+		}
+		return options.TaskEnqueuer.EnqueueRedirectRuleDeleteRequest(record.ID)
+	}
+	record := &core.RedirectRule{
+		DomainID:    domain.ID,
+		Protocol:    resource.Protocol,
+		RedirectURL: resource.RedirectURL,
+	}
+	err = record.Create(ctx, db)
+	if err != nil {
+		return err
+	}
+	return options.TaskEnqueuer.EnqueueRedirectRuleApplyRequest(record.ID)
+}
+
+func findDomain(db gorm.DB, name string) (*core.Domain, error) {
+	domain := &core.Domain{}
+	err := db.Where("name = ?", name).First(domain).Error
+	if err != nil {
+		return nil, fmt.Errorf("domain %s not found", name)
+	}
+	return domain, nil
+}
+
+// findIngressRule : find the active ingress rule of same protocol, domain and port
+func findIngressRule(db gorm.DB, resource IngressRule) (*core.IngressRule, error) {
+	query := db.Where("protocol = ? AND port = ? AND status != ?", resource.Protocol, resource.Port, core.IngressRuleStatusDeleting)
+	if resource.Domain != "" {
+		domain, err := findDomain(db, resource.Domain)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("domain_id = ?", domain.ID)
+	} else {
+		query = query.Where("domain_id IS NULL")
+	}
+	record := &core.IngressRule{}
+	err := query.First(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func isSameOptionalString(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,337 @@
+package declarative_state
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// Export : export the current state of swiftwave as document, secrets are not included
+func Export(ctx context.Context, db gorm.DB) (*Document, error) {
+	return exportDocument(ctx, db, false)
+}
+
+// Parse : parse the document and fill the default values
+func Parse(content []byte) (*Document, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	document := &Document{}
+	err := decoder.Decode(document)
+	if err != nil {
+		return nil, errors.New("invalid document > " + err.Error())
+	}
+	if document.Version == "" {
+		return nil, errors.New("version of the document is required")
+	}
+	if document.Version != DocumentVersion {
+		return nil, fmt.Errorf("unsupported document version %s, supported version is %s", document.Version, DocumentVersion)
+	}
+	document.fillDefaults()
+	document.sort()
+	return document, nil
+}
+
+// Marshal : serialize the document as yaml
+func (d *Document) Marshal() ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	err := encoder.Encode(d)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// exportDocument : secrets are included only to compare with the secrets provided in the document while planning
+func exportDocument(ctx context.Context, db gorm.DB, includeSecrets bool) (*Document, error) {
+	document := &Document{
+		Version: DocumentVersion,
+	}
+	// credentials
+	gitCredentials, err := core.FindAllGitCredentials(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	gitCredentialNames := make(map[uint]string)
+	for _, record := range gitCredentials {
+		gitCredentialNames[record.ID] = record.Name
+		document.GitCredentials = append(document.GitCredentials, GitCredentialReference{
+			Name:     record.Name,
+			Type:     record.Type,
+			Username: record.Username,
+		})
+	}
+	imageRegistryCredentials, err := core.FindAllImageRegistryCredentials(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	imageRegistryCredentialReferences := make(map[uint]ImageRegistryCredentialReference)
+	for _, record := range imageRegistryCredentials {
+		reference := ImageRegistryCredentialReference{
+			Url:      record.Url,
+			Username: record.Username,
+		}
+		imageRegistryCredentialReferences[record.ID] = reference
+		document.ImageRegistryCredentials = append(document.ImageRegistryCredentials, reference)
+	}
+	// persistent volumes
+	persistentVolumes, err := core.FindAllPersistentVolumes(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	persistentVolumeNames := make(map[uint]string)
+	for _, record := range persistentVolumes {
+		persistentVolumeNames[record.ID] = record.Name
+		persistentVolume := PersistentVolume{
+			Name: record.Name,
+			Type: record.Type,
+		}
+		switch record.Type {
+		case core.PersistentVolumeTypeNFS:
+			nfsConfig := record.NFSConfig
+			persistentVolume.NFSConfig = &nfsConfig
+		case core.PersistentVolumeTypeCIFS:
+			cifsConfig := record.CIFSConfig
+			if !includeSecrets {
+				cifsConfig.Password = ""
+			}
+			persistentVolume.CIFSConfig = &cifsConfig
+		}
+		document.PersistentVolumes = append(document.PersistentVolumes, persistentVolume)
+	}
+	// access control lists
+	accessControlLists, err := core.FindAllAppBasicAuthAccessControlLists(ctx, &db)
+	if err != nil {
+		return nil, err
+	}
+	accessControlListNames := make(map[uint]string)
+	for _, record := range accessControlLists {
+		accessControlListNames[record.ID] = record.Name
+		users, err := core.FetchAppBasicAuthAccessControlUsers(ctx, &db, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		accessControlList := AccessControlList{
+			Name: record.Name,
+		}
+		for _, user := range users {
+			accessControlList.Users = append(accessControlList.Users, AccessControlUser{
+				Username:          user.Username,
+				EncryptedPassword: user.EncryptedPassword,
+			})
+		}
+		sort.Slice(accessControlList.Users, func(i, j int) bool {
+			return accessControlList.Users[i].Username < accessControlList.Users[j].Username
+		})
+		document.AccessControlLists = append(document.AccessControlLists, accessControlList)
+	}
+	// domains
+	domains, err := core.FindAllDomains(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	domainNames := make(map[uint]string)
+	for _, record := range domains {
+		domainNames[record.ID] = record.Name
+		domain := Domain{
+			Name: record.Name,
+		}
+		if record.DNSProvider.Type != "" && record.DNSProvider.Type != core.DNSProviderNone {
+			dnsProvider := record.DNSProvider
+			if !includeSecrets {
+				dnsProvider.RFC2136TSIGSecret = ""
+				dnsProvider.WebhookSecret = ""
+			}
+			domain.DNSProvider = &dnsProvider
+		}
+		document.Domains = append(document.Domains, domain)
+	}
+	// application groups
+	applicationGroups, err := core.FindAllApplicationGroups(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	applicationGroupNames := make(map[string]string)
+	for _, record := range applicationGroups {
+		applicationGroupNames[record.ID] = record.Name
+		document.ApplicationGroups = append(document.ApplicationGroups, ApplicationGroup{
+			Name: record.Name,
+			Logo: record.Logo,
+		})
+	}
+	// applications
+	applications, err := core.FindAllApplications(ctx, db, true)
+	if err != nil {
+		return nil, err
+	}
+	applicationNames := make(map[string]string)
+	for _, record := range applications {
+		if record.IsDeleted {
+			continue
+		}
+		applicationNames[record.ID] = record.Name
+		application := Application{
+			Name:                     record.Name,
+			Kind:                     record.Kind,
+			DeploymentMode:           record.DeploymentMode,
+			Replicas:                 record.Replicas,
+			Hostname:                 record.Hostname,
+			Command:                  record.Command,
+			ReleaseCommand:           record.ReleaseCommand,
+			Capabilities:             record.Capabilities,
+			Sysctls:                  record.Sysctls,
+			PreferredServerHostnames: record.PreferredServerHostnames,
+			ResourceLimit:            record.ResourceLimit,
+			ReservedResource:         record.ReservedResource,
+			CustomHealthCheck:        record.CustomHealthCheck,
+			DockerProxy:              record.DockerProxy,
+			AutoSleep:                record.AutoSleep,
+			AutoScaling:              record.AutoScaling,
+			DeploymentStrategy:       record.DeploymentStrategy,
+		}
+		if record.ApplicationGroupID != nil {
+			application.Group = applicationGroupNames[*record.ApplicationGroupID]
+		}
+		if record.IsCronJob() {
+			cronJob := record.CronJob
+			application.CronJob = &cronJob
+		}
+		// source
+		deployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, db, record.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deployment of application %s > %s", record.Name, err.Error())
+		}
+		application.Source = ApplicationSource{
+			UpstreamType:                 deployment.UpstreamType,
+			GitProvider:                  deployment.GitProvider,
+			GitEndpoint:                  deployment.GitEndpoint,
+			GitSshUser:                   deployment.GitSshUser,
+			RepositoryOwner:              deployment.RepositoryOwner,
+			RepositoryName:               deployment.RepositoryName,
+			RepositoryBranch:             deployment.RepositoryBranch,
+			CodePath:                     deployment.CodePath,
+			SourceCodeCompressedFileName: deployment.SourceCodeCompressedFileName,
+			DockerImage:                  deployment.DockerImage,
+			Dockerfile:                   deployment.Dockerfile,
+		}
+		if deployment.UpstreamType == core.UpstreamTypeGit {
+			application.Source.GitType = deployment.GitType
+		}
+		if deployment.GitCredentialID != nil {
+			application.Source.GitCredential = gitCredentialNames[*deployment.GitCredentialID]
+		}
+		if deployment.ImageRegistryCredentialID != nil {
+			if reference, ok := imageRegistryCredentialReferences[*deployment.ImageRegistryCredentialID]; ok {
+				application.Source.ImageRegistryCredential = &reference
+			}
+		}
+		buildArgs, err := core.FindBuildArgsByDeploymentId(ctx, db, deployment.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, buildArg := range buildArgs {
+			application.Source.BuildArgs = append(application.Source.BuildArgs, BuildArg{
+				Key:   buildArg.Key,
+				Value: buildArg.Value,
+			})
+		}
+		// environment variables
+		environmentVariables, err := core.FindEnvironmentVariablesByApplicationId(ctx, db, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, environmentVariable := range environmentVariables {
+			value := environmentVariable.Value
+			if environmentVariable.ExposeAsSecret && !includeSecrets {
+				value = ""
+			}
+			application.EnvironmentVariables = append(application.EnvironmentVariables, EnvironmentVariable{
+				Key:            environmentVariable.Key,
+				Value:          value,
+				ExposeAsSecret: environmentVariable.ExposeAsSecret,
+			})
+		}
+		// config mounts
+		configMounts, err := core.FindConfigMountsByApplicationId(ctx, db, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, configMount := range configMounts {
+			application.ConfigMounts = append(application.ConfigMounts, ConfigMount{
+				MountingPath: configMount.MountingPath,
+				Content:      configMount.Content,
+				Uid:          configMount.Uid,
+				Gid:          configMount.Gid,
+			})
+		}
+		// persistent volume bindings
+		persistentVolumeBindings, err := core.FindPersistentVolumeBindingsByApplicationId(ctx, db, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, persistentVolumeBinding := range persistentVolumeBindings {
+			application.PersistentVolumeBindings = append(application.PersistentVolumeBindings, PersistentVolumeBinding{
+				PersistentVolume: persistentVolumeNames[persistentVolumeBinding.PersistentVolumeID],
+				MountingPath:     persistentVolumeBinding.MountingPath,
+			})
+		}
+		document.Applications = append(document.Applications, application)
+	}
+	// ingress rules
+	ingressRules, err := core.FindAllIngressRules(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range ingressRules {
+		if record.Status == core.IngressRuleStatusDeleting {
+			continue
+		}
+		ingressRule := IngressRule{
+			Protocol:        record.Protocol,
+			Port:            record.Port,
+			TargetType:      record.TargetType,
+			ExternalService: record.ExternalService,
+			TargetPort:      record.TargetPort,
+			HttpsRedirect:   record.HttpsRedirect,
+		}
+		if record.DomainID != nil {
+			ingressRule.Domain = domainNames[*record.DomainID]
+		}
+		if record.ApplicationID != nil {
+			ingressRule.Application = applicationNames[*record.ApplicationID]
+		}
+		if record.Authentication.AuthType == core.IngressRuleBasicAuthentication && record.Authentication.AppBasicAuthAccessControlListID != nil {
+			ingressRule.AccessControlList = accessControlListNames[*record.Authentication.AppBasicAuthAccessControlListID]
+		}
+		document.IngressRules = append(document.IngressRules, ingressRule)
+	}
+	// redirect rules
+	redirectRules, err := core.FindAllRedirectRules(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range redirectRules {
+		if record.Status == core.RedirectRuleStatusDeleting {
+			continue
+		}
+		document.RedirectRules = append(document.RedirectRules, RedirectRule{
+			Protocol:    record.Protocol,
+			Domain:      domainNames[record.DomainID],
+			RedirectURL: record.RedirectURL,
+		})
+	}
+	document.fillDefaults()
+	document.sort()
+	return document, nil
+}
@@ -0,0 +1,146 @@
+package declarative_state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	gitmanager "github.com/swiftwave-org/swiftwave/pkg/git_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/stack_parser"
+)
+
+// GitOpsSyncOptions : dependencies required to sync application group from git repository
+type GitOpsSyncOptions struct {
+	ApplyOptions
+	ServiceManager   service_manager.ServiceManager
+	SwiftwaveVersion string
+	// Force - fetch the stack file even if there is no new commit
+	Force bool
+}
+
+// syncs of application groups are serialized, as the scheduled sync and the manual sync can overlap
+var gitOpsSyncLock sync.Mutex
+
+// SyncApplicationGroup : reconcile the applications of the group with the stack file of git repository
+// If there is no new commit, the last applied stack is reconciled again, and the reverted changes are recorded as drift.
+// Variable STACK_NAME of the stack is filled with the name of the group.
+// Returns the applied changes.
+func SyncApplicationGroup(ctx context.Context, options GitOpsSyncOptions, group *core.ApplicationGroup) (*Plan, error) {
+	gitOpsSyncLock.Lock()
+	defer gitOpsSyncLock.Unlock()
+	plan, err := syncApplicationGroup(ctx, options, group)
+	if err != nil {
+		markErr := group.MarkGitOpsSyncFailed(ctx, options.DbClient, err.Error())
+		if markErr != nil {
+			return plan, markErr
+		}
+		return plan, err
+	}
+	return plan, nil
+}
+
+func syncApplicationGroup(ctx context.Context, options GitOpsSyncOptions, group *core.ApplicationGroup) (*Plan, error) {
+	gitOps := group.GitOps
+	if !gitOps.Enabled {
+		return nil, errors.New("git ops is not enabled for the application group")
+	}
+	gitUsername := ""
+	gitPassword := ""
+	gitPrivateKey := ""
+	if gitOps.GitCredentialID != nil {
+		gitCredential := &core.GitCredential{}
+		err := gitCredential.FindById(ctx, options.DbClient, *gitOps.GitCredentialID)
+		if err != nil {
+			return nil, errors.New("failed to fetch git credential")
+		}
+		gitUsername = gitCredential.Username
+		gitPassword = gitCredential.Password
+		gitPrivateKey = gitCredential.SshPrivateKey
+	}
+	commitHash, err := gitmanager.FetchLatestCommitHash(gitOps.RepositoryURL, gitOps.RepositoryBranch, gitUsername, gitPassword, gitPrivateKey)
+	if err != nil {
+		return nil, errors.New("failed to fetch latest commit > " + err.Error())
+	}
+	stackContent := group.StackContent
+	isNewCommit := commitHash != gitOps.LastAppliedCommit || stackContent == ""
+	if isNewCommit || options.Force {
+		commitHash, stackContent, err = fetchStackFile(gitOps, gitUsername, gitPassword, gitPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	stack, err := stack_parser.ParseStackYaml(stackContent, options.SwiftwaveVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stack file of commit %s > %s", commitHash, err.Error())
+	}
+	variableMapping := map[string]string{
+		"STACK_NAME": group.Name,
+	}
+	stackFilled, err := stack.FillAndVerifyVariables(&variableMapping, options.ServiceManager)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stack file of commit %s > %s", commitHash, err.Error())
+	}
+	applications, err := ApplicationsFromStack(stackFilled)
+	if err != nil {
+		return nil, err
+	}
+	logo := group.Logo
+	if stackFilled.Docs != nil && stackFilled.Docs.LogoURL != "" {
+		logo = stackFilled.Docs.LogoURL
+	}
+	plan, err := ApplyApplicationGroup(ctx, options.ApplyOptions, ApplicationGroup{
+		Name: group.Name,
+		Logo: logo,
+	}, applications, IngressRulesFromStack(stackFilled))
+	if err != nil {
+		return plan, err
+	}
+	driftDetails := ""
+	if !isNewCommit && plan.HasChanges() {
+		driftDetails = plan.String()
+	}
+	return plan, group.MarkGitOpsSynced(ctx, options.DbClient, commitHash, stackContent, driftDetails)
+}
+
+// fetchStackFile : clone the repository and read the stack file, returns the cloned commit hash and content of the stack file
+func fetchStackFile(gitOps core.ApplicationGroupGitOps, gitUsername string, gitPassword string, gitPrivateKey string) (string, string, error) {
+	tempDirectory, err := os.MkdirTemp("", "swiftwave-gitops-")
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDirectory)
+	}()
+	commitHash, _, err := gitmanager.CloneRepository(gitOps.RepositoryURL, gitOps.RepositoryBranch, gitUsername, gitPassword, gitPrivateKey, tempDirectory)
+	if err != nil {
+		return "", "", errors.New("failed to clone git repository > " + err.Error())
+	}
+	content, err := readFileInsideDirectory(tempDirectory, gitOps.StackFilePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read stack file %s of commit %s > %s", gitOps.StackFilePath, commitHash, err.Error())
+	}
+	return commitHash, string(content), nil
+}
+
+// readFileInsideDirectory : read the file of the directory, symlinks are followed only if they resolve inside the directory
+func readFileInsideDirectory(directory string, path string) ([]byte, error) {
+	directory, err := filepath.EvalSymlinks(directory)
+	if err != nil {
+		return nil, err
+	}
+	resolvedPath, err := filepath.EvalSymlinks(filepath.Join(directory, filepath.Clean("/"+path)))
+	if err != nil {
+		return nil, errors.New("file not found")
+	}
+	relativePath, err := filepath.Rel(directory, resolvedPath)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return nil, errors.New("file is outside of the repository")
+	}
+	return os.ReadFile(resolvedPath)
+}
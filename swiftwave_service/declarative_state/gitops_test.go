@@ -0,0 +1,44 @@
+package declarative_state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFileInsideDirectory(t *testing.T) {
+	repository := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	assert.NoError(t, os.WriteFile(outside, []byte("secret"), 0600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(repository, "deploy"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(repository, "deploy", "stack.yml"), []byte("services: {}"), 0644))
+	assert.NoError(t, os.Symlink("deploy/stack.yml", filepath.Join(repository, "stack.yml")))
+	assert.NoError(t, os.Symlink(outside, filepath.Join(repository, "absolute.yml")))
+	assert.NoError(t, os.Symlink("../"+filepath.Base(filepath.Dir(outside))+"/secret.txt", filepath.Join(repository, "relative.yml")))
+
+	t.Run("file of the repository is read", func(t *testing.T) {
+		content, err := readFileInsideDirectory(repository, "deploy/stack.yml")
+		assert.NoError(t, err)
+		assert.Equal(t, "services: {}", string(content))
+	})
+
+	t.Run("symlink inside the repository is followed", func(t *testing.T) {
+		content, err := readFileInsideDirectory(repository, "stack.yml")
+		assert.NoError(t, err)
+		assert.Equal(t, "services: {}", string(content))
+	})
+
+	t.Run("path can't escape the repository", func(t *testing.T) {
+		_, err := readFileInsideDirectory(repository, "../../etc/passwd")
+		assert.Error(t, err)
+	})
+
+	t.Run("symlink outside the repository is rejected", func(t *testing.T) {
+		_, err := readFileInsideDirectory(repository, "absolute.yml")
+		assert.Error(t, err)
+		_, err = readFileInsideDirectory(repository, "relative.yml")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,566 @@
+package declarative_state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// kinds of the resources in the plan
+const (
+	persistentVolumeKind  = "persistent_volume"
+	accessControlListKind = "access_control_list"
+	domainKind            = "domain"
+	applicationGroupKind  = "application_group"
+	applicationKind       = "application"
+	ingressRuleKind       = "ingress_rule"
+	redirectRuleKind      = "redirect_rule"
+)
+
+// ComputePlan : compute the changes required to reconcile the database with the document
+// If prune is enabled, applications, ingress rules and redirect rules which are not in the document will be deleted.
+// Persistent volumes, domains, access control lists and application groups are never deleted, as those can hold data.
+func ComputePlan(ctx context.Context, db gorm.DB, desired *Document, prune bool) (*Plan, error) {
+	current, err := exportDocument(ctx, db, true)
+	if err != nil {
+		return nil, err
+	}
+	desired.fillOmittedSecrets(current)
+	return computePlan(current, desired, prune)
+}
+
+// HasChanges : check if there is anything to apply
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// String : human-readable diff of the plan
+func (p *Plan) String() string {
+	var builder strings.Builder
+	if !p.HasChanges() {
+		builder.WriteString("No changes, database is in sync with the document\n")
+	}
+	for _, change := range p.Changes {
+		symbol := "~"
+		switch change.Action {
+		case ChangeActionCreate:
+			symbol = "+"
+		case ChangeActionDelete:
+			symbol = "-"
+		}
+		builder.WriteString(fmt.Sprintf("%s %s %s\n", symbol, change.Kind, change.Name))
+		for _, detail := range change.Details {
+			builder.WriteString("    " + detail + "\n")
+		}
+	}
+	for _, warning := range p.Warnings {
+		builder.WriteString("! " + warning + "\n")
+	}
+	return builder.String()
+}
+
+func computePlan(current *Document, desired *Document, prune bool) (*Plan, error) {
+	err := desired.validateReferences(current, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{
+		Changes:  make([]Change, 0),
+		Warnings: make([]string, 0),
+	}
+	// persistent volumes
+	changes, _, err := diffResources(persistentVolumeKind, current.PersistentVolumes, desired.PersistentVolumes, func(v PersistentVolume) string {
+		return v.Name
+	}, func(_ PersistentVolume, desired PersistentVolume) error {
+		return fmt.Errorf("persistent volume %s can't be updated, create a new persistent volume instead", desired.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// access control lists
+	changes, _, err = diffResources(accessControlListKind, current.AccessControlLists, desired.AccessControlLists, func(l AccessControlList) string {
+		return l.Name
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// domains
+	changes, _, err = diffResources(domainKind, current.Domains, desired.Domains, func(d Domain) string {
+		return d.Name
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// application groups
+	changes, _, err = diffResources(applicationGroupKind, current.ApplicationGroups, desired.ApplicationGroups, func(g ApplicationGroup) string {
+		return g.Name
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// applications
+	desired.keepAutoScaledReplicas(current)
+	changes, applicationDeletions, err := diffResources(applicationKind, current.Applications, desired.Applications, func(a Application) string {
+		return a.Name
+	}, func(current Application, desired Application) error {
+		if current.Kind != desired.Kind {
+			return fmt.Errorf("kind of application %s can't be changed", desired.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// ingress rules
+	changes, ingressRuleDeletions, err := diffResources(ingressRuleKind, current.IngressRules, desired.IngressRules, IngressRule.key, func(current IngressRule, desired IngressRule) error {
+		// only https redirect and authentication can be changed in place
+		current.HttpsRedirect, desired.HttpsRedirect = false, false
+		current.AccessControlList, desired.AccessControlList = "", ""
+		if !reflect.DeepEqual(current, desired) {
+			return fmt.Errorf("target of ingress rule %s can't be updated, delete the ingress rule first", desired.key())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// redirect rules
+	changes, redirectRuleDeletions, err := diffResources(redirectRuleKind, current.RedirectRules, desired.RedirectRules, RedirectRule.key, func(_ RedirectRule, desired RedirectRule) error {
+		return fmt.Errorf("redirect rule %s can't be updated, delete the redirect rule first", desired.key())
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+	// deletions, the rules first as those refer the applications
+	if prune {
+		plan.Changes = append(plan.Changes, ingressRuleDeletions...)
+		plan.Changes = append(plan.Changes, redirectRuleDeletions...)
+		plan.Changes = append(plan.Changes, applicationDeletions...)
+	}
+	return plan, nil
+}
+
+// diffResources : returns the creations and updates required to reconcile the current resources with the desired ones, and the deletions of the resources which are not desired
+// validateUpdate is called for the resources which need to be updated, it should return error if the update is not supported
+func diffResources[T any](kind string, current []T, desired []T, key func(T) string, validateUpdate func(current T, desired T) error) ([]Change, []Change, error) {
+	currentMap := make(map[string]T)
+	for _, resource := range current {
+		currentMap[key(resource)] = resource
+	}
+	desiredKeys := make(map[string]bool)
+	changes := make([]Change, 0)
+	for _, resource := range desired {
+		resourceKey := key(resource)
+		if desiredKeys[resourceKey] {
+			return nil, nil, fmt.Errorf("duplicate %s %s in the document", kind, resourceKey)
+		}
+		desiredKeys[resourceKey] = true
+		currentResource, ok := currentMap[resourceKey]
+		if !ok {
+			changes = append(changes, Change{
+				Action:   ChangeActionCreate,
+				Kind:     kind,
+				Name:     resourceKey,
+				resource: resource,
+			})
+			continue
+		}
+		details, err := diffFields(currentResource, resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(details) == 0 {
+			continue
+		}
+		if validateUpdate != nil {
+			err = validateUpdate(currentResource, resource)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		changes = append(changes, Change{
+			Action:   ChangeActionUpdate,
+			Kind:     kind,
+			Name:     resourceKey,
+			Details:  details,
+			resource: resource,
+		})
+	}
+	deletions := make([]Change, 0)
+	for _, resource := range current {
+		resourceKey := key(resource)
+		if desiredKeys[resourceKey] {
+			continue
+		}
+		deletions = append(deletions, Change{
+			Action:   ChangeActionDelete,
+			Kind:     kind,
+			Name:     resourceKey,
+			resource: resource,
+		})
+	}
+	return changes, deletions, nil
+}
+
+// diffFields : top level fields which are changed
+// Values are shown only for scalar fields, so that values of environment variables and secrets are not leaked in the plan
+func diffFields(current interface{}, desired interface{}) ([]string, error) {
+	currentFields, err := toFieldMap(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, err := toFieldMap(desired)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0)
+	for key := range currentFields {
+		keys = append(keys, key)
+	}
+	for key := range desiredFields {
+		if _, ok := currentFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	details := make([]string, 0)
+	for _, key := range keys {
+		currentValue, desiredValue := currentFields[key], desiredFields[key]
+		if reflect.DeepEqual(currentValue, desiredValue) {
+			continue
+		}
+		if isScalar(currentValue) && isScalar(desiredValue) {
+			details = append(details, fmt.Sprintf("%s: %s -> %s", key, formatScalar(currentValue), formatScalar(desiredValue)))
+		} else {
+			details = append(details, key+" changed")
+		}
+	}
+	return details, nil
+}
+
+func toFieldMap(resource interface{}) (map[string]interface{}, error) {
+	content, err := yaml.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	err = yaml.Unmarshal(content, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func isScalar(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+func formatScalar(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// validateReferences : references should point to the resources of the document or the existing ones
+func (d *Document) validateReferences(current *Document, prune bool) error {
+	gitCredentials := make(map[string]bool)
+	for _, gitCredential := range current.GitCredentials {
+		gitCredentials[gitCredential.Name] = true
+	}
+	imageRegistryCredentials := make(map[ImageRegistryCredentialReference]bool)
+	for _, imageRegistryCredential := range current.ImageRegistryCredentials {
+		imageRegistryCredentials[imageRegistryCredential] = true
+	}
+	persistentVolumes := make(map[string]bool)
+	for _, persistentVolume := range append(current.PersistentVolumes, d.PersistentVolumes...) {
+		persistentVolumes[persistentVolume.Name] = true
+	}
+	accessControlLists := make(map[string]bool)
+	for _, accessControlList := range append(current.AccessControlLists, d.AccessControlLists...) {
+		accessControlLists[accessControlList.Name] = true
+	}
+	domains := make(map[string]bool)
+	for _, domain := range append(current.Domains, d.Domains...) {
+		domains[domain.Name] = true
+	}
+	applicationGroups := make(map[string]bool)
+	for _, applicationGroup := range append(current.ApplicationGroups, d.ApplicationGroups...) {
+		applicationGroups[applicationGroup.Name] = true
+	}
+	// pruned applications can't be referred
+	applications := make(map[string]bool)
+	for _, application := range d.Applications {
+		applications[application.Name] = true
+	}
+	if !prune {
+		for _, application := range current.Applications {
+			applications[application.Name] = true
+		}
+	}
+	for _, application := range d.Applications {
+		if application.Name == "" {
+			return errors.New("name of application is required")
+		}
+		if application.Group != "" && !applicationGroups[application.Group] {
+			return fmt.Errorf("application group %s of application %s not found", application.Group, application.Name)
+		}
+		if application.Source.GitCredential != "" && !gitCredentials[application.Source.GitCredential] {
+			return fmt.Errorf("git credential %s of application %s not found, create it before applying the document", application.Source.GitCredential, application.Name)
+		}
+		if application.Source.ImageRegistryCredential != nil && !imageRegistryCredentials[*application.Source.ImageRegistryCredential] {
+			return fmt.Errorf("image registry credential %s@%s of application %s not found, create it before applying the document", application.Source.ImageRegistryCredential.Username, application.Source.ImageRegistryCredential.Url, application.Name)
+		}
+		for _, persistentVolumeBinding := range application.PersistentVolumeBindings {
+			if !persistentVolumes[persistentVolumeBinding.PersistentVolume] {
+				return fmt.Errorf("persistent volume %s of application %s not found", persistentVolumeBinding.PersistentVolume, application.Name)
+			}
+		}
+	}
+	for _, ingressRule := range d.IngressRules {
+		if ingressRule.Domain != "" && !domains[ingressRule.Domain] {
+			return fmt.Errorf("domain %s of ingress rule %s not found", ingressRule.Domain, ingressRule.key())
+		}
+		if ingressRule.TargetType == core.ApplicationIngressRule && !applications[ingressRule.Application] {
+			return fmt.Errorf("application %s of ingress rule %s not found", ingressRule.Application, ingressRule.key())
+		}
+		if ingressRule.AccessControlList != "" && !accessControlLists[ingressRule.AccessControlList] {
+			return fmt.Errorf("access control list %s of ingress rule %s not found", ingressRule.AccessControlList, ingressRule.key())
+		}
+	}
+	for _, redirectRule := range d.RedirectRules {
+		if !domains[redirectRule.Domain] {
+			return fmt.Errorf("domain %s of redirect rule %s not found", redirectRule.Domain, redirectRule.key())
+		}
+	}
+	return nil
+}
+
+// fillOmittedSecrets : secrets are not exported, keep the existing ones if those are omitted in the document
+func (d *Document) fillOmittedSecrets(current *Document) {
+	currentPersistentVolumes := make(map[string]PersistentVolume)
+	for _, persistentVolume := range current.PersistentVolumes {
+		currentPersistentVolumes[persistentVolume.Name] = persistentVolume
+	}
+	for i, persistentVolume := range d.PersistentVolumes {
+		currentPersistentVolume, ok := currentPersistentVolumes[persistentVolume.Name]
+		if !ok || persistentVolume.CIFSConfig == nil || currentPersistentVolume.CIFSConfig == nil {
+			continue
+		}
+		if persistentVolume.CIFSConfig.Password == "" {
+			d.PersistentVolumes[i].CIFSConfig.Password = currentPersistentVolume.CIFSConfig.Password
+		}
+	}
+	currentDomains := make(map[string]Domain)
+	for _, domain := range current.Domains {
+		currentDomains[domain.Name] = domain
+	}
+	for i, domain := range d.Domains {
+		currentDomain, ok := currentDomains[domain.Name]
+		if !ok || domain.DNSProvider == nil || currentDomain.DNSProvider == nil {
+			continue
+		}
+		if domain.DNSProvider.RFC2136TSIGSecret == "" {
+			d.Domains[i].DNSProvider.RFC2136TSIGSecret = currentDomain.DNSProvider.RFC2136TSIGSecret
+		}
+		if domain.DNSProvider.WebhookSecret == "" {
+			d.Domains[i].DNSProvider.WebhookSecret = currentDomain.DNSProvider.WebhookSecret
+		}
+	}
+	currentSecretEnvironmentVariables := make(map[string]map[string]string)
+	for _, application := range current.Applications {
+		for _, environmentVariable := range application.EnvironmentVariables {
+			if !environmentVariable.ExposeAsSecret {
+				continue
+			}
+			if _, ok := currentSecretEnvironmentVariables[application.Name]; !ok {
+				currentSecretEnvironmentVariables[application.Name] = make(map[string]string)
+			}
+			currentSecretEnvironmentVariables[application.Name][environmentVariable.Key] = environmentVariable.Value
+		}
+	}
+	for i, application := range d.Applications {
+		for j, environmentVariable := range application.EnvironmentVariables {
+			if !environmentVariable.ExposeAsSecret || environmentVariable.Value != "" {
+				continue
+			}
+			if value, ok := currentSecretEnvironmentVariables[application.Name][environmentVariable.Key]; ok {
+				d.Applications[i].EnvironmentVariables[j].Value = value
+			}
+		}
+	}
+}
+
+// keepAutoScaledReplicas : replicas of auto scaled applications are managed by the autoscaler, so keep the current replicas while those are within the bounds
+func (d *Document) keepAutoScaledReplicas(current *Document) {
+	currentReplicas := make(map[string]uint)
+	for _, application := range current.Applications {
+		currentReplicas[application.Name] = application.Replicas
+	}
+	for i, application := range d.Applications {
+		replicas, ok := currentReplicas[application.Name]
+		if !ok || !application.AutoScaling.Enabled {
+			continue
+		}
+		d.Applications[i].Replicas = application.AutoScaling.ClampReplicas(replicas)
+	}
+}
+
+// fillDefaults : fill the values which are stored as default in database, so that omitted fields are not reported as changes
+func (d *Document) fillDefaults() {
+	for i := range d.PersistentVolumes {
+		if d.PersistentVolumes[i].Type == "" {
+			d.PersistentVolumes[i].Type = core.PersistentVolumeTypeLocal
+		}
+	}
+	for i := range d.Domains {
+		if d.Domains[i].DNSProvider != nil && (d.Domains[i].DNSProvider.Type == "" || d.Domains[i].DNSProvider.Type == core.DNSProviderNone) {
+			d.Domains[i].DNSProvider = nil
+		}
+	}
+	for i := range d.Applications {
+		application := &d.Applications[i]
+		if application.Kind == "" {
+			application.Kind = core.ApplicationKindService
+		}
+		if application.DeploymentMode == "" {
+			application.DeploymentMode = core.DeploymentModeReplicated
+		}
+		if application.Source.UpstreamType == core.UpstreamTypeGit && application.Source.GitType == "" {
+			application.Source.GitType = core.GitHttp
+		}
+		if application.DeploymentStrategy.Type == "" {
+			application.DeploymentStrategy.Type = core.DeploymentStrategyRolling
+		}
+		if application.DeploymentStrategy.CanaryWeightPercent == 0 {
+			application.DeploymentStrategy.CanaryWeightPercent = 10
+		}
+		if application.AutoSleep.IdleTimeoutMinutes == 0 {
+			application.AutoSleep.IdleTimeoutMinutes = 30
+		}
+		if application.AutoScaling.MinReplicas == 0 {
+			application.AutoScaling.MinReplicas = 1
+		}
+		if application.AutoScaling.MaxReplicas == 0 {
+			application.AutoScaling.MaxReplicas = 1
+		}
+		if application.AutoScaling.Metric == "" {
+			application.AutoScaling.Metric = core.AutoScalingMetricCPU
+		}
+		if application.AutoScaling.TargetPercent == 0 {
+			application.AutoScaling.TargetPercent = 70
+		}
+		if application.AutoScaling.CooldownSeconds == 0 {
+			application.AutoScaling.CooldownSeconds = 300
+		}
+		if application.CustomHealthCheck.IntervalSeconds == 0 {
+			application.CustomHealthCheck.IntervalSeconds = 10
+		}
+		if application.CustomHealthCheck.TimeoutSeconds == 0 {
+			application.CustomHealthCheck.TimeoutSeconds = 5
+		}
+		if application.CustomHealthCheck.StartPeriodSeconds == 0 {
+			application.CustomHealthCheck.StartPeriodSeconds = 5
+		}
+		if application.CustomHealthCheck.StartIntervalSeconds == 0 {
+			application.CustomHealthCheck.StartIntervalSeconds = 5
+		}
+		if application.DockerProxy.Permission.Ping == "" {
+			application.DockerProxy.Permission.Ping = core.DockerProxyReadPermission
+		}
+		permissions := reflect.ValueOf(&application.DockerProxy.Permission).Elem()
+		for j := 0; j < permissions.NumField(); j++ {
+			if permissions.Field(j).String() == "" {
+				permissions.Field(j).SetString(string(core.DockerProxyNoPermission))
+			}
+		}
+		if application.CronJob != nil {
+			if application.CronJob.ConcurrencyPolicy == "" {
+				application.CronJob.ConcurrencyPolicy = core.CronJobConcurrencyForbid
+			}
+			if application.CronJob.HistoryLimit == 0 {
+				application.CronJob.HistoryLimit = 10
+			}
+		}
+	}
+	for i := range d.IngressRules {
+		if d.IngressRules[i].TargetType == "" {
+			d.IngressRules[i].TargetType = core.ApplicationIngressRule
+		}
+	}
+}
+
+// sort : sort the resources, so that the exported document is stable
+func (d *Document) sort() {
+	sort.Slice(d.GitCredentials, func(i, j int) bool {
+		return d.GitCredentials[i].Name < d.GitCredentials[j].Name
+	})
+	sort.Slice(d.ImageRegistryCredentials, func(i, j int) bool {
+		return d.ImageRegistryCredentials[i].Url+d.ImageRegistryCredentials[i].Username < d.ImageRegistryCredentials[j].Url+d.ImageRegistryCredentials[j].Username
+	})
+	sort.Slice(d.PersistentVolumes, func(i, j int) bool {
+		return d.PersistentVolumes[i].Name < d.PersistentVolumes[j].Name
+	})
+	sort.Slice(d.AccessControlLists, func(i, j int) bool {
+		return d.AccessControlLists[i].Name < d.AccessControlLists[j].Name
+	})
+	sort.Slice(d.Domains, func(i, j int) bool {
+		return d.Domains[i].Name < d.Domains[j].Name
+	})
+	sort.Slice(d.ApplicationGroups, func(i, j int) bool {
+		return d.ApplicationGroups[i].Name < d.ApplicationGroups[j].Name
+	})
+	sort.Slice(d.Applications, func(i, j int) bool {
+		return d.Applications[i].Name < d.Applications[j].Name
+	})
+	for i := range d.Applications {
+		application := &d.Applications[i]
+		sort.Slice(application.EnvironmentVariables, func(i, j int) bool {
+			return application.EnvironmentVariables[i].Key < application.EnvironmentVariables[j].Key
+		})
+		sort.Slice(application.ConfigMounts, func(i, j int) bool {
+			return application.ConfigMounts[i].MountingPath < application.ConfigMounts[j].MountingPath
+		})
+		sort.Slice(application.PersistentVolumeBindings, func(i, j int) bool {
+			return application.PersistentVolumeBindings[i].MountingPath < application.PersistentVolumeBindings[j].MountingPath
+		})
+		sort.Slice(application.Source.BuildArgs, func(i, j int) bool {
+			return application.Source.BuildArgs[i].Key < application.Source.BuildArgs[j].Key
+		})
+	}
+	sort.Slice(d.IngressRules, func(i, j int) bool {
+		return d.IngressRules[i].key() < d.IngressRules[j].key()
+	})
+	sort.Slice(d.RedirectRules, func(i, j int) bool {
+		return d.RedirectRules[i].key() < d.RedirectRules[j].key()
+	})
+}
+
+func (r IngressRule) key() string {
+	return fmt.Sprintf("%s://%s:%d", r.Protocol, r.Domain, r.Port)
+}
+
+func (r RedirectRule) key() string {
+	return fmt.Sprintf("%s://%s", r.Protocol, r.Domain)
+}
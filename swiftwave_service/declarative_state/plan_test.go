@@ -0,0 +1,161 @@
+package declarative_state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+)
+
+const sampleDocument = `
+version: v1
+domains:
+  - name: example.com
+applications:
+  - name: web
+    source:
+      upstream_type: image
+      docker_image: nginx:latest
+    replicas: 1
+ingress_rules:
+  - protocol: https
+    domain: example.com
+    port: 443
+    application: web
+    target_port: 80
+`
+
+func mustParse(t *testing.T, content string) *Document {
+	document, err := Parse([]byte(content))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return document
+}
+
+func TestParse(t *testing.T) {
+	t.Run("version is required", func(t *testing.T) {
+		_, err := Parse([]byte("applications: []\n"))
+		assert.Error(t, err)
+		_, err = Parse([]byte("version: v0\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown fields are rejected", func(t *testing.T) {
+		_, err := Parse([]byte("version: v1\nservices: []\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("defaults are filled", func(t *testing.T) {
+		document := mustParse(t, sampleDocument)
+		application := document.Applications[0]
+		assert.Equal(t, core.ApplicationKindService, application.Kind)
+		assert.Equal(t, core.DeploymentModeReplicated, application.DeploymentMode)
+		assert.Equal(t, core.DeploymentStrategyRolling, application.DeploymentStrategy.Type)
+		assert.Equal(t, core.ApplicationIngressRule, document.IngressRules[0].TargetType)
+	})
+
+	t.Run("exported document can be parsed again", func(t *testing.T) {
+		document := mustParse(t, sampleDocument)
+		content, err := document.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, document, mustParse(t, string(content)))
+	})
+}
+
+func TestComputePlan(t *testing.T) {
+	t.Run("everything is created on empty state", func(t *testing.T) {
+		plan, err := computePlan(mustParse(t, "version: v1\n"), mustParse(t, sampleDocument), false)
+		assert.NoError(t, err)
+		assert.Len(t, plan.Changes, 3)
+		for _, change := range plan.Changes {
+			assert.Equal(t, ChangeActionCreate, change.Action)
+		}
+		assert.Equal(t, domainKind, plan.Changes[0].Kind)
+		assert.Equal(t, applicationKind, plan.Changes[1].Kind)
+		assert.Equal(t, ingressRuleKind, plan.Changes[2].Kind)
+	})
+
+	t.Run("same document has no changes", func(t *testing.T) {
+		plan, err := computePlan(mustParse(t, sampleDocument), mustParse(t, sampleDocument), true)
+		assert.NoError(t, err)
+		assert.False(t, plan.HasChanges())
+	})
+
+	t.Run("changed fields are reported", func(t *testing.T) {
+		desired := mustParse(t, sampleDocument)
+		desired.Applications[0].Replicas = 3
+		plan, err := computePlan(mustParse(t, sampleDocument), desired, false)
+		assert.NoError(t, err)
+		if assert.Len(t, plan.Changes, 1) {
+			assert.Equal(t, ChangeActionUpdate, plan.Changes[0].Action)
+			assert.Equal(t, "web", plan.Changes[0].Name)
+			assert.NotEmpty(t, plan.Changes[0].Details)
+		}
+	})
+
+	t.Run("missing resources are deleted only on prune", func(t *testing.T) {
+		desired := mustParse(t, "version: v1\ndomains:\n  - name: example.com\n")
+		plan, err := computePlan(mustParse(t, sampleDocument), desired, false)
+		assert.NoError(t, err)
+		assert.False(t, plan.HasChanges())
+		plan, err = computePlan(mustParse(t, sampleDocument), desired, true)
+		assert.NoError(t, err)
+		if assert.Len(t, plan.Changes, 2) {
+			assert.Equal(t, ChangeActionDelete, plan.Changes[0].Action)
+			assert.Equal(t, ingressRuleKind, plan.Changes[0].Kind)
+			assert.Equal(t, applicationKind, plan.Changes[1].Kind)
+		}
+	})
+
+	t.Run("duplicate resources are rejected", func(t *testing.T) {
+		desired := mustParse(t, sampleDocument)
+		desired.Domains = append(desired.Domains, desired.Domains[0])
+		_, err := computePlan(mustParse(t, "version: v1\n"), desired, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown references are rejected", func(t *testing.T) {
+		desired := mustParse(t, sampleDocument)
+		desired.Domains = nil
+		_, err := computePlan(mustParse(t, "version: v1\n"), desired, false)
+		assert.Error(t, err)
+
+		desired = mustParse(t, sampleDocument)
+		desired.Applications[0].Source.GitCredential = "github"
+		_, err = computePlan(mustParse(t, "version: v1\n"), desired, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("target of ingress rule can't be updated", func(t *testing.T) {
+		desired := mustParse(t, sampleDocument)
+		desired.IngressRules[0].TargetPort = 8080
+		_, err := computePlan(mustParse(t, sampleDocument), desired, false)
+		assert.Error(t, err)
+
+		desired = mustParse(t, sampleDocument)
+		desired.IngressRules[0].HttpsRedirect = true
+		plan, err := computePlan(mustParse(t, sampleDocument), desired, false)
+		assert.NoError(t, err)
+		assert.Len(t, plan.Changes, 1)
+	})
+
+	t.Run("omitted secret environment variables are kept", func(t *testing.T) {
+		current := mustParse(t, sampleDocument)
+		current.Applications[0].EnvironmentVariables = []EnvironmentVariable{
+			{Key: "DB_PASSWORD", Value: "s3cr3t", ExposeAsSecret: true},
+			{Key: "DEBUG", Value: "true"},
+		}
+		desired := mustParse(t, sampleDocument)
+		desired.Applications[0].EnvironmentVariables = []EnvironmentVariable{
+			{Key: "DB_PASSWORD", ExposeAsSecret: true},
+			{Key: "DEBUG", Value: ""},
+		}
+		desired.fillOmittedSecrets(current)
+		assert.Equal(t, "s3cr3t", desired.Applications[0].EnvironmentVariables[0].Value)
+		assert.Equal(t, "", desired.Applications[0].EnvironmentVariables[1].Value)
+		plan, err := computePlan(current, desired, false)
+		assert.NoError(t, err)
+		assert.Len(t, plan.Changes, 1)
+	})
+}
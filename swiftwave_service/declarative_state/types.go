@@ -0,0 +1,207 @@
+package declarative_state
+
+import (
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+)
+
+// DocumentVersion : version of the document format, bump it on breaking changes of the format
+const DocumentVersion = "v1"
+
+// Document : declarative state of swiftwave
+// Secrets (credentials, passwords of volumes and dns providers) are never exported.
+// Credentials are referenced by name and should exist before applying the document.
+// Passwords can be added to the document to create the resources, if omitted the stored ones are kept.
+type Document struct {
+	Version                  string                             `yaml:"version"`
+	GitCredentials           []GitCredentialReference           `yaml:"git_credentials,omitempty"`
+	ImageRegistryCredentials []ImageRegistryCredentialReference `yaml:"image_registry_credentials,omitempty"`
+	PersistentVolumes        []PersistentVolume                 `yaml:"persistent_volumes,omitempty"`
+	AccessControlLists       []AccessControlList                `yaml:"access_control_lists,omitempty"`
+	Domains                  []Domain                           `yaml:"domains,omitempty"`
+	ApplicationGroups        []ApplicationGroup                 `yaml:"application_groups,omitempty"`
+	Applications             []Application                      `yaml:"applications,omitempty"`
+	IngressRules             []IngressRule                      `yaml:"ingress_rules,omitempty"`
+	RedirectRules            []RedirectRule                     `yaml:"redirect_rules,omitempty"`
+}
+
+// GitCredentialReference : reference of git credential, identified by name
+type GitCredentialReference struct {
+	Name     string       `yaml:"name"`
+	Type     core.GitType `yaml:"type"`
+	Username string       `yaml:"username,omitempty"`
+}
+
+// ImageRegistryCredentialReference : reference of image registry credential, identified by url and username
+type ImageRegistryCredentialReference struct {
+	Url      string `yaml:"url"`
+	Username string `yaml:"username"`
+}
+
+// PersistentVolume : persistent volume, identified by name
+type PersistentVolume struct {
+	Name       string                    `yaml:"name"`
+	Type       core.PersistentVolumeType `yaml:"type"`
+	NFSConfig  *core.NFSConfig           `yaml:"nfs_config,omitempty"`
+	CIFSConfig *core.CIFSConfig          `yaml:"cifs_config,omitempty"`
+}
+
+// AccessControlList : basic auth access control list, identified by name
+type AccessControlList struct {
+	Name  string              `yaml:"name"`
+	Users []AccessControlUser `yaml:"users,omitempty"`
+}
+
+// AccessControlUser : user of access control list, password is stored as hash
+type AccessControlUser struct {
+	Username          string `yaml:"username"`
+	EncryptedPassword string `yaml:"encrypted_password"`
+}
+
+// Domain : domain, identified by name
+// SSL certificates are not part of the document, those are issued again on the new install
+type Domain struct {
+	Name        string                  `yaml:"name"`
+	DNSProvider *core.DomainDNSProvider `yaml:"dns_provider,omitempty"`
+}
+
+// ApplicationGroup : application group, identified by name
+type ApplicationGroup struct {
+	Name string `yaml:"name"`
+	Logo string `yaml:"logo,omitempty"`
+}
+
+// Application : application, identified by name
+type Application struct {
+	Name                     string                             `yaml:"name"`
+	Group                    string                             `yaml:"group,omitempty"`
+	Kind                     core.ApplicationKind               `yaml:"kind"`
+	Source                   ApplicationSource                  `yaml:"source"`
+	DeploymentMode           core.DeploymentMode                `yaml:"deployment_mode"`
+	Replicas                 uint                               `yaml:"replicas"`
+	Hostname                 string                             `yaml:"hostname,omitempty"`
+	Command                  string                             `yaml:"command,omitempty"`
+	ReleaseCommand           string                             `yaml:"release_command,omitempty"`
+	Capabilities             []string                           `yaml:"capabilities,omitempty"`
+	Sysctls                  []string                           `yaml:"sysctls,omitempty"`
+	PreferredServerHostnames []string                           `yaml:"preferred_server_hostnames,omitempty"`
+	EnvironmentVariables     []EnvironmentVariable              `yaml:"environment_variables,omitempty"`
+	ConfigMounts             []ConfigMount                      `yaml:"config_mounts,omitempty"`
+	PersistentVolumeBindings []PersistentVolumeBinding          `yaml:"persistent_volume_bindings,omitempty"`
+	ResourceLimit            core.ApplicationResourceLimit      `yaml:"resource_limit"`
+	ReservedResource         core.ApplicationReservedResource   `yaml:"reserved_resource"`
+	CustomHealthCheck        core.ApplicationCustomHealthCheck  `yaml:"custom_health_check"`
+	DockerProxy              core.DockerProxyConfig             `yaml:"docker_proxy"`
+	AutoSleep                core.ApplicationAutoSleep          `yaml:"auto_sleep"`
+	AutoScaling              core.ApplicationAutoScaling        `yaml:"auto_scaling"`
+	DeploymentStrategy       core.ApplicationDeploymentStrategy `yaml:"deployment_strategy"`
+	CronJob                  *core.ApplicationCronJob           `yaml:"cron_job,omitempty"`
+}
+
+// ApplicationSource : source of the latest deployment of the application
+type ApplicationSource struct {
+	UpstreamType                 core.UpstreamType                 `yaml:"upstream_type"`
+	GitCredential                string                            `yaml:"git_credential,omitempty"`
+	GitProvider                  string                            `yaml:"git_provider,omitempty"`
+	GitType                      core.GitType                      `yaml:"git_type,omitempty"`
+	GitEndpoint                  string                            `yaml:"git_endpoint,omitempty"`
+	GitSshUser                   string                            `yaml:"git_ssh_user,omitempty"`
+	RepositoryOwner              string                            `yaml:"repository_owner,omitempty"`
+	RepositoryName               string                            `yaml:"repository_name,omitempty"`
+	RepositoryBranch             string                            `yaml:"repository_branch,omitempty"`
+	CodePath                     string                            `yaml:"code_path,omitempty"`
+	SourceCodeCompressedFileName string                            `yaml:"source_code_compressed_file_name,omitempty"`
+	DockerImage                  string                            `yaml:"docker_image,omitempty"`
+	ImageRegistryCredential      *ImageRegistryCredentialReference `yaml:"image_registry_credential,omitempty"`
+	Dockerfile                   string                            `yaml:"dockerfile,omitempty"`
+	BuildArgs                    []BuildArg                        `yaml:"build_args,omitempty"`
+}
+
+// EnvironmentVariable : environment variable of application
+type EnvironmentVariable struct {
+	Key            string `yaml:"key"`
+	Value          string `yaml:"value"`
+	ExposeAsSecret bool   `yaml:"expose_as_secret,omitempty"`
+}
+
+// BuildArg : build arg of application
+type BuildArg struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+// ConfigMount : config mount of application, identified by mounting path
+type ConfigMount struct {
+	MountingPath string `yaml:"mounting_path"`
+	Content      string `yaml:"content"`
+	Uid          uint   `yaml:"uid,omitempty"`
+	Gid          uint   `yaml:"gid,omitempty"`
+}
+
+// PersistentVolumeBinding : binding of persistent volume in application, identified by mounting path
+type PersistentVolumeBinding struct {
+	PersistentVolume string `yaml:"persistent_volume"`
+	MountingPath     string `yaml:"mounting_path"`
+}
+
+// IngressRule : ingress rule, identified by protocol, domain and port
+type IngressRule struct {
+	Protocol        core.ProtocolType          `yaml:"protocol"`
+	Domain          string                     `yaml:"domain,omitempty"`
+	Port            uint                       `yaml:"port"`
+	TargetType      core.IngressRuleTargetType `yaml:"target_type"`
+	Application     string                     `yaml:"application,omitempty"`
+	ExternalService string                     `yaml:"external_service,omitempty"`
+	TargetPort      uint                       `yaml:"target_port"`
+	HttpsRedirect   bool                       `yaml:"https_redirect,omitempty"`
+	// AccessControlList - if set, ingress rule is protected by basic auth using the access control list
+	AccessControlList string `yaml:"access_control_list,omitempty"`
+}
+
+// RedirectRule : redirect rule, identified by protocol and domain
+type RedirectRule struct {
+	Protocol    core.ProtocolType `yaml:"protocol"`
+	Domain      string            `yaml:"domain"`
+	RedirectURL string            `yaml:"redirect_url"`
+}
+
+// ChangeAction : action required to reconcile a resource
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "create"
+	ChangeActionUpdate ChangeAction = "update"
+	ChangeActionDelete ChangeAction = "delete"
+)
+
+// Change : change required to reconcile a resource
+type Change struct {
+	Action ChangeAction
+	// Kind - kind of the resource, e.g. application, ingress_rule
+	Kind string
+	// Name - identifier of the resource in the document
+	Name string
+	// Details - changed fields, values of environment variables and secrets are not included
+	Details []string
+	// resource - desired resource, or the current one for deletion
+	resource interface{}
+}
+
+// Plan : changes required to reconcile the database with the document
+type Plan struct {
+	Changes []Change
+	// Warnings - changes which can't be applied yet, applying the document again later will take care of them
+	Warnings []string
+}
+
+// TaskEnqueuer : enqueue the tasks required to bring the changes live, implemented by worker.Manager
+type TaskEnqueuer interface {
+	EnqueueBuildApplicationRequest(applicationId string, deploymentId string) error
+	EnqueueDeployApplicationRequest(applicationId string, deploymentId string) error
+	EnqueueDeleteApplicationRequest(applicationId string) error
+	EnqueueSSLGenerateRequest(domainId uint) error
+	EnqueueIngressRuleApplyRequest(ingressRuleId uint) error
+	EnqueueIngressRuleDeleteRequest(ingressRuleId uint) error
+	EnqueueIngressRuleHttpsRedirectRequest(ingressRuleId uint, enabled bool) error
+	EnqueueRedirectRuleApplyRequest(redirectRuleId uint) error
+	EnqueueRedirectRuleDeleteRequest(redirectRuleId uint) error
+}
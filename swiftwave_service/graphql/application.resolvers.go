@@ -94,12 +94,9 @@ func (r *applicationResolver) RealtimeInfo(ctx context.Context, obj *model.Appli
 // LatestDeployment is the resolver for the latestDeployment field.
 func (r *applicationResolver) LatestDeployment(ctx context.Context, obj *model.Application) (*model.Deployment, error) {
 	// fetch running instance
-	record, err := core.FindCurrentDeployedDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, obj.ID)
+	record, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, obj.ID)
 	if err != nil {
-		record, err = core.FindLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, obj.ID)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 	return deploymentToGraphqlObject(record), nil
 }
@@ -147,31 +144,44 @@ func (r *applicationResolver) ApplicationGroup(ctx context.Context, obj *model.A
 	return applicationGroupToGraphqlObject(record), nil
 }
 
-// CreateApplication is the resolver for the createApplication field.
-func (r *mutationResolver) CreateApplication(ctx context.Context, input model.ApplicationInput) (*model.Application, error) {
-	record := applicationInputToDatabaseObject(&input)
-	// create transaction
-	transaction := r.ServiceManager.DbClient.Begin()
-	dockerManager, err := FetchDockerManager(ctx, &r.ServiceManager.DbClient)
+// AutoScalingEvents is the resolver for the autoScalingEvents field.
+func (r *applicationResolver) AutoScalingEvents(ctx context.Context, obj *model.Application) ([]*model.AutoScalingEvent, error) {
+	// fetch record
+	records, err := core.FindAutoScalingEventsByApplicationId(ctx, r.ServiceManager.DbClient, obj.ID)
 	if err != nil {
 		return nil, err
 	}
-	err = record.Create(ctx, *transaction, *dockerManager, r.Config.LocalConfig.ServiceConfig.TarballDirectoryPath)
-	if err != nil {
-		transaction.Rollback()
-		return nil, err
+	// convert to graphql object
+	var result = make([]*model.AutoScalingEvent, 0)
+	for _, record := range records {
+		result = append(result, autoScalingEventToGraphqlObject(record))
 	}
-	err = transaction.Commit().Error
+	return result, nil
+}
+
+// CronJobRuns is the resolver for the cronJobRuns field.
+func (r *applicationResolver) CronJobRuns(ctx context.Context, obj *model.Application) ([]*model.CronJobRun, error) {
+	// fetch record
+	records, err := core.FindCronJobRunsByApplicationId(ctx, r.ServiceManager.DbClient, obj.ID)
 	if err != nil {
 		return nil, err
 	}
-	// fetch latest deployment
-	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, record.ID)
+	// convert to graphql object
+	var result = make([]*model.CronJobRun, 0)
+	for _, record := range records {
+		result = append(result, cronJobRunToGraphqlObject(record))
+	}
+	return result, nil
+}
+
+// CreateApplication is the resolver for the createApplication field.
+func (r *mutationResolver) CreateApplication(ctx context.Context, input model.ApplicationInput) (*model.Application, error) {
+	record, deploymentId, err := r.createApplication(ctx, input)
 	if err != nil {
-		return nil, errors.New("failed to fetch latest deployment")
+		return nil, err
 	}
 	// push build request to worker
-	err = r.WorkerManager.EnqueueBuildApplicationRequest(record.ID, latestDeployment.ID)
+	err = r.WorkerManager.EnqueueBuildApplicationRequest(record.ID, deploymentId)
 	if err != nil {
 		return nil, errors.New("failed to process application build request")
 	}
@@ -189,6 +199,7 @@ func (r *mutationResolver) UpdateApplication(ctx context.Context, id string, inp
 	// convert input to database object
 	var databaseObject = applicationInputToDatabaseObject(&input)
 	databaseObject.ID = record.ID
+	keepOmittedResourceInputs(databaseObject, record, &input)
 	databaseObject.LatestDeployment.ApplicationID = record.ID
 	if databaseObject.LatestDeployment.UpstreamType == core.UpstreamTypeGit {
 		gitUsername := ""
@@ -364,8 +375,8 @@ func (r *mutationResolver) SleepApplication(ctx context.Context, id string) (boo
 		tx.Rollback()
 		return false, errors.New("failed to mark application as sleeping due to database error")
 	}
-	// fetch latest deployment
-	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, record.ID)
+	// fetch current deployment
+	latestDeployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, record.ID)
 	if err != nil {
 		return false, errors.New("failed to fetch latest deployment")
 	}
@@ -398,8 +409,8 @@ func (r *mutationResolver) WakeApplication(ctx context.Context, id string) (bool
 		tx.Rollback()
 		return false, errors.New("failed to mark application as sleeping due to database error")
 	}
-	// fetch latest deployment
-	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, record.ID)
+	// fetch current deployment
+	latestDeployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, record.ID)
 	if err != nil {
 		return false, errors.New("failed to fetch latest deployment")
 	}
@@ -0,0 +1,54 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.48
+
+import (
+	"context"
+	"errors"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
+)
+
+// TriggerCronJob is the resolver for the triggerCronJob field.
+func (r *mutationResolver) TriggerCronJob(ctx context.Context, id string) (*model.CronJobRun, error) {
+	var application = &core.Application{}
+	err := application.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return nil, err
+	}
+	run, err := r.WorkerManager.TriggerCronJob(ctx, application, core.CronJobRunTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.New("previous run is still running, concurrent runs are forbidden by the concurrency policy")
+	}
+	return cronJobRunToGraphqlObject(run), nil
+}
+
+// CancelCronJobRun is the resolver for the cancelCronJobRun field.
+func (r *mutationResolver) CancelCronJobRun(ctx context.Context, id uint) (bool, error) {
+	var run = &core.CronJobRun{}
+	err := run.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return false, err
+	}
+	err = r.WorkerManager.CancelCronJobRun(ctx, run, "cancelled by user")
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CronJobRun is the resolver for the cronJobRun field.
+func (r *queryResolver) CronJobRun(ctx context.Context, id uint) (*model.CronJobRun, error) {
+	var run = &core.CronJobRun{}
+	err := run.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return nil, err
+	}
+	return cronJobRunToGraphqlObject(run), nil
+}
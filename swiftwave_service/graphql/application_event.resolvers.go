@@ -0,0 +1,91 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.48
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/swiftwave-org/swiftwave/pkg/pubsub"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
+)
+
+// ApplicationEvents is the resolver for the applicationEvents field.
+func (r *queryResolver) ApplicationEvents(ctx context.Context, applicationID string, filter *model.ApplicationEventFilter) ([]*model.ApplicationEvent, error) {
+	// fetch record
+	records, err := core.FindApplicationEventsByApplicationId(ctx, r.ServiceManager.DbClient, applicationID, applicationEventFilterToDatabaseObject(filter))
+	if err != nil {
+		return nil, err
+	}
+	// convert to graphql object
+	var result = make([]*model.ApplicationEvent, 0)
+	for _, record := range records {
+		result = append(result, applicationEventToGraphqlObject(record))
+	}
+	return result, nil
+}
+
+// WatchApplicationEvents is the resolver for the watchApplicationEvents field.
+func (r *subscriptionResolver) WatchApplicationEvents(ctx context.Context, applicationID string) (<-chan *model.ApplicationEvent, error) {
+	// check if application exists
+	var application core.Application
+	err := application.FindById(ctx, r.ServiceManager.DbClient, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	// create a subscription
+	topic := core.ApplicationEventsTopic(applicationID)
+	subscriptionId, subscriptionChannel, err := r.ServiceManager.PubSubClient.Subscribe(topic)
+	if err != nil {
+		return nil, err
+	}
+	// create a channel
+	var channel = make(chan *model.ApplicationEvent, 100)
+
+	go func() {
+		defer close(channel)
+		// defer handle panic
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Recovered from panic in WatchApplicationEvents: %v", r)
+				return
+			}
+		}()
+		// defer unsubscribe
+		defer func(PubSubClient pubsub.Client, topic string, subscriptionId string) {
+			err := PubSubClient.Unsubscribe(topic, subscriptionId)
+			if err != nil {
+				log.Println(err)
+				log.Println("error while unsubscribing from pubsub")
+			}
+		}(r.ServiceManager.PubSubClient, topic, subscriptionId)
+		// iterate over channel
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-subscriptionChannel:
+				if !ok {
+					return
+				}
+				var event core.ApplicationEvent
+				err := json.Unmarshal([]byte(data), &event)
+				if err != nil {
+					log.Println("failed to decode application event", err)
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case channel <- applicationEventToGraphqlObject(&event):
+				}
+			}
+		}
+	}()
+
+	return channel, nil
+}
@@ -6,7 +6,9 @@ package graphql
 
 import (
 	"context"
+	"errors"
 
+	gitmanager "github.com/swiftwave-org/swiftwave/pkg/git_manager"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
 )
@@ -51,6 +53,49 @@ func (r *mutationResolver) DeleteApplicationGroup(ctx context.Context, id string
 	return true, nil
 }
 
+// UpdateApplicationGroupGitOps is the resolver for the updateApplicationGroupGitOps field.
+func (r *mutationResolver) UpdateApplicationGroupGitOps(ctx context.Context, id string, input model.ApplicationGroupGitOpsInput) (*model.ApplicationGroup, error) {
+	var record = &core.ApplicationGroup{}
+	err := record.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return nil, err
+	}
+	record.GitOps = applicationGroupGitOpsInputToDatabaseObject(&input)
+	if record.GitOps.Enabled {
+		_, err = gitmanager.ParseGitRepoInfo(record.GitOps.RepositoryURL)
+		if err != nil {
+			return nil, errors.New("invalid repository url provided")
+		}
+	}
+	err = record.UpdateGitOps(ctx, r.ServiceManager.DbClient)
+	if err != nil {
+		return nil, err
+	}
+	return applicationGroupToGraphqlObject(record), nil
+}
+
+// SyncApplicationGroup is the resolver for the syncApplicationGroup field.
+func (r *mutationResolver) SyncApplicationGroup(ctx context.Context, id string) (*model.ApplicationGroup, error) {
+	var record = &core.ApplicationGroup{}
+	err := record.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return nil, err
+	}
+	if !record.GitOps.Enabled {
+		return nil, errors.New("git ops is not enabled for the application group")
+	}
+	// the repository is cloned and applied by the worker, failure is recorded in sync status of the group
+	err = record.MarkGitOpsSyncPending(ctx, r.ServiceManager.DbClient)
+	if err != nil {
+		return nil, err
+	}
+	err = r.WorkerManager.EnqueueSyncApplicationGroupRequest(record.ID)
+	if err != nil {
+		return nil, errors.New("failed to schedule the sync of application group")
+	}
+	return applicationGroupToGraphqlObject(record), nil
+}
+
 // ApplicationGroups is the resolver for the applicationGroups field.
 func (r *queryResolver) ApplicationGroups(ctx context.Context) ([]*model.ApplicationGroup, error) {
 	groups, err := core.FindAllApplicationGroups(ctx, r.ServiceManager.DbClient)
@@ -0,0 +1,72 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.48
+
+import (
+	"context"
+	"errors"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/declarative_state"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
+)
+
+// ApplyState is the resolver for the applyState field.
+func (r *mutationResolver) ApplyState(ctx context.Context, input model.StateApplyInput) (*model.StateApplyResult, error) {
+	document, err := declarative_state.Parse([]byte(input.Content))
+	if err != nil {
+		return nil, err
+	}
+	prune := DefaultBool(input.Prune, false)
+	if DefaultBool(input.DryRun, false) {
+		plan, err := declarative_state.ComputePlan(ctx, r.ServiceManager.DbClient, document, prune)
+		if err != nil {
+			return nil, err
+		}
+		result := statePlanToGraphqlObject(plan)
+		result.Message = "Dry run, nothing is applied"
+		return result, nil
+	}
+	dockerManager, err := FetchDockerManager(ctx, &r.ServiceManager.DbClient)
+	if err != nil {
+		return nil, errors.New("failed to fetch docker manager")
+	}
+	restrictedPorts := make([]int, 0)
+	for _, port := range r.Config.SystemConfig.RestrictedPorts {
+		restrictedPorts = append(restrictedPorts, int(port))
+	}
+	plan, err := declarative_state.Apply(ctx, declarative_state.ApplyOptions{
+		DbClient:        r.ServiceManager.DbClient,
+		DockerManager:   dockerManager,
+		TaskEnqueuer:    &r.WorkerManager,
+		RestrictedPorts: restrictedPorts,
+		CodeTarballDir:  r.Config.LocalConfig.ServiceConfig.TarballDirectoryPath,
+		Prune:           prune,
+	}, document)
+	if plan == nil {
+		return nil, err
+	}
+	result := statePlanToGraphqlObject(plan)
+	if err != nil {
+		// changes applied before the failure are kept, report those along with the error
+		result.Success = false
+		result.Message = err.Error()
+		return result, nil
+	}
+	result.Message = "Applied successfully"
+	return result, nil
+}
+
+// ExportState is the resolver for the exportState field.
+func (r *queryResolver) ExportState(ctx context.Context) (string, error) {
+	document, err := declarative_state.Export(ctx, r.ServiceManager.DbClient)
+	if err != nil {
+		return "", err
+	}
+	content, err := document.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
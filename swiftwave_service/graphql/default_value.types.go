@@ -48,3 +48,10 @@ func DefaultFloat32(value *float32, defaultValue float32) float32 {
 	}
 	return *value
 }
+
+func DefaultUint64(value *uint64, defaultValue uint64) uint64 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
@@ -80,6 +80,64 @@ func (r *mutationResolver) CancelDeployment(ctx context.Context, id string) (boo
 	return true, nil
 }
 
+// RollbackToDeployment is the resolver for the rollbackToDeployment field.
+func (r *mutationResolver) RollbackToDeployment(ctx context.Context, id string) (bool, error) {
+	deployment := &core.Deployment{}
+	err := deployment.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return false, err
+	}
+	// only the deployments which have been deployed before have a deployable image
+	if deployment.Status != core.DeploymentStalled && deployment.Status != core.DeploymentStatusDeployed {
+		return false, errors.New("only previously deployed deployments can be rolled back to")
+	}
+	var application = &core.Application{}
+	err = application.FindById(ctx, r.ServiceManager.DbClient, deployment.ApplicationID)
+	if err != nil {
+		return false, err
+	}
+	// the rollback is verified like any other deployment
+	err = r.WorkerManager.EnqueueDeployApplicationRequest(application.ID, deployment.ID)
+	if err != nil {
+		return false, errors.New("failed to enqueue rollback request")
+	}
+	return true, nil
+}
+
+// PromoteDeployment is the resolver for the promoteDeployment field.
+func (r *mutationResolver) PromoteDeployment(ctx context.Context, id string) (bool, error) {
+	deployment := &core.Deployment{}
+	err := deployment.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return false, err
+	}
+	if deployment.Status != core.DeploymentStatusCanary {
+		return false, errors.New("only blue/green or canary deployment can be promoted")
+	}
+	err = r.WorkerManager.EnqueuePromoteDeploymentRequest(deployment.ApplicationID, deployment.ID)
+	if err != nil {
+		return false, errors.New("failed to enqueue promote request")
+	}
+	return true, nil
+}
+
+// AbortDeployment is the resolver for the abortDeployment field.
+func (r *mutationResolver) AbortDeployment(ctx context.Context, id string) (bool, error) {
+	deployment := &core.Deployment{}
+	err := deployment.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return false, err
+	}
+	if deployment.Status != core.DeploymentStatusCanary {
+		return false, errors.New("only blue/green or canary deployment can be aborted")
+	}
+	err = r.WorkerManager.EnqueueAbortDeploymentRequest(deployment.ApplicationID, deployment.ID)
+	if err != nil {
+		return false, errors.New("failed to enqueue abort request")
+	}
+	return true, nil
+}
+
 // Deployment is the resolver for the deployment field.
 func (r *queryResolver) Deployment(ctx context.Context, id string) (*model.Deployment, error) {
 	var deployment = &core.Deployment{}
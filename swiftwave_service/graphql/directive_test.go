@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
+	swiftwaveMiddleware "github.com/swiftwave-org/swiftwave/swiftwave_service/middleware"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newAuthenticatedContext : resolver context carrying the auth info of the given session, as set by the auth middleware
+func newAuthenticatedContext(t *testing.T, db *gorm.DB, sessionID string) context.Context {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	if sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	}
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	err := swiftwaveMiddleware.AuthResolverMiddleware(db)(func(c echo.Context) error { return nil })(c)
+	assert.NoError(t, err)
+	return context.WithValue(req.Context(), "echoContext", c)
+}
+
+func TestDirectives(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&core.User{}, &core.UserSession{}))
+	ctx := context.Background()
+	createSession := func(username string, role core.UserRole) string {
+		user, err := core.CreateUser(ctx, *db, core.User{Username: username, Role: role, PasswordHash: "hash"})
+		assert.NoError(t, err)
+		sessionID, err := core.CreateSession(ctx, *db, user)
+		assert.NoError(t, err)
+		return sessionID
+	}
+	sessions := map[string]string{
+		"admin":           createSession("admin", core.AdministratorRole),
+		"manager":         createSession("manager", core.ManagerRole),
+		"unauthenticated": "",
+	}
+	next := func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	}
+
+	t.Run("isAuthenticated", func(t *testing.T) {
+		expectedErrors := map[string]string{
+			"admin":           "",
+			"manager":         "",
+			"unauthenticated": "unauthenticated",
+		}
+		for name, expectedError := range expectedErrors {
+			res, err := isAuthenticatedDirective(newAuthenticatedContext(t, db, sessions[name]), nil, next)
+			if expectedError == "" {
+				assert.NoError(t, err, name)
+				assert.Equal(t, true, res, name)
+			} else {
+				assert.EqualError(t, err, expectedError, name)
+				assert.Nil(t, res, name)
+			}
+		}
+	})
+
+	t.Run("hasRole", func(t *testing.T) {
+		expectedErrors := map[model.UserRole]map[string]string{
+			model.UserRoleAdmin: {
+				"admin":           "",
+				"manager":         "forbidden, requires admin role",
+				"unauthenticated": "unauthenticated",
+			},
+			model.UserRoleManager: {
+				"admin":           "",
+				"manager":         "",
+				"unauthenticated": "unauthenticated",
+			},
+		}
+		for role, errorsBySession := range expectedErrors {
+			for name, expectedError := range errorsBySession {
+				res, err := hasRoleDirective(newAuthenticatedContext(t, db, sessions[name]), nil, next, role)
+				if expectedError == "" {
+					assert.NoError(t, err, "%s with %s role", name, role)
+					assert.Equal(t, true, res, "%s with %s role", name, role)
+				} else {
+					assert.EqualError(t, err, expectedError, "%s with %s role", name, role)
+					assert.Nil(t, res, "%s with %s role", name, role)
+				}
+			}
+		}
+	})
+
+	t.Run("hasRole rejects session of deleted user", func(t *testing.T) {
+		sessionID := createSession("removed", core.AdministratorRole)
+		user, err := core.FindUserByUsername(ctx, *db, "removed")
+		assert.NoError(t, err)
+		assert.NoError(t, db.Delete(&user).Error)
+		_, err = hasRoleDirective(newAuthenticatedContext(t, db, sessionID), nil, next, model.UserRoleManager)
+		assert.EqualError(t, err, "unauthenticated")
+	})
+}
@@ -79,8 +79,8 @@ func (r *mutationResolver) IssueSsl(ctx context.Context, id uint) (*model.Domain
 	if err != nil {
 		return nil, err
 	}
-	// verify domain configuration
-	configured := r.ServiceManager.SslManager.VerifyDomain(record.Name)
+	// verify domain configuration, not required for dns-01 challenge
+	configured := record.DNSProvider.IsConfigured() || r.ServiceManager.SslManager.VerifyDomain(record.Name)
 	if !configured {
 		return nil, errors.New("domain not configured")
 	}
@@ -114,9 +114,16 @@ func (r *mutationResolver) AddCustomSsl(ctx context.Context, id uint, input mode
 		return nil, err
 	}
 
+	// validate certificate
+	err = record.ValidateCustomSSL(input.PrivateKey, input.FullChain)
+	if err != nil {
+		return nil, err
+	}
 	// update record
+	// auto renew is disabled, so that the certificate is not replaced by let's encrypt
 	record.SSLPrivateKey = input.PrivateKey
 	record.SSLFullChain = input.FullChain
+	record.SSLACMEDirectory = ""
 	record.SSLStatus = core.DomainSSLStatusPending
 	record.SslAutoRenew = false
 	err = record.Update(ctx, r.ServiceManager.DbClient)
@@ -134,6 +141,32 @@ func (r *mutationResolver) AddCustomSsl(ctx context.Context, id uint, input mode
 	return domainToGraphqlObject(&record), nil
 }
 
+// UpdateDomainDNSProvider is the resolver for the updateDomainDNSProvider field.
+func (r *mutationResolver) UpdateDomainDNSProvider(ctx context.Context, id uint, input model.DomainDNSProviderInput) (*model.Domain, error) {
+	// fetch record
+	record := core.Domain{}
+	err := record.FindById(ctx, r.ServiceManager.DbClient, id)
+	if err != nil {
+		return nil, err
+	}
+	dnsProvider := domainDNSProviderInputToDatabaseObject(&input)
+	// secrets are not exposed, so keep the existing ones if not provided
+	if dnsProvider.Type == record.DNSProvider.Type {
+		if input.Rfc2136TSIGSecret == nil {
+			dnsProvider.RFC2136TSIGSecret = record.DNSProvider.RFC2136TSIGSecret
+		}
+		if input.WebhookSecret == nil {
+			dnsProvider.WebhookSecret = record.DNSProvider.WebhookSecret
+		}
+	}
+	record.DNSProvider = *dnsProvider
+	err = record.Update(ctx, r.ServiceManager.DbClient)
+	if err != nil {
+		return nil, err
+	}
+	return domainToGraphqlObject(&record), nil
+}
+
 // Domains is the resolver for the domains field.
 func (r *queryResolver) Domains(ctx context.Context) ([]*model.Domain, error) {
 	records, err := core.FindAllDomains(ctx, r.ServiceManager.DbClient)
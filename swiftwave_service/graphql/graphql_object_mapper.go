@@ -11,7 +11,10 @@ import (
 	"time"
 
 	gitmanager "github.com/swiftwave-org/swiftwave/pkg/git_manager"
+	"github.com/swiftwave-org/swiftwave/pkg/task_queue"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/declarative_state"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/stack_parser"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/worker"
 	"golang.org/x/crypto/ed25519"
 	"golang.org/x/crypto/ssh"
 	"gorm.io/gorm"
@@ -222,16 +225,18 @@ func persistentVolumeRestoreToGraphqlObject(record *core.PersistentVolumeRestore
 // environmentVariableInputToDatabaseObject converts EnvironmentVariableInput to EnvironmentVariableDatabaseObject
 func environmentVariableInputToDatabaseObject(record *model.EnvironmentVariableInput) *core.EnvironmentVariable {
 	return &core.EnvironmentVariable{
-		Key:   strings.TrimSpace(record.Key),
-		Value: strings.TrimSpace(record.Value),
+		Key:            strings.TrimSpace(record.Key),
+		Value:          strings.TrimSpace(record.Value),
+		ExposeAsSecret: DefaultBool(record.ExposeAsSecret, false),
 	}
 }
 
 // environmentVariableToGraphqlObject converts EnvironmentVariable to EnvironmentVariableGraphqlObject
 func environmentVariableToGraphqlObject(record *core.EnvironmentVariable) *model.EnvironmentVariable {
 	return &model.EnvironmentVariable{
-		Key:   record.Key,
-		Value: record.Value,
+		Key:            record.Key,
+		Value:          record.Value,
+		ExposeAsSecret: record.ExposeAsSecret,
 	}
 }
 
@@ -274,14 +279,31 @@ func configMountToGraphqlObject(record *core.ConfigMount) *model.ConfigMount {
 // resourceLimitInputToDatabaseObject converts ResourceLimitInput to ResourceLimitDatabaseObject
 func resourceLimitInputToDatabaseObject(record *model.ResourceLimitInput) *core.ApplicationResourceLimit {
 	return &core.ApplicationResourceLimit{
-		MemoryMB: record.MemoryMb,
+		MemoryMB:  record.MemoryMb,
+		NanoCPUs:  int64(DefaultUint64(record.NanoCpus, 0)),
+		PidsLimit: int64(DefaultUint64(record.PidsLimit, 0)),
+	}
+}
+
+// keepOmittedResourceInputs keeps the current cpu and pids limits of the application for the optional resource inputs which are not provided
+func keepOmittedResourceInputs(application *core.Application, current *core.Application, record *model.ApplicationInput) {
+	if record.ResourceLimit.NanoCpus == nil {
+		application.ResourceLimit.NanoCPUs = current.ResourceLimit.NanoCPUs
+	}
+	if record.ResourceLimit.PidsLimit == nil {
+		application.ResourceLimit.PidsLimit = current.ResourceLimit.PidsLimit
+	}
+	if record.ReservedResource.NanoCpus == nil {
+		application.ReservedResource.NanoCPUs = current.ReservedResource.NanoCPUs
 	}
 }
 
 // resourceLimitToGraphqlObject converts ResourceLimit to ResourceLimitGraphqlObject
 func resourceLimitToGraphqlObject(record *core.ApplicationResourceLimit) *model.ResourceLimit {
 	return &model.ResourceLimit{
-		MemoryMb: record.MemoryMB,
+		MemoryMb:  record.MemoryMB,
+		NanoCpus:  uint64(record.NanoCPUs),
+		PidsLimit: uint64(record.PidsLimit),
 	}
 }
 
@@ -289,6 +311,7 @@ func resourceLimitToGraphqlObject(record *core.ApplicationResourceLimit) *model.
 func reservedResourceInputToDatabaseObject(record *model.ReservedResourceInput) *core.ApplicationReservedResource {
 	return &core.ApplicationReservedResource{
 		MemoryMB: record.MemoryMb,
+		NanoCPUs: int64(DefaultUint64(record.NanoCpus, 0)),
 	}
 }
 
@@ -296,6 +319,7 @@ func reservedResourceInputToDatabaseObject(record *model.ReservedResourceInput)
 func reservedResourceToGraphqlObject(record *core.ApplicationReservedResource) *model.ReservedResource {
 	return &model.ReservedResource{
 		MemoryMb: record.MemoryMB,
+		NanoCpus: uint64(record.NanoCPUs),
 	}
 }
 
@@ -352,9 +376,38 @@ func applicationGroupInputToDatabaseObject(record *model.ApplicationGroupInput)
 // applicationGroupToGraphqlObject converts ApplicationGroup to ApplicationGroupGraphqlObject
 func applicationGroupToGraphqlObject(record *core.ApplicationGroup) *model.ApplicationGroup {
 	return &model.ApplicationGroup{
-		ID:   record.ID,
-		Name: record.Name,
-		Logo: record.Logo,
+		ID:     record.ID,
+		Name:   record.Name,
+		Logo:   record.Logo,
+		GitOps: applicationGroupGitOpsToGraphqlObject(&record.GitOps),
+	}
+}
+
+// applicationGroupGitOpsToGraphqlObject converts ApplicationGroupGitOps to ApplicationGroupGitOpsGraphqlObject
+func applicationGroupGitOpsToGraphqlObject(record *core.ApplicationGroupGitOps) *model.ApplicationGroupGitOps {
+	return &model.ApplicationGroupGitOps{
+		Enabled:           record.Enabled,
+		GitCredentialID:   record.GitCredentialID,
+		RepositoryURL:     record.RepositoryURL,
+		RepositoryBranch:  record.RepositoryBranch,
+		StackFilePath:     record.StackFilePath,
+		SyncStatus:        model.GitOpsSyncStatus(record.SyncStatus),
+		LastAppliedCommit: record.LastAppliedCommit,
+		LastSyncedAt:      record.LastSyncedAt,
+		SyncError:         record.SyncError,
+		DriftDetectedAt:   record.DriftDetectedAt,
+		DriftDetails:      record.DriftDetails,
+	}
+}
+
+// applicationGroupGitOpsInputToDatabaseObject converts ApplicationGroupGitOpsInput to ApplicationGroupGitOpsDatabaseObject
+func applicationGroupGitOpsInputToDatabaseObject(record *model.ApplicationGroupGitOpsInput) core.ApplicationGroupGitOps {
+	return core.ApplicationGroupGitOps{
+		Enabled:          record.Enabled,
+		GitCredentialID:  record.GitCredentialID,
+		RepositoryURL:    strings.TrimSpace(record.RepositoryURL),
+		RepositoryBranch: strings.TrimSpace(record.RepositoryBranch),
+		StackFilePath:    record.StackFilePath,
 	}
 }
 
@@ -384,6 +437,7 @@ func applicationInputToDatabaseObject(record *model.ApplicationInput) *core.Appl
 		IngressRules:             make([]core.IngressRule, 0),
 		Hostname:                 record.Hostname,
 		Command:                  record.Command,
+		ReleaseCommand:           DefaultString(record.ReleaseCommand, ""),
 		Capabilities:             record.Capabilities,
 		Sysctls:                  record.Sysctls,
 		ReservedResource:         *reservedResourceInputToDatabaseObject(record.ReservedResource),
@@ -393,6 +447,11 @@ func applicationInputToDatabaseObject(record *model.ApplicationInput) *core.Appl
 		PreferredServerHostnames: record.PreferredServerHostnames,
 		DockerProxy:              *dockerProxyConfigToDatabaseObject(record.DockerProxyConfig),
 		CustomHealthCheck:        *applicationCustomHealthCheckInputToDatabaseObject(record.CustomHealthCheck),
+		AutoSleep:                *applicationAutoSleepInputToDatabaseObject(record.AutoSleep),
+		AutoScaling:              *applicationAutoScalingInputToDatabaseObject(record.AutoScaling),
+		DeploymentStrategy:       *applicationDeploymentStrategyInputToDatabaseObject(record.DeploymentStrategy),
+		Kind:                     applicationKindInputToDatabaseObject(record.Kind),
+		CronJob:                  *applicationCronJobInputToDatabaseObject(record.CronJob),
 	}
 }
 
@@ -412,11 +471,17 @@ func applicationToGraphqlObject(record *core.Application) *model.Application {
 		IsSleeping:               record.IsSleeping,
 		Hostname:                 record.Hostname,
 		Command:                  record.Command,
+		ReleaseCommand:           record.ReleaseCommand,
 		ApplicationGroupID:       record.ApplicationGroupID,
 		PreferredServerHostnames: record.PreferredServerHostnames,
 		DockerProxyHost:          record.DockerProxyServiceName(),
 		DockerProxyConfig:        dockerProxyConfigToGraphqlObject(&record.DockerProxy),
 		CustomHealthCheck:        applicationCustomHealthCheckToGraphqlObject(&record.CustomHealthCheck),
+		AutoSleep:                applicationAutoSleepToGraphqlObject(&record.AutoSleep),
+		AutoScaling:              applicationAutoScalingToGraphqlObject(&record.AutoScaling),
+		DeploymentStrategy:       applicationDeploymentStrategyToGraphqlObject(&record.DeploymentStrategy),
+		Kind:                     applicationKindToGraphqlObject(record.Kind),
+		CronJob:                  applicationCronJobToGraphqlObject(&record.CronJob),
 	}
 }
 
@@ -460,23 +525,67 @@ func deploymentToGraphqlObject(record *core.Deployment) *model.Deployment {
 // domainInputToDatabaseObject converts DomainInput to DomainDatabaseObject
 func domainInputToDatabaseObject(record *model.DomainInput) *core.Domain {
 	return &core.Domain{
-		Name:         record.Name,
+		Name:         strings.TrimSpace(record.Name),
 		SSLStatus:    core.DomainSSLStatusNone,
 		SslAutoRenew: false,
+		DNSProvider:  *domainDNSProviderInputToDatabaseObject(record.DNSProvider),
 	}
 }
 
 // domainToGraphqlObject converts Domain to DomainGraphqlObject
 func domainToGraphqlObject(record *core.Domain) *model.Domain {
 	return &model.Domain{
-		ID:            record.ID,
-		Name:          record.Name,
-		SslStatus:     model.DomainSSLStatus(record.SSLStatus),
-		SslPrivateKey: record.SSLPrivateKey,
-		SslFullChain:  record.SSLFullChain,
-		SslIssuedAt:   record.SSLIssuedAt,
-		SslIssuer:     record.SSLIssuer,
-		SslAutoRenew:  record.SslAutoRenew,
+		ID:               record.ID,
+		Name:             record.Name,
+		SslStatus:        model.DomainSSLStatus(record.SSLStatus),
+		SslPrivateKey:    record.SSLPrivateKey,
+		SslFullChain:     record.SSLFullChain,
+		SslIssuedAt:      record.SSLIssuedAt,
+		SslExpiredAt:     record.SSLExpiredAt,
+		SslIssuer:        record.SSLIssuer,
+		SslAcmeDirectory: record.SSLACMEDirectory,
+		SslAutoRenew:     record.SslAutoRenew,
+		IsWildcard:       record.IsWildcard(),
+		DNSProvider:      domainDNSProviderToGraphqlObject(&record.DNSProvider),
+	}
+}
+
+// domainDNSProviderToGraphqlObject converts DomainDNSProvider to DomainDNSProviderGraphqlObject
+func domainDNSProviderToGraphqlObject(record *core.DomainDNSProvider) *model.DomainDNSProvider {
+	providerType := record.Type
+	if providerType == "" {
+		providerType = core.DNSProviderNone
+	}
+	return &model.DomainDNSProvider{
+		Type:                 model.DNSProviderType(providerType),
+		Rfc2136Nameserver:    record.RFC2136Nameserver,
+		Rfc2136TSIGKeyName:   record.RFC2136TSIGKeyName,
+		Rfc2136TSIGAlgorithm: record.RFC2136TSIGAlgorithm,
+		WebhookURL:           record.WebhookURL,
+	}
+}
+
+// domainDNSProviderInputToDatabaseObject converts DomainDNSProviderInput to DomainDNSProviderDatabaseObject
+func domainDNSProviderInputToDatabaseObject(record *model.DomainDNSProviderInput) *core.DomainDNSProvider {
+	if record == nil {
+		return &core.DomainDNSProvider{
+			Type: core.DNSProviderNone,
+		}
+	}
+	valueOf := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return strings.TrimSpace(*value)
+	}
+	return &core.DomainDNSProvider{
+		Type:                 core.DNSProviderType(record.Type),
+		RFC2136Nameserver:    valueOf(record.Rfc2136Nameserver),
+		RFC2136TSIGKeyName:   valueOf(record.Rfc2136TSIGKeyName),
+		RFC2136TSIGSecret:    valueOf(record.Rfc2136TSIGSecret),
+		RFC2136TSIGAlgorithm: valueOf(record.Rfc2136TSIGAlgorithm),
+		WebhookURL:           valueOf(record.WebhookURL),
+		WebhookSecret:        valueOf(record.WebhookSecret),
 	}
 }
 
@@ -580,6 +689,231 @@ func applicationCustomHealthCheckInputToDatabaseObject(record *model.Application
 	}
 }
 
+// applicationAutoSleepToGraphqlObject converts ApplicationAutoSleep to ApplicationAutoSleepGraphqlObject
+func applicationAutoSleepToGraphqlObject(record *core.ApplicationAutoSleep) *model.ApplicationAutoSleep {
+	return &model.ApplicationAutoSleep{
+		Enabled:            record.Enabled,
+		IdleTimeoutMinutes: record.IdleTimeoutMinutes,
+	}
+}
+
+// applicationAutoSleepInputToDatabaseObject converts ApplicationAutoSleepInput to ApplicationAutoSleepDatabaseObject
+func applicationAutoSleepInputToDatabaseObject(record *model.ApplicationAutoSleepInput) *core.ApplicationAutoSleep {
+	if record == nil {
+		return &core.ApplicationAutoSleep{
+			Enabled:            false,
+			IdleTimeoutMinutes: 30,
+		}
+	}
+	return &core.ApplicationAutoSleep{
+		Enabled:            record.Enabled,
+		IdleTimeoutMinutes: record.IdleTimeoutMinutes,
+	}
+}
+
+// applicationAutoScalingToGraphqlObject converts ApplicationAutoScaling to ApplicationAutoScalingGraphqlObject
+func applicationAutoScalingToGraphqlObject(record *core.ApplicationAutoScaling) *model.ApplicationAutoScaling {
+	return &model.ApplicationAutoScaling{
+		Enabled:         record.Enabled,
+		MinReplicas:     record.MinReplicas,
+		MaxReplicas:     record.MaxReplicas,
+		Metric:          model.AutoScalingMetric(record.Metric),
+		TargetPercent:   record.TargetPercent,
+		CooldownSeconds: record.CooldownSeconds,
+	}
+}
+
+// applicationAutoScalingInputToDatabaseObject converts ApplicationAutoScalingInput to ApplicationAutoScalingDatabaseObject
+func applicationAutoScalingInputToDatabaseObject(record *model.ApplicationAutoScalingInput) *core.ApplicationAutoScaling {
+	if record == nil {
+		return &core.ApplicationAutoScaling{
+			Enabled:         false,
+			MinReplicas:     1,
+			MaxReplicas:     1,
+			Metric:          core.AutoScalingMetricCPU,
+			TargetPercent:   70,
+			CooldownSeconds: 300,
+		}
+	}
+	return &core.ApplicationAutoScaling{
+		Enabled:         record.Enabled,
+		MinReplicas:     record.MinReplicas,
+		MaxReplicas:     record.MaxReplicas,
+		Metric:          core.AutoScalingMetric(record.Metric),
+		TargetPercent:   record.TargetPercent,
+		CooldownSeconds: record.CooldownSeconds,
+	}
+}
+
+// autoScalingEventToGraphqlObject converts AutoScalingEvent to AutoScalingEventGraphqlObject
+func autoScalingEventToGraphqlObject(record *core.AutoScalingEvent) *model.AutoScalingEvent {
+	return &model.AutoScalingEvent{
+		ID:            record.ID,
+		FromReplicas:  record.FromReplicas,
+		ToReplicas:    record.ToReplicas,
+		Metric:        model.AutoScalingMetric(record.Metric),
+		UsagePercent:  record.UsagePercent,
+		TargetPercent: record.TargetPercent,
+		CreatedAt:     record.CreatedAt,
+	}
+}
+
+// applicationDeploymentStrategyToGraphqlObject converts ApplicationDeploymentStrategy to ApplicationDeploymentStrategyGraphqlObject
+func applicationDeploymentStrategyToGraphqlObject(record *core.ApplicationDeploymentStrategy) *model.ApplicationDeploymentStrategy {
+	strategyType := model.DeploymentStrategyType(record.Type)
+	if record.Type == "" {
+		strategyType = model.DeploymentStrategyTypeRolling
+	}
+	return &model.ApplicationDeploymentStrategy{
+		Type:                strategyType,
+		CanaryWeightPercent: record.CanaryWeightPercent,
+	}
+}
+
+// applicationDeploymentStrategyInputToDatabaseObject converts ApplicationDeploymentStrategyInput to ApplicationDeploymentStrategyDatabaseObject
+func applicationDeploymentStrategyInputToDatabaseObject(record *model.ApplicationDeploymentStrategyInput) *core.ApplicationDeploymentStrategy {
+	if record == nil {
+		return &core.ApplicationDeploymentStrategy{
+			Type:                core.DeploymentStrategyRolling,
+			CanaryWeightPercent: 10,
+		}
+	}
+	return &core.ApplicationDeploymentStrategy{
+		Type:                core.DeploymentStrategyType(record.Type),
+		CanaryWeightPercent: record.CanaryWeightPercent,
+	}
+}
+
+// applicationKindToGraphqlObject converts ApplicationKind to ApplicationKindGraphqlObject
+func applicationKindToGraphqlObject(kind core.ApplicationKind) model.ApplicationKind {
+	if kind == "" {
+		return model.ApplicationKindService
+	}
+	return model.ApplicationKind(kind)
+}
+
+// applicationKindInputToDatabaseObject converts ApplicationKindInput to ApplicationKindDatabaseObject
+func applicationKindInputToDatabaseObject(kind *model.ApplicationKind) core.ApplicationKind {
+	if kind == nil {
+		return core.ApplicationKindService
+	}
+	return core.ApplicationKind(*kind)
+}
+
+// applicationCronJobToGraphqlObject converts ApplicationCronJob to ApplicationCronJobGraphqlObject
+func applicationCronJobToGraphqlObject(record *core.ApplicationCronJob) *model.ApplicationCronJob {
+	return &model.ApplicationCronJob{
+		Schedule:          record.Schedule,
+		ConcurrencyPolicy: model.CronJobConcurrencyPolicy(record.ConcurrencyPolicy),
+		HistoryLimit:      record.HistoryLimit,
+	}
+}
+
+// applicationCronJobInputToDatabaseObject converts ApplicationCronJobInput to ApplicationCronJobDatabaseObject
+func applicationCronJobInputToDatabaseObject(record *model.ApplicationCronJobInput) *core.ApplicationCronJob {
+	if record == nil {
+		return &core.ApplicationCronJob{
+			Schedule:          "",
+			ConcurrencyPolicy: core.CronJobConcurrencyForbid,
+			HistoryLimit:      10,
+		}
+	}
+	return &core.ApplicationCronJob{
+		Schedule:          record.Schedule,
+		ConcurrencyPolicy: core.CronJobConcurrencyPolicy(record.ConcurrencyPolicy),
+		HistoryLimit:      record.HistoryLimit,
+	}
+}
+
+// cronJobRunToGraphqlObject converts CronJobRun to CronJobRunGraphqlObject
+func cronJobRunToGraphqlObject(record *core.CronJobRun) *model.CronJobRun {
+	return &model.CronJobRun{
+		ID:            record.ID,
+		ApplicationID: record.ApplicationID,
+		DeploymentID:  record.DeploymentID,
+		Trigger:       model.CronJobRunTrigger(record.Trigger),
+		Status:        model.CronJobRunStatus(record.Status),
+		ExitCode:      record.ExitCode,
+		Message:       record.Message,
+		Logs:          record.Logs,
+		CreatedAt:     record.CreatedAt,
+		StartedAt:     record.StartedAt,
+		FinishedAt:    record.FinishedAt,
+	}
+}
+
+// applicationEventToGraphqlObject converts ApplicationEvent to ApplicationEventGraphqlObject
+func applicationEventToGraphqlObject(record *core.ApplicationEvent) *model.ApplicationEvent {
+	return &model.ApplicationEvent{
+		ID:             record.ID,
+		ApplicationID:  record.ApplicationID,
+		Type:           model.ApplicationEventType(record.Type),
+		ServerHostName: record.ServerHostName,
+		ServiceName:    record.ServiceName,
+		TaskID:         record.TaskID,
+		ContainerID:    record.ContainerID,
+		ExitCode:       record.ExitCode,
+		Message:        record.Message,
+		CreatedAt:      record.CreatedAt,
+	}
+}
+
+// applicationEventFilterToDatabaseObject converts ApplicationEventFilter to ApplicationEventFilterDatabaseObject
+func applicationEventFilterToDatabaseObject(record *model.ApplicationEventFilter) core.ApplicationEventFilter {
+	filter := core.ApplicationEventFilter{
+		Types: make([]core.ApplicationEventType, 0),
+	}
+	if record == nil {
+		return filter
+	}
+	for _, eventType := range record.Types {
+		filter.Types = append(filter.Types, core.ApplicationEventType(eventType))
+	}
+	filter.From = record.From
+	filter.To = record.To
+	if record.Limit != nil {
+		filter.Limit = *record.Limit
+	}
+	return filter
+}
+
+// taskRunToGraphqlObject converts TaskRun to TaskRunGraphqlObject
+func taskRunToGraphqlObject(record *task_queue.TaskRun) *model.TaskRun {
+	return &model.TaskRun{
+		ID:         record.ID,
+		QueueName:  record.QueueName,
+		TaskID:     record.TaskID,
+		Body:       record.Body,
+		Label:      record.Label,
+		Status:     model.TaskRunStatus(record.Status),
+		Error:      record.Error,
+		StartedAt:  record.StartedAt,
+		FinishedAt: record.FinishedAt,
+		Duration:   record.Duration().Seconds(),
+	}
+}
+
+// taskRunFilterToDatabaseObject converts TaskRunFilter to TaskRunFilterDatabaseObject
+func taskRunFilterToDatabaseObject(record *model.TaskRunFilter) task_queue.TaskRunFilter {
+	filter := task_queue.TaskRunFilter{}
+	if record == nil {
+		return filter
+	}
+	if record.QueueName != nil {
+		filter.QueueName = *record.QueueName
+	}
+	if record.ApplicationID != nil {
+		filter.Label = worker.ApplicationTaskRunLabel(*record.ApplicationID)
+	}
+	if record.Status != nil {
+		filter.Status = task_queue.TaskRunStatus(*record.Status)
+	}
+	if record.Limit != nil {
+		filter.Limit = int(*record.Limit)
+	}
+	return filter
+}
+
 // ingressRuleInputToDatabaseObject converts IngressRuleInput to IngressRuleDatabaseObject
 func ingressRuleInputToDatabaseObject(record *model.IngressRuleInput) *core.IngressRule {
 	// unset domain id if protocol is tcp or udp
@@ -685,6 +1019,7 @@ func userToGraphqlObject(record *core.User) *model.User {
 	return &model.User{
 		ID:          record.ID,
 		Username:    record.Username,
+		Role:        model.UserRole(record.Role),
 		TotpEnabled: record.TotpEnabled,
 	}
 }
@@ -692,7 +1027,12 @@ func userToGraphqlObject(record *core.User) *model.User {
 // stackToApplicationsInput converts Stack to ApplicationInput
 func stackToApplicationsInput(applicationGroupID *string, record *stack_parser.Stack, db gorm.DB) ([]model.ApplicationInput, error) {
 	applications := make([]model.ApplicationInput, 0)
-	for serviceName, service := range record.Services {
+	serviceNames, err := record.ServiceNamesInDeployOrder()
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceName := range serviceNames {
+		service := record.Services[serviceName]
 		environmentVariables := make([]*model.EnvironmentVariableInput, 0)
 		for key, value := range service.Environment {
 			environmentVariables = append(environmentVariables, &model.EnvironmentVariableInput{
@@ -700,6 +1040,14 @@ func stackToApplicationsInput(applicationGroupID *string, record *stack_parser.S
 				Value: value,
 			})
 		}
+		exposeAsSecret := true
+		for key, value := range service.SecretEnvironment {
+			environmentVariables = append(environmentVariables, &model.EnvironmentVariableInput{
+				Key:            key,
+				Value:          value,
+				ExposeAsSecret: &exposeAsSecret,
+			})
+		}
 		persistentVolumeBindings := make([]*model.PersistentVolumeBindingInput, 0)
 		for _, volume := range service.Volumes {
 			// fetch volume from database
@@ -732,6 +1080,9 @@ func stackToApplicationsInput(applicationGroupID *string, record *stack_parser.S
 		if service.Command != nil {
 			command = service.Command.String()
 		}
+		limitNanoCPUs := uint64(service.Deploy.Resources.Limits.NanoCPUs)
+		pidsLimit := uint64(service.Deploy.Resources.Limits.Pids)
+		reservedNanoCPUs := uint64(service.Deploy.Resources.Reservations.NanoCPUs)
 		// docker proxy config
 
 		app := model.ApplicationInput{
@@ -746,10 +1097,13 @@ func stackToApplicationsInput(applicationGroupID *string, record *stack_parser.S
 			DeploymentMode:           model.DeploymentMode(service.Deploy.Mode),
 			Replicas:                 &replicas,
 			ResourceLimit: &model.ResourceLimitInput{
-				MemoryMb: service.Deploy.Resources.Limits.MemoryMB,
+				MemoryMb:  service.Deploy.Resources.Limits.MemoryMB,
+				NanoCpus:  &limitNanoCPUs,
+				PidsLimit: &pidsLimit,
 			},
 			ReservedResource: &model.ReservedResourceInput{
 				MemoryMb: service.Deploy.Resources.Reservations.MemoryMB,
+				NanoCpus: &reservedNanoCPUs,
 			},
 			UpstreamType:                 model.UpstreamTypeImage,
 			DockerImage:                  &image,
@@ -940,3 +1294,27 @@ func appBasicAuthAccessControlUserInputToDatabaseObject(record *model.AppBasicAu
 		AppBasicAuthAccessControlListID: record.AppBasicAuthAccessControlListID,
 	}
 }
+
+// statePlanToGraphqlObject converts declarative state plan to StateApplyResultGraphqlObject
+func statePlanToGraphqlObject(plan *declarative_state.Plan) *model.StateApplyResult {
+	result := &model.StateApplyResult{
+		Success:  true,
+		Changes:  make([]*model.StateChange, 0),
+		Warnings: make([]string, 0),
+		Diff:     plan.String(),
+	}
+	for _, change := range plan.Changes {
+		details := change.Details
+		if details == nil {
+			details = make([]string, 0)
+		}
+		result.Changes = append(result.Changes, &model.StateChange{
+			Action:  model.StateChangeAction(change.Action),
+			Kind:    change.Kind,
+			Name:    change.Name,
+			Details: details,
+		})
+	}
+	result.Warnings = append(result.Warnings, plan.Warnings...)
+	return result
+}
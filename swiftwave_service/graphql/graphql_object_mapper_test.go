@@ -0,0 +1,44 @@
+package graphql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
+	"testing"
+)
+
+func TestKeepOmittedResourceInputs(t *testing.T) {
+	current := &core.Application{
+		ResourceLimit:    core.ApplicationResourceLimit{MemoryMB: 512, NanoCPUs: 2e9, PidsLimit: 100},
+		ReservedResource: core.ApplicationReservedResource{MemoryMB: 256, NanoCPUs: 1e9},
+	}
+
+	t.Run("omitted inputs keep the current limits", func(t *testing.T) {
+		input := &model.ApplicationInput{
+			ResourceLimit:    &model.ResourceLimitInput{MemoryMb: 1024},
+			ReservedResource: &model.ReservedResourceInput{MemoryMb: 128},
+		}
+		application := &core.Application{
+			ResourceLimit:    *resourceLimitInputToDatabaseObject(input.ResourceLimit),
+			ReservedResource: *reservedResourceInputToDatabaseObject(input.ReservedResource),
+		}
+		keepOmittedResourceInputs(application, current, input)
+		assert.Equal(t, core.ApplicationResourceLimit{MemoryMB: 1024, NanoCPUs: 2e9, PidsLimit: 100}, application.ResourceLimit)
+		assert.Equal(t, core.ApplicationReservedResource{MemoryMB: 128, NanoCPUs: 1e9}, application.ReservedResource)
+	})
+
+	t.Run("zero inputs clear the limits", func(t *testing.T) {
+		zero := uint64(0)
+		input := &model.ApplicationInput{
+			ResourceLimit:    &model.ResourceLimitInput{MemoryMb: 1024, NanoCpus: &zero, PidsLimit: &zero},
+			ReservedResource: &model.ReservedResourceInput{MemoryMb: 128, NanoCpus: &zero},
+		}
+		application := &core.Application{
+			ResourceLimit:    *resourceLimitInputToDatabaseObject(input.ResourceLimit),
+			ReservedResource: *reservedResourceInputToDatabaseObject(input.ReservedResource),
+		}
+		keepOmittedResourceInputs(application, current, input)
+		assert.Equal(t, core.ApplicationResourceLimit{MemoryMB: 1024}, application.ResourceLimit)
+		assert.Equal(t, core.ApplicationReservedResource{MemoryMB: 128}, application.ReservedResource)
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/stack_parser"
 	"gorm.io/gorm"
 )
 
@@ -118,67 +119,9 @@ func AppendPublicSSHKeyLocally(pubKey string) error {
 }
 
 func (r *mutationResolver) RunActionsInAllHAProxyNodes(ctx context.Context, db *gorm.DB, innerFunction func(ctx context.Context, db *gorm.DB, transactionId string, manager *haproxymanager.Manager) error) error {
-	// fetch all proxy servers
-	proxyServers, err := core.FetchProxyActiveServers(&r.ServiceManager.DbClient)
-	if err != nil {
-		return err
-	}
-	// don't attempt if no proxy servers are active
-	if len(proxyServers) == 0 {
-		return errors.New("no proxy servers are active")
-	}
-	// fetch all haproxy managers
-	var haproxyManagers []*haproxymanager.Manager
-	haproxyManagers, err = manager.HAProxyClients(context.Background(), proxyServers)
-	if err != nil {
-		return err
-	}
-	// map of server ip and transaction id
-	transactionIdMap := make(map[*haproxymanager.Manager]string)
-	var isFailed bool
-
-	errString := ""
-
-	for _, haproxyManager := range haproxyManagers {
-		// create new haproxy transaction
-		haproxyTransactionId, err := haproxyManager.FetchNewTransactionId()
-		if err != nil {
-			isFailed = true
-			break
-		}
-		// add to map
-		transactionIdMap[haproxyManager] = haproxyTransactionId
-		// run the inner function
-		err = innerFunction(ctx, db, haproxyTransactionId, haproxyManager)
-		if err != nil {
-			errString += err.Error() + "\n"
-			isFailed = true
-			break
-		}
-	}
-
-	for haproxyManager, haproxyTransactionId := range transactionIdMap {
-		var err error
-		if !isFailed {
-			err = haproxyManager.CommitTransaction(haproxyTransactionId)
-		}
-		if isFailed || err != nil {
-			isFailed = true
-			err2 := haproxyManager.DeleteTransaction(haproxyTransactionId)
-			if err2 != nil {
-				errString += err2.Error() + "\n"
-			}
-		}
-	}
-
-	if isFailed {
-		if strings.Compare(errString, "") != 0 {
-			return errors.New("failed to run actions in all haproxy nodes: " + errString)
-		}
-		return errors.New("failed to run actions in all haproxy nodes due to unknown error")
-	} else {
-		return nil
-	}
+	return manager.RunActionsInAllHAProxyNodes(ctx, r.ServiceManager.DbClient, func(transactionId string, haproxyManager *haproxymanager.Manager) error {
+		return innerFunction(ctx, db, transactionId, haproxyManager)
+	})
 }
 
 func GetEchoContext(ctx context.Context) (echo.Context, error) {
@@ -200,3 +143,69 @@ func GetAuthInfo(ctx context.Context) swiftwaveMiddleware.AuthInfo {
 		return swiftwaveMiddleware.AuthInfo{}
 	}
 }
+
+// createApplication : create the application along with its first deployment, the build is enqueued by the caller
+// Returns the id of the first deployment
+func (r *mutationResolver) createApplication(ctx context.Context, input model.ApplicationInput) (*core.Application, string, error) {
+	record := applicationInputToDatabaseObject(&input)
+	// create transaction
+	transaction := r.ServiceManager.DbClient.Begin()
+	dockerManager, err := FetchDockerManager(ctx, &r.ServiceManager.DbClient)
+	if err != nil {
+		return nil, "", err
+	}
+	err = record.Create(ctx, *transaction, *dockerManager, r.Config.LocalConfig.ServiceConfig.TarballDirectoryPath)
+	if err != nil {
+		transaction.Rollback()
+		return nil, "", err
+	}
+	err = transaction.Commit().Error
+	if err != nil {
+		return nil, "", err
+	}
+	// fetch latest deployment
+	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, r.ServiceManager.DbClient, record.ID)
+	if err != nil {
+		return nil, "", errors.New("failed to fetch latest deployment")
+	}
+	return record, latestDeployment.ID, nil
+}
+
+// stackDependencyApplicationIds : ids of the applications of the dependencies of a stack service
+// Dependencies are created before the service, the ones which failed to be created are looked up by name, as an application with the same name may exist already
+func (r *mutationResolver) stackDependencyApplicationIds(ctx context.Context, dependencies stack_parser.DependsOn, createdApplicationIds map[string]string) []string {
+	applicationIds := make([]string, 0)
+	for _, dependency := range dependencies {
+		if applicationId, ok := createdApplicationIds[dependency]; ok {
+			applicationIds = append(applicationIds, applicationId)
+			continue
+		}
+		application := &core.Application{}
+		err := application.FindByName(ctx, r.ServiceManager.DbClient, dependency)
+		if err == nil {
+			applicationIds = append(applicationIds, application.ID)
+		}
+	}
+	return applicationIds
+}
+
+// createIngressRuleForStackPort : expose the published port of stack service as ingress rule of the application
+func (r *mutationResolver) createIngressRuleForStackPort(ctx context.Context, applicationID string, port stack_parser.Port) error {
+	input := model.IngressRuleInput{
+		TargetType:    model.IngressRuleTargetTypeApplication,
+		ApplicationID: applicationID,
+		Protocol:      model.ProtocolType(port.IngressProtocol()),
+		Port:          port.Published,
+		TargetPort:    port.Target,
+	}
+	if port.Domain != "" {
+		domain := &core.Domain{}
+		err := domain.FindByName(ctx, r.ServiceManager.DbClient, port.Domain)
+		if err != nil {
+			return fmt.Errorf("domain %s not found", port.Domain)
+		}
+		input.DomainID = &domain.ID
+	}
+	_, err := r.CreateIngressRule(ctx, input)
+	return err
+}
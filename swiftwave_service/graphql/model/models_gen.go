@@ -32,32 +32,80 @@ type AppBasicAuthAccessControlUserInput struct {
 }
 
 type Application struct {
-	ID                       string                        `json:"id"`
-	Name                     string                        `json:"name"`
-	EnvironmentVariables     []*EnvironmentVariable        `json:"environmentVariables"`
-	PersistentVolumeBindings []*PersistentVolumeBinding    `json:"persistentVolumeBindings"`
-	ConfigMounts             []*ConfigMount                `json:"configMounts"`
-	Capabilities             []string                      `json:"capabilities"`
-	Sysctls                  []string                      `json:"sysctls"`
-	ResourceLimit            *ResourceLimit                `json:"resourceLimit"`
-	ReservedResource         *ReservedResource             `json:"reservedResource"`
-	RealtimeInfo             *RealtimeInfo                 `json:"realtimeInfo"`
-	LatestDeployment         *Deployment                   `json:"latestDeployment"`
-	Deployments              []*Deployment                 `json:"deployments"`
-	DeploymentMode           DeploymentMode                `json:"deploymentMode"`
-	Replicas                 uint                          `json:"replicas"`
-	IngressRules             []*IngressRule                `json:"ingressRules"`
-	IsDeleted                bool                          `json:"isDeleted"`
-	WebhookToken             string                        `json:"webhookToken"`
-	IsSleeping               bool                          `json:"isSleeping"`
-	Command                  string                        `json:"command"`
-	Hostname                 string                        `json:"hostname"`
-	ApplicationGroupID       *string                       `json:"applicationGroupID,omitempty"`
-	ApplicationGroup         *ApplicationGroup             `json:"applicationGroup,omitempty"`
-	PreferredServerHostnames []string                      `json:"preferredServerHostnames"`
-	DockerProxyHost          string                        `json:"dockerProxyHost"`
-	DockerProxyConfig        *DockerProxyConfig            `json:"dockerProxyConfig"`
-	CustomHealthCheck        *ApplicationCustomHealthCheck `json:"customHealthCheck"`
+	ID                       string                         `json:"id"`
+	Name                     string                         `json:"name"`
+	EnvironmentVariables     []*EnvironmentVariable         `json:"environmentVariables"`
+	PersistentVolumeBindings []*PersistentVolumeBinding     `json:"persistentVolumeBindings"`
+	ConfigMounts             []*ConfigMount                 `json:"configMounts"`
+	Capabilities             []string                       `json:"capabilities"`
+	Sysctls                  []string                       `json:"sysctls"`
+	ResourceLimit            *ResourceLimit                 `json:"resourceLimit"`
+	ReservedResource         *ReservedResource              `json:"reservedResource"`
+	RealtimeInfo             *RealtimeInfo                  `json:"realtimeInfo"`
+	LatestDeployment         *Deployment                    `json:"latestDeployment"`
+	Deployments              []*Deployment                  `json:"deployments"`
+	DeploymentMode           DeploymentMode                 `json:"deploymentMode"`
+	Replicas                 uint                           `json:"replicas"`
+	IngressRules             []*IngressRule                 `json:"ingressRules"`
+	IsDeleted                bool                           `json:"isDeleted"`
+	WebhookToken             string                         `json:"webhookToken"`
+	IsSleeping               bool                           `json:"isSleeping"`
+	Command                  string                         `json:"command"`
+	ReleaseCommand           string                         `json:"releaseCommand"`
+	Hostname                 string                         `json:"hostname"`
+	ApplicationGroupID       *string                        `json:"applicationGroupID,omitempty"`
+	ApplicationGroup         *ApplicationGroup              `json:"applicationGroup,omitempty"`
+	PreferredServerHostnames []string                       `json:"preferredServerHostnames"`
+	DockerProxyHost          string                         `json:"dockerProxyHost"`
+	DockerProxyConfig        *DockerProxyConfig             `json:"dockerProxyConfig"`
+	CustomHealthCheck        *ApplicationCustomHealthCheck  `json:"customHealthCheck"`
+	AutoSleep                *ApplicationAutoSleep          `json:"autoSleep"`
+	AutoScaling              *ApplicationAutoScaling        `json:"autoScaling"`
+	AutoScalingEvents        []*AutoScalingEvent            `json:"autoScalingEvents"`
+	DeploymentStrategy       *ApplicationDeploymentStrategy `json:"deploymentStrategy"`
+	Kind                     ApplicationKind                `json:"kind"`
+	CronJob                  *ApplicationCronJob            `json:"cronJob"`
+	CronJobRuns              []*CronJobRun                  `json:"cronJobRuns"`
+}
+
+type ApplicationAutoScaling struct {
+	Enabled         bool              `json:"enabled"`
+	MinReplicas     uint              `json:"min_replicas"`
+	MaxReplicas     uint              `json:"max_replicas"`
+	Metric          AutoScalingMetric `json:"metric"`
+	TargetPercent   uint              `json:"target_percent"`
+	CooldownSeconds uint64            `json:"cooldown_seconds"`
+}
+
+type ApplicationAutoScalingInput struct {
+	Enabled         bool              `json:"enabled"`
+	MinReplicas     uint              `json:"min_replicas"`
+	MaxReplicas     uint              `json:"max_replicas"`
+	Metric          AutoScalingMetric `json:"metric"`
+	TargetPercent   uint              `json:"target_percent"`
+	CooldownSeconds uint64            `json:"cooldown_seconds"`
+}
+
+type ApplicationAutoSleep struct {
+	Enabled            bool   `json:"enabled"`
+	IdleTimeoutMinutes uint64 `json:"idle_timeout_minutes"`
+}
+
+type ApplicationAutoSleepInput struct {
+	Enabled            bool   `json:"enabled"`
+	IdleTimeoutMinutes uint64 `json:"idle_timeout_minutes"`
+}
+
+type ApplicationCronJob struct {
+	Schedule          string                   `json:"schedule"`
+	ConcurrencyPolicy CronJobConcurrencyPolicy `json:"concurrency_policy"`
+	HistoryLimit      uint                     `json:"history_limit"`
+}
+
+type ApplicationCronJobInput struct {
+	Schedule          string                   `json:"schedule"`
+	ConcurrencyPolicy CronJobConcurrencyPolicy `json:"concurrency_policy"`
+	HistoryLimit      uint                     `json:"history_limit"`
 }
 
 type ApplicationCustomHealthCheck struct {
@@ -86,11 +134,64 @@ type ApplicationDeployResult struct {
 	Application *Application `json:"application,omitempty"`
 }
 
+type ApplicationDeploymentStrategy struct {
+	Type                DeploymentStrategyType `json:"type"`
+	CanaryWeightPercent uint                   `json:"canary_weight_percent"`
+}
+
+type ApplicationDeploymentStrategyInput struct {
+	Type                DeploymentStrategyType `json:"type"`
+	CanaryWeightPercent uint                   `json:"canary_weight_percent"`
+}
+
+type ApplicationEvent struct {
+	ID             uint                 `json:"id"`
+	ApplicationID  string               `json:"applicationID"`
+	Type           ApplicationEventType `json:"type"`
+	ServerHostName string               `json:"serverHostName"`
+	ServiceName    string               `json:"serviceName"`
+	TaskID         string               `json:"taskID"`
+	ContainerID    string               `json:"containerID"`
+	ExitCode       int                  `json:"exitCode"`
+	Message        string               `json:"message"`
+	CreatedAt      time.Time            `json:"createdAt"`
+}
+
+type ApplicationEventFilter struct {
+	Types []ApplicationEventType `json:"types,omitempty"`
+	From  *time.Time             `json:"from,omitempty"`
+	To    *time.Time             `json:"to,omitempty"`
+	Limit *uint                  `json:"limit,omitempty"`
+}
+
 type ApplicationGroup struct {
-	ID           string         `json:"id"`
-	Name         string         `json:"name"`
-	Logo         string         `json:"logo"`
-	Applications []*Application `json:"applications"`
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Logo         string                  `json:"logo"`
+	GitOps       *ApplicationGroupGitOps `json:"gitOps"`
+	Applications []*Application          `json:"applications"`
+}
+
+type ApplicationGroupGitOps struct {
+	Enabled           bool             `json:"enabled"`
+	GitCredentialID   *uint            `json:"gitCredentialID,omitempty"`
+	RepositoryURL     string           `json:"repositoryUrl"`
+	RepositoryBranch  string           `json:"repositoryBranch"`
+	StackFilePath     string           `json:"stackFilePath"`
+	SyncStatus        GitOpsSyncStatus `json:"syncStatus"`
+	LastAppliedCommit string           `json:"lastAppliedCommit"`
+	LastSyncedAt      *time.Time       `json:"lastSyncedAt,omitempty"`
+	SyncError         string           `json:"syncError"`
+	DriftDetectedAt   *time.Time       `json:"driftDetectedAt,omitempty"`
+	DriftDetails      string           `json:"driftDetails"`
+}
+
+type ApplicationGroupGitOpsInput struct {
+	Enabled          bool   `json:"enabled"`
+	GitCredentialID  *uint  `json:"gitCredentialID,omitempty"`
+	RepositoryURL    string `json:"repositoryUrl"`
+	RepositoryBranch string `json:"repositoryBranch"`
+	StackFilePath    string `json:"stackFilePath"`
 }
 
 type ApplicationGroupInput struct {
@@ -98,32 +199,38 @@ type ApplicationGroupInput struct {
 }
 
 type ApplicationInput struct {
-	Name                         string                             `json:"name"`
-	EnvironmentVariables         []*EnvironmentVariableInput        `json:"environmentVariables"`
-	PersistentVolumeBindings     []*PersistentVolumeBindingInput    `json:"persistentVolumeBindings"`
-	ConfigMounts                 []*ConfigMountInput                `json:"configMounts"`
-	Capabilities                 []string                           `json:"capabilities"`
-	Sysctls                      []string                           `json:"sysctls"`
-	Dockerfile                   *string                            `json:"dockerfile,omitempty"`
-	BuildArgs                    []*BuildArgInput                   `json:"buildArgs"`
-	DeploymentMode               DeploymentMode                     `json:"deploymentMode"`
-	Replicas                     *uint                              `json:"replicas,omitempty"`
-	ResourceLimit                *ResourceLimitInput                `json:"resourceLimit"`
-	ReservedResource             *ReservedResourceInput             `json:"reservedResource"`
-	UpstreamType                 UpstreamType                       `json:"upstreamType"`
-	Command                      string                             `json:"command"`
-	GitCredentialID              *uint                              `json:"gitCredentialID,omitempty"`
-	RepositoryURL                *string                            `json:"repositoryUrl,omitempty"`
-	RepositoryBranch             *string                            `json:"repositoryBranch,omitempty"`
-	CodePath                     *string                            `json:"codePath,omitempty"`
-	SourceCodeCompressedFileName *string                            `json:"sourceCodeCompressedFileName,omitempty"`
-	DockerImage                  *string                            `json:"dockerImage,omitempty"`
-	Hostname                     string                             `json:"hostname"`
-	ImageRegistryCredentialID    *uint                              `json:"imageRegistryCredentialID,omitempty"`
-	ApplicationGroupID           *string                            `json:"applicationGroupID,omitempty"`
-	PreferredServerHostnames     []string                           `json:"preferredServerHostnames"`
-	DockerProxyConfig            *DockerProxyConfigInput            `json:"dockerProxyConfig"`
-	CustomHealthCheck            *ApplicationCustomHealthCheckInput `json:"customHealthCheck"`
+	Name                         string                              `json:"name"`
+	EnvironmentVariables         []*EnvironmentVariableInput         `json:"environmentVariables"`
+	PersistentVolumeBindings     []*PersistentVolumeBindingInput     `json:"persistentVolumeBindings"`
+	ConfigMounts                 []*ConfigMountInput                 `json:"configMounts"`
+	Capabilities                 []string                            `json:"capabilities"`
+	Sysctls                      []string                            `json:"sysctls"`
+	Dockerfile                   *string                             `json:"dockerfile,omitempty"`
+	BuildArgs                    []*BuildArgInput                    `json:"buildArgs"`
+	DeploymentMode               DeploymentMode                      `json:"deploymentMode"`
+	Replicas                     *uint                               `json:"replicas,omitempty"`
+	ResourceLimit                *ResourceLimitInput                 `json:"resourceLimit"`
+	ReservedResource             *ReservedResourceInput              `json:"reservedResource"`
+	UpstreamType                 UpstreamType                        `json:"upstreamType"`
+	Command                      string                              `json:"command"`
+	ReleaseCommand               *string                             `json:"releaseCommand,omitempty"`
+	GitCredentialID              *uint                               `json:"gitCredentialID,omitempty"`
+	RepositoryURL                *string                             `json:"repositoryUrl,omitempty"`
+	RepositoryBranch             *string                             `json:"repositoryBranch,omitempty"`
+	CodePath                     *string                             `json:"codePath,omitempty"`
+	SourceCodeCompressedFileName *string                             `json:"sourceCodeCompressedFileName,omitempty"`
+	DockerImage                  *string                             `json:"dockerImage,omitempty"`
+	Hostname                     string                              `json:"hostname"`
+	ImageRegistryCredentialID    *uint                               `json:"imageRegistryCredentialID,omitempty"`
+	ApplicationGroupID           *string                             `json:"applicationGroupID,omitempty"`
+	PreferredServerHostnames     []string                            `json:"preferredServerHostnames"`
+	DockerProxyConfig            *DockerProxyConfigInput             `json:"dockerProxyConfig"`
+	CustomHealthCheck            *ApplicationCustomHealthCheckInput  `json:"customHealthCheck"`
+	AutoSleep                    *ApplicationAutoSleepInput          `json:"autoSleep,omitempty"`
+	AutoScaling                  *ApplicationAutoScalingInput        `json:"autoScaling,omitempty"`
+	DeploymentStrategy           *ApplicationDeploymentStrategyInput `json:"deploymentStrategy,omitempty"`
+	Kind                         *ApplicationKind                    `json:"kind,omitempty"`
+	CronJob                      *ApplicationCronJobInput            `json:"cronJob,omitempty"`
 }
 
 type ApplicationResourceAnalytics struct {
@@ -139,6 +246,16 @@ type ApplicationResourceAnalytics struct {
 	Timestamp            time.Time `json:"timestamp"`
 }
 
+type AutoScalingEvent struct {
+	ID            uint              `json:"id"`
+	FromReplicas  uint              `json:"fromReplicas"`
+	ToReplicas    uint              `json:"toReplicas"`
+	Metric        AutoScalingMetric `json:"metric"`
+	UsagePercent  uint              `json:"usagePercent"`
+	TargetPercent uint              `json:"targetPercent"`
+	CreatedAt     time.Time         `json:"createdAt"`
+}
+
 type BuildArg struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -185,6 +302,20 @@ type ConfigMountInput struct {
 	Gid          uint   `json:"gid"`
 }
 
+type CronJobRun struct {
+	ID            uint              `json:"id"`
+	ApplicationID string            `json:"applicationID"`
+	DeploymentID  string            `json:"deploymentID"`
+	Trigger       CronJobRunTrigger `json:"trigger"`
+	Status        CronJobRunStatus  `json:"status"`
+	ExitCode      int               `json:"exitCode"`
+	Message       string            `json:"message"`
+	Logs          string            `json:"logs"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	StartedAt     *time.Time        `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time        `json:"finishedAt,omitempty"`
+}
+
 type CustomSSLInput struct {
 	FullChain  string `json:"fullChain"`
 	PrivateKey string `json:"privateKey"`
@@ -314,30 +445,55 @@ type DockerProxyPermissionInput struct {
 }
 
 type Domain struct {
-	ID            uint            `json:"id"`
-	Name          string          `json:"name"`
-	SslStatus     DomainSSLStatus `json:"sslStatus"`
-	SslFullChain  string          `json:"sslFullChain"`
-	SslPrivateKey string          `json:"sslPrivateKey"`
-	SslIssuedAt   time.Time       `json:"sslIssuedAt"`
-	SslIssuer     string          `json:"sslIssuer"`
-	SslAutoRenew  bool            `json:"sslAutoRenew"`
-	IngressRules  []*IngressRule  `json:"ingressRules"`
-	RedirectRules []*RedirectRule `json:"redirectRules"`
+	ID               uint               `json:"id"`
+	Name             string             `json:"name"`
+	SslStatus        DomainSSLStatus    `json:"sslStatus"`
+	SslFullChain     string             `json:"sslFullChain"`
+	SslPrivateKey    string             `json:"sslPrivateKey"`
+	SslIssuedAt      time.Time          `json:"sslIssuedAt"`
+	SslExpiredAt     time.Time          `json:"sslExpiredAt"`
+	SslIssuer        string             `json:"sslIssuer"`
+	SslAcmeDirectory string             `json:"sslAcmeDirectory"`
+	SslAutoRenew     bool               `json:"sslAutoRenew"`
+	IsWildcard       bool               `json:"isWildcard"`
+	DNSProvider      *DomainDNSProvider `json:"dnsProvider"`
+	IngressRules     []*IngressRule     `json:"ingressRules"`
+	RedirectRules    []*RedirectRule    `json:"redirectRules"`
+}
+
+type DomainDNSProvider struct {
+	Type                 DNSProviderType `json:"type"`
+	Rfc2136Nameserver    string          `json:"rfc2136Nameserver"`
+	Rfc2136TSIGKeyName   string          `json:"rfc2136TSIGKeyName"`
+	Rfc2136TSIGAlgorithm string          `json:"rfc2136TSIGAlgorithm"`
+	WebhookURL           string          `json:"webhookURL"`
+}
+
+type DomainDNSProviderInput struct {
+	Type                 DNSProviderType `json:"type"`
+	Rfc2136Nameserver    *string         `json:"rfc2136Nameserver,omitempty"`
+	Rfc2136TSIGKeyName   *string         `json:"rfc2136TSIGKeyName,omitempty"`
+	Rfc2136TSIGSecret    *string         `json:"rfc2136TSIGSecret,omitempty"`
+	Rfc2136TSIGAlgorithm *string         `json:"rfc2136TSIGAlgorithm,omitempty"`
+	WebhookURL           *string         `json:"webhookURL,omitempty"`
+	WebhookSecret        *string         `json:"webhookSecret,omitempty"`
 }
 
 type DomainInput struct {
-	Name string `json:"name"`
+	Name        string                  `json:"name"`
+	DNSProvider *DomainDNSProviderInput `json:"dnsProvider,omitempty"`
 }
 
 type EnvironmentVariable struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	ExposeAsSecret bool   `json:"exposeAsSecret"`
 }
 
 type EnvironmentVariableInput struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	ExposeAsSecret *bool  `json:"exposeAsSecret,omitempty"`
 }
 
 type FileInfo struct {
@@ -546,19 +702,25 @@ type RequestTotpEnable struct {
 }
 
 type ReservedResource struct {
-	MemoryMb int `json:"memoryMb"`
+	MemoryMb int    `json:"memoryMb"`
+	NanoCpus uint64 `json:"nanoCpus"`
 }
 
 type ReservedResourceInput struct {
-	MemoryMb int `json:"memoryMb"`
+	MemoryMb int     `json:"memoryMb"`
+	NanoCpus *uint64 `json:"nanoCpus,omitempty"`
 }
 
 type ResourceLimit struct {
-	MemoryMb int `json:"memoryMb"`
+	MemoryMb  int    `json:"memoryMb"`
+	NanoCpus  uint64 `json:"nanoCpus"`
+	PidsLimit uint64 `json:"pidsLimit"`
 }
 
 type ResourceLimitInput struct {
-	MemoryMb int `json:"memoryMb"`
+	MemoryMb  int     `json:"memoryMb"`
+	NanoCpus  *uint64 `json:"nanoCpus,omitempty"`
+	PidsLimit *uint64 `json:"pidsLimit,omitempty"`
 }
 
 type RuntimeLog struct {
@@ -642,15 +804,60 @@ type StackVerifyResult struct {
 	InvalidServices         []string `json:"invalidServices"`
 	ValidPreferredServers   []string `json:"validPreferredServers"`
 	InvalidPreferredServers []string `json:"invalidPreferredServers"`
+	ValidDomains            []string `json:"validDomains"`
+	InvalidDomains          []string `json:"invalidDomains"`
+	Warnings                []string `json:"warnings"`
+}
+
+type StateApplyInput struct {
+	Content string `json:"content"`
+	DryRun  *bool  `json:"dryRun,omitempty"`
+	Prune   *bool  `json:"prune,omitempty"`
+}
+
+type StateApplyResult struct {
+	Success  bool           `json:"success"`
+	Message  string         `json:"message"`
+	Changes  []*StateChange `json:"changes"`
+	Warnings []string       `json:"warnings"`
+	Diff     string         `json:"diff"`
+}
+
+type StateChange struct {
+	Action  StateChangeAction `json:"action"`
+	Kind    string            `json:"kind"`
+	Name    string            `json:"name"`
+	Details []string          `json:"details"`
 }
 
 type Subscription struct {
 }
 
+type TaskRun struct {
+	ID         uint          `json:"id"`
+	QueueName  string        `json:"queueName"`
+	TaskID     string        `json:"taskID"`
+	Body       string        `json:"body"`
+	Label      string        `json:"label"`
+	Status     TaskRunStatus `json:"status"`
+	Error      string        `json:"error"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"`
+	Duration   float64       `json:"duration"`
+}
+
+type TaskRunFilter struct {
+	QueueName     *string        `json:"queueName,omitempty"`
+	ApplicationID *string        `json:"applicationId,omitempty"`
+	Status        *TaskRunStatus `json:"status,omitempty"`
+	Limit         *uint          `json:"limit,omitempty"`
+}
+
 type User struct {
-	ID          uint   `json:"id"`
-	Username    string `json:"username"`
-	TotpEnabled bool   `json:"totpEnabled"`
+	ID          uint     `json:"id"`
+	Username    string   `json:"username"`
+	Role        UserRole `json:"role"`
+	TotpEnabled bool     `json:"totpEnabled"`
 }
 
 type UserCredential struct {
@@ -659,8 +866,97 @@ type UserCredential struct {
 }
 
 type UserInput struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string    `json:"username"`
+	Password string    `json:"password"`
+	Role     *UserRole `json:"role,omitempty"`
+}
+
+type ApplicationEventType string
+
+const (
+	ApplicationEventTypeCrashed     ApplicationEventType = "crashed"
+	ApplicationEventTypeOomKilled   ApplicationEventType = "oom_killed"
+	ApplicationEventTypeUnhealthy   ApplicationEventType = "unhealthy"
+	ApplicationEventTypeRestarted   ApplicationEventType = "restarted"
+	ApplicationEventTypeRescheduled ApplicationEventType = "rescheduled"
+)
+
+var AllApplicationEventType = []ApplicationEventType{
+	ApplicationEventTypeCrashed,
+	ApplicationEventTypeOomKilled,
+	ApplicationEventTypeUnhealthy,
+	ApplicationEventTypeRestarted,
+	ApplicationEventTypeRescheduled,
+}
+
+func (e ApplicationEventType) IsValid() bool {
+	switch e {
+	case ApplicationEventTypeCrashed, ApplicationEventTypeOomKilled, ApplicationEventTypeUnhealthy, ApplicationEventTypeRestarted, ApplicationEventTypeRescheduled:
+		return true
+	}
+	return false
+}
+
+func (e ApplicationEventType) String() string {
+	return string(e)
+}
+
+func (e *ApplicationEventType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ApplicationEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ApplicationEventType", str)
+	}
+	return nil
+}
+
+func (e ApplicationEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ApplicationKind string
+
+const (
+	ApplicationKindService ApplicationKind = "service"
+	ApplicationKindCronJob ApplicationKind = "cron_job"
+)
+
+var AllApplicationKind = []ApplicationKind{
+	ApplicationKindService,
+	ApplicationKindCronJob,
+}
+
+func (e ApplicationKind) IsValid() bool {
+	switch e {
+	case ApplicationKindService, ApplicationKindCronJob:
+		return true
+	}
+	return false
+}
+
+func (e ApplicationKind) String() string {
+	return string(e)
+}
+
+func (e *ApplicationKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ApplicationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ApplicationKind", str)
+	}
+	return nil
+}
+
+func (e ApplicationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
 type ApplicationResourceAnalyticsTimeframe string
@@ -714,6 +1010,221 @@ func (e ApplicationResourceAnalyticsTimeframe) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type AutoScalingMetric string
+
+const (
+	AutoScalingMetricCPU    AutoScalingMetric = "cpu"
+	AutoScalingMetricMemory AutoScalingMetric = "memory"
+)
+
+var AllAutoScalingMetric = []AutoScalingMetric{
+	AutoScalingMetricCPU,
+	AutoScalingMetricMemory,
+}
+
+func (e AutoScalingMetric) IsValid() bool {
+	switch e {
+	case AutoScalingMetricCPU, AutoScalingMetricMemory:
+		return true
+	}
+	return false
+}
+
+func (e AutoScalingMetric) String() string {
+	return string(e)
+}
+
+func (e *AutoScalingMetric) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AutoScalingMetric(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AutoScalingMetric", str)
+	}
+	return nil
+}
+
+func (e AutoScalingMetric) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CronJobConcurrencyPolicy string
+
+const (
+	CronJobConcurrencyPolicyAllow   CronJobConcurrencyPolicy = "allow"
+	CronJobConcurrencyPolicyForbid  CronJobConcurrencyPolicy = "forbid"
+	CronJobConcurrencyPolicyReplace CronJobConcurrencyPolicy = "replace"
+)
+
+var AllCronJobConcurrencyPolicy = []CronJobConcurrencyPolicy{
+	CronJobConcurrencyPolicyAllow,
+	CronJobConcurrencyPolicyForbid,
+	CronJobConcurrencyPolicyReplace,
+}
+
+func (e CronJobConcurrencyPolicy) IsValid() bool {
+	switch e {
+	case CronJobConcurrencyPolicyAllow, CronJobConcurrencyPolicyForbid, CronJobConcurrencyPolicyReplace:
+		return true
+	}
+	return false
+}
+
+func (e CronJobConcurrencyPolicy) String() string {
+	return string(e)
+}
+
+func (e *CronJobConcurrencyPolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CronJobConcurrencyPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CronJobConcurrencyPolicy", str)
+	}
+	return nil
+}
+
+func (e CronJobConcurrencyPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CronJobRunStatus string
+
+const (
+	CronJobRunStatusPending   CronJobRunStatus = "pending"
+	CronJobRunStatusRunning   CronJobRunStatus = "running"
+	CronJobRunStatusSucceeded CronJobRunStatus = "succeeded"
+	CronJobRunStatusFailed    CronJobRunStatus = "failed"
+	CronJobRunStatusCancelled CronJobRunStatus = "cancelled"
+)
+
+var AllCronJobRunStatus = []CronJobRunStatus{
+	CronJobRunStatusPending,
+	CronJobRunStatusRunning,
+	CronJobRunStatusSucceeded,
+	CronJobRunStatusFailed,
+	CronJobRunStatusCancelled,
+}
+
+func (e CronJobRunStatus) IsValid() bool {
+	switch e {
+	case CronJobRunStatusPending, CronJobRunStatusRunning, CronJobRunStatusSucceeded, CronJobRunStatusFailed, CronJobRunStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e CronJobRunStatus) String() string {
+	return string(e)
+}
+
+func (e *CronJobRunStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CronJobRunStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CronJobRunStatus", str)
+	}
+	return nil
+}
+
+func (e CronJobRunStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CronJobRunTrigger string
+
+const (
+	CronJobRunTriggerSchedule CronJobRunTrigger = "schedule"
+	CronJobRunTriggerManual   CronJobRunTrigger = "manual"
+)
+
+var AllCronJobRunTrigger = []CronJobRunTrigger{
+	CronJobRunTriggerSchedule,
+	CronJobRunTriggerManual,
+}
+
+func (e CronJobRunTrigger) IsValid() bool {
+	switch e {
+	case CronJobRunTriggerSchedule, CronJobRunTriggerManual:
+		return true
+	}
+	return false
+}
+
+func (e CronJobRunTrigger) String() string {
+	return string(e)
+}
+
+func (e *CronJobRunTrigger) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CronJobRunTrigger(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CronJobRunTrigger", str)
+	}
+	return nil
+}
+
+func (e CronJobRunTrigger) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DNSProviderType string
+
+const (
+	DNSProviderTypeNone    DNSProviderType = "none"
+	DNSProviderTypeRfc2136 DNSProviderType = "rfc2136"
+	DNSProviderTypeWebhook DNSProviderType = "webhook"
+)
+
+var AllDNSProviderType = []DNSProviderType{
+	DNSProviderTypeNone,
+	DNSProviderTypeRfc2136,
+	DNSProviderTypeWebhook,
+}
+
+func (e DNSProviderType) IsValid() bool {
+	switch e {
+	case DNSProviderTypeNone, DNSProviderTypeRfc2136, DNSProviderTypeWebhook:
+		return true
+	}
+	return false
+}
+
+func (e DNSProviderType) String() string {
+	return string(e)
+}
+
+func (e *DNSProviderType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DNSProviderType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DNSProviderType", str)
+	}
+	return nil
+}
+
+func (e DNSProviderType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type DeploymentMode string
 
 const (
@@ -765,6 +1276,7 @@ const (
 	DeploymentStatusStopped       DeploymentStatus = "stopped"
 	DeploymentStatusFailed        DeploymentStatus = "failed"
 	DeploymentStatusStalled       DeploymentStatus = "stalled"
+	DeploymentStatusCanary        DeploymentStatus = "canary"
 )
 
 var AllDeploymentStatus = []DeploymentStatus{
@@ -775,11 +1287,12 @@ var AllDeploymentStatus = []DeploymentStatus{
 	DeploymentStatusStopped,
 	DeploymentStatusFailed,
 	DeploymentStatusStalled,
+	DeploymentStatusCanary,
 }
 
 func (e DeploymentStatus) IsValid() bool {
 	switch e {
-	case DeploymentStatusPending, DeploymentStatusDeployPending, DeploymentStatusDeploying, DeploymentStatusDeployed, DeploymentStatusStopped, DeploymentStatusFailed, DeploymentStatusStalled:
+	case DeploymentStatusPending, DeploymentStatusDeployPending, DeploymentStatusDeploying, DeploymentStatusDeployed, DeploymentStatusStopped, DeploymentStatusFailed, DeploymentStatusStalled, DeploymentStatusCanary:
 		return true
 	}
 	return false
@@ -806,6 +1319,49 @@ func (e DeploymentStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type DeploymentStrategyType string
+
+const (
+	DeploymentStrategyTypeRolling   DeploymentStrategyType = "rolling"
+	DeploymentStrategyTypeBlueGreen DeploymentStrategyType = "blue_green"
+	DeploymentStrategyTypeCanary    DeploymentStrategyType = "canary"
+)
+
+var AllDeploymentStrategyType = []DeploymentStrategyType{
+	DeploymentStrategyTypeRolling,
+	DeploymentStrategyTypeBlueGreen,
+	DeploymentStrategyTypeCanary,
+}
+
+func (e DeploymentStrategyType) IsValid() bool {
+	switch e {
+	case DeploymentStrategyTypeRolling, DeploymentStrategyTypeBlueGreen, DeploymentStrategyTypeCanary:
+		return true
+	}
+	return false
+}
+
+func (e DeploymentStrategyType) String() string {
+	return string(e)
+}
+
+func (e *DeploymentStrategyType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeploymentStrategyType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeploymentStrategyType", str)
+	}
+	return nil
+}
+
+func (e DeploymentStrategyType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type DockerConfigSourceType string
 
 const (
@@ -937,6 +1493,49 @@ func (e DomainSSLStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type GitOpsSyncStatus string
+
+const (
+	GitOpsSyncStatusPending GitOpsSyncStatus = "pending"
+	GitOpsSyncStatusSynced  GitOpsSyncStatus = "synced"
+	GitOpsSyncStatusFailed  GitOpsSyncStatus = "failed"
+)
+
+var AllGitOpsSyncStatus = []GitOpsSyncStatus{
+	GitOpsSyncStatusPending,
+	GitOpsSyncStatusSynced,
+	GitOpsSyncStatusFailed,
+}
+
+func (e GitOpsSyncStatus) IsValid() bool {
+	switch e {
+	case GitOpsSyncStatusPending, GitOpsSyncStatusSynced, GitOpsSyncStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e GitOpsSyncStatus) String() string {
+	return string(e)
+}
+
+func (e *GitOpsSyncStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GitOpsSyncStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GitOpsSyncStatus", str)
+	}
+	return nil
+}
+
+func (e GitOpsSyncStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type GitType string
 
 const (
@@ -1635,6 +2234,49 @@ func (e ServerStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type StateChangeAction string
+
+const (
+	StateChangeActionCreate StateChangeAction = "create"
+	StateChangeActionUpdate StateChangeAction = "update"
+	StateChangeActionDelete StateChangeAction = "delete"
+)
+
+var AllStateChangeAction = []StateChangeAction{
+	StateChangeActionCreate,
+	StateChangeActionUpdate,
+	StateChangeActionDelete,
+}
+
+func (e StateChangeAction) IsValid() bool {
+	switch e {
+	case StateChangeActionCreate, StateChangeActionUpdate, StateChangeActionDelete:
+		return true
+	}
+	return false
+}
+
+func (e StateChangeAction) String() string {
+	return string(e)
+}
+
+func (e *StateChangeAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StateChangeAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StateChangeAction", str)
+	}
+	return nil
+}
+
+func (e StateChangeAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type SwarmMode string
 
 const (
@@ -1676,6 +2318,49 @@ func (e SwarmMode) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type TaskRunStatus string
+
+const (
+	TaskRunStatusRunning   TaskRunStatus = "running"
+	TaskRunStatusSucceeded TaskRunStatus = "succeeded"
+	TaskRunStatusFailed    TaskRunStatus = "failed"
+)
+
+var AllTaskRunStatus = []TaskRunStatus{
+	TaskRunStatusRunning,
+	TaskRunStatusSucceeded,
+	TaskRunStatusFailed,
+}
+
+func (e TaskRunStatus) IsValid() bool {
+	switch e {
+	case TaskRunStatusRunning, TaskRunStatusSucceeded, TaskRunStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e TaskRunStatus) String() string {
+	return string(e)
+}
+
+func (e *TaskRunStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskRunStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskRunStatus", str)
+	}
+	return nil
+}
+
+func (e TaskRunStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type UpstreamType string
 
 const (
@@ -1718,3 +2403,44 @@ func (e *UpstreamType) UnmarshalGQL(v interface{}) error {
 func (e UpstreamType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type UserRole string
+
+const (
+	UserRoleAdmin   UserRole = "admin"
+	UserRoleManager UserRole = "manager"
+)
+
+var AllUserRole = []UserRole{
+	UserRoleAdmin,
+	UserRoleManager,
+}
+
+func (e UserRole) IsValid() bool {
+	switch e {
+	case UserRoleAdmin, UserRoleManager:
+		return true
+	}
+	return false
+}
+
+func (e UserRole) String() string {
+	return string(e)
+}
+
+func (e *UserRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserRole", str)
+	}
+	return nil
+}
+
+func (e UserRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
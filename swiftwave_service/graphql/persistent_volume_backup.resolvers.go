@@ -24,7 +24,7 @@ func (r *mutationResolver) BackupPersistentVolume(ctx context.Context, input mod
 		return nil, err
 	}
 	// send to task queue
-	err = r.WorkerManager.EnqueuePersistentVolumeBackupRequest(record.ID)
+	err = r.WorkerManager.EnqueuePersistentVolumeBackupRequest(record.ID, record.PersistentVolumeID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+	"testing"
+)
+
+// operations that only admin can perform
+// manager can't manage users, system configuration and servers
+var adminOnlyOperations = map[ast.Operation][]string{
+	ast.Query: {
+		"users",
+		"user",
+		"fetchSystemLogRecords",
+	},
+	ast.Mutation: {
+		"createUser",
+		"deleteUser",
+		"changeUserRole",
+		"createServer",
+		"deleteServer",
+		"fetchAnalyticsServiceToken",
+		"changeServerIpAddress",
+		"restartSystem",
+	},
+}
+
+func TestRoleDirective(t *testing.T) {
+	schema := NewExecutableSchema(Config{}).Schema()
+
+	t.Run("admin only operations require admin role", func(t *testing.T) {
+		for operation, fields := range adminOnlyOperations {
+			definition := schema.Query
+			if operation == ast.Mutation {
+				definition = schema.Mutation
+			}
+			for _, fieldName := range fields {
+				field := definition.Fields.ForName(fieldName)
+				if !assert.NotNil(t, field, "`%s` should be present in schema", fieldName) {
+					continue
+				}
+				directive := field.Directives.ForName("hasRole")
+				if !assert.NotNil(t, directive, "`%s` should have hasRole directive", fieldName) {
+					continue
+				}
+				assert.Equal(t, "admin", directive.Arguments.ForName("role").Value.Raw, "`%s` should require admin role", fieldName)
+			}
+		}
+	})
+
+	t.Run("every operation requires authentication", func(t *testing.T) {
+		publicOperations := map[string]bool{
+			"login":  true,
+			"logout": true,
+		}
+		for _, definition := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+			for _, field := range definition.Fields {
+				if publicOperations[field.Name] || field.Name == "__schema" || field.Name == "__type" {
+					continue
+				}
+				isProtected := field.Directives.ForName("isAuthenticated") != nil || field.Directives.ForName("hasRole") != nil
+				assert.True(t, isProtected, "`%s` should have isAuthenticated or hasRole directive", field.Name)
+			}
+		}
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
 )
 
 func (server *Server) Initialize() {
@@ -17,13 +19,8 @@ func (server *Server) Initialize() {
 		ServiceManager: *server.ServiceManager,
 		WorkerManager:  *server.WorkerManager,
 	}}
-	c.Directives.IsAuthenticated = func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
-		info := GetAuthInfo(ctx)
-		if info.IsAuthorized() {
-			return next(ctx)
-		}
-		return nil, errors.New("unauthenticated")
-	}
+	c.Directives.IsAuthenticated = isAuthenticatedDirective
+	c.Directives.HasRole = hasRoleDirective
 	graphqlHandler := handler.New(
 		NewExecutableSchema(c),
 	)
@@ -98,3 +95,28 @@ func (server *Server) Initialize() {
 		return nil
 	})
 }
+
+// isAuthenticatedDirective : allow the resolver to run only for authenticated users
+func isAuthenticatedDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	info := GetAuthInfo(ctx)
+	if info.IsAuthorized() {
+		return next(ctx)
+	}
+	return nil, errors.New("unauthenticated")
+}
+
+// hasRoleDirective : allow the resolver to run only for authenticated users having the required role
+func hasRoleDirective(ctx context.Context, obj interface{}, next graphql.Resolver, role model.UserRole) (interface{}, error) {
+	info := GetAuthInfo(ctx)
+	if !info.IsAuthorized() {
+		return nil, errors.New("unauthenticated")
+	}
+	allowed, err := info.HasRole(core.UserRole(role))
+	if err != nil {
+		return nil, errors.New("unauthenticated")
+	}
+	if !allowed {
+		return nil, errors.New("forbidden, requires " + string(role) + " role")
+	}
+	return next(ctx)
+}
@@ -53,6 +53,12 @@ func (r *mutationResolver) VerifyStack(ctx context.Context, input model.StackInp
 		InvalidServices:         make([]string, 0),
 		ValidPreferredServers:   make([]string, 0),
 		InvalidPreferredServers: make([]string, 0),
+		ValidDomains:            make([]string, 0),
+		InvalidDomains:          make([]string, 0),
+		Warnings:                stackFilled.Warnings,
+	}
+	if result.Warnings == nil {
+		result.Warnings = make([]string, 0)
 	}
 	// fetch all the service names
 	serviceNames := stackFilled.ServiceNames()
@@ -96,6 +102,16 @@ func (r *mutationResolver) VerifyStack(ctx context.Context, input model.StackInp
 			result.ValidPreferredServers = append(result.ValidPreferredServers, preferredServerHostname)
 		}
 	}
+	// check domains of published ports
+	for _, domainName := range stackFilled.DomainNames() {
+		domain := &core.Domain{}
+		err := domain.FindByName(ctx, r.ServiceManager.DbClient, domainName)
+		if err != nil {
+			result.InvalidDomains = append(result.InvalidDomains, domainName)
+		} else {
+			result.ValidDomains = append(result.ValidDomains, domainName)
+		}
+	}
 
 	// set message
 	if len(result.InvalidServices) == 0 {
@@ -140,6 +156,13 @@ func (r *mutationResolver) VerifyStack(ctx context.Context, input model.StackInp
 		result.Error = fmt.Sprintf("%s\nThese preferred servers doesn't exist -> %s . Please fix in stack config.\n", result.Error, unverifiedPreferredServerStr)
 	}
 
+	if len(result.InvalidDomains) == 0 {
+		result.Message = fmt.Sprintf("%s\nAll domains are verified", result.Message)
+	} else {
+		result.Success = false
+		result.Error = fmt.Sprintf("%s\nThese domains doesn't exist -> %s . Please add domains from dashboard.\n", result.Error, strings.Join(result.InvalidDomains, ", "))
+	}
+
 	// validate docker proxy config
 	for _, service := range stackFilled.ServiceNames() {
 		s := stackFilled.Services[service]
@@ -210,23 +233,51 @@ func (r *mutationResolver) DeployStack(ctx context.Context, input model.StackInp
 	results := make([]*model.ApplicationDeployResult, 0)
 	// at-least one application created ?
 	isAnyApplicationCreated := false
-	// create application
+	// ids of the created applications by service name
+	createdApplicationIds := make(map[string]string)
+	// create application, dependencies are created first
 	for _, applicationInput := range applicationsInput {
-		application, err := r.CreateApplication(ctx, applicationInput)
+		record, deploymentId, err := r.createApplication(ctx, applicationInput)
 		if err != nil {
 			results = append(results, &model.ApplicationDeployResult{
 				Success:     false,
 				Message:     err.Error(),
-				Application: application,
+				Application: nil,
 			})
+			continue
+		}
+		isAnyApplicationCreated = true
+		createdApplicationIds[applicationInput.Name] = record.ID
+		application := applicationToGraphqlObject(record)
+		message := "Application created successfully"
+		// build starts once the dependencies are deployed
+		dependencyApplicationIds := r.stackDependencyApplicationIds(ctx, stackFilled.Services[applicationInput.Name].DependsOn, createdApplicationIds)
+		if len(dependencyApplicationIds) == 0 {
+			err = r.WorkerManager.EnqueueBuildApplicationRequest(record.ID, deploymentId)
 		} else {
-			isAnyApplicationCreated = true
+			err = r.WorkerManager.EnqueueWaitForDependenciesRequest(record.ID, deploymentId, dependencyApplicationIds, 0)
+			message = fmt.Sprintf("%s\nDeployment will start once its dependencies are deployed", message)
+		}
+		if err != nil {
 			results = append(results, &model.ApplicationDeployResult{
-				Success:     true,
-				Message:     "Application created successfully",
+				Success:     false,
+				Message:     "failed to process application build request",
 				Application: application,
 			})
+			continue
+		}
+		// expose the published ports
+		for _, port := range stackFilled.Services[applicationInput.Name].Ports {
+			err = r.createIngressRuleForStackPort(ctx, application.ID, port)
+			if err != nil {
+				message = fmt.Sprintf("%s\nFailed to expose port %d > %s", message, port.Published, err.Error())
+			}
 		}
+		results = append(results, &model.ApplicationDeployResult{
+			Success:     true,
+			Message:     message,
+			Application: application,
+		})
 	}
 	if !isAnyApplicationCreated && applicationGroupID != nil {
 		applicationGroup := &core.ApplicationGroup{
@@ -0,0 +1,88 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.48
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/swiftwave-org/swiftwave/pkg/pubsub"
+	"github.com/swiftwave-org/swiftwave/pkg/task_queue"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/graphql/model"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+)
+
+// TaskRuns is the resolver for the taskRuns field.
+func (r *queryResolver) TaskRuns(ctx context.Context, filter *model.TaskRunFilter) ([]*model.TaskRun, error) {
+	records, err := task_queue.FindTaskRuns(&r.ServiceManager.DbClient, taskRunFilterToDatabaseObject(filter))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.TaskRun, 0)
+	for _, record := range records {
+		result = append(result, taskRunToGraphqlObject(&record))
+	}
+	return result, nil
+}
+
+// WatchTaskRuns is the resolver for the watchTaskRuns field.
+func (r *subscriptionResolver) WatchTaskRuns(ctx context.Context, filter *model.TaskRunFilter) (<-chan *model.TaskRun, error) {
+	runFilter := taskRunFilterToDatabaseObject(filter)
+	// create a subscription
+	topic := service_manager.TaskRunsTopic
+	subscriptionId, subscriptionChannel, err := r.ServiceManager.PubSubClient.Subscribe(topic)
+	if err != nil {
+		return nil, err
+	}
+	// create a channel
+	var channel = make(chan *model.TaskRun, 100)
+
+	go func() {
+		defer close(channel)
+		// defer handle panic
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Recovered from panic in WatchTaskRuns: %v", r)
+				return
+			}
+		}()
+		// defer unsubscribe
+		defer func(PubSubClient pubsub.Client, topic string, subscriptionId string) {
+			err := PubSubClient.Unsubscribe(topic, subscriptionId)
+			if err != nil {
+				log.Println(err)
+				log.Println("error while unsubscribing from pubsub")
+			}
+		}(r.ServiceManager.PubSubClient, topic, subscriptionId)
+		// iterate over channel
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-subscriptionChannel:
+				if !ok {
+					return
+				}
+				var run task_queue.TaskRun
+				err := json.Unmarshal([]byte(data), &run)
+				if err != nil {
+					log.Println("failed to decode task run", err)
+					continue
+				}
+				if !runFilter.Matches(run) {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case channel <- taskRunToGraphqlObject(&run):
+				}
+			}
+		}
+	}()
+
+	return channel, nil
+}
@@ -28,6 +28,10 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input *model.UserInpu
 	}
 	user := core.User{
 		Username: input.Username,
+		Role:     core.AdministratorRole,
+	}
+	if input.Role != nil {
+		user.Role = core.UserRole(*input.Role)
 	}
 	err = user.SetPassword(input.Password)
 	if err != nil {
@@ -49,8 +53,7 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id uint) (bool, error
 		return true, nil
 	}
 	// Check if user is not current user
-	currentUsername := ctx.Value("username").(string)
-	if user.Username == currentUsername {
+	if user.ID == GetAuthInfo(ctx).GetUserID() {
 		return false, errors.New("cannot delete current user")
 	}
 	// Delete user
@@ -61,6 +64,19 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id uint) (bool, error
 	return true, nil
 }
 
+// ChangeUserRole is the resolver for the changeUserRole field.
+func (r *mutationResolver) ChangeUserRole(ctx context.Context, id uint, role model.UserRole) (bool, error) {
+	// Check if user is not current user
+	if id == GetAuthInfo(ctx).GetUserID() {
+		return false, errors.New("cannot change role of current user")
+	}
+	err := core.ChangeUserRole(ctx, r.ServiceManager.DbClient, id, core.UserRole(role))
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // ChangePassword is the resolver for the changePassword field.
 func (r *mutationResolver) ChangePassword(ctx context.Context, input *model.PasswordUpdateInput) (bool, error) {
 	// Validate input
@@ -71,8 +87,11 @@ func (r *mutationResolver) ChangePassword(ctx context.Context, input *model.Pass
 		return false, errors.New("new password cannot be empty")
 	}
 	// Change password
-	username := ctx.Value("username").(string)
-	err := core.ChangePassword(ctx, r.ServiceManager.DbClient, username, input.OldPassword, input.NewPassword)
+	user, err := GetAuthInfo(ctx).GetUser()
+	if err != nil {
+		return false, err
+	}
+	err = core.ChangePassword(ctx, r.ServiceManager.DbClient, user.Username, input.OldPassword, input.NewPassword)
 	if err != nil {
 		return false, err
 	}
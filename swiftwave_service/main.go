@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"github.com/fatih/color"
 	"github.com/swiftwave-org/swiftwave/pkg/ssh_toolkit"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/analytics"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/dashboard"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/metrics"
 	custom_middleware "github.com/swiftwave-org/swiftwave/swiftwave_service/middleware"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/wakeup"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/webhook"
 	"log"
 	"net/http"
 
@@ -108,6 +112,40 @@ func StartServer(config *config.Config, manager *service_manager.ServiceManager,
 	}
 	graphqlServer.Initialize()
 
+	// Webhook Server
+	webhookServer := webhook.Server{
+		EchoServer:     echoServer,
+		Config:         config,
+		ServiceManager: manager,
+		WorkerManager:  workerManager,
+	}
+	webhookServer.Initialize()
+
+	// Analytics ingestion server
+	analyticsServer := analytics.Server{
+		EchoServer:     echoServer,
+		Config:         config,
+		ServiceManager: manager,
+	}
+	analyticsServer.Initialize()
+
+	// Prometheus metrics exporter
+	metricsServer := metrics.Server{
+		EchoServer:     echoServer,
+		Config:         config,
+		ServiceManager: manager,
+	}
+	metricsServer.Initialize()
+
+	// Wake-up handler for sleeping applications
+	wakeUpServer := wakeup.Server{
+		EchoServer:     echoServer,
+		Config:         config,
+		ServiceManager: manager,
+		WorkerManager:  workerManager,
+	}
+	wakeUpServer.Initialize()
+
 	// Start the server
 	address := fmt.Sprintf("%s:%d", config.LocalConfig.ServiceConfig.BindAddress, config.LocalConfig.ServiceConfig.BindPort)
 	if config.LocalConfig.ServiceConfig.UseTLS {
@@ -2,11 +2,14 @@ package manager
 
 import (
 	"context"
+	"errors"
 	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
 	"github.com/swiftwave-org/swiftwave/pkg/ssh_toolkit"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"gorm.io/gorm"
 	"net"
+	"strings"
 )
 
 func HAProxyClient(_ context.Context, server core.Server) (*haproxymanager.Manager, error) {
@@ -40,3 +43,68 @@ func HAProxyClients(ctx context.Context, servers []core.Server) ([]*haproxymanag
 	}
 	return managers, nil
 }
+
+// RunActionsInAllHAProxyNodes : run the action in all the active haproxy nodes, changes are committed only if it succeeds in all the nodes
+func RunActionsInAllHAProxyNodes(ctx context.Context, db gorm.DB, action func(transactionId string, haproxyManager *haproxymanager.Manager) error) error {
+	// fetch all proxy servers
+	proxyServers, err := core.FetchProxyActiveServers(&db)
+	if err != nil {
+		return err
+	}
+	// don't attempt if no proxy servers are active
+	if len(proxyServers) == 0 {
+		return errors.New("no proxy servers are active")
+	}
+	// fetch all haproxy managers
+	var haproxyManagers []*haproxymanager.Manager
+	haproxyManagers, err = HAProxyClients(ctx, proxyServers)
+	if err != nil {
+		return err
+	}
+	// map of server ip and transaction id
+	transactionIdMap := make(map[*haproxymanager.Manager]string)
+	var isFailed bool
+
+	errString := ""
+
+	for _, haproxyManager := range haproxyManagers {
+		// create new haproxy transaction
+		haproxyTransactionId, err := haproxyManager.FetchNewTransactionId()
+		if err != nil {
+			isFailed = true
+			break
+		}
+		// add to map
+		transactionIdMap[haproxyManager] = haproxyTransactionId
+		// run the action
+		err = action(haproxyTransactionId, haproxyManager)
+		if err != nil {
+			errString += err.Error() + "\n"
+			isFailed = true
+			break
+		}
+	}
+
+	for haproxyManager, haproxyTransactionId := range transactionIdMap {
+		var err error
+		if !isFailed {
+			err = haproxyManager.CommitTransaction(haproxyTransactionId)
+		}
+		if isFailed || err != nil {
+			isFailed = true
+			err2 := haproxyManager.DeleteTransaction(haproxyTransactionId)
+			if err2 != nil {
+				errString += err2.Error() + "\n"
+			}
+		}
+	}
+
+	if isFailed {
+		if strings.Compare(errString, "") != 0 {
+			return errors.New("failed to run actions in all haproxy nodes: " + errString)
+		}
+		return errors.New("failed to run actions in all haproxy nodes due to unknown error")
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func newGauge(name string, help string) *metricFamily {
+	return &metricFamily{
+		name:    name,
+		help:    help,
+		kind:    gaugeMetric,
+		samples: make([]metricSample, 0),
+	}
+}
+
+func (m *metricFamily) add(value float64, labels map[string]string) {
+	m.samples = append(m.samples, metricSample{
+		labels: labels,
+		value:  value,
+	})
+}
+
+// writeMetricFamilies : write the metric families in prometheus text exposition format (version 0.0.4)
+func writeMetricFamilies(w io.Writer, families []*metricFamily) error {
+	var builder strings.Builder
+	for _, family := range families {
+		builder.WriteString("# HELP ")
+		builder.WriteString(family.name)
+		builder.WriteString(" ")
+		builder.WriteString(helpEscaper.Replace(family.help))
+		builder.WriteString("\n# TYPE ")
+		builder.WriteString(family.name)
+		builder.WriteString(" ")
+		builder.WriteString(string(family.kind))
+		builder.WriteString("\n")
+		for _, sample := range family.samples {
+			builder.WriteString(family.name)
+			writeLabels(&builder, sample.labels)
+			builder.WriteString(" ")
+			builder.WriteString(strconv.FormatFloat(sample.value, 'g', -1, 64))
+			builder.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// writeLabels : labels are sorted by name to keep the output stable
+func writeLabels(builder *strings.Builder, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	builder.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(name)
+		builder.WriteString(`="`)
+		builder.WriteString(labelValueEscaper.Replace(labels[name]))
+		builder.WriteString(`"`)
+	}
+	builder.WriteString("}")
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMetricFamilies(t *testing.T) {
+	t.Run("writes help, type and samples", func(t *testing.T) {
+		gauge := newGauge("swiftwave_deployments", "No of deployments by status")
+		gauge.add(2, map[string]string{"status": "deployed"})
+		gauge.add(0.5, nil)
+		var out strings.Builder
+		err := writeMetricFamilies(&out, []*metricFamily{gauge})
+		assert.NoError(t, err)
+		assert.Equal(t, "# HELP swiftwave_deployments No of deployments by status\n"+
+			"# TYPE swiftwave_deployments gauge\n"+
+			"swiftwave_deployments{status=\"deployed\"} 2\n"+
+			"swiftwave_deployments 0.5\n", out.String())
+	})
+	t.Run("sorts and escapes labels", func(t *testing.T) {
+		gauge := newGauge("test_metric", "help with \\ and\nnewline")
+		gauge.add(1, map[string]string{"b": "quote \"x\"", "a": "back\\slash\nline"})
+		var out strings.Builder
+		err := writeMetricFamilies(&out, []*metricFamily{gauge})
+		assert.NoError(t, err)
+		assert.Equal(t, "# HELP test_metric help with \\\\ and\\nnewline\n"+
+			"# TYPE test_metric gauge\n"+
+			"test_metric{a=\"back\\\\slash\\nline\",b=\"quote \\\"x\\\"\"} 1\n", out.String())
+	})
+}
@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/worker"
+)
+
+const expositionContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Initialize : Initialize the server and its routes
+func (server *Server) Initialize() {
+	server.EchoServer.GET("/metrics", server.exportMetrics)
+}
+
+// Handler to export the metrics in prometheus text exposition format
+// Request should be authenticated with the metrics token of local config
+// Authorization: Bearer <token>
+func (server *Server) exportMetrics(c echo.Context) error {
+	if !server.isAuthorized(c.Request().Header.Get(echo.HeaderAuthorization)) {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+		return c.String(http.StatusUnauthorized, "Invalid metrics token")
+	}
+	families, err := server.collectMetrics(c.Request().Context())
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to collect metrics", err.Error())
+		return c.String(http.StatusInternalServerError, "Failed to collect metrics")
+	}
+	c.Response().Header().Set(echo.HeaderContentType, expositionContentType)
+	c.Response().WriteHeader(http.StatusOK)
+	return writeMetricFamilies(c.Response(), families)
+}
+
+func (server *Server) isAuthorized(header string) bool {
+	expectedToken := server.Config.LocalConfig.ServiceConfig.MetricsToken
+	if expectedToken == "" {
+		return false
+	}
+	token, found := strings.CutPrefix(strings.TrimSpace(header), "Bearer ")
+	if !found {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(expectedToken)) == 1
+}
+
+func (server *Server) collectMetrics(ctx context.Context) ([]*metricFamily, error) {
+	db := server.ServiceManager.DbClient
+	families := make([]*metricFamily, 0)
+	// task queue
+	queueDepth := newGauge("swiftwave_task_queue_depth", "No of pending tasks in the queue")
+	for _, queueName := range worker.Queues() {
+		messages, err := server.ServiceManager.TaskQueueClient.ListMessages(queueName)
+		if err != nil {
+			return nil, err
+		}
+		queueDepth.add(float64(len(messages)), map[string]string{"queue": queueName})
+	}
+	families = append(families, queueDepth)
+	// deployments
+	deploymentCounts, err := core.CountDeploymentsByStatus(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	deployments := newGauge("swiftwave_deployments", "No of deployments by status")
+	addStatusCounts(deployments, deploymentCounts, []string{
+		string(core.DeploymentStatusPending),
+		string(core.DeploymentStatusDeployPending),
+		string(core.DeploymentStatusDeployed),
+		string(core.DeploymentStatusStopped),
+		string(core.DeploymentStatusFailed),
+		string(core.DeploymentStalled),
+		string(core.DeploymentStatusCanary),
+	})
+	families = append(families, deployments)
+	// ingress rules
+	ingressRuleCounts, err := core.CountIngressRulesByStatus(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	ingressRules := newGauge("swiftwave_ingress_rules", "No of ingress rules by status")
+	addStatusCounts(ingressRules, ingressRuleCounts, []string{
+		string(core.IngressRuleStatusPending),
+		string(core.IngressRuleStatusApplied),
+		string(core.IngressRuleStatusFailed),
+		string(core.IngressRuleStatusDeleting),
+	})
+	families = append(families, ingressRules)
+	// servers
+	servers, err := core.FetchAllServers(&db)
+	if err != nil {
+		return nil, err
+	}
+	serverStatus := newGauge("swiftwave_server_status", "Current status of the server, 1 for the active status")
+	serverLastPing := newGauge("swiftwave_server_last_ping_timestamp_seconds", "Unix timestamp of the last ping from the server")
+	serverCpu := newGauge("swiftwave_server_cpu_usage_percent", "CPU usage of the server in the latest resource stats")
+	serverMemoryTotal := newGauge("swiftwave_server_memory_total_bytes", "Total memory of the server in the latest resource stats")
+	serverMemoryUsed := newGauge("swiftwave_server_memory_used_bytes", "Used memory of the server in the latest resource stats")
+	serverNetworkSent := newGauge("swiftwave_server_network_sent_bytes_per_second", "Outgoing network traffic of the server in the latest resource stats")
+	serverNetworkRecv := newGauge("swiftwave_server_network_received_bytes_per_second", "Incoming network traffic of the server in the latest resource stats")
+	serverStatsTimestamp := newGauge("swiftwave_server_resource_stats_timestamp_seconds", "Unix timestamp of the latest resource stats of the server")
+	for _, s := range servers {
+		for _, status := range []core.ServerStatus{core.ServerNeedsSetup, core.ServerPreparing, core.ServerOnline, core.ServerOffline} {
+			value := 0.0
+			if s.Status == status {
+				value = 1
+			}
+			serverStatus.add(value, map[string]string{"hostname": s.HostName, "status": string(status)})
+		}
+		if !s.LastPing.IsZero() {
+			serverLastPing.add(float64(s.LastPing.Unix()), map[string]string{"hostname": s.HostName})
+		}
+		stat, err := core.FetchLatestServerResourceAnalytics(ctx, db, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		// no stats reported yet
+		if stat.ID == 0 {
+			continue
+		}
+		labels := map[string]string{"hostname": s.HostName}
+		serverCpu.add(float64(stat.CpuUsagePercent), labels)
+		serverMemoryTotal.add(gbToBytes(stat.MemStat.TotalGB), labels)
+		serverMemoryUsed.add(gbToBytes(stat.MemStat.UsedGB), labels)
+		serverNetworkSent.add(float64(stat.NetStat.SentKBPS*1024), labels)
+		serverNetworkRecv.add(float64(stat.NetStat.RecvKBPS*1024), labels)
+		serverStatsTimestamp.add(float64(stat.RecordedAt.Unix()), labels)
+	}
+	families = append(families, serverStatus, serverLastPing, serverCpu, serverMemoryTotal, serverMemoryUsed, serverNetworkSent, serverNetworkRecv, serverStatsTimestamp)
+	// domains
+	domains, err := core.FetchDomainsWithIssuedSSL(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	sslExpiry := newGauge("swiftwave_domain_ssl_expiry_timestamp_seconds", "Unix timestamp of the expiry of the issued ssl certificate")
+	for _, domain := range domains {
+		sslExpiry.add(float64(domain.SSLExpiredAt.Unix()), map[string]string{"domain": domain.Name})
+	}
+	families = append(families, sslExpiry)
+	// applications
+	applications, err := core.FindAllApplications(ctx, db, true)
+	if err != nil {
+		return nil, err
+	}
+	applicationNames := make(map[string]string)
+	for _, application := range applications {
+		applicationNames[application.ID] = application.Name
+	}
+	appStats, err := core.FetchLatestApplicationServiceResourceStats(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	appCpu := newGauge("swiftwave_application_cpu_usage_percent", "CPU usage of the application in the latest resource stats")
+	appMemoryUsed := newGauge("swiftwave_application_memory_used_bytes", "Used memory of the application in the latest resource stats")
+	appNetworkSent := newGauge("swiftwave_application_network_sent_bytes_per_second", "Outgoing network traffic of the application in the latest resource stats")
+	appNetworkRecv := newGauge("swiftwave_application_network_received_bytes_per_second", "Incoming network traffic of the application in the latest resource stats")
+	appStatsTimestamp := newGauge("swiftwave_application_resource_stats_timestamp_seconds", "Unix timestamp of the latest resource stats of the application")
+	for _, stat := range appStats {
+		applicationName, ok := applicationNames[stat.ApplicationID]
+		if !ok {
+			continue
+		}
+		labels := map[string]string{"application": applicationName}
+		appCpu.add(float64(stat.CpuUsagePercent), labels)
+		appMemoryUsed.add(float64(stat.UsedMemoryMB*1024*1024), labels)
+		appNetworkSent.add(float64(stat.NetStat.SentKBPS*1024), labels)
+		appNetworkRecv.add(float64(stat.NetStat.RecvKBPS*1024), labels)
+		appStatsTimestamp.add(float64(stat.RecordedAt.Unix()), labels)
+	}
+	families = append(families, appCpu, appMemoryUsed, appNetworkSent, appNetworkRecv, appStatsTimestamp)
+	return families, nil
+}
+
+// addStatusCounts : add a sample for each known status, so that the series do not disappear when count drops to zero
+func addStatusCounts(gauge *metricFamily, counts []core.StatusCount, knownStatuses []string) {
+	values := make(map[string]int64)
+	for _, status := range knownStatuses {
+		values[status] = 0
+	}
+	statuses := append([]string{}, knownStatuses...)
+	for _, count := range counts {
+		if _, ok := values[count.Status]; !ok {
+			statuses = append(statuses, count.Status)
+		}
+	}
+	for _, count := range counts {
+		values[count.Status] += count.Count
+	}
+	for _, status := range statuses {
+		gauge.add(float64(values[status]), map[string]string{"status": status})
+	}
+}
+
+func gbToBytes(value float32) float64 {
+	return math.Round(float64(value) * 1024 * 1024 * 1024)
+}
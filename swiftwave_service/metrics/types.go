@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+)
+
+// Server : hold references to other components of service
+type Server struct {
+	EchoServer     *echo.Echo
+	Config         *config.Config
+	ServiceManager *service_manager.ServiceManager
+}
+
+// metricType : type of the metric family in prometheus text exposition format
+type metricType string
+
+const (
+	gaugeMetric metricType = "gauge"
+)
+
+// metricFamily : metric with its samples
+type metricFamily struct {
+	name    string
+	help    string
+	kind    metricType
+	samples []metricSample
+}
+
+// metricSample : single value of a metric family
+type metricSample struct {
+	labels map[string]string
+	value  float64
+}
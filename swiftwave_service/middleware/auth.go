@@ -40,6 +40,15 @@ func (a AuthInfo) GetUser() (core.User, error) {
 	return user, nil
 }
 
+// HasRole : check if the authenticated user is allowed to perform operations that require the given role
+func (a AuthInfo) HasRole(role core.UserRole) (bool, error) {
+	user, err := a.GetUser()
+	if err != nil {
+		return false, err
+	}
+	return user.Role.HasPermission(role), nil
+}
+
 // AuthResolverMiddleware will add reference of auth info
 // It will allow unauthenticated access as well
 // Handler should verify requests
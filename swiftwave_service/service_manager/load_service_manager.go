@@ -2,6 +2,7 @@ package service_manager
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/nats-io/nats.go"
 	dockerConfigGenerator "github.com/swiftwave-org/swiftwave/pkg/docker_config_generator"
 	"github.com/swiftwave-org/swiftwave/pkg/pubsub"
 	ssl "github.com/swiftwave-org/swiftwave/pkg/ssl_manager"
@@ -31,6 +33,10 @@ func (manager *ServiceManager) Load(config config.Config) {
 		IsStaging:         config.SystemConfig.LetsEncryptConfig.Staging,
 		Email:             config.SystemConfig.LetsEncryptConfig.EmailID,
 		AccountPrivateKey: config.SystemConfig.LetsEncryptConfig.PrivateKey,
+		DirectoryURL:      config.SystemConfig.LetsEncryptConfig.DirectoryURL,
+		EABKeyID:          config.SystemConfig.LetsEncryptConfig.EABKeyID,
+		EABHMACKey:        config.SystemConfig.LetsEncryptConfig.EABHMACKey,
+		CACertificate:     config.SystemConfig.LetsEncryptConfig.CACertificate,
 	}
 	sslManager := ssl.Manager{}
 	err = sslManager.Init(context.Background(), *dbClient, options)
@@ -82,12 +88,43 @@ func (manager *ServiceManager) Load(config config.Config) {
 			panic(err)
 		}
 		manager.PubSubClient = pubSubClient
+	} else if config.SystemConfig.PubSubConfig.Mode == system_config.NatsPubSub {
+		natsConnection, err := connectToNats(config.SystemConfig.PubSubConfig.NATSConfig)
+		if err != nil {
+			logger.InternalLogger.Println("Failed to connect to NATS server for PubSub")
+			logger.InternalLoggerError.Println(err)
+			panic(err)
+		}
+		pubSubClient, err := pubsub.NewClient(pubsub.Options{
+			Type:              pubsub.Nats,
+			BufferLength:      int(config.SystemConfig.PubSubConfig.BufferLength),
+			NatsConnection:    natsConnection,
+			TopicsChannelName: "swiftwave_pubsub_topics",
+			EventsChannelName: "swiftwave.pubsub.events",
+		})
+		if err != nil {
+			logger.InternalLogger.Println("Failed to initiate PubSub Client")
+			logger.InternalLoggerError.Println(err)
+			panic(err)
+		}
+		manager.PubSubClient = pubSubClient
 	} else {
 		panic("Invalid PubSub Mode in config")
 	}
 
 	// Create TaskQueue client
-	taskQueueClient, err := FetchTaskQueueClient(&config, dbClient)
+	// publish the task runs for live progress
+	pubSubClient := manager.PubSubClient
+	taskQueueClient, err := FetchTaskQueueClient(&config, dbClient, func(run task_queue.TaskRun) {
+		data, err := json.Marshal(run)
+		if err != nil {
+			return
+		}
+		err = pubSubClient.Publish(TaskRunsTopic, string(data))
+		if err != nil {
+			logger.InternalLoggerError.Println("Failed to publish task run", err)
+		}
+	})
 	if err != nil {
 		logger.InternalLoggerError.Println("Failed to initiate TaskQueue Client\n", err)
 		panic(err)
@@ -95,13 +132,15 @@ func (manager *ServiceManager) Load(config config.Config) {
 	manager.TaskQueueClient = taskQueueClient
 }
 
-func FetchTaskQueueClient(c *config.Config, db *gorm.DB) (task_queue.Client, error) {
+// FetchTaskQueueClient : onTaskRunUpdate is called on every change of a task run, can be nil
+func FetchTaskQueueClient(c *config.Config, db *gorm.DB, onTaskRunUpdate func(run task_queue.TaskRun)) (task_queue.Client, error) {
 	if c.SystemConfig.TaskQueueConfig.Mode == system_config.LocalTaskQueue {
 		taskQueueClient, err := task_queue.NewClient(task_queue.Options{
 			Type:                task_queue.Local,
 			MaxMessagesPerQueue: int(c.SystemConfig.TaskQueueConfig.MaxOutstandingMessagesPerQueue),
 			NoOfWorkersPerQueue: int(c.SystemConfig.TaskQueueConfig.NoOfWorkersPerQueue),
 			DbClient:            db,
+			OnTaskRunUpdate:     onTaskRunUpdate,
 		})
 		return taskQueueClient, err
 	} else if c.SystemConfig.TaskQueueConfig.Mode == system_config.RemoteTaskQueue {
@@ -117,6 +156,13 @@ func FetchTaskQueueClient(c *config.Config, db *gorm.DB) (task_queue.Client, err
 				DB:       int(c.SystemConfig.TaskQueueConfig.RedisConfig.DatabaseID),
 			})
 		}
+		var natsConnection *nats.Conn
+		if c.SystemConfig.TaskQueueConfig.RemoteTaskQueueType == system_config.NatsQueue {
+			natsConnection, err = connectToNats(c.SystemConfig.TaskQueueConfig.NATSConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
 		taskQueueClient, err := task_queue.NewClient(task_queue.Options{
 			Type:                task_queue.Remote,
 			RemoteQueueType:     task_queue.RemoteQueueType(c.SystemConfig.TaskQueueConfig.RemoteTaskQueueType),
@@ -126,6 +172,9 @@ func FetchTaskQueueClient(c *config.Config, db *gorm.DB) (task_queue.Client, err
 			AMQPVhost:           c.SystemConfig.TaskQueueConfig.AMQPConfig.VHost,
 			AMQPClientName:      hostname,
 			RedisClient:         redisClient,
+			NatsConnection:      natsConnection,
+			DbClient:            db,
+			OnTaskRunUpdate:     onTaskRunUpdate,
 		})
 		if err != nil {
 			return nil, err
@@ -135,3 +184,14 @@ func FetchTaskQueueClient(c *config.Config, db *gorm.DB) (task_queue.Client, err
 		return nil, errors.New("invalid TaskQueue Mode in config")
 	}
 }
+
+func connectToNats(c system_config.NATSConfig) (*nats.Conn, error) {
+	options := []nats.Option{nats.MaxReconnects(-1)}
+	if hostname, err := os.Hostname(); err == nil {
+		options = append(options, nats.Name(hostname))
+	}
+	if c.Username != "" {
+		options = append(options, nats.UserInfo(c.Username, c.Password))
+	}
+	return nats.Connect(c.URL, options...)
+}
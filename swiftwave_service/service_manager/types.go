@@ -19,3 +19,6 @@ type ServiceManager struct {
 	TaskQueueRedisClient  redis.Client
 	CancelImageBuildTopic string
 }
+
+// TaskRunsTopic : pubsub topic of the task runs, a message is published when a task starts running and when it finishes
+const TaskRunsTopic = "task_runs"
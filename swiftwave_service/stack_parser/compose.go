@@ -0,0 +1,428 @@
+package stack_parser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// This file contains the conversion of docker compose specific keys to the swiftwave stack format
+
+var supportedStackKeys = map[string]bool{
+	"minimum_swiftwave_version": true,
+	"services":                  true,
+	"docs":                      true,
+	"secrets":                   true,
+	// volumes should exist in swiftwave, those are verified before deploying the stack
+	"volumes": true,
+	// obsolete in compose
+	"version": true,
+	"name":    true,
+}
+
+var supportedServiceKeys = map[string]bool{
+	"image":                      true,
+	"deploy":                     true,
+	"volumes":                    true,
+	"environment":                true,
+	"cap_add":                    true,
+	"sysctls":                    true,
+	"hostname":                   true,
+	"command":                    true,
+	"configs":                    true,
+	"custom_health_check":        true,
+	"preferred_server_hostnames": true,
+	"docker_proxy_config":        true,
+	"ports":                      true,
+	"depends_on":                 true,
+	"healthcheck":                true,
+	"secrets":                    true,
+	"secret_environment":         true,
+}
+
+var supportedDeployKeys = map[string]bool{
+	"mode":      true,
+	"replicas":  true,
+	"resources": true,
+}
+
+var supportedResourceKeys = map[string]map[string]bool{
+	"limits": {
+		"memory": true,
+		"cpus":   true,
+		"pids":   true,
+	},
+	"reservations": {
+		"memory": true,
+		"cpus":   true,
+	},
+}
+
+// unsupportedKeyReasons : reason for the commonly used keys of compose which are not supported
+var unsupportedKeyReasons = map[string]string{
+	"labels":         "labels are not supported",
+	"env_file":       "env_file is not supported, move the variables to environment",
+	"networks":       "networks are ignored, all the applications are attached to swiftwave network",
+	"build":          "build is not supported, use a pre-built image",
+	"container_name": "container_name is ignored, service name is used as application name",
+	"restart":        "restart is ignored, applications are always restarted on failure",
+	"expose":         "expose is ignored, applications can reach each other on any port",
+}
+
+var memoryRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([bkmg]?)i?b?$`)
+
+// unsupportedKeyWarnings : warnings for the keys of stack which are ignored while parsing
+func unsupportedKeyWarnings(yamlStr string) ([]string, error) {
+	raw := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(yamlStr), &raw)
+	if err != nil {
+		return nil, err
+	}
+	warnings := make([]string, 0)
+	for key := range raw {
+		if !supportedStackKeys[key] {
+			warnings = append(warnings, unsupportedKeyWarning("", key))
+		}
+	}
+	services, _ := raw["services"].(map[string]interface{})
+	for serviceName, service := range services {
+		serviceMap, _ := service.(map[string]interface{})
+		for key := range serviceMap {
+			if !supportedServiceKeys[key] {
+				warnings = append(warnings, unsupportedKeyWarning(serviceName, key))
+			}
+		}
+		deploy, _ := serviceMap["deploy"].(map[string]interface{})
+		for key := range deploy {
+			if !supportedDeployKeys[key] {
+				warnings = append(warnings, unsupportedKeyWarning(serviceName, "deploy."+key))
+			}
+		}
+		resources, _ := deploy["resources"].(map[string]interface{})
+		for resourceKey, supportedKeys := range supportedResourceKeys {
+			resource, _ := resources[resourceKey].(map[string]interface{})
+			for key := range resource {
+				if !supportedKeys[key] {
+					warnings = append(warnings, unsupportedKeyWarning(serviceName, "deploy.resources."+resourceKey+"."+key))
+				}
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
+func unsupportedKeyWarning(serviceName string, key string) string {
+	keyParts := strings.Split(key, ".")
+	reason, ok := unsupportedKeyReasons[keyParts[len(keyParts)-1]]
+	if !ok {
+		reason = fmt.Sprintf("%s is not supported and ignored", key)
+	}
+	if serviceName == "" {
+		return reason
+	}
+	return fmt.Sprintf("service %s > %s", serviceName, reason)
+}
+
+// convertComposeKeys : convert healthcheck, secrets and bind mounts of compose to the swiftwave stack format
+// Service names are not prefixed with STACK_NAME yet
+func (s *Stack) convertComposeKeys() error {
+	for serviceName, service := range s.Services {
+		// healthcheck
+		if service.HealthCheck != nil {
+			if service.CustomHealthCheck.Enabled {
+				s.Warnings = append(s.Warnings, fmt.Sprintf("service %s > healthcheck is ignored, custom_health_check is used instead", serviceName))
+			} else {
+				customHealthCheck, err := service.HealthCheck.toCustomHealthCheck()
+				if err != nil {
+					return fmt.Errorf("service %s > %s", serviceName, err.Error())
+				}
+				service.CustomHealthCheck = customHealthCheck
+			}
+			service.HealthCheck = nil
+		}
+		// secrets
+		for _, secret := range service.Secrets {
+			stackSecret, ok := s.Secrets[secret.Source]
+			if !ok {
+				return fmt.Errorf("service %s > secret %s is not defined", serviceName, secret.Source)
+			}
+			if stackSecret.Environment == "" {
+				s.Warnings = append(s.Warnings, fmt.Sprintf("service %s > secret %s is ignored, only secrets with environment source are supported", serviceName, secret.Source))
+				continue
+			}
+			if service.SecretEnvironment == nil {
+				service.SecretEnvironment = make(map[string]string)
+			}
+			service.SecretEnvironment[secret.Name()] = fmt.Sprintf("{{%s}}", stackSecret.Environment)
+		}
+		service.Secrets = nil
+		// bind mounts
+		volumes := make([]Volume, 0)
+		for _, volume := range service.Volumes {
+			if volume.isNamedVolume() {
+				volumes = append(volumes, volume)
+			} else {
+				s.Warnings = append(s.Warnings, fmt.Sprintf("service %s > bind mount %s is ignored, only named volumes are supported", serviceName, volume.Name))
+			}
+		}
+		service.Volumes = volumes
+		// ports
+		for _, port := range service.Ports {
+			if port.Domain != "" && port.Protocol != "tcp" {
+				return fmt.Errorf("service %s > port %d with domain should use tcp protocol", serviceName, port.Published)
+			}
+		}
+		// depends on
+		for _, dependency := range service.DependsOn {
+			if _, ok := s.Services[dependency]; !ok {
+				return fmt.Errorf("service %s > depends on unknown service %s", serviceName, dependency)
+			}
+		}
+		s.Services[serviceName] = service
+	}
+	s.Secrets = nil
+	_, err := s.ServiceNamesInDeployOrder()
+	return err
+}
+
+// ServiceNamesInDeployOrder : service names ordered by depends_on, the dependencies come first
+func (s *Stack) ServiceNamesInDeployOrder() ([]string, error) {
+	serviceNames := s.ServiceNames()
+	sort.Strings(serviceNames)
+	orderedServiceNames := make([]string, 0, len(serviceNames))
+	// 0 - not visited, 1 - visiting, 2 - visited
+	state := make(map[string]int)
+	var visit func(serviceName string) error
+	visit = func(serviceName string) error {
+		switch state[serviceName] {
+		case 1:
+			return fmt.Errorf("circular dependency found in depends_on of service %s", serviceName)
+		case 2:
+			return nil
+		}
+		state[serviceName] = 1
+		for _, dependency := range s.Services[serviceName].DependsOn {
+			if _, ok := s.Services[dependency]; !ok {
+				continue
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[serviceName] = 2
+		orderedServiceNames = append(orderedServiceNames, serviceName)
+		return nil
+	}
+	for _, serviceName := range serviceNames {
+		if err := visit(serviceName); err != nil {
+			return nil, err
+		}
+	}
+	return orderedServiceNames, nil
+}
+
+// DomainNames : domains used in ports of the services
+func (s *Stack) DomainNames() []string {
+	domainNames := make([]string, 0)
+	for _, service := range s.Services {
+		for _, port := range service.Ports {
+			if port.Domain != "" {
+				domainNames = append(domainNames, port.Domain)
+			}
+		}
+	}
+	return domainNames
+}
+
+func (h *HealthCheck) toCustomHealthCheck() (CustomHealthCheck, error) {
+	customHealthCheck := CustomHealthCheck{
+		Enabled: !h.Disable,
+		Retries: h.Retries,
+	}
+	if len(h.Test) > 0 {
+		switch h.Test[0] {
+		case "NONE":
+			customHealthCheck.Enabled = false
+		case "CMD":
+			arguments := make([]string, 0)
+			for _, argument := range h.Test[1:] {
+				if strings.ContainsAny(argument, " \t'\"") {
+					argument = "'" + strings.ReplaceAll(argument, "'", `'"'"'`) + "'"
+				}
+				arguments = append(arguments, argument)
+			}
+			customHealthCheck.TestCommand = strings.Join(arguments, " ")
+		case "CMD-SHELL":
+			customHealthCheck.TestCommand = strings.Join(h.Test[1:], " ")
+		default:
+			customHealthCheck.TestCommand = h.Test.String()
+		}
+	}
+	if customHealthCheck.Enabled && strings.TrimSpace(customHealthCheck.TestCommand) == "" {
+		return CustomHealthCheck{}, errors.New("test command of healthcheck is required")
+	}
+	var err error
+	if customHealthCheck.IntervalSeconds, err = parseDurationSeconds(h.Interval); err != nil {
+		return CustomHealthCheck{}, err
+	}
+	if customHealthCheck.TimeoutSeconds, err = parseDurationSeconds(h.Timeout); err != nil {
+		return CustomHealthCheck{}, err
+	}
+	if customHealthCheck.StartPeriodSeconds, err = parseDurationSeconds(h.StartPeriod); err != nil {
+		return CustomHealthCheck{}, err
+	}
+	if customHealthCheck.StartIntervalSeconds, err = parseDurationSeconds(h.StartInterval); err != nil {
+		return CustomHealthCheck{}, err
+	}
+	return customHealthCheck, nil
+}
+
+// parseDurationSeconds : parse duration of compose (e.g. 1m30s), blank is considered as 0
+func parseDurationSeconds(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %s", value)
+	}
+	return uint64(math.Ceil(duration.Seconds())), nil
+}
+
+// parseMemoryMB : parse memory in MB, supports plain number (in MB) and byte values of compose (e.g. 512m, 1gb)
+func parseMemoryMB(value interface{}) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+	if memoryMB, ok := value.(int); ok {
+		return memoryMB, nil
+	}
+	memory := strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", value)))
+	if memory == "" {
+		return 0, nil
+	}
+	matches := memoryRegex.FindStringSubmatch(memory)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid memory %s", memory)
+	}
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory %s", memory)
+	}
+	switch matches[2] {
+	case "b":
+		number = number / (1024 * 1024)
+	case "k":
+		number = number / 1024
+	case "g":
+		number = number * 1024
+	}
+	return int(math.Ceil(number)), nil
+}
+
+// parseNanoCPUs : parse cpus of compose (e.g. 0.5) to units of 10^-9 CPUs
+func parseNanoCPUs(value interface{}) (int64, error) {
+	if value == nil {
+		return 0, nil
+	}
+	cpus := strings.TrimSpace(fmt.Sprintf("%v", value))
+	if cpus == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(cpus, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid cpus %s", cpus)
+	}
+	return int64(math.Round(number * 1e9)), nil
+}
+
+func formatNanoCPUs(nanoCPUs int64) string {
+	if nanoCPUs == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(nanoCPUs)/1e9, 'f', -1, 64)
+}
+
+func parseShortSyntaxPort(value string) (Port, error) {
+	port := Port{
+		Protocol: "tcp",
+	}
+	value = strings.TrimSpace(value)
+	if protocolSplit := strings.SplitN(value, "/", 2); len(protocolSplit) == 2 {
+		value = protocolSplit[0]
+		port.Protocol = strings.ToLower(strings.TrimSpace(protocolSplit[1]))
+	}
+	parts := strings.Split(value, ":")
+	var err error
+	switch len(parts) {
+	case 1:
+		port.Target, err = parsePortNumber(parts[0])
+		port.Published = port.Target
+	case 2, 3:
+		if len(parts) == 3 && net.ParseIP(strings.TrimSpace(parts[0])) == nil {
+			port.Domain = strings.TrimSpace(parts[0])
+		}
+		port.Published, err = parsePortNumber(parts[len(parts)-2])
+		if err == nil {
+			port.Target, err = parsePortNumber(parts[len(parts)-1])
+		}
+	default:
+		err = fmt.Errorf("invalid port %s", value)
+	}
+	if err != nil {
+		return Port{}, err
+	}
+	return port, validatePort(port)
+}
+
+func parseLongSyntaxPort(value map[string]interface{}) (Port, error) {
+	port := Port{
+		Protocol: "tcp",
+	}
+	var err error
+	if port.Target, err = parsePortNumber(fmt.Sprintf("%v", value["target"])); err != nil {
+		return Port{}, err
+	}
+	port.Published = port.Target
+	if published, ok := value["published"]; ok {
+		if port.Published, err = parsePortNumber(fmt.Sprintf("%v", published)); err != nil {
+			return Port{}, err
+		}
+	}
+	if protocol, ok := value["protocol"].(string); ok {
+		port.Protocol = strings.ToLower(strings.TrimSpace(protocol))
+	}
+	if domain, ok := value["domain"].(string); ok {
+		port.Domain = strings.TrimSpace(domain)
+	}
+	return port, validatePort(port)
+}
+
+func parsePortNumber(value string) (uint, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "-") {
+		return 0, fmt.Errorf("port range %s is not supported", value)
+	}
+	number, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || number == 0 {
+		return 0, fmt.Errorf("invalid port %s", value)
+	}
+	return uint(number), nil
+}
+
+func validatePort(port Port) error {
+	if port.Protocol != "tcp" && port.Protocol != "udp" {
+		return fmt.Errorf("invalid protocol %s of port %d", port.Protocol, port.Published)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package stack_parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const composeStack = `
+version: "3.8"
+services:
+  web:
+    image: ghost:latest
+    ports:
+      - "blog.example.com:443:2368"
+      - target: 9000
+        published: 9000
+        protocol: udp
+    depends_on:
+      db:
+        condition: service_healthy
+    labels:
+      app: blog
+    restart: always
+    deploy:
+      resources:
+        limits:
+          memory: 1g
+          cpus: "0.5"
+          pids: 100
+    secrets:
+      - source: db_password
+        target: DB_PASSWORD
+  db:
+    image: mysql:8
+    volumes:
+      - db_data:/var/lib/mysql
+      - ./init.sql:/docker-entrypoint-initdb.d/init.sql
+      - .:/app
+    healthcheck:
+      test: ["CMD", "mysqladmin", "ping"]
+      interval: 10s
+      timeout: 1m30s
+      retries: 3
+    deploy:
+      resources:
+        reservations:
+          memory: 256M
+          cpus: 2
+secrets:
+  db_password:
+    environment: DB_PASSWORD
+`
+
+func TestParseComposeStack(t *testing.T) {
+	stack, err := ParseStackYaml(composeStack, "develop")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	web := stack.Services["{{STACK_NAME}}_web"]
+	db := stack.Services["{{STACK_NAME}}_db"]
+
+	t.Run("ports are parsed", func(t *testing.T) {
+		assert.Equal(t, PortList{
+			{Published: 443, Target: 2368, Protocol: "tcp", Domain: "blog.example.com"},
+			{Published: 9000, Target: 9000, Protocol: "udp"},
+		}, web.Ports)
+		assert.Equal(t, "https", web.Ports[0].IngressProtocol())
+		assert.Equal(t, "udp", web.Ports[1].IngressProtocol())
+	})
+
+	t.Run("dependencies are deployed first", func(t *testing.T) {
+		assert.Equal(t, DependsOn{"{{STACK_NAME}}_db"}, web.DependsOn)
+		serviceNames, err := stack.ServiceNamesInDeployOrder()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"{{STACK_NAME}}_db", "{{STACK_NAME}}_web"}, serviceNames)
+	})
+
+	t.Run("healthcheck is converted", func(t *testing.T) {
+		assert.Nil(t, db.HealthCheck)
+		assert.Equal(t, CustomHealthCheck{
+			Enabled:         true,
+			TestCommand:     "mysqladmin ping",
+			IntervalSeconds: 10,
+			TimeoutSeconds:  90,
+			Retries:         3,
+		}, db.CustomHealthCheck)
+	})
+
+	t.Run("resources are converted", func(t *testing.T) {
+		assert.Equal(t, ResourcesLimits{MemoryMB: 1024, NanoCPUs: 5e8, Pids: 100}, web.Deploy.Resources.Limits)
+		assert.Equal(t, ResourcesReservations{MemoryMB: 256, NanoCPUs: 2e9}, db.Deploy.Resources.Reservations)
+		stackCopy, err := stack.deepCopy()
+		assert.NoError(t, err)
+		assert.Equal(t, web.Deploy.Resources, stackCopy.Services["{{STACK_NAME}}_web"].Deploy.Resources)
+	})
+
+	t.Run("secrets are converted", func(t *testing.T) {
+		assert.Equal(t, KeyValuePair{"DB_PASSWORD": "{{DB_PASSWORD}}"}, web.SecretEnvironment)
+		assert.Empty(t, web.Secrets)
+	})
+
+	t.Run("unsupported keys are reported", func(t *testing.T) {
+		assert.Equal(t, VolumeList{{Name: "db_data", MountingPoint: "/var/lib/mysql"}}, db.Volumes)
+		assert.Equal(t, []string{
+			"service db > bind mount . is ignored, only named volumes are supported",
+			"service db > bind mount ./init.sql is ignored, only named volumes are supported",
+			"service web > labels are not supported",
+			"service web > restart is ignored, applications are always restarted on failure",
+		}, stack.Warnings)
+	})
+}
+
+func TestParseComposeStackErrors(t *testing.T) {
+	testCases := map[string]string{
+		"port range": `
+services:
+  web:
+    image: nginx
+    ports:
+      - "8000-8010:80"
+`,
+		"unknown dependency": `
+services:
+  web:
+    image: nginx
+    depends_on:
+      - db
+`,
+		"circular dependency": `
+services:
+  web:
+    image: nginx
+    depends_on:
+      - api
+  api:
+    image: nginx
+    depends_on:
+      - web
+`,
+		"undefined secret": `
+services:
+  web:
+    image: nginx
+    secrets:
+      - password
+`,
+	}
+	for name, content := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseStackYaml(content, "develop")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseMemoryMB(t *testing.T) {
+	testCases := map[interface{}]int{
+		nil:     0,
+		512:     512,
+		"512":   512,
+		"512m":  512,
+		"512Mi": 512,
+		"2GB":   2048,
+		"1.5g":  1536,
+		"100k":  1,
+	}
+	for value, expected := range testCases {
+		memoryMB, err := parseMemoryMB(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, memoryMB, value)
+	}
+	_, err := parseMemoryMB("1t")
+	assert.Error(t, err)
+}
@@ -2,8 +2,10 @@ package stack_parser
 
 import (
 	"errors"
+	"fmt"
 	"github.com/hashicorp/go-set"
 	"reflect"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -60,7 +62,11 @@ type Volume struct {
 	MountingPoint string `yaml:"mounting_point"`
 }
 
+// isNamedVolume : sources of bind mounts are paths, like ".", "./data", "/data" or "~/data"
 func (v Volume) isNamedVolume() bool {
+	if v.Name == "" || strings.HasPrefix(v.Name, ".") || strings.HasPrefix(v.Name, "~") {
+		return false
+	}
 	return !strings.Contains(v.Name, "/")
 }
 
@@ -81,6 +87,7 @@ func (l *VolumeList) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 
 	// iterate over the elements of the list
+	// bind mounts are kept here, those are dropped with a warning while parsing the stack
 	for _, record := range raw.([]interface{}) {
 		if record != nil && reflect.TypeOf(record).Kind() == reflect.String {
 			volume := Volume{}
@@ -93,18 +100,27 @@ func (l *VolumeList) UnmarshalYAML(unmarshal func(interface{}) error) error {
 					*l = append(*l, volume)
 				}
 			}
+		} else if record != nil && reflect.TypeOf(record).Kind() == reflect.Map {
+			// long syntax of compose
+			recordMap := record.(map[string]interface{})
+			source, _ := recordMap["source"].(string)
+			target, _ := recordMap["target"].(string)
+			if strings.TrimSpace(source) == "" || strings.TrimSpace(target) == "" {
+				return errors.New("source and target are required in volume definition")
+			}
+			if volumeType, ok := recordMap["type"].(string); ok && volumeType == "bind" && !strings.Contains(source, "/") {
+				// keep it distinguishable from named volume
+				source = "./" + source
+			}
+			*l = append(*l, Volume{
+				Name:          strings.TrimSpace(source),
+				MountingPoint: strings.TrimSpace(target),
+			})
 		} else {
 			return errors.New("invalid volume definition")
 		}
 	}
 
-	// check if any volume is not named volume
-	for _, volume := range *l {
-		if !volume.isNamedVolume() {
-			return errors.New("only named volumes are supported")
-		}
-	}
-
 	return nil
 }
 
@@ -143,6 +159,10 @@ type Stack struct {
 	MinimumSwiftwaveVersion string             `yaml:"minimum_swiftwave_version"`
 	Services                map[string]Service `yaml:"services"`
 	Docs                    *Docs              `yaml:"docs"`
+	// Secrets - secrets of compose, converted to SecretEnvironment of the services while parsing
+	Secrets map[string]StackSecret `yaml:"secrets,omitempty"`
+	// Warnings - configuration of compose which is not supported and ignored while parsing
+	Warnings []string `yaml:"-"`
 }
 
 type Service struct {
@@ -158,6 +178,16 @@ type Service struct {
 	CustomHealthCheck        CustomHealthCheck `yaml:"custom_health_check"`
 	PreferredServerHostnames []string          `yaml:"preferred_server_hostnames"`
 	DockerProxyConfig        DockerProxyConfig `yaml:"docker_proxy_config"`
+	// Ports - published ports, exposed as ingress rules of the application
+	Ports PortList `yaml:"ports,omitempty"`
+	// DependsOn - services which should be deployed before this service, the build of this service waits for their deployments
+	DependsOn DependsOn `yaml:"depends_on,omitempty"`
+	// HealthCheck - healthcheck of compose, converted to CustomHealthCheck while parsing
+	HealthCheck *HealthCheck `yaml:"healthcheck,omitempty"`
+	// Secrets - secrets of compose, converted to SecretEnvironment while parsing
+	Secrets ServiceSecretList `yaml:"secrets,omitempty"`
+	// SecretEnvironment - environment variables mounted as docker secret at /run/secrets/<key>
+	SecretEnvironment KeyValuePair `yaml:"secret_environment,omitempty"`
 }
 
 // DeploymentMode mode of deployment of application (replicated or global)
@@ -183,10 +213,84 @@ type Resources struct {
 
 type ResourcesLimits struct {
 	MemoryMB int `yaml:"memory"`
+	// NanoCPUs - cpus of compose (e.g. 0.5) in units of 10^-9 CPUs
+	NanoCPUs int64 `yaml:"cpus"`
+	Pids     int64 `yaml:"pids"`
+}
+
+func (r *ResourcesLimits) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw struct {
+		Memory interface{} `yaml:"memory"`
+		CPUs   interface{} `yaml:"cpus"`
+		Pids   int64       `yaml:"pids"`
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	memoryMB, err := parseMemoryMB(raw.Memory)
+	if err != nil {
+		return err
+	}
+	nanoCPUs, err := parseNanoCPUs(raw.CPUs)
+	if err != nil {
+		return err
+	}
+	if raw.Pids < 0 {
+		return errors.New("pids limit should be positive")
+	}
+	r.MemoryMB = memoryMB
+	r.NanoCPUs = nanoCPUs
+	r.Pids = raw.Pids
+	return nil
+}
+
+func (r ResourcesLimits) MarshalYAML() (interface{}, error) {
+	return struct {
+		MemoryMB int    `yaml:"memory"`
+		CPUs     string `yaml:"cpus,omitempty"`
+		Pids     int64  `yaml:"pids,omitempty"`
+	}{
+		MemoryMB: r.MemoryMB,
+		CPUs:     formatNanoCPUs(r.NanoCPUs),
+		Pids:     r.Pids,
+	}, nil
 }
 
 type ResourcesReservations struct {
 	MemoryMB int `yaml:"memory"`
+	// NanoCPUs - cpus of compose (e.g. 0.5) in units of 10^-9 CPUs
+	NanoCPUs int64 `yaml:"cpus"`
+}
+
+func (r *ResourcesReservations) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw struct {
+		Memory interface{} `yaml:"memory"`
+		CPUs   interface{} `yaml:"cpus"`
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	memoryMB, err := parseMemoryMB(raw.Memory)
+	if err != nil {
+		return err
+	}
+	nanoCPUs, err := parseNanoCPUs(raw.CPUs)
+	if err != nil {
+		return err
+	}
+	r.MemoryMB = memoryMB
+	r.NanoCPUs = nanoCPUs
+	return nil
+}
+
+func (r ResourcesReservations) MarshalYAML() (interface{}, error) {
+	return struct {
+		MemoryMB int    `yaml:"memory"`
+		CPUs     string `yaml:"cpus,omitempty"`
+	}{
+		MemoryMB: r.MemoryMB,
+		CPUs:     formatNanoCPUs(r.NanoCPUs),
+	}, nil
 }
 
 // Config for the service
@@ -287,6 +391,148 @@ type CustomHealthCheck struct {
 	Retries              uint64 `yaml:"retries"`                // Consecutive failures needed to report unhealthy
 }
 
+// PortList List of published ports
+// Supports both short ([DOMAIN:]PUBLISHED:TARGET[/PROTOCOL]) and long syntax of compose
+// In place of host ip, a domain can be provided to expose the port as http/https ingress rule of the domain
+type PortList []Port
+
+type Port struct {
+	Published uint   `yaml:"published"`
+	Target    uint   `yaml:"target"`
+	Protocol  string `yaml:"protocol"`
+	Domain    string `yaml:"domain,omitempty"`
+}
+
+func (l *PortList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*l = make([]Port, 0)
+	if raw == nil || reflect.TypeOf(raw).Kind() != reflect.Slice {
+		return errors.New("invalid ports definition")
+	}
+	for _, record := range raw.([]interface{}) {
+		var port Port
+		var err error
+		if record != nil && reflect.TypeOf(record).Kind() == reflect.Map {
+			port, err = parseLongSyntaxPort(record.(map[string]interface{}))
+		} else {
+			port, err = parseShortSyntaxPort(fmt.Sprintf("%v", record))
+		}
+		if err != nil {
+			return err
+		}
+		*l = append(*l, port)
+	}
+	return nil
+}
+
+// IngressProtocol : protocol of the ingress rule to expose the port
+func (p Port) IngressProtocol() string {
+	if p.Domain == "" {
+		return p.Protocol
+	}
+	if p.Published == 443 {
+		return "https"
+	}
+	return "http"
+}
+
+// DependsOn List of services
+// Supports both list and map (with condition) formats of compose, conditions are ignored
+type DependsOn []string
+
+func (d *DependsOn) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*d = make([]string, 0)
+	if raw == nil {
+		return nil
+	}
+	switch reflect.TypeOf(raw).Kind() {
+	case reflect.Slice:
+		for _, record := range raw.([]interface{}) {
+			if serviceName, ok := record.(string); ok {
+				*d = append(*d, strings.TrimSpace(serviceName))
+			} else {
+				return errors.New("invalid depends_on definition")
+			}
+		}
+	case reflect.Map:
+		for serviceName := range raw.(map[string]interface{}) {
+			*d = append(*d, strings.TrimSpace(serviceName))
+		}
+		sort.Strings(*d)
+	default:
+		return errors.New("invalid depends_on definition")
+	}
+	return nil
+}
+
+// HealthCheck healthcheck of compose
+type HealthCheck struct {
+	Test          Command `yaml:"test"`
+	Interval      string  `yaml:"interval"`
+	Timeout       string  `yaml:"timeout"`
+	StartPeriod   string  `yaml:"start_period"`
+	StartInterval string  `yaml:"start_interval"`
+	Retries       uint64  `yaml:"retries"`
+	Disable       bool    `yaml:"disable"`
+}
+
+// ServiceSecretList List of secrets used by service
+// Supports both short (name) and long (source, target) syntax of compose
+type ServiceSecretList []ServiceSecret
+
+type ServiceSecret struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target,omitempty"`
+}
+
+func (l *ServiceSecretList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*l = make([]ServiceSecret, 0)
+	if raw == nil || reflect.TypeOf(raw).Kind() != reflect.Slice {
+		return errors.New("invalid secrets definition")
+	}
+	for _, record := range raw.([]interface{}) {
+		if source, ok := record.(string); ok {
+			*l = append(*l, ServiceSecret{Source: strings.TrimSpace(source)})
+		} else if recordMap, ok := record.(map[string]interface{}); ok {
+			source, _ := recordMap["source"].(string)
+			target, _ := recordMap["target"].(string)
+			if strings.TrimSpace(source) == "" {
+				return errors.New("source is required in secret definition")
+			}
+			*l = append(*l, ServiceSecret{Source: strings.TrimSpace(source), Target: strings.TrimSpace(target)})
+		} else {
+			return errors.New("invalid secrets definition")
+		}
+	}
+	return nil
+}
+
+// Name : name of the file in /run/secrets
+func (s ServiceSecret) Name() string {
+	if s.Target != "" {
+		return s.Target
+	}
+	return s.Source
+}
+
+// StackSecret secret of compose
+// Only `environment` source is supported, the value is taken from the stack variable of same name
+type StackSecret struct {
+	Environment string `yaml:"environment,omitempty"`
+	File        string `yaml:"file,omitempty"`
+}
+
 func (s *Stack) deepCopy() (*Stack, error) {
 	yamlBytes, err := yaml.Marshal(s)
 	if err != nil {
@@ -297,6 +543,7 @@ func (s *Stack) deepCopy() (*Stack, error) {
 	if err != nil {
 		return nil, err
 	}
+	newStack.Warnings = s.Warnings
 	return newStack, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
 	"math/rand"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,6 +26,16 @@ func ParseStackYaml(yamlStr string, currentSwiftwaveVersion string) (Stack, erro
 	if !isCurrentVersionSameOrLargerThanMinimum(stack.MinimumSwiftwaveVersion, currentSwiftwaveVersion) {
 		return Stack{}, fmt.Errorf(`required Swiftwave %s. Current Version %s. Please upgrade to latest`, stack.MinimumSwiftwaveVersion, currentSwiftwaveVersion)
 	}
+	// convert the keys of compose and collect warnings for the unsupported ones
+	stack.Warnings, err = unsupportedKeyWarnings(yamlStr)
+	if err != nil {
+		return Stack{}, err
+	}
+	err = stack.convertComposeKeys()
+	if err != nil {
+		return Stack{}, err
+	}
+	sort.Strings(stack.Warnings)
 	// Pre-fill default values
 	for serviceName, service := range stack.Services {
 		if service.Deploy.Mode == DeploymentModeNone {
@@ -67,6 +78,13 @@ func ParseStackYaml(yamlStr string, currentSwiftwaveVersion string) (Stack, erro
 		stack.Services[serviceName] = service
 	}
 	// Append Stack Name Variable {{STACK_NAME}} to the services
+	for _, service := range stack.Services {
+		for i, dependency := range service.DependsOn {
+			if !strings.Contains(dependency, "{{STACK_NAME}}") {
+				service.DependsOn[i] = "{{STACK_NAME}}_" + dependency
+			}
+		}
+	}
 	for serviceName, service := range stack.Services {
 		if !strings.Contains(serviceName, "{{STACK_NAME}}") {
 			newServiceName := "{{STACK_NAME}}_" + serviceName
@@ -142,6 +160,22 @@ func (s *Stack) FillAndVerifyVariables(variableMapping *map[string]string, servi
 			config.MountingPath = variableFillerHelper(config.MountingPath, variableMapping)
 			service.Configs[i] = config
 		}
+		// iterate over secret environment variables
+		for key, value := range service.SecretEnvironment {
+			newKey := variableFillerHelper(key, variableMapping)
+			newValue := variableFillerHelper(value, variableMapping)
+			delete(service.SecretEnvironment, key)
+			service.SecretEnvironment[newKey] = newValue
+		}
+		// iterate over ports
+		for i, port := range service.Ports {
+			port.Domain = variableFillerHelper(port.Domain, variableMapping)
+			service.Ports[i] = port
+		}
+		// iterate over dependencies
+		for i, dependency := range service.DependsOn {
+			service.DependsOn[i] = variableFillerHelper(dependency, variableMapping)
+		}
 		// [IGNORE] CapAdd shouldn't have any variables
 		// [IGNORE] Sysctls shouldn't have any variables
 		// iterate over command
@@ -0,0 +1,34 @@
+package wakeup
+
+import (
+	"fmt"
+	"html"
+)
+
+// statusPage : minimal html page shown to the visitors of the application
+func statusPage(title string, subtitle string, autoRefresh bool) string {
+	refreshTag := ""
+	if autoRefresh {
+		refreshTag = fmt.Sprintf(`<meta http-equiv="refresh" content="%s">`, retryAfterSeconds)
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+%s
+<title>%s</title>
+<style>
+body { font-family: sans-serif; display: flex; align-items: center; justify-content: center; height: 100vh; margin: 0; color: #333; }
+div { text-align: center; }
+p { color: #777; }
+</style>
+</head>
+<body>
+<div>
+<h2>%s</h2>
+<p>%s</p>
+</div>
+</body>
+</html>`, refreshTag, html.EscapeString(title), html.EscapeString(title), html.EscapeString(subtitle))
+}
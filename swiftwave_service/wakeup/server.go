@@ -0,0 +1,182 @@
+package wakeup
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+const (
+	// maximum time to hold a request while the application is waking up
+	holdTimeout = 30 * time.Second
+	// interval to check the running replicas of the application
+	pollInterval = 1 * time.Second
+	// time given to the proxies to resolve the new containers, before the client retries the request
+	proxyResolveDelay = 3 * time.Second
+	// clients are asked to retry after this many seconds, if the application is not ready in time
+	retryAfterSeconds = "5"
+)
+
+// Initialize : Register the wake-up handler
+// While an application is sleeping, HAProxy forwards its requests to SwiftWave with the wake-up header.
+// Those requests have the host of the application, so they are handled before routing.
+func (server *Server) Initialize() {
+	server.EchoServer.Pre(server.wakeUpMiddleware)
+}
+
+func (server *Server) wakeUpMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		serviceName := strings.TrimSpace(c.Request().Header.Get(haproxymanager.WakeUpHeader))
+		if serviceName == "" {
+			return next(c)
+		}
+		return server.wakeUpApplication(c, serviceName)
+	}
+}
+
+// Handler to wake up a sleeping application on the first request
+// The request is held until the application has running replicas, then the client is redirected to the same url
+func (server *Server) wakeUpApplication(c echo.Context, serviceName string) error {
+	ctx := c.Request().Context()
+	db := server.ServiceManager.DbClient
+	// fetch application
+	application := &core.Application{}
+	err := application.FindByName(ctx, db, serviceName)
+	if err != nil || application.IsDeleted {
+		return c.HTML(http.StatusNotFound, statusPage("Application not found", "", false))
+	}
+	// the request should be for one of the domains of the application
+	isValid, err := isApplicationDomain(ctx, db, application, c.Request().Host)
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to verify domain of wake-up request", err.Error())
+		return c.HTML(http.StatusInternalServerError, statusPage("Something went wrong", "", false))
+	}
+	if !isValid {
+		return c.HTML(http.StatusNotFound, statusPage("Application not found", "", false))
+	}
+	switch decideWakeUp(application) {
+	case rejectRequest:
+		return c.HTML(http.StatusServiceUnavailable, statusPage("Application is sleeping", "Contact the administrator to wake it up", false))
+	case wakeUpAndHoldRequest:
+		err = server.wakeUp(ctx, application)
+		if err != nil {
+			logger.HTTPLoggerError.Println("Failed to wake up application", application.Name, err.Error())
+			return c.HTML(http.StatusInternalServerError, statusPage("Failed to wake up application", "", false))
+		}
+	}
+	// hold the request until the application is ready
+	if !server.waitForRunningReplicas(ctx, application.Name) {
+		c.Response().Header().Set("Retry-After", retryAfterSeconds)
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		return c.HTML(http.StatusServiceUnavailable, statusPage("Application is waking up", "This page will refresh automatically", true))
+	}
+	// give proxies some time to resolve the containers
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(proxyResolveDelay):
+	}
+	// 307 keeps the method and body of the request
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Redirect(http.StatusTemporaryRedirect, c.Request().RequestURI)
+}
+
+// decideWakeUp : sleeping application is woken up only if auto sleep is enabled, otherwise it has been put to sleep manually
+func decideWakeUp(application *core.Application) wakeUpDecision {
+	if !application.IsSleeping {
+		return holdRequest
+	}
+	if !application.AutoSleep.Enabled {
+		return rejectRequest
+	}
+	return wakeUpAndHoldRequest
+}
+
+// wakeUp : mark the application as wake and deploy it
+// only the first request triggers the deployment, others just wait for it
+func (server *Server) wakeUp(ctx context.Context, application *core.Application) error {
+	isWoken, err := application.WakeUpIfSleeping(ctx, server.ServiceManager.DbClient)
+	if err != nil {
+		return err
+	}
+	if !isWoken {
+		return nil
+	}
+	latestDeployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, server.ServiceManager.DbClient, application.ID)
+	if err != nil {
+		return errors.New("failed to fetch latest deployment")
+	}
+	err = server.WorkerManager.EnqueueDeployApplicationRequest(application.ID, latestDeployment.ID)
+	if err != nil {
+		return err
+	}
+	logger.HTTPLogger.Println("Application", application.Name, "received a request while sleeping, waking up")
+	return nil
+}
+
+// waitForRunningReplicas : wait till the service has at least one running replica
+func (server *Server) waitForRunningReplicas(ctx context.Context, serviceName string) bool {
+	swarmManager, err := core.FetchSwarmManager(&server.ServiceManager.DbClient)
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to fetch swarm manager", err.Error())
+		return false
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManager)
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to create docker client", err.Error())
+		return false
+	}
+	timeout := time.After(holdTimeout)
+	for {
+		runningReplicas, err := dockerManager.NoOfRunningTasks(serviceName)
+		if err == nil && runningReplicas > 0 {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timeout:
+			return false
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// isApplicationDomain : check if the host is bound to the application by any http/https ingress rule
+func isApplicationDomain(ctx context.Context, db gorm.DB, application *core.Application, host string) (bool, error) {
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	ingressRules, err := core.FindIngressRulesByApplicationID(ctx, db, application.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, ingressRule := range ingressRules {
+		if ingressRule.Protocol != core.HTTPProtocol && ingressRule.Protocol != core.HTTPSProtocol {
+			continue
+		}
+		if ingressRule.DomainID == nil {
+			continue
+		}
+		domain := &core.Domain{}
+		err = domain.FindById(ctx, db, *ingressRule.DomainID)
+		if err != nil {
+			return false, err
+		}
+		if domain.MatchesHost(hostname) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
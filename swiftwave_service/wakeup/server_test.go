@@ -0,0 +1,142 @@
+package wakeup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestIsApplicationDomain(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "ingress_rules.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&core.Domain{}, &core.IngressRule{}))
+	ctx := context.Background()
+	application := &core.Application{ID: "app"}
+	otherApplicationID := "other"
+	addIngressRule := func(applicationID *string, domainName string, protocol core.ProtocolType) {
+		domain := core.Domain{Name: domainName}
+		assert.NoError(t, db.Create(&domain).Error)
+		assert.NoError(t, db.Create(&core.IngressRule{
+			DomainID:      &domain.ID,
+			Protocol:      protocol,
+			Port:          443,
+			ApplicationID: applicationID,
+		}).Error)
+	}
+	addIngressRule(&application.ID, "app.example.com", core.HTTPSProtocol)
+	addIngressRule(&application.ID, "*.preview.example.com", core.HTTPProtocol)
+	addIngressRule(&application.ID, "tcp.example.com", core.TCPProtocol)
+	addIngressRule(&otherApplicationID, "other.example.com", core.HTTPSProtocol)
+
+	expected := map[string]bool{
+		"app.example.com":            true,
+		"APP.example.com:443":        true,
+		"pr-1.preview.example.com":   true,
+		"a.pr-1.preview.example.com": true,
+		"preview.example.com":        false,
+		"tcp.example.com":            false,
+		"other.example.com":          false,
+		"unknown.example.com":        false,
+	}
+	for host, isValid := range expected {
+		result, err := isApplicationDomain(ctx, *db, application, host)
+		assert.NoError(t, err, host)
+		assert.Equal(t, isValid, result, host)
+	}
+}
+
+func TestDecideWakeUp(t *testing.T) {
+	t.Run("awake application", func(t *testing.T) {
+		application := &core.Application{IsSleeping: false, AutoSleep: core.ApplicationAutoSleep{Enabled: true}}
+		assert.Equal(t, holdRequest, decideWakeUp(application))
+		application.AutoSleep.Enabled = false
+		assert.Equal(t, holdRequest, decideWakeUp(application))
+	})
+	t.Run("sleeping application with auto sleep", func(t *testing.T) {
+		application := &core.Application{IsSleeping: true, AutoSleep: core.ApplicationAutoSleep{Enabled: true}}
+		assert.Equal(t, wakeUpAndHoldRequest, decideWakeUp(application))
+	})
+	t.Run("application put to sleep manually", func(t *testing.T) {
+		application := &core.Application{IsSleeping: true, AutoSleep: core.ApplicationAutoSleep{Enabled: false}}
+		assert.Equal(t, rejectRequest, decideWakeUp(application))
+	})
+}
+
+func TestWakeUpMiddleware(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "applications.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&core.Application{}, &core.Domain{}, &core.IngressRule{}))
+	addApplication := func(application core.Application) {
+		assert.NoError(t, db.Create(&application).Error)
+		domain := core.Domain{Name: application.Name + ".example.com"}
+		assert.NoError(t, db.Create(&domain).Error)
+		assert.NoError(t, db.Create(&core.IngressRule{
+			DomainID:      &domain.ID,
+			Protocol:      core.HTTPSProtocol,
+			Port:          443,
+			ApplicationID: &application.ID,
+		}).Error)
+	}
+	addApplication(core.Application{ID: "1", Name: "manual", IsSleeping: true})
+	addApplication(core.Application{ID: "2", Name: "deleted", IsSleeping: true, IsDeleted: true, AutoSleep: core.ApplicationAutoSleep{Enabled: true}})
+
+	server := &Server{
+		EchoServer:     echo.New(),
+		ServiceManager: &service_manager.ServiceManager{DbClient: *db},
+	}
+	server.Initialize()
+	server.EchoServer.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "routed")
+	})
+	request := func(host string, serviceName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		if serviceName != "" {
+			req.Header.Set(haproxymanager.WakeUpHeader, serviceName)
+		}
+		rec := httptest.NewRecorder()
+		server.EchoServer.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("request without wake-up header is routed", func(t *testing.T) {
+		rec := request("manual.example.com", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "routed", rec.Body.String())
+	})
+	t.Run("unknown application", func(t *testing.T) {
+		rec := request("unknown.example.com", "unknown")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("deleted application", func(t *testing.T) {
+		rec := request("deleted.example.com", "deleted")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("host of another application", func(t *testing.T) {
+		rec := request("deleted.example.com", "manual")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("application put to sleep manually is not woken up", func(t *testing.T) {
+		rec := request("manual.example.com", "manual")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Application is sleeping")
+		var application core.Application
+		assert.NoError(t, db.First(&application, "id = ?", "1").Error)
+		assert.True(t, application.IsSleeping)
+	})
+}
@@ -0,0 +1,28 @@
+package wakeup
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/worker"
+)
+
+// Server : hold references to other components of service
+type Server struct {
+	EchoServer     *echo.Echo
+	Config         *config.Config
+	ServiceManager *service_manager.ServiceManager
+	WorkerManager  *worker.Manager
+}
+
+// wakeUpDecision : how to handle a request forwarded to the wake-up handler
+type wakeUpDecision int
+
+const (
+	// holdRequest : application is awake, hold the request until it is ready
+	holdRequest wakeUpDecision = iota
+	// wakeUpAndHoldRequest : wake up the application, then hold the request until it is ready
+	wakeUpAndHoldRequest
+	// rejectRequest : application is sleeping and should not be woken up by requests
+	rejectRequest
+)
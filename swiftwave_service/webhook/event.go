@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	branchRefPrefix = "refs/heads/"
+	nullCommitHash  = "0000000000000000000000000000000000000000"
+)
+
+// DetectProvider : detect the git provider from the request headers
+// Gitea also sends GitHub compatible headers, so it needs to be checked first
+func DetectProvider(header http.Header) GitProvider {
+	if header.Get("X-Gitea-Event") != "" {
+		return GiteaProvider
+	}
+	if header.Get("X-GitHub-Event") != "" {
+		return GitHubProvider
+	}
+	if header.Get("X-Gitlab-Event") != "" {
+		return GitLabProvider
+	}
+	return GenericProvider
+}
+
+// VerifyRequest : verify the request with the webhook token of the application
+// GitHub and Gitea requests are verified with the HMAC signature of the body if present,
+// GitLab requests with the X-Gitlab-Token header and generic requests with the provided token
+func VerifyRequest(provider GitProvider, header http.Header, body []byte, providedToken string, webhookToken string) error {
+	if webhookToken == "" {
+		return errors.New("webhook token is not configured for the application")
+	}
+	switch provider {
+	case GitHubProvider, GiteaProvider:
+		if signature := header.Get("X-Hub-Signature-256"); signature != "" {
+			return verifyHMACSignature(strings.TrimPrefix(signature, "sha256="), body, webhookToken)
+		}
+		if signature := header.Get("X-Gitea-Signature"); signature != "" {
+			return verifyHMACSignature(signature, body, webhookToken)
+		}
+	case GitLabProvider:
+		if token := header.Get("X-Gitlab-Token"); token != "" {
+			return verifyToken(token, webhookToken)
+		}
+	}
+	if providedToken == "" {
+		providedToken = header.Get("X-Webhook-Token")
+	}
+	if providedToken == "" {
+		return errors.New("missing webhook token or signature")
+	}
+	return verifyToken(providedToken, webhookToken)
+}
+
+// ParsePushEvent : parse the request body to a normalized push event
+func ParsePushEvent(provider GitProvider, header http.Header, body []byte) (*PushEvent, error) {
+	event := &PushEvent{
+		Provider: provider,
+	}
+	switch provider {
+	case GitHubProvider:
+		event.IsPush = header.Get("X-GitHub-Event") == "push"
+	case GiteaProvider:
+		event.IsPush = header.Get("X-Gitea-Event") == "push"
+	case GitLabProvider:
+		event.IsPush = header.Get("X-Gitlab-Event") == "Push Hook"
+	case GenericProvider:
+		event.IsPush = true
+	}
+	if !event.IsPush {
+		return event, nil
+	}
+	// generic webhooks can be sent without body
+	if provider == GenericProvider && len(strings.TrimSpace(string(body))) == 0 {
+		return event, nil
+	}
+	var payload pushPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("invalid payload")
+	}
+	event.Ref = payload.Ref
+	if event.Ref == "" && payload.Branch != "" {
+		event.Ref = branchRefPrefix + payload.Branch
+	}
+	event.Deleted = payload.Deleted || payload.After == nullCommitHash
+	return event, nil
+}
+
+// Branch : returns branch name of the push, blank if the ref is not a branch
+func (e *PushEvent) Branch() string {
+	if !strings.HasPrefix(e.Ref, branchRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(e.Ref, branchRefPrefix)
+}
+
+// private functions
+
+func verifyHMACSignature(signature string, body []byte, secret string) error {
+	expectedSignature, err := hex.DecodeString(signature)
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expectedSignature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func verifyToken(token string, webhookToken string) error {
+	if subtle.ConstantTimeCompare([]byte(token), []byte(webhookToken)) != 1 {
+		return errors.New("invalid webhook token")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testWebhookToken = "s3cr3t"
+
+func testSignature(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func testHeader(pairs ...string) http.Header {
+	header := http.Header{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		header.Set(pairs[i], pairs[i+1])
+	}
+	return header
+}
+
+func TestDetectProvider(t *testing.T) {
+	assert.Equal(t, GiteaProvider, DetectProvider(testHeader("X-Gitea-Event", "push", "X-GitHub-Event", "push")))
+	assert.Equal(t, GitHubProvider, DetectProvider(testHeader("X-GitHub-Event", "push")))
+	assert.Equal(t, GitLabProvider, DetectProvider(testHeader("X-Gitlab-Event", "Push Hook")))
+	assert.Equal(t, GenericProvider, DetectProvider(testHeader()))
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+
+	t.Run("valid signature", func(t *testing.T) {
+		header := testHeader("X-Hub-Signature-256", "sha256="+testSignature(body, testWebhookToken))
+		assert.NoError(t, VerifyRequest(GitHubProvider, header, body, "", testWebhookToken))
+		header = testHeader("X-Gitea-Signature", testSignature(body, testWebhookToken))
+		assert.NoError(t, VerifyRequest(GiteaProvider, header, body, "", testWebhookToken))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		header := testHeader("X-Hub-Signature-256", "sha256="+testSignature(body, "other"))
+		assert.Error(t, VerifyRequest(GitHubProvider, header, body, "", testWebhookToken))
+		header = testHeader("X-Hub-Signature-256", "sha256=not-hex")
+		assert.Error(t, VerifyRequest(GitHubProvider, header, body, "", testWebhookToken))
+		// signature is of the original body
+		header = testHeader("X-Gitea-Signature", testSignature(body, testWebhookToken))
+		assert.Error(t, VerifyRequest(GiteaProvider, header, []byte(`{"ref":"refs/heads/other"}`), "", testWebhookToken))
+	})
+
+	t.Run("tokens", func(t *testing.T) {
+		assert.NoError(t, VerifyRequest(GitLabProvider, testHeader("X-Gitlab-Token", testWebhookToken), body, "", testWebhookToken))
+		assert.Error(t, VerifyRequest(GitLabProvider, testHeader("X-Gitlab-Token", "other"), body, "", testWebhookToken))
+		assert.NoError(t, VerifyRequest(GenericProvider, testHeader(), nil, testWebhookToken, testWebhookToken))
+		assert.NoError(t, VerifyRequest(GenericProvider, testHeader("X-Webhook-Token", testWebhookToken), nil, "", testWebhookToken))
+		assert.Error(t, VerifyRequest(GenericProvider, testHeader(), nil, "", testWebhookToken))
+		assert.Error(t, VerifyRequest(GenericProvider, testHeader(), nil, "other", testWebhookToken))
+	})
+
+	t.Run("application without webhook token", func(t *testing.T) {
+		assert.Error(t, VerifyRequest(GenericProvider, testHeader(), nil, "", ""))
+		header := testHeader("X-Hub-Signature-256", "sha256="+testSignature(body, ""))
+		assert.Error(t, VerifyRequest(GitHubProvider, header, body, "", ""))
+	})
+}
+
+func TestParsePushEvent(t *testing.T) {
+	t.Run("push payloads", func(t *testing.T) {
+		event, err := ParsePushEvent(GitHubProvider, testHeader("X-GitHub-Event", "push"), []byte(`{"ref":"refs/heads/main","after":"5f2c1d"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, &PushEvent{Provider: GitHubProvider, IsPush: true, Ref: "refs/heads/main"}, event)
+		assert.Equal(t, "main", event.Branch())
+
+		event, err = ParsePushEvent(GitLabProvider, testHeader("X-Gitlab-Event", "Push Hook"), []byte(`{"ref":"refs/heads/develop","after":"0000000000000000000000000000000000000000"}`))
+		assert.NoError(t, err)
+		assert.True(t, event.Deleted)
+		assert.Equal(t, "develop", event.Branch())
+
+		event, err = ParsePushEvent(GiteaProvider, testHeader("X-Gitea-Event", "push"), []byte(`{"ref":"refs/tags/v1.0.0"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "", event.Branch())
+	})
+
+	t.Run("generic payloads", func(t *testing.T) {
+		event, err := ParsePushEvent(GenericProvider, testHeader(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &PushEvent{Provider: GenericProvider, IsPush: true}, event)
+
+		event, err = ParsePushEvent(GenericProvider, testHeader(), []byte(`{"branch":"main"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "main", event.Branch())
+	})
+
+	t.Run("other events are not push", func(t *testing.T) {
+		event, err := ParsePushEvent(GitHubProvider, testHeader("X-GitHub-Event", "ping"), []byte(`not json`))
+		assert.NoError(t, err)
+		assert.False(t, event.IsPush)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := ParsePushEvent(GitHubProvider, testHeader("X-GitHub-Event", "push"), []byte(`not json`))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
+	"gorm.io/gorm"
+)
+
+// maximum size of the webhook payload, push payloads with many commits can be large
+const maxPayloadSize = 10 << 20 // 10 MB
+
+// Initialize : Initialize the server and its routes
+func (server *Server) Initialize() {
+	server.EchoServer.POST("/webhook/redeploy-app/:id", server.redeployApplication)
+	server.EchoServer.POST("/webhook/redeploy-app/:id/:token", server.redeployApplication)
+}
+
+// Handler to redeploy application on git push
+// Supports GitHub, GitLab, Gitea and generic webhooks
+func (server *Server) redeployApplication(c echo.Context) error {
+	ctx := c.Request().Context()
+	// read payload
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPayloadSize))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Failed to read payload")
+	}
+	// fetch application
+	application := &core.Application{}
+	err = application.FindById(ctx, server.ServiceManager.DbClient, c.Param("id"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.String(http.StatusInternalServerError, "Failed to fetch application")
+	}
+	// request for an unknown application fails the verification like an invalid one, so application ids can't be probed
+	webhookToken := ""
+	if err == nil && !application.IsDeleted {
+		webhookToken = application.WebhookToken
+	}
+	// verify request
+	provider := DetectProvider(c.Request().Header)
+	providedToken := c.Param("token")
+	if providedToken == "" {
+		providedToken = c.QueryParam("token")
+	}
+	err = VerifyRequest(provider, c.Request().Header, body, providedToken, webhookToken)
+	if err != nil {
+		return c.String(http.StatusUnauthorized, "Invalid webhook token or signature")
+	}
+	// parse event
+	event, err := ParsePushEvent(provider, c.Request().Header, body)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if !event.IsPush {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": "Event ignored, only push events trigger deployment",
+		})
+	}
+	// match branch with the deployment
+	deployment, err := core.FindCurrentOrLatestDeploymentByApplicationId(ctx, server.ServiceManager.DbClient, application.ID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch latest deployment")
+	}
+	if event.Provider != GenericProvider || event.Ref != "" {
+		if deployment.UpstreamType != core.UpstreamTypeGit {
+			return c.String(http.StatusBadRequest, "Application is not deployed from a git repository")
+		}
+		if event.Deleted || event.Branch() == "" || event.Branch() != deployment.RepositoryBranch {
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"message": "Push ignored, branch does not match the deployed branch " + deployment.RepositoryBranch,
+			})
+		}
+	}
+	// create new deployment
+	tx := server.ServiceManager.DbClient.Begin()
+	deploymentId, err := application.RebuildApplication(ctx, *tx)
+	if err != nil {
+		tx.Rollback()
+		return c.String(http.StatusInternalServerError, "Failed to create new deployment")
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		tx.Rollback()
+		return c.String(http.StatusInternalServerError, "Failed to create new deployment due to database error")
+	}
+	// enqueue build request
+	err = server.WorkerManager.EnqueueBuildApplicationRequest(application.ID, deploymentId)
+	if err != nil {
+		logger.HTTPLoggerError.Println("Failed to enqueue build request for application " + application.ID + " > " + err.Error())
+		return c.String(http.StatusInternalServerError, "Failed to queue build request")
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":       "Deployment triggered",
+		"deployment_id": deploymentId,
+	})
+}
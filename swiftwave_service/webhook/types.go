@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/worker"
+)
+
+// Server : hold references to other components of service
+type Server struct {
+	EchoServer     *echo.Echo
+	Config         *config.Config
+	ServiceManager *service_manager.ServiceManager
+	WorkerManager  *worker.Manager
+}
+
+// GitProvider : provider which has sent the webhook request
+type GitProvider string
+
+const (
+	GitHubProvider  GitProvider = "github"
+	GitLabProvider  GitProvider = "gitlab"
+	GiteaProvider   GitProvider = "gitea"
+	GenericProvider GitProvider = "generic"
+)
+
+// PushEvent : normalized push event received from any provider
+type PushEvent struct {
+	Provider GitProvider
+	// IsPush is false for events which should not trigger a deployment (ping, issues, etc.)
+	IsPush bool
+	// Ref of the push, can be blank for generic webhooks
+	Ref string
+	// Deleted is true if the ref has been deleted by the push
+	Deleted bool
+}
+
+// pushPayload : common fields of GitHub, GitLab and Gitea push payloads
+// Generic webhooks can send either `ref` or `branch`
+type pushPayload struct {
+	Ref     string `json:"ref"`
+	Branch  string `json:"branch"`
+	After   string `json:"after"`
+	Deleted bool   `json:"deleted"`
+}
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"github.com/swiftwave-org/swiftwave/pkg/task_queue"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
 )
@@ -31,6 +32,7 @@ func (m Manager) WaitForConsumers() {
 
 func (m Manager) registerWorkerFunctions() {
 	taskQueueClient := m.ServiceManager.TaskQueueClient
+	// tasks which are not safe to repeat or are retried by the user are attempted only once
 	panicOnError(taskQueueClient.RegisterFunction(buildApplicationQueueName, m.BuildApplication))
 	panicOnError(taskQueueClient.RegisterFunction(deployApplicationQueueName, m.DeployApplication))
 	panicOnError(taskQueueClient.RegisterFunction(deleteApplicationQueueName, m.DeleteApplication))
@@ -39,15 +41,26 @@ func (m Manager) registerWorkerFunctions() {
 	panicOnError(taskQueueClient.RegisterFunction(ingressRuleHttpsRedirectQueueName, m.IngressRuleHttpsRedirect))
 	panicOnError(taskQueueClient.RegisterFunction(redirectRuleApplyQueueName, m.RedirectRuleApply))
 	panicOnError(taskQueueClient.RegisterFunction(redirectRuleDeleteQueueName, m.RedirectRuleDelete))
-	panicOnError(taskQueueClient.RegisterFunction(sslGenerateQueueName, m.SSLGenerate))
+	panicOnError(taskQueueClient.RegisterFunctionWithRetryPolicy(sslGenerateQueueName, m.SSLGenerate, task_queue.NoRetryPolicy()))
 	panicOnError(taskQueueClient.RegisterFunction(sslProxyUpdateQueueName, m.SSLProxyUpdate))
 	panicOnError(taskQueueClient.RegisterFunction(deletePersistentVolumeQueueName, m.PersistentVolumeDeletion))
-	panicOnError(taskQueueClient.RegisterFunction(persistentVolumeBackupQueueName, m.PersistentVolumeBackup))
-	panicOnError(taskQueueClient.RegisterFunction(persistentVolumeRestoreQueueName, m.PersistentVolumeRestore))
+	panicOnError(taskQueueClient.RegisterFunctionWithRetryPolicy(persistentVolumeBackupQueueName, m.PersistentVolumeBackup, task_queue.NoRetryPolicy()))
+	panicOnError(taskQueueClient.RegisterFunctionWithRetryPolicy(persistentVolumeRestoreQueueName, m.PersistentVolumeRestore, task_queue.NoRetryPolicy()))
 	panicOnError(taskQueueClient.RegisterFunction(installDependenciesOnServerQueueName, m.InstallDependenciesOnServer))
 	panicOnError(taskQueueClient.RegisterFunction(setupServerQueueName, m.SetupServer))
 	panicOnError(taskQueueClient.RegisterFunction(setupAndEnableProxyQueueName, m.SetupAndEnableProxy))
 	panicOnError(taskQueueClient.RegisterFunction(updateApplicationOnServerScheduleDeploymentUpdateQueueName, m.UpdateApplicationOnServerScheduleDeploymentUpdate))
+	panicOnError(taskQueueClient.RegisterFunction(verifyDeploymentQueueName, m.VerifyDeployment))
+	panicOnError(taskQueueClient.RegisterFunction(promoteDeploymentQueueName, m.PromoteDeployment))
+	panicOnError(taskQueueClient.RegisterFunction(abortDeploymentQueueName, m.AbortDeployment))
+	panicOnError(taskQueueClient.RegisterFunctionWithRetryPolicy(runCronJobQueueName, m.RunCronJob, task_queue.NoRetryPolicy()))
+	panicOnError(taskQueueClient.RegisterFunction(checkCronJobRunQueueName, m.CheckCronJobRun))
+	panicOnError(taskQueueClient.RegisterFunction(waitForDependenciesQueueName, m.WaitForDependencies))
+	panicOnError(taskQueueClient.RegisterFunction(syncApplicationGroupQueueName, m.SyncApplicationGroup))
+	// auto scaling decides again on the next run
+	panicOnError(taskQueueClient.RegisterFunctionWithRetryPolicy(scaleApplicationQueueName, m.ScaleApplication, task_queue.NoRetryPolicy()))
+	// service is scaled already, so the update of the proxies is retried
+	panicOnError(taskQueueClient.RegisterFunction(updateBackendReplicasQueueName, m.UpdateBackendReplicas))
 	// When adding a new function, add it to the list of Queues() as well
 }
 
@@ -70,6 +83,15 @@ func Queues() []string {
 		setupServerQueueName,
 		setupAndEnableProxyQueueName,
 		updateApplicationOnServerScheduleDeploymentUpdateQueueName,
+		verifyDeploymentQueueName,
+		promoteDeploymentQueueName,
+		abortDeploymentQueueName,
+		runCronJobQueueName,
+		checkCronJobRunQueueName,
+		waitForDependenciesQueueName,
+		syncApplicationGroupQueueName,
+		scaleApplicationQueueName,
+		updateBackendReplicasQueueName,
 	}
 }
 
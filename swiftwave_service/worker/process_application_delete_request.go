@@ -32,6 +32,11 @@ func (m Manager) DeleteApplication(request DeleteApplicationRequest, ctx context
 			return err
 		}
 	}
+	// runs of cron job, to remove the job services
+	activeCronJobRuns, err := core.FindActiveCronJobRunsByApplicationId(ctx, dbWithoutTx, application.ID)
+	if err != nil {
+		return err
+	}
 	// start a db transaction
 	tx := dbWithoutTx.Begin()
 	// delete application
@@ -52,9 +57,16 @@ func (m Manager) DeleteApplication(request DeleteApplicationRequest, ctx context
 	if err != nil {
 		log.Println("[WARN] error deleting application from swarm manager : " + application.Name)
 	}
+	// remove blue/green or canary deployment, if any
+	_ = dockerManager.RemoveService(application.CanaryServiceName())
+	// remove the job services of the active runs
+	for _, run := range activeCronJobRuns {
+		_ = dockerManager.RemoveService(run.ServiceName())
+	}
 	// remove docker proxy
 	dockerManager.RemoveDockerProxy(application.DockerProxyServiceName())
 	// prune config mounts
 	dockerManager.PruneConfig(application.ID)
+	dockerManager.PruneSecrets(application.ID)
 	return nil
 }
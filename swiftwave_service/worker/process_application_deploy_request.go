@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	secretmanager "github.com/swiftwave-org/swiftwave/pkg/secret_manager"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
 	"gorm.io/gorm"
 )
@@ -50,8 +51,9 @@ func (m Manager) DeployApplication(request DeployApplicationRequest, _ context.C
 			log.Println("failed to update deployment status to failed", err)
 		}
 	}
-	// prune config mounts
+	// prune config mounts and secrets
 	dockerManager.PruneConfig(request.AppId)
+	dockerManager.PruneSecrets(request.AppId)
 	return nil
 }
 
@@ -59,15 +61,11 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 	// context
 	ctx := context.Background()
 	dbWithoutTx := m.ServiceManager.DbClient
-	db := m.ServiceManager.DbClient.Begin()
-	defer func() {
-		db.Rollback()
-	}()
 	// pubSub client
 	pubSubClient := m.ServiceManager.PubSubClient
 	// fetch application
 	var application core.Application
-	err := application.FindById(ctx, *db, request.AppId)
+	err := application.FindById(ctx, dbWithoutTx, request.AppId)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// return nil as don't want to requeue the job
@@ -79,7 +77,7 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 	// fetch deployment
 	deployment := &core.Deployment{}
 	deployment.ID = request.DeploymentId
-	err = deployment.FindById(ctx, *db, request.DeploymentId)
+	err = deployment.FindById(ctx, dbWithoutTx, request.DeploymentId)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// create new deployment
@@ -90,175 +88,46 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 	}
 	// log message
 	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Deployment starting...\n", false)
-	// fetch environment variables
-	environmentVariables, err := core.FindEnvironmentVariablesByApplicationId(ctx, *db, request.AppId)
+	// prepare the service
+	applicationService, err := m.prepareApplicationService(ctx, dbWithoutTx, &application, deployment, dockerManager, func(content string) {
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, content, false)
+	})
 	if err != nil {
-		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to fetch environment variables\n", false)
 		return err
 	}
-	var environmentVariablesMap = make(map[string]string)
-	for _, environmentVariable := range environmentVariables {
-		value := environmentVariable.Value
-		if application.DockerProxy.Enabled {
-			value = strings.ReplaceAll(value, "{{DOCKER_PROXY_HOST}}", application.DockerProxyServiceName())
-		}
-		environmentVariablesMap[environmentVariable.Key] = value
-	}
-
-	// fetch persistent volumes
-	persistentVolumeBindings, err := core.FindPersistentVolumeBindingsByApplicationId(ctx, *db, request.AppId)
+	service := applicationService.service
+	imageRegistryUsername := applicationService.imageRegistryUsername
+	imageRegistryPassword := applicationService.imageRegistryPassword
+	refetchImage := applicationService.refetchImage
+	placementConstraints := service.PlacementConstraints
+	// find current deployment
+	previousDeploymentId := ""
+	currentDeployment, err := core.FindCurrentDeployedDeploymentByApplicationId(ctx, dbWithoutTx, request.AppId)
 	if err != nil {
-		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to fetch persistent volumes\n", false)
-		return err
-	}
-	var volumeMounts = make([]containermanger.VolumeMount, 0)
-	for _, persistentVolumeBinding := range persistentVolumeBindings {
-		// fetch the volume
-		var persistentVolume core.PersistentVolume
-		err := persistentVolume.FindById(ctx, dbWithoutTx, persistentVolumeBinding.PersistentVolumeID)
-		if err != nil {
-			addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to fetch persistent volume\n", false)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
-		volumeMounts = append(volumeMounts, containermanger.VolumeMount{
-			Source:   persistentVolume.Name,
-			Target:   persistentVolumeBinding.MountingPath,
-			ReadOnly: false,
-		})
-	}
-	sysctls := make(map[string]string)
-	for _, sysctl := range application.Sysctls {
-		sysctlPart := strings.SplitN(sysctl, "=", 2)
-		if len(sysctlPart) == 2 {
-			sysctls[sysctlPart[0]] = sysctlPart[1]
-		}
-	}
-	command := make([]string, 0)
-	if application.Command != "" {
-		command = strings.Split(application.Command, " ")
-	}
-	// docker image info
-	dockerImageUri := deployment.DeployableDockerImageURI(m.Config.ImageRegistryURI())
-	refetchImage := false
-	imageRegistryUsername := m.Config.ImageRegistryUsername()
-	imageRegistryPassword := m.Config.ImageRegistryPassword()
-
-	if deployment.UpstreamType == core.UpstreamTypeImage {
-		// fetch image registry credential
-		if deployment.ImageRegistryCredentialID != nil && *deployment.ImageRegistryCredentialID != 0 {
-			var imageRegistryCredential core.ImageRegistryCredential
-			err := imageRegistryCredential.FindById(ctx, dbWithoutTx, *deployment.ImageRegistryCredentialID)
-			if err != nil {
-				addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to fetch image registry credential\n", false)
-				return err
-			}
-			imageRegistryUsername = imageRegistryCredential.Username
-			imageRegistryPassword = imageRegistryCredential.Password
-		} else {
-			imageRegistryUsername = ""
-			imageRegistryPassword = ""
-		}
-		refetchImage = true
-	}
-
-	if refetchImage {
-		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "[Notice] Image will be fetched from remote during deployment\n", false)
-	}
-	// create the configs if required
-	configMountRecord, err := core.FindConfigMountsByApplicationId(ctx, *db, request.AppId)
-	if err != nil {
-		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to fetch config mounts", false)
-		return err
-	}
-	for _, configMount := range configMountRecord {
-		if strings.Compare(configMount.ConfigID, "") == 0 {
-			// create config
-			configID, err := dockerManager.CreateConfig(configMount.Content, configMount.ApplicationID)
-			if err != nil {
-				addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to create config", false)
-				return err
-			}
-			// update config id
-			err = configMount.UpdateConfigID(ctx, dbWithoutTx, configID)
-			if err != nil {
-				addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to update config id in database", false)
-				return err
-			}
-		}
-	}
-	// prepare config mounts
-	var configMounts = make([]containermanger.ConfigMount, 0)
-	for _, configMount := range configMountRecord {
-		configMounts = append(configMounts, containermanger.ConfigMount{
-			ConfigID:     configMount.ConfigID,
-			Uid:          configMount.Uid,
-			Gid:          configMount.Gid,
-			FileMode:     configMount.FileMode,
-			MountingPath: configMount.MountingPath,
-		})
-	}
-	// prepare placement constraints
-	var placementConstraints = make([]string, 0)
-	disabledServerHostnames, err := core.FetchDisabledDeploymentServerHostNames(&m.ServiceManager.DbClient)
-	if err != nil {
-		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to fetch disabled deployment servers\nPlease check database connection\n", false)
-		return err
-	}
-	for _, hostname := range disabledServerHostnames {
-		placementConstraints = append(placementConstraints, "node.hostname!="+hostname)
+	} else {
+		previousDeploymentId = currentDeployment.ID
 	}
-	for _, preferredServerHostName := range application.PreferredServerHostnames {
-		// if it's not a disabled server, add it to the placement constraints
-		isDisabled := false
-		for _, hostname := range disabledServerHostnames {
-			if strings.Compare(hostname, preferredServerHostName) == 0 {
-				isDisabled = true
-				break
-			}
-		}
-		if !isDisabled {
-			placementConstraints = append(placementConstraints, "node.hostname=="+preferredServerHostName)
+	// run the release command before the deployment receives any traffic
+	if isReleaseCommandRequired(request, &application, previousDeploymentId) {
+		err = m.runReleaseCommand(&application, deployment, service, imageRegistryUsername, imageRegistryPassword, refetchImage, dockerManager)
+		if err != nil {
+			return err
 		}
 	}
-	// create service
-	service := containermanger.Service{
-		Name:                 application.Name,
-		Image:                dockerImageUri,
-		Hostname:             application.Hostname,
-		Command:              command,
-		Env:                  environmentVariablesMap,
-		Networks:             []string{m.Config.SystemConfig.NetworkName},
-		DeploymentMode:       containermanger.DeploymentMode(application.DeploymentMode),
-		Replicas:             uint64(application.ReplicaCount()),
-		VolumeMounts:         volumeMounts,
-		ConfigMounts:         configMounts,
-		Capabilities:         application.Capabilities,
-		Sysctls:              sysctls,
-		PlacementConstraints: placementConstraints,
-		ResourceLimit: containermanger.Resource{
-			MemoryMB: application.ResourceLimit.MemoryMB,
-		},
-		ReservedResource: containermanger.Resource{
-			MemoryMB: application.ReservedResource.MemoryMB,
-		},
-		CustomHealthCheck: containermanger.CustomHealthCheck{
-			Enabled:              application.CustomHealthCheck.Enabled,
-			TestCommand:          application.CustomHealthCheck.TestCommand,
-			IntervalSeconds:      application.CustomHealthCheck.IntervalSeconds,
-			TimeoutSeconds:       application.CustomHealthCheck.TimeoutSeconds,
-			StartPeriodSeconds:   application.CustomHealthCheck.StartPeriodSeconds,
-			StartIntervalSeconds: application.CustomHealthCheck.StartIntervalSeconds,
-			Retries:              application.CustomHealthCheck.Retries,
-		},
+	// transaction is opened after the release command, as the command can run for long
+	db := m.ServiceManager.DbClient.Begin()
+	defer func() {
+		db.Rollback()
+	}()
+	// blue/green and canary deployments run in parallel of the current deployment till promoted
+	if isParallelDeploymentRequired(request, &application, previousDeploymentId, dockerManager) {
+		return m.deployParallelApplicationHelper(ctx, db, &application, deployment, service, imageRegistryUsername, imageRegistryPassword, refetchImage, dockerManager, haproxyManagers)
 	}
-	// find current deployment and mark it as stalled
-	currentDeployment, err := core.FindCurrentDeployedDeploymentByApplicationId(ctx, *db, request.AppId)
-	if err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return err
-		}
-	} else {
-		// Update status to stalled
+	// mark current deployment as stalled
+	if currentDeployment != nil {
 		err = currentDeployment.UpdateStatus(ctx, *db, core.DeploymentStalled)
 		if err != nil {
 			return err
@@ -308,6 +177,11 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 		dockerManager.RemoveDockerProxy(application.DockerProxyServiceName())
 	}
 
+	// cron jobs don't have a long-running service, the runs are created from the deployed deployment
+	if application.IsCronJob() {
+		return m.deployCronJobHelper(db, &application, deployment)
+	}
+
 	// check if the service already exists
 	_, err = dockerManager.GetService(service.Name)
 	if err != nil {
@@ -337,6 +211,13 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 			log.Println("failed to rollback service > "+service.Name, err)
 			addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to rollback service\n", false)
 		}
+	} else if isDeploymentVerificationRequired(request, &application, previousDeploymentId) {
+		// watch the rollout, restore the previous deployment if it doesn't converge
+		err = m.EnqueueVerifyDeploymentRequest(application.ID, deployment.ID, previousDeploymentId)
+		if err != nil {
+			log.Println("failed to enqueue deployment verification request", err)
+			addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to schedule deployment verification\n", false)
+		}
 	}
 
 	if !request.IgnoreProxyUpdate {
@@ -388,6 +269,15 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 									addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to update replica count\n", false)
 								}
 							}
+							// route the requests to the wake-up handler while the application is sleeping
+							if backendProtocol == haproxymanager.HTTPBackend {
+								err = m.configureWakeUpServer(haproxyManager, haproxyTransactionId, backendName, &application)
+								if err != nil {
+									isFailed = true
+									log.Println("failed to configure wake-up server", err)
+									addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to configure wake-up server\n", false)
+								}
+							}
 						}
 					}
 				}
@@ -418,6 +308,210 @@ func (m Manager) deployApplicationHelper(request DeployApplicationRequest, docke
 	return nil
 }
 
+// applicationServiceSpec : swarm service of a deployment along with the credentials to pull its image
+type applicationServiceSpec struct {
+	service               containermanger.Service
+	imageRegistryUsername string
+	imageRegistryPassword string
+	refetchImage          bool
+}
+
+// prepareApplicationService : build the swarm service of the deployment of the application
+// It creates the pending config mounts as well, addLog is used to report the progress
+func (m Manager) prepareApplicationService(ctx context.Context, db gorm.DB, application *core.Application, deployment *core.Deployment, dockerManager *containermanger.Manager, addLog func(content string)) (*applicationServiceSpec, error) {
+	dbWithoutTx := m.ServiceManager.DbClient
+	// fetch environment variables
+	environmentVariables, err := core.FindEnvironmentVariablesByApplicationId(ctx, db, application.ID)
+	if err != nil {
+		addLog("Failed to fetch environment variables\n")
+		return nil, err
+	}
+	var environmentVariablesMap = make(map[string]string)
+	var secretMounts = make([]containermanger.SecretMount, 0)
+	for _, environmentVariable := range environmentVariables {
+		value := environmentVariable.Value
+		if application.DockerProxy.Enabled {
+			value = strings.ReplaceAll(value, "{{DOCKER_PROXY_HOST}}", application.DockerProxyServiceName())
+		}
+		if !environmentVariable.ExposeAsSecret {
+			environmentVariablesMap[environmentVariable.Key] = value
+			continue
+		}
+		// mount as docker secret, the application reads it from the file referred by <key>_FILE
+		fingerprint, err := secretmanager.Fingerprint(environmentVariable.Key + "=" + value)
+		if err != nil {
+			addLog("Failed to create secret for environment variable " + environmentVariable.Key + "\n")
+			return nil, err
+		}
+		secretName := containermanger.SecretName(application.ID, fingerprint)
+		err = dockerManager.CreateSecret(secretName, value, application.ID)
+		if err != nil {
+			addLog("Failed to create secret for environment variable " + environmentVariable.Key + "\n")
+			return nil, err
+		}
+		secretMounts = append(secretMounts, containermanger.SecretMount{
+			SecretName: secretName,
+			Target:     environmentVariable.Key,
+		})
+		environmentVariablesMap[environmentVariable.Key+"_FILE"] = "/run/secrets/" + environmentVariable.Key
+	}
+
+	// fetch persistent volumes
+	persistentVolumeBindings, err := core.FindPersistentVolumeBindingsByApplicationId(ctx, db, application.ID)
+	if err != nil {
+		addLog("Failed to fetch persistent volumes\n")
+		return nil, err
+	}
+	var volumeMounts = make([]containermanger.VolumeMount, 0)
+	for _, persistentVolumeBinding := range persistentVolumeBindings {
+		// fetch the volume
+		var persistentVolume core.PersistentVolume
+		err := persistentVolume.FindById(ctx, dbWithoutTx, persistentVolumeBinding.PersistentVolumeID)
+		if err != nil {
+			addLog("Failed to fetch persistent volume\n")
+			return nil, err
+		}
+		volumeMounts = append(volumeMounts, containermanger.VolumeMount{
+			Source:   persistentVolume.Name,
+			Target:   persistentVolumeBinding.MountingPath,
+			ReadOnly: false,
+		})
+	}
+	sysctls := make(map[string]string)
+	for _, sysctl := range application.Sysctls {
+		sysctlPart := strings.SplitN(sysctl, "=", 2)
+		if len(sysctlPart) == 2 {
+			sysctls[sysctlPart[0]] = sysctlPart[1]
+		}
+	}
+	command := make([]string, 0)
+	if application.Command != "" {
+		command = strings.Split(application.Command, " ")
+	}
+	// docker image info
+	dockerImageUri := deployment.DeployableDockerImageURI(m.Config.ImageRegistryURI())
+	refetchImage := false
+	imageRegistryUsername := m.Config.ImageRegistryUsername()
+	imageRegistryPassword := m.Config.ImageRegistryPassword()
+
+	if deployment.UpstreamType == core.UpstreamTypeImage {
+		// fetch image registry credential
+		if deployment.ImageRegistryCredentialID != nil && *deployment.ImageRegistryCredentialID != 0 {
+			var imageRegistryCredential core.ImageRegistryCredential
+			err := imageRegistryCredential.FindById(ctx, dbWithoutTx, *deployment.ImageRegistryCredentialID)
+			if err != nil {
+				addLog("Failed to fetch image registry credential\n")
+				return nil, err
+			}
+			imageRegistryUsername = imageRegistryCredential.Username
+			imageRegistryPassword = imageRegistryCredential.Password
+		} else {
+			imageRegistryUsername = ""
+			imageRegistryPassword = ""
+		}
+		refetchImage = true
+	}
+
+	if refetchImage {
+		addLog("[Notice] Image will be fetched from remote during deployment\n")
+	}
+	// create the configs if required
+	configMountRecord, err := core.FindConfigMountsByApplicationId(ctx, db, application.ID)
+	if err != nil {
+		addLog("Failed to fetch config mounts")
+		return nil, err
+	}
+	for _, configMount := range configMountRecord {
+		if strings.Compare(configMount.ConfigID, "") == 0 {
+			// create config
+			configID, err := dockerManager.CreateConfig(configMount.Content, configMount.ApplicationID)
+			if err != nil {
+				addLog("Failed to create config")
+				return nil, err
+			}
+			// update config id
+			err = configMount.UpdateConfigID(ctx, dbWithoutTx, configID)
+			if err != nil {
+				addLog("Failed to update config id in database")
+				return nil, err
+			}
+		}
+	}
+	// prepare config mounts
+	var configMounts = make([]containermanger.ConfigMount, 0)
+	for _, configMount := range configMountRecord {
+		configMounts = append(configMounts, containermanger.ConfigMount{
+			ConfigID:     configMount.ConfigID,
+			Uid:          configMount.Uid,
+			Gid:          configMount.Gid,
+			FileMode:     configMount.FileMode,
+			MountingPath: configMount.MountingPath,
+		})
+	}
+	// prepare placement constraints
+	var placementConstraints = make([]string, 0)
+	disabledServerHostnames, err := core.FetchDisabledDeploymentServerHostNames(&m.ServiceManager.DbClient)
+	if err != nil {
+		addLog("Failed to fetch disabled deployment servers\nPlease check database connection\n")
+		return nil, err
+	}
+	for _, hostname := range disabledServerHostnames {
+		placementConstraints = append(placementConstraints, "node.hostname!="+hostname)
+	}
+	for _, preferredServerHostName := range application.PreferredServerHostnames {
+		// if it's not a disabled server, add it to the placement constraints
+		isDisabled := false
+		for _, hostname := range disabledServerHostnames {
+			if strings.Compare(hostname, preferredServerHostName) == 0 {
+				isDisabled = true
+				break
+			}
+		}
+		if !isDisabled {
+			placementConstraints = append(placementConstraints, "node.hostname=="+preferredServerHostName)
+		}
+	}
+	return &applicationServiceSpec{
+		service: containermanger.Service{
+			Name:                 application.Name,
+			Image:                dockerImageUri,
+			Hostname:             application.Hostname,
+			Command:              command,
+			Env:                  environmentVariablesMap,
+			Networks:             []string{m.Config.SystemConfig.NetworkName},
+			DeploymentMode:       containermanger.DeploymentMode(application.DeploymentMode),
+			Replicas:             uint64(application.ReplicaCount()),
+			VolumeMounts:         volumeMounts,
+			ConfigMounts:         configMounts,
+			SecretMounts:         secretMounts,
+			Capabilities:         application.Capabilities,
+			Sysctls:              sysctls,
+			PlacementConstraints: placementConstraints,
+			ResourceLimit: containermanger.Resource{
+				MemoryMB:  application.ResourceLimit.MemoryMB,
+				NanoCPUs:  application.ResourceLimit.NanoCPUs,
+				PidsLimit: application.ResourceLimit.PidsLimit,
+			},
+			ReservedResource: containermanger.Resource{
+				MemoryMB: application.ReservedResource.MemoryMB,
+				NanoCPUs: application.ReservedResource.NanoCPUs,
+			},
+			CustomHealthCheck: containermanger.CustomHealthCheck{
+				Enabled:              application.CustomHealthCheck.Enabled,
+				TestCommand:          application.CustomHealthCheck.TestCommand,
+				IntervalSeconds:      application.CustomHealthCheck.IntervalSeconds,
+				TimeoutSeconds:       application.CustomHealthCheck.TimeoutSeconds,
+				StartPeriodSeconds:   application.CustomHealthCheck.StartPeriodSeconds,
+				StartIntervalSeconds: application.CustomHealthCheck.StartIntervalSeconds,
+				Retries:              application.CustomHealthCheck.Retries,
+			},
+		},
+		imageRegistryUsername: imageRegistryUsername,
+		imageRegistryPassword: imageRegistryPassword,
+		refetchImage:          refetchImage,
+	}, nil
+}
+
 // private functions
 func ingressRuleProtocolToBackendProtocol(protocol core.ProtocolType) haproxymanager.BackendProtocol {
 	if protocol == core.HTTPProtocol || protocol == core.HTTPSProtocol {
@@ -432,6 +526,17 @@ func ingressRuleProtocolToBackendProtocol(protocol core.ProtocolType) haproxyman
 	return haproxymanager.HTTPBackend
 }
 
+// configureWakeUpServer : add the wake-up server in the backend if the application is sleeping, otherwise remove it
+func (m Manager) configureWakeUpServer(haproxyManager *haproxymanager.Manager, transactionId string, backendName string, application *core.Application) error {
+	if !application.IsSleeping {
+		return haproxyManager.DisableWakeUpServer(transactionId, backendName)
+	}
+	return haproxyManager.EnableWakeUpServer(transactionId, backendName, application.Name,
+		m.Config.LocalConfig.ManagementNodeAddressConsideringTunnelling(),
+		m.Config.LocalConfig.ManagementNodePortConsideringTunnelling(),
+		m.Config.LocalConfig.ServiceConfig.UseTLS)
+}
+
 func isHAProxyAccessRequired(ingressRule *core.IngressRule) bool {
 	if ingressRule.Protocol == core.HTTPProtocol || ingressRule.Protocol == core.HTTPSProtocol || ingressRule.Protocol == core.TCPProtocol {
 		return true
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/declarative_state"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+// SyncApplicationGroup : sync the application group from git repository on request of the user
+// Failure is recorded in the sync status of the group, so the task is not retried
+func (m Manager) SyncApplicationGroup(request SyncApplicationGroupRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	group := &core.ApplicationGroup{}
+	err := group.FindById(ctx, dbWithoutTx, request.ApplicationGroupId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !group.GitOps.Enabled {
+		return nil
+	}
+	swarmManagerServer, err := core.FetchSwarmManager(&dbWithoutTx)
+	if err != nil {
+		return err
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManagerServer)
+	if err != nil {
+		return err
+	}
+	restrictedPorts := make([]int, 0)
+	for _, port := range m.Config.SystemConfig.RestrictedPorts {
+		restrictedPorts = append(restrictedPorts, int(port))
+	}
+	_, err = declarative_state.SyncApplicationGroup(ctx, declarative_state.GitOpsSyncOptions{
+		ApplyOptions: declarative_state.ApplyOptions{
+			DbClient:        dbWithoutTx,
+			DockerManager:   dockerManager,
+			TaskEnqueuer:    m,
+			RestrictedPorts: restrictedPorts,
+			CodeTarballDir:  m.Config.LocalConfig.ServiceConfig.TarballDirectoryPath,
+		},
+		ServiceManager:   *m.ServiceManager,
+		SwiftwaveVersion: m.Config.LocalConfig.Version,
+		Force:            true,
+	}, group)
+	if err != nil {
+		log.Println("failed to sync application group "+group.Name, err)
+	}
+	return nil
+}
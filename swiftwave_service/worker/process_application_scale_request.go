@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"gorm.io/gorm"
+)
+
+// ScaleApplication : scale the replicas of the application as decided by auto scaling
+// The replicas are saved before the service is scaled, and restored if the scaling fails
+// Once the service is scaled, the scaling is kept even if the proxies fail to update, their update is retried separately
+func (m Manager) ScaleApplication(request ScaleApplicationRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	application := &core.Application{}
+	err := application.FindById(ctx, dbWithoutTx, request.AppId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// the decision is stale if auto scaling is disabled or the replicas are changed meanwhile
+	if !application.AutoScaling.Enabled || application.Replicas != request.FromReplicas || request.FromReplicas == request.ToReplicas {
+		return nil
+	}
+	// don't interfere with an ongoing deployment
+	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, dbWithoutTx, application.ID)
+	if err != nil {
+		return err
+	}
+	if latestDeployment.Status != core.DeploymentStatusDeployed {
+		return nil
+	}
+	dockerManager, haproxyManagers, err := m.fetchDeploymentManagers(ctx)
+	if err != nil {
+		return err
+	}
+	err = application.UpdateReplicas(ctx, dbWithoutTx, request.ToReplicas)
+	if err != nil {
+		return err
+	}
+	err = dockerManager.SetServiceReplicaCount(application.Name, int(request.ToReplicas))
+	if err != nil {
+		restoreErr := application.UpdateReplicas(ctx, dbWithoutTx, request.FromReplicas)
+		if restoreErr != nil {
+			log.Println("failed to restore replicas of application "+application.Name, restoreErr)
+		}
+		return err
+	}
+	err = updateBackendReplicas(ctx, dbWithoutTx, haproxyManagers, application.Name, application.ID, int(request.ToReplicas))
+	if err != nil {
+		log.Println("failed to update proxies after scaling of application "+application.Name+", retrying separately", err)
+		return m.EnqueueUpdateBackendReplicasRequest(request)
+	}
+	recordAutoScalingEvent(ctx, dbWithoutTx, application, request)
+	return nil
+}
+
+// UpdateBackendReplicas : retry the update of the proxies after the auto scaling of the application
+// The scaling event is recorded once the proxies are updated
+func (m Manager) UpdateBackendReplicas(request UpdateBackendReplicasRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	application := &core.Application{}
+	err := application.FindById(ctx, dbWithoutTx, request.AppId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// replicas changed meanwhile, the proxies are updated by the newer scaling or deployment
+	if application.Replicas != request.ToReplicas {
+		return nil
+	}
+	_, haproxyManagers, err := m.fetchDeploymentManagers(ctx)
+	if err != nil {
+		return err
+	}
+	err = updateBackendReplicas(ctx, dbWithoutTx, haproxyManagers, application.Name, application.ID, int(application.ReplicaCount()))
+	if err != nil {
+		return err
+	}
+	recordAutoScalingEvent(ctx, dbWithoutTx, application, ScaleApplicationRequest(request))
+	return nil
+}
+
+// recordAutoScalingEvent : keep the history of the scaling of the application
+func recordAutoScalingEvent(ctx context.Context, db gorm.DB, application *core.Application, request ScaleApplicationRequest) {
+	event := &core.AutoScalingEvent{
+		ApplicationID: application.ID,
+		FromReplicas:  request.FromReplicas,
+		ToReplicas:    request.ToReplicas,
+		Metric:        application.AutoScaling.Metric,
+		UsagePercent:  request.UsagePercent,
+		TargetPercent: application.AutoScaling.TargetPercent,
+		CreatedAt:     time.Now(),
+	}
+	err := event.Create(ctx, db)
+	if err != nil {
+		log.Println("failed to record auto scaling event of application "+application.Name, err)
+	}
+	log.Println("Application", application.Name, "scaled from", event.FromReplicas, "to", event.ToReplicas, "replicas by", event.Metric, "usage", event.UsagePercent, "%")
+}
+
+// updateBackendReplicas : update the replicas of the backends of the application in all the proxies
+func updateBackendReplicas(ctx context.Context, db gorm.DB, haproxyManagers []*haproxymanager.Manager, applicationName string, applicationId string, replicas int) error {
+	ingressRules, err := core.FetchIngressRulesWithTargetPortAndProtocolOnly(ctx, db, applicationId)
+	if err != nil {
+		return err
+	}
+	var updateErr error
+	for _, haproxyManager := range haproxyManagers {
+		transactionId, err := haproxyManager.FetchNewTransactionId()
+		if err != nil {
+			updateErr = errors.Join(updateErr, err)
+			continue
+		}
+		isFailed := false
+		for _, ingressRule := range ingressRules {
+			var backendProtocol haproxymanager.BackendProtocol
+			if ingressRule.Protocol == core.HTTPProtocol || ingressRule.Protocol == core.HTTPSProtocol {
+				backendProtocol = haproxymanager.HTTPBackend
+			} else if ingressRule.Protocol == core.TCPProtocol {
+				backendProtocol = haproxymanager.TCPBackend
+			} else {
+				continue
+			}
+			backendName := haproxyManager.GenerateBackendName(backendProtocol, applicationName, int(ingressRule.TargetPort))
+			isBackendExist, err := haproxyManager.IsBackendExist(transactionId, backendName)
+			if err != nil {
+				updateErr = errors.Join(updateErr, err)
+				isFailed = true
+				continue
+			}
+			if !isBackendExist {
+				continue
+			}
+			err = haproxyManager.UpdateBackendReplicas(transactionId, backendProtocol, applicationName, int(ingressRule.TargetPort), replicas)
+			if err != nil {
+				updateErr = errors.Join(updateErr, err)
+				isFailed = true
+			}
+		}
+		if !isFailed {
+			err = haproxyManager.CommitTransaction(transactionId)
+			if err == nil {
+				continue
+			}
+			updateErr = errors.Join(updateErr, err)
+		}
+		err = haproxyManager.DeleteTransaction(transactionId)
+		if err != nil {
+			updateErr = errors.Join(updateErr, err)
+		}
+	}
+	return updateErr
+}
@@ -0,0 +1,243 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"gorm.io/gorm"
+)
+
+// PromoteDeployment : route all the traffic to the blue/green or canary deployment, update the service with it and remove the parallel service
+func (m Manager) PromoteDeployment(request PromoteDeploymentRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	pubSubClient := m.ServiceManager.PubSubClient
+	application, deployment, err := m.fetchCanaryDeployment(ctx, request.AppId, request.DeploymentId)
+	if err != nil || application == nil {
+		return err
+	}
+	dockerManager, haproxyManagers, err := m.fetchDeploymentManagers(ctx)
+	if err != nil {
+		return err
+	}
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Promoting deployment, routing all the traffic to it\n", false)
+	err = m.updateCanaryTraffic(application, haproxyManagers, func(haproxyManager *haproxymanager.Manager, transactionId string, backendProtocol haproxymanager.BackendProtocol, port int) error {
+		replicas := int(application.ReplicaCount())
+		return haproxyManager.SetCanaryTraffic(transactionId, backendProtocol, application.Name, application.CanaryServiceName(), port, replicas, replicas, 100)
+	})
+	if err != nil {
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Failed to route traffic to the deployment > "+err.Error()+"\n", false)
+		return err
+	}
+	// update the service in place, the parallel service keeps serving the requests meanwhile
+	err = m.deployApplicationHelper(DeployApplicationRequest{
+		AppId:                    application.ID,
+		DeploymentId:             deployment.ID,
+		IgnoreDeploymentStrategy: true,
+	}, dockerManager, haproxyManagers)
+	if err != nil {
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Promotion failed > "+err.Error()+"\n", false)
+		m.removeParallelDeployment(ctx, application, deployment, dockerManager, haproxyManagers, core.DeploymentStatusFailed)
+		return nil
+	}
+	// wait for the service before removing the parallel service
+	// the service is already updated, so the parallel service is removed even if the wait is cancelled
+	window := deploymentVerificationWindow(application.CustomHealthCheck)
+	deadline := time.Now().Add(window)
+waitForService:
+	for time.Now().Before(deadline) {
+		convergence, err := dockerManager.ServiceConvergence(application.Name)
+		if err == nil && (convergence.IsConverged() || convergence.IsFailed()) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break waitForService
+		case <-time.After(deploymentVerificationPollInterval):
+		}
+	}
+	m.removeParallelDeployment(ctx, application, nil, dockerManager, haproxyManagers, "")
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, "Deployment promoted successfully\n", true)
+	return nil
+}
+
+// AbortDeployment : route all the traffic back to the deployed deployment and remove the parallel service
+func (m Manager) AbortDeployment(request AbortDeploymentRequest, ctx context.Context, _ context.CancelFunc) error {
+	application, deployment, err := m.fetchCanaryDeployment(ctx, request.AppId, request.DeploymentId)
+	if err != nil || application == nil {
+		return err
+	}
+	dockerManager, haproxyManagers, err := m.fetchDeploymentManagers(ctx)
+	if err != nil {
+		return err
+	}
+	m.removeParallelDeployment(ctx, application, deployment, dockerManager, haproxyManagers, core.DeploymentStatusStopped)
+	addPersistentDeploymentLog(m.ServiceManager.DbClient, m.ServiceManager.PubSubClient, deployment.ID, "Deployment aborted\n", true)
+	return nil
+}
+
+// isParallelDeploymentRequired : blue/green or canary strategy is used only when there is a deployed deployment serving the traffic
+func isParallelDeploymentRequired(request DeployApplicationRequest, application *core.Application, previousDeploymentId string, dockerManager *containermanger.Manager) bool {
+	if !application.DeploymentStrategy.IsParallel() {
+		return false
+	}
+	// automatic rollbacks, promotions and re-deployments due to server changes update the service in place
+	if request.IgnoreDeploymentStrategy || request.SkipVerification || request.IgnoreProxyUpdate {
+		return false
+	}
+	if previousDeploymentId == "" || previousDeploymentId == request.DeploymentId || application.ReplicaCount() == 0 {
+		return false
+	}
+	_, err := dockerManager.GetService(application.Name)
+	return err == nil
+}
+
+// deployParallelApplicationHelper : deploy the new deployment as a separate service and register it in the backends of the application
+func (m Manager) deployParallelApplicationHelper(ctx context.Context, db *gorm.DB, application *core.Application, deployment *core.Deployment, service containermanger.Service, imageRegistryUsername string, imageRegistryPassword string, refetchImage bool, dockerManager *containermanger.Manager, haproxyManagers []*haproxymanager.Manager) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	pubSubClient := m.ServiceManager.PubSubClient
+	// a new deployment replaces the running canary
+	err := core.MarkCanaryDeploymentsAsStalled(ctx, *db, application.ID)
+	if err != nil {
+		return err
+	}
+	err = deployment.UpdateStatus(ctx, *db, core.DeploymentStatusCanary)
+	if err != nil {
+		return err
+	}
+	service.Name = application.CanaryServiceName()
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, fmt.Sprintf("Deploying as %s deployment in parallel of the current deployment\n", application.DeploymentStrategy.Type), false)
+	_, err = dockerManager.GetService(service.Name)
+	if err != nil {
+		err = dockerManager.CreateService(service, imageRegistryUsername, imageRegistryPassword, refetchImage)
+	} else {
+		err = dockerManager.UpdateService(service, imageRegistryUsername, imageRegistryPassword, refetchImage)
+	}
+	if err != nil {
+		return err
+	}
+	err = db.Commit().Error
+	if err != nil {
+		m.removeParallelDeployment(ctx, application, nil, dockerManager, haproxyManagers, "")
+		return err
+	}
+	trafficPercent := application.DeploymentStrategy.InitialTrafficPercent()
+	err = m.updateCanaryTraffic(application, haproxyManagers, func(haproxyManager *haproxymanager.Manager, transactionId string, backendProtocol haproxymanager.BackendProtocol, port int) error {
+		replicas := int(application.ReplicaCount())
+		return haproxyManager.SetCanaryTraffic(transactionId, backendProtocol, application.Name, service.Name, port, replicas, replicas, trafficPercent)
+	})
+	if err != nil {
+		m.removeParallelDeployment(ctx, application, nil, dockerManager, haproxyManagers, "")
+		return errors.New("failed to route traffic to the deployment > " + err.Error())
+	}
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, fmt.Sprintf("Deployment is running in parallel with %d%% of the traffic, promote or abort it\n", trafficPercent), true)
+	return nil
+}
+
+// removeParallelDeployment : remove the parallel service and its servers from the backends
+// If deployment is provided, it will be marked with the given status
+func (m Manager) removeParallelDeployment(ctx context.Context, application *core.Application, deployment *core.Deployment, dockerManager *containermanger.Manager, haproxyManagers []*haproxymanager.Manager, status core.DeploymentStatus) {
+	err := m.updateCanaryTraffic(application, haproxyManagers, func(haproxyManager *haproxymanager.Manager, transactionId string, backendProtocol haproxymanager.BackendProtocol, port int) error {
+		return haproxyManager.RemoveCanaryTraffic(transactionId, backendProtocol, application.Name, application.CanaryServiceName(), port, int(application.ReplicaCount()))
+	})
+	if err != nil {
+		log.Println("failed to remove canary traffic of "+application.Name, err)
+	}
+	err = dockerManager.RemoveService(application.CanaryServiceName())
+	if err != nil {
+		log.Println("failed to remove canary service of "+application.Name, err)
+	}
+	if deployment != nil {
+		err = deployment.UpdateStatus(ctx, m.ServiceManager.DbClient, status)
+		if err != nil {
+			log.Println("failed to update deployment status", err)
+		}
+	}
+}
+
+// updateCanaryTraffic : run the update on the backends of all the ingress rules of the application in a single transaction per proxy
+func (m Manager) updateCanaryTraffic(application *core.Application, haproxyManagers []*haproxymanager.Manager, update func(haproxyManager *haproxymanager.Manager, transactionId string, backendProtocol haproxymanager.BackendProtocol, port int) error) error {
+	ingressRules, err := core.FetchIngressRulesWithTargetPortAndProtocolOnly(context.Background(), m.ServiceManager.DbClient, application.ID)
+	if err != nil {
+		return err
+	}
+	for _, haproxyManager := range haproxyManagers {
+		transactionId, err := haproxyManager.FetchNewTransactionId()
+		if err != nil {
+			return err
+		}
+		for _, record := range ingressRules {
+			if record.Protocol == core.UDPProtocol {
+				continue
+			}
+			err = update(haproxyManager, transactionId, ingressRuleProtocolToBackendProtocol(record.Protocol), int(record.TargetPort))
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = haproxyManager.CommitTransaction(transactionId)
+		}
+		if err != nil {
+			deleteErr := haproxyManager.DeleteTransaction(transactionId)
+			if deleteErr != nil {
+				log.Println("failed to rollback haproxy transaction", deleteErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchCanaryDeployment : returns nil application if the application or the deployment doesn't exist anymore, or the deployment is not waiting for promotion
+func (m Manager) fetchCanaryDeployment(ctx context.Context, applicationId string, deploymentId string) (*core.Application, *core.Deployment, error) {
+	dbWithoutTx := m.ServiceManager.DbClient
+	application := &core.Application{}
+	err := application.FindById(ctx, dbWithoutTx, applicationId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	deployment := &core.Deployment{}
+	err = deployment.FindById(ctx, dbWithoutTx, deploymentId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if deployment.Status != core.DeploymentStatusCanary {
+		return nil, nil, nil
+	}
+	return application, deployment, nil
+}
+
+// fetchDeploymentManagers : docker manager of the swarm manager and haproxy managers of all the active proxies
+func (m Manager) fetchDeploymentManagers(ctx context.Context) (*containermanger.Manager, []*haproxymanager.Manager, error) {
+	swarmManager, err := core.FetchSwarmManager(&m.ServiceManager.DbClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManager)
+	if err != nil {
+		return nil, nil, err
+	}
+	proxyServers, err := core.FetchProxyActiveServers(&m.ServiceManager.DbClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	haproxyManagers, err := manager.HAProxyClients(ctx, proxyServers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dockerManager, haproxyManagers, nil
+}
@@ -0,0 +1,240 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+const (
+	// cronJobRunCheckInterval : interval between the checks of the job of a run
+	cronJobRunCheckInterval = 5 * time.Second
+	// cronJobRunMaxLogSize : only the tail of the logs of a run is stored
+	cronJobRunMaxLogSize = 1024 * 1024
+)
+
+// RunCronJob : run the deployment of the cron job application as a one-off swarm job and store the result
+func (m Manager) RunCronJob(request RunCronJobRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	run := &core.CronJobRun{}
+	err := run.FindById(ctx, dbWithoutTx, request.RunId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// cancelled before it started
+	if run.Status != core.CronJobRunStatusPending {
+		return nil
+	}
+	var application core.Application
+	err = application.FindById(ctx, dbWithoutTx, run.ApplicationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	deployment := &core.Deployment{}
+	err = deployment.FindById(ctx, dbWithoutTx, run.DeploymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return run.MarkAsFinished(ctx, dbWithoutTx, core.CronJobRunStatusFailed, -1, "deployment not found", "")
+		}
+		return err
+	}
+	swarmManager, err := core.FetchSwarmManager(&dbWithoutTx)
+	if err != nil {
+		return err
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManager)
+	if err != nil {
+		return err
+	}
+	// the messages of the preparation are reported only if the run fails to start
+	var messages strings.Builder
+	applicationService, err := m.prepareApplicationService(ctx, dbWithoutTx, &application, deployment, dockerManager, func(content string) {
+		messages.WriteString(content)
+	})
+	if err != nil {
+		return run.MarkAsFinished(ctx, dbWithoutTx, core.CronJobRunStatusFailed, -1, strings.TrimSpace(messages.String()+" "+err.Error()), "")
+	}
+	service := applicationService.service
+	service.Name = run.ServiceName()
+	service.DeploymentMode = containermanger.DeploymentModeReplicatedJob
+	service.Replicas = 1
+	err = dockerManager.CreateService(service, applicationService.imageRegistryUsername, applicationService.imageRegistryPassword, applicationService.refetchImage)
+	if err != nil {
+		return run.MarkAsFinished(ctx, dbWithoutTx, core.CronJobRunStatusFailed, -1, "failed to start the run > "+err.Error(), "")
+	}
+	err = run.MarkAsRunning(ctx, dbWithoutTx)
+	if err != nil {
+		log.Println("failed to mark cron job run as running", err)
+	}
+	// the worker is not held till the job completes, the job is checked periodically instead
+	return m.EnqueueCheckCronJobRunRequest(run.ApplicationID, run.ID, 1)
+}
+
+// CheckCronJobRun : store the result of the run once its job completes, otherwise check again after a while
+func (m Manager) CheckCronJobRun(request CheckCronJobRunRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	run := &core.CronJobRun{}
+	err := run.FindById(ctx, dbWithoutTx, request.RunId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	swarmManager, err := core.FetchSwarmManager(&dbWithoutTx)
+	if err != nil {
+		return err
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManager)
+	if err != nil {
+		return err
+	}
+	serviceName := run.ServiceName()
+	// the run has been cancelled in the meantime
+	if run.IsFinished() {
+		_ = dockerManager.RemoveService(serviceName)
+		return nil
+	}
+	status, err := dockerManager.JobStatus(serviceName)
+	if err != nil {
+		// can be a temporary issue with the swarm manager
+		log.Println("failed to fetch job status of "+serviceName, err)
+	}
+	if err != nil {
+		return m.EnqueueCheckCronJobRunRequest(run.ApplicationID, run.ID, request.Check+1)
+	}
+	if !status.Completed {
+		// job service can be removed outside of swiftwave, then the run never completes
+		_, err = dockerManager.GetService(serviceName)
+		if err != nil {
+			return run.MarkAsFinished(ctx, dbWithoutTx, core.CronJobRunStatusFailed, -1, "job service of the run not found", "")
+		}
+		return m.EnqueueCheckCronJobRunRequest(run.ApplicationID, run.ID, request.Check+1)
+	}
+	logs, err := dockerManager.JobLogs(serviceName, cronJobRunMaxLogSize)
+	if err != nil {
+		log.Println("failed to fetch logs of "+serviceName, err)
+	}
+	runStatus := core.CronJobRunStatusFailed
+	if status.Succeeded {
+		runStatus = core.CronJobRunStatusSucceeded
+	}
+	err = run.MarkAsFinished(ctx, dbWithoutTx, runStatus, status.ExitCode, status.Message, logs)
+	if err != nil {
+		return err
+	}
+	err = dockerManager.RemoveService(serviceName)
+	if err != nil {
+		log.Println("failed to remove job service "+serviceName, err)
+	}
+	var application core.Application
+	err = application.FindById(ctx, dbWithoutTx, run.ApplicationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return core.DeleteOldCronJobRuns(ctx, dbWithoutTx, application.ID, application.CronJob.HistoryLimit)
+}
+
+// TriggerCronJob : create a run of the deployed deployment of the cron job application as per its concurrency policy
+// Returns nil run if the run is skipped as the previous run is still running
+func (m Manager) TriggerCronJob(ctx context.Context, application *core.Application, trigger core.CronJobRunTrigger) (*core.CronJobRun, error) {
+	dbWithoutTx := m.ServiceManager.DbClient
+	if !application.IsCronJob() {
+		return nil, errors.New("application is not a cron job")
+	}
+	deployment, err := core.FindCurrentDeployedDeploymentByApplicationId(ctx, dbWithoutTx, application.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cron job has no deployed deployment")
+		}
+		return nil, err
+	}
+	activeRuns, err := core.FindActiveCronJobRunsByApplicationId(ctx, dbWithoutTx, application.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(activeRuns) > 0 {
+		switch application.CronJob.ConcurrencyPolicy {
+		case core.CronJobConcurrencyAllow:
+		case core.CronJobConcurrencyReplace:
+			for _, activeRun := range activeRuns {
+				err = m.CancelCronJobRun(ctx, activeRun, "replaced by a new run")
+				if err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, nil
+		}
+	}
+	run := &core.CronJobRun{
+		ApplicationID: application.ID,
+		DeploymentID:  deployment.ID,
+		Trigger:       trigger,
+	}
+	err = run.Create(ctx, dbWithoutTx)
+	if err != nil {
+		return nil, err
+	}
+	err = m.EnqueueRunCronJobRequest(run.ApplicationID, run.ID)
+	if err != nil {
+		_ = run.MarkAsFinished(ctx, dbWithoutTx, core.CronJobRunStatusFailed, -1, "failed to enqueue the run", "")
+		return nil, err
+	}
+	return run, nil
+}
+
+// CancelCronJobRun : mark the run as cancelled and remove its job service
+func (m Manager) CancelCronJobRun(ctx context.Context, run *core.CronJobRun, reason string) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	if run.IsFinished() {
+		return errors.New("run has already finished")
+	}
+	var application core.Application
+	err := application.FindById(ctx, dbWithoutTx, run.ApplicationID)
+	if err != nil {
+		return err
+	}
+	err = run.MarkAsFinished(ctx, dbWithoutTx, core.CronJobRunStatusCancelled, -1, reason, "")
+	if err != nil {
+		return err
+	}
+	swarmManager, err := core.FetchSwarmManager(&dbWithoutTx)
+	if err != nil {
+		return err
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManager)
+	if err != nil {
+		return err
+	}
+	// the service doesn't exist if the run has not started yet
+	_ = dockerManager.RemoveService(run.ServiceName())
+	return nil
+}
+
+// deployCronJobHelper : cron job doesn't have a long-running service, the deployment is marked as deployed to be used by the next runs
+func (m Manager) deployCronJobHelper(db *gorm.DB, application *core.Application, deployment *core.Deployment) error {
+	err := db.Commit().Error
+	if err != nil {
+		return err
+	}
+	addPersistentDeploymentLog(m.ServiceManager.DbClient, m.ServiceManager.PubSubClient, deployment.ID, fmt.Sprintf("Cron job deployed successfully, it will run as per the schedule `%s`\n", application.CronJob.Schedule), true)
+	return nil
+}
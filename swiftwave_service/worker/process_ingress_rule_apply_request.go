@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	udpproxymanager "github.com/swiftwave-org/swiftwave/pkg/udp_proxy_manager"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
-	udpproxymanager "github.com/swiftwave-org/swiftwave/pkg/udp_proxy_manager"
 	"gorm.io/gorm"
 	"log"
 )
@@ -45,10 +45,11 @@ func (m Manager) IngressRuleApply(request IngressRuleApplyRequest, ctx context.C
 	// service name
 	serviceName := ""
 	var serviceReplicas uint = 1
+	var application *core.Application
 
 	if ingressRule.TargetType == core.ApplicationIngressRule {
 		// fetch application
-		application := &core.Application{}
+		application = &core.Application{}
 		err = application.FindById(ctx, dbWithoutTx, *ingressRule.ApplicationID)
 		if err != nil {
 			return err
@@ -113,6 +114,14 @@ func (m Manager) IngressRuleApply(request IngressRuleApplyRequest, ctx context.C
 			isFailed = true
 			break
 		}
+		// route the requests to the wake-up handler, if the application is sleeping
+		if application != nil && backendProtocol == haproxymanager.HTTPBackend {
+			err = m.configureWakeUpServer(haproxyManager, haproxyTransactionId, backendName, application)
+			if err != nil {
+				isFailed = true
+				break
+			}
+		}
 		// add frontend
 		if ingressRule.Protocol == core.HTTPSProtocol {
 			err = haproxyManager.AddHTTPSLink(haproxyTransactionId, backendName, domain.Name)
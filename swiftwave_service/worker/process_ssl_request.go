@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	haproxymanager "github.com/swiftwave-org/swiftwave/pkg/haproxy_manager"
+	ssl "github.com/swiftwave-org/swiftwave/pkg/ssl_manager"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/logger"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
@@ -33,8 +34,10 @@ func (m Manager) SSLGenerate(request SSLGenerateRequest, ctx context.Context, _
 	if domain.IsIPv4() {
 		return nil
 	}
+	// with dns-01 challenge, domain doesn't need to point to this server
+	useDNSChallenge := domain.DNSProvider.IsConfigured()
 	// verify domain points to this server
-	isDomainPointingToThisServer := m.ServiceManager.SslManager.VerifyDomain(domain.Name)
+	isDomainPointingToThisServer := useDNSChallenge || m.ServiceManager.SslManager.VerifyDomain(domain.Name)
 	if !isDomainPointingToThisServer {
 		if domain.SSLStatus == core.DomainSSLStatusNone {
 			// If SSL generation is invoked at the time of domain creation, don't mark it as failed if domain is not pointing to this server
@@ -44,8 +47,9 @@ func (m Manager) SSLGenerate(request SSLGenerateRequest, ctx context.Context, _
 		logger.CronJobLoggerError.Println("Domain", domain.Name, "is not pointing to this server. Marking SSL Issue as failed")
 		return nil
 	}
-	// generate private key [if not found]
-	if domain.SSLPrivateKey == "" || strings.Compare(domain.SSLIssuer, "Let's Encrypt") != 0 {
+	// generate private key [if not found or the current certificate is not issued by ACME CA]
+	isIssuedByACME := domain.SSLACMEDirectory != "" || strings.Compare(domain.SSLIssuer, "Let's Encrypt") == 0
+	if domain.SSLPrivateKey == "" || !isIssuedByACME {
 		privateKey, err := generatePrivateKey()
 		if err != nil {
 			return err
@@ -58,7 +62,12 @@ func (m Manager) SSLGenerate(request SSLGenerateRequest, ctx context.Context, _
 		}
 	}
 	// obtain certificate
-	fullChain, err := m.ServiceManager.SslManager.ObtainCertificate(domain.Name, domain.SSLPrivateKey)
+	var fullChain string
+	if useDNSChallenge {
+		fullChain, err = m.ServiceManager.SslManager.ObtainCertificateWithDNSChallenge(domain.Name, domain.SSLPrivateKey, dnsProviderOfDomain(domain))
+	} else {
+		fullChain, err = m.ServiceManager.SslManager.ObtainCertificate(domain.Name, domain.SSLPrivateKey)
+	}
 	if err != nil {
 		// don' requeue, if anything happen user can anytime re-request for certificate
 		logger.CronJobLoggerError.Println("Failed to obtain certificate", err.Error())
@@ -67,6 +76,7 @@ func (m Manager) SSLGenerate(request SSLGenerateRequest, ctx context.Context, _
 	}
 	// store certificate
 	domain.SSLFullChain = fullChain
+	domain.SSLACMEDirectory = m.ServiceManager.SslManager.FetchDirectoryURL()
 	// update status
 	domain.SSLStatus = core.DomainSSLStatusIssued
 	// enable auto renew
@@ -204,6 +214,25 @@ func (m Manager) SSLProxyUpdate(request SSLProxyUpdateRequest, ctx context.Conte
 }
 
 // private functions
+func dnsProviderOfDomain(domain core.Domain) ssl.DNS01Provider {
+	switch domain.DNSProvider.Type {
+	case core.DNSProviderRFC2136:
+		return ssl.RFC2136Provider{
+			Nameserver:    domain.DNSProvider.RFC2136Nameserver,
+			TSIGKeyName:   domain.DNSProvider.RFC2136TSIGKeyName,
+			TSIGSecret:    domain.DNSProvider.RFC2136TSIGSecret,
+			TSIGAlgorithm: domain.DNSProvider.RFC2136TSIGAlgorithm,
+		}
+	case core.DNSProviderWebhook:
+		return ssl.WebhookDNSProvider{
+			URL:    domain.DNSProvider.WebhookURL,
+			Secret: domain.DNSProvider.WebhookSecret,
+		}
+	default:
+		return nil
+	}
+}
+
 func generatePrivateKey() (string, error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/manager"
+	"gorm.io/gorm"
+)
+
+const (
+	// deploymentVerificationBaseWindow : time given to swarm to pull the image and start the tasks
+	deploymentVerificationBaseWindow   = 3 * time.Minute
+	deploymentVerificationPollInterval = 5 * time.Second
+)
+
+// deploymentVerificationDecision : outcome of a check of the deployment being verified
+type deploymentVerificationDecision int
+
+const (
+	// deploymentVerificationPending : service is still converging, check again after a while
+	deploymentVerificationPending deploymentVerificationDecision = iota
+	// deploymentVerificationSkipped : deployment has been replaced, nothing to verify anymore
+	deploymentVerificationSkipped
+	deploymentVerificationSucceeded
+	deploymentVerificationFailed
+)
+
+// VerifyDeployment : watch the rollout of a deployment
+// If the service doesn't converge within the verification window, mark the deployment as failed and restore the previous deployment
+func (m Manager) VerifyDeployment(request VerifyDeploymentRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	pubSubClient := m.ServiceManager.PubSubClient
+	// fetch application
+	var application core.Application
+	err := application.FindById(ctx, dbWithoutTx, request.AppId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// fetch the swarm server
+	swarmManager, err := core.FetchSwarmManager(&dbWithoutTx)
+	if err != nil {
+		return err
+	}
+	dockerManager, err := manager.DockerClient(ctx, swarmManager)
+	if err != nil {
+		return err
+	}
+	window := deploymentVerificationWindow(application.CustomHealthCheck)
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, fmt.Sprintf("Verifying deployment, waiting up to %s for all replicas to be running\n", window), false)
+
+	startedAt := time.Now()
+	var convergence containermanger.ServiceConvergence
+	for {
+		// stop if the deployment has been replaced by another deployment in the meantime
+		deployment := &core.Deployment{}
+		err = deployment.FindById(ctx, dbWithoutTx, request.DeploymentId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		// last fetched convergence is kept to report the running replicas in case of timeout
+		currentConvergence, convergenceErr := dockerManager.ServiceConvergence(application.Name)
+		if convergenceErr != nil {
+			// can be a temporary issue with the swarm manager, retry till the deadline
+			log.Println("failed to fetch service convergence of "+application.Name, convergenceErr)
+		} else {
+			convergence = currentConvergence
+		}
+		decision, reason := decideDeploymentVerification(deployment.Status, convergence, convergenceErr, time.Since(startedAt), window)
+		switch decision {
+		case deploymentVerificationSkipped:
+			return nil
+		case deploymentVerificationSucceeded:
+			addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, fmt.Sprintf("Deployment verified, %d/%d replicas running\n", convergence.RunningReplicas, convergence.DesiredReplicas), false)
+			return nil
+		case deploymentVerificationFailed:
+			return m.rollbackFailedDeployment(ctx, request, reason)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deploymentVerificationPollInterval):
+		}
+	}
+}
+
+// decideDeploymentVerification : decide the outcome of a check of the deployment being verified
+// Failure to fetch the convergence is tolerated till the end of the window, the last fetched convergence is reported then
+// Returns the failure reason for failed verification
+func decideDeploymentVerification(deploymentStatus core.DeploymentStatus, convergence containermanger.ServiceConvergence, convergenceErr error, elapsed time.Duration, window time.Duration) (deploymentVerificationDecision, string) {
+	// the deployment has been replaced by a newer deployment
+	if deploymentStatus != core.DeploymentStatusDeployed {
+		return deploymentVerificationSkipped, ""
+	}
+	if convergenceErr == nil {
+		if convergence.IsConverged() {
+			return deploymentVerificationSucceeded, ""
+		}
+		if convergence.IsFailed() {
+			failureReason := fmt.Sprintf("Service update %s", convergence.UpdateState)
+			if convergence.UpdateMessage != "" {
+				failureReason += " > " + convergence.UpdateMessage
+			}
+			return deploymentVerificationFailed, failureReason
+		}
+	}
+	if elapsed > window {
+		return deploymentVerificationFailed, fmt.Sprintf("Deployment didn't converge within %s, %d/%d replicas running", window, convergence.RunningReplicas, convergence.DesiredReplicas)
+	}
+	return deploymentVerificationPending, ""
+}
+
+// rollbackFailedDeployment : mark the deployment as failed and redeploy the previous deployment
+func (m Manager) rollbackFailedDeployment(ctx context.Context, request VerifyDeploymentRequest, failureReason string) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	pubSubClient := m.ServiceManager.PubSubClient
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Deployment verification failed > "+failureReason+"\n", false)
+	deployment := &core.Deployment{}
+	deployment.ID = request.DeploymentId
+	err := deployment.UpdateStatus(ctx, dbWithoutTx, core.DeploymentStatusFailed)
+	if err != nil {
+		return err
+	}
+	if request.PreviousDeploymentId == "" {
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "No previous deployment found to rollback\n", true)
+		return nil
+	}
+	// previous deployment can be deleted in the meantime
+	previousDeployment := &core.Deployment{}
+	err = previousDeployment.FindById(ctx, dbWithoutTx, request.PreviousDeploymentId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Previous deployment not found, skipping rollback\n", true)
+			return nil
+		}
+		return err
+	}
+	// the rollback is not verified, otherwise a broken previous deployment would cause an endless loop of rollbacks
+	err = m.EnqueueDeployApplicationRequestWithNoVerification(request.AppId, previousDeployment.ID)
+	if err != nil {
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Failed to enqueue rollback to previous deployment\n", true)
+		return err
+	}
+	addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Rolling back to previous deployment "+previousDeployment.ID+"\n", true)
+	return nil
+}
+
+// isDeploymentVerificationRequired : verification is skipped for automatic rollbacks, redeployment of the same deployment and stopped applications
+func isDeploymentVerificationRequired(request DeployApplicationRequest, application *core.Application, previousDeploymentId string) bool {
+	if request.SkipVerification || previousDeploymentId == request.DeploymentId {
+		return false
+	}
+	if application.DeploymentMode == core.DeploymentModeGlobal {
+		return true
+	}
+	return application.ReplicaCount() > 0
+}
+
+// deploymentVerificationWindow : base window + the time the health check needs to mark a task healthy or unhealthy
+func deploymentVerificationWindow(healthCheck core.ApplicationCustomHealthCheck) time.Duration {
+	window := deploymentVerificationBaseWindow
+	if !healthCheck.Enabled {
+		return window
+	}
+	// zero values fallback to the docker defaults
+	interval := time.Duration(healthCheck.IntervalSeconds) * time.Second
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+	timeout := time.Duration(healthCheck.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	retries := time.Duration(healthCheck.Retries)
+	if retries == 0 {
+		retries = 3
+	}
+	window += time.Duration(healthCheck.StartPeriodSeconds)*time.Second + (interval+timeout)*retries
+	return window
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+)
+
+func TestDeploymentVerificationWindow(t *testing.T) {
+	t.Run("without custom healthcheck", func(t *testing.T) {
+		assert.Equal(t, deploymentVerificationBaseWindow, deploymentVerificationWindow(core.ApplicationCustomHealthCheck{}))
+		// disabled healthcheck is ignored
+		assert.Equal(t, deploymentVerificationBaseWindow, deploymentVerificationWindow(core.ApplicationCustomHealthCheck{
+			Enabled:            false,
+			StartPeriodSeconds: 60,
+			IntervalSeconds:    10,
+		}))
+	})
+	t.Run("with custom healthcheck", func(t *testing.T) {
+		window := deploymentVerificationWindow(core.ApplicationCustomHealthCheck{
+			Enabled:            true,
+			StartPeriodSeconds: 60,
+			IntervalSeconds:    10,
+			TimeoutSeconds:     5,
+			Retries:            4,
+		})
+		assert.Equal(t, deploymentVerificationBaseWindow+60*time.Second+4*15*time.Second, window)
+	})
+	t.Run("with custom healthcheck using docker defaults", func(t *testing.T) {
+		window := deploymentVerificationWindow(core.ApplicationCustomHealthCheck{Enabled: true})
+		assert.Equal(t, deploymentVerificationBaseWindow+3*60*time.Second, window)
+	})
+}
+
+func TestDecideDeploymentVerification(t *testing.T) {
+	window := 3 * time.Minute
+	converged := containermanger.ServiceConvergence{DesiredReplicas: 2, RunningReplicas: 2, UpdateState: string(swarm.UpdateStateCompleted)}
+	converging := containermanger.ServiceConvergence{DesiredReplicas: 2, RunningReplicas: 1, UpdateState: string(swarm.UpdateStateUpdating)}
+
+	t.Run("converged", func(t *testing.T) {
+		decision, reason := decideDeploymentVerification(core.DeploymentStatusDeployed, converged, nil, time.Minute, window)
+		assert.Equal(t, deploymentVerificationSucceeded, decision)
+		assert.Empty(t, reason)
+		// new service has never been updated
+		decision, _ = decideDeploymentVerification(core.DeploymentStatusDeployed, containermanger.ServiceConvergence{DesiredReplicas: 1, RunningReplicas: 1}, nil, time.Minute, window)
+		assert.Equal(t, deploymentVerificationSucceeded, decision)
+	})
+	t.Run("converging within the window", func(t *testing.T) {
+		decision, reason := decideDeploymentVerification(core.DeploymentStatusDeployed, converging, nil, time.Minute, window)
+		assert.Equal(t, deploymentVerificationPending, decision)
+		assert.Empty(t, reason)
+	})
+	t.Run("timed out", func(t *testing.T) {
+		decision, reason := decideDeploymentVerification(core.DeploymentStatusDeployed, converging, nil, window+time.Second, window)
+		assert.Equal(t, deploymentVerificationFailed, decision)
+		assert.Equal(t, "Deployment didn't converge within 3m0s, 1/2 replicas running", reason)
+	})
+	t.Run("swarm rolled back the update", func(t *testing.T) {
+		convergence := containermanger.ServiceConvergence{DesiredReplicas: 2, RunningReplicas: 2, UpdateState: string(swarm.UpdateStateRollbackCompleted), UpdateMessage: "update rolled back due to failure"}
+		decision, reason := decideDeploymentVerification(core.DeploymentStatusDeployed, convergence, nil, time.Minute, window)
+		assert.Equal(t, deploymentVerificationFailed, decision)
+		assert.Equal(t, "Service update rollback_completed > update rolled back due to failure", reason)
+	})
+	t.Run("convergence unavailable", func(t *testing.T) {
+		decision, _ := decideDeploymentVerification(core.DeploymentStatusDeployed, converging, errors.New("swarm manager unreachable"), time.Minute, window)
+		assert.Equal(t, deploymentVerificationPending, decision)
+		// last fetched convergence is reported once the window is over
+		decision, reason := decideDeploymentVerification(core.DeploymentStatusDeployed, converging, errors.New("swarm manager unreachable"), window+time.Second, window)
+		assert.Equal(t, deploymentVerificationFailed, decision)
+		assert.Equal(t, "Deployment didn't converge within 3m0s, 1/2 replicas running", reason)
+	})
+	t.Run("superseded by a newer deployment", func(t *testing.T) {
+		for _, status := range []core.DeploymentStatus{core.DeploymentStatusStopped, core.DeploymentStatusFailed} {
+			decision, reason := decideDeploymentVerification(status, converging, nil, window+time.Second, window)
+			assert.Equal(t, deploymentVerificationSkipped, decision, status)
+			assert.Empty(t, reason, status)
+			decision, _ = decideDeploymentVerification(status, converged, nil, time.Minute, window)
+			assert.Equal(t, deploymentVerificationSkipped, decision, status)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+	"gorm.io/gorm"
+)
+
+const (
+	// dependenciesCheckInterval : interval between the checks of the dependencies of a deployment
+	dependenciesCheckInterval = 10 * time.Second
+	// maxDependenciesChecks : dependencies have 30 minutes to build and deploy
+	maxDependenciesChecks = 180
+)
+
+// dependenciesState : state of the latest deployments of the dependencies of an application
+type dependenciesState int
+
+const (
+	dependenciesDeployed dependenciesState = iota
+	dependenciesPending
+	dependenciesFailed
+)
+
+// WaitForDependencies : build the deployment once the latest deployments of its dependencies are deployed
+// The deployment fails if any dependency fails or the dependencies are not deployed in time
+func (m Manager) WaitForDependencies(request WaitForDependenciesRequest, ctx context.Context, _ context.CancelFunc) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	pubSubClient := m.ServiceManager.PubSubClient
+	// the deployment is dropped if the application has been updated or deleted in the meantime
+	latestDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, dbWithoutTx, request.AppId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if latestDeployment.ID != request.DeploymentId || latestDeployment.Status != core.DeploymentStatusPending {
+		return nil
+	}
+	// statuses of the latest deployments by the names of the dependencies
+	statuses := make(map[string]core.DeploymentStatus)
+	for _, dependencyAppId := range request.DependencyAppIds {
+		dependency := &core.Application{}
+		err = dependency.FindById(ctx, dbWithoutTx, dependencyAppId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// deleted dependency can never be deployed
+				statuses[dependencyAppId] = core.DeploymentStatusFailed
+				continue
+			}
+			return err
+		}
+		dependencyDeployment, err := core.FindLatestDeploymentByApplicationId(ctx, dbWithoutTx, dependencyAppId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statuses[dependency.Name] = core.DeploymentStatusFailed
+				continue
+			}
+			return err
+		}
+		statuses[dependency.Name] = dependencyDeployment.Status
+	}
+	state, failedDependencies := checkDependencies(statuses)
+	switch state {
+	case dependenciesDeployed:
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Dependencies are deployed, starting the build\n", false)
+		return m.EnqueueBuildApplicationRequest(request.AppId, request.DeploymentId)
+	case dependenciesPending:
+		if request.Check < maxDependenciesChecks {
+			return m.EnqueueWaitForDependenciesRequest(request.AppId, request.DeploymentId, request.DependencyAppIds, request.Check+1)
+		}
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Dependencies are not deployed in time\n", true)
+	case dependenciesFailed:
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, request.DeploymentId, "Dependencies failed to deploy > "+strings.Join(failedDependencies, ", ")+"\n", true)
+	}
+	return latestDeployment.UpdateStatus(ctx, dbWithoutTx, core.DeploymentStatusFailed)
+}
+
+// checkDependencies : dependencies are deployed once all of their latest deployments are deployed
+// Any failed or stopped dependency fails the wait, as it won't be deployed without a new deployment
+// Returns the names of the failed dependencies as well
+func checkDependencies(statuses map[string]core.DeploymentStatus) (dependenciesState, []string) {
+	failedDependencies := make([]string, 0)
+	isPending := false
+	for dependency, status := range statuses {
+		switch status {
+		case core.DeploymentStatusDeployed:
+		case core.DeploymentStatusFailed, core.DeploymentStatusStopped:
+			failedDependencies = append(failedDependencies, dependency)
+		default:
+			isPending = true
+		}
+	}
+	if len(failedDependencies) > 0 {
+		sort.Strings(failedDependencies)
+		return dependenciesFailed, failedDependencies
+	}
+	if isPending {
+		return dependenciesPending, failedDependencies
+	}
+	return dependenciesDeployed, failedDependencies
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	t.Run("all dependencies deployed", func(t *testing.T) {
+		state, failedDependencies := checkDependencies(map[string]core.DeploymentStatus{
+			"db":    core.DeploymentStatusDeployed,
+			"cache": core.DeploymentStatusDeployed,
+		})
+		assert.Equal(t, dependenciesDeployed, state)
+		assert.Empty(t, failedDependencies)
+	})
+	t.Run("dependency still building or deploying", func(t *testing.T) {
+		for _, status := range []core.DeploymentStatus{core.DeploymentStatusPending, core.DeploymentStatusDeployPending, core.DeploymentStatusCanary} {
+			state, failedDependencies := checkDependencies(map[string]core.DeploymentStatus{
+				"db":    core.DeploymentStatusDeployed,
+				"cache": status,
+			})
+			assert.Equal(t, dependenciesPending, state, status)
+			assert.Empty(t, failedDependencies, status)
+		}
+	})
+	t.Run("failed or stopped dependencies", func(t *testing.T) {
+		state, failedDependencies := checkDependencies(map[string]core.DeploymentStatus{
+			"db":     core.DeploymentStatusStopped,
+			"cache":  core.DeploymentStatusFailed,
+			"search": core.DeploymentStatusPending,
+			"queue":  core.DeploymentStatusDeployed,
+		})
+		assert.Equal(t, dependenciesFailed, state)
+		assert.Equal(t, []string{"cache", "db"}, failedDependencies)
+	})
+}
@@ -23,6 +23,80 @@ func (m Manager) EnqueueDeployApplicationRequestWithNoProxyUpdate(applicationId
 	})
 }
 
+func (m Manager) EnqueueDeployApplicationRequestWithNoVerification(applicationId string, deploymentId string) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(deployApplicationQueueName, DeployApplicationRequest{
+		AppId:             applicationId,
+		DeploymentId:      deploymentId,
+		IgnoreProxyUpdate: false,
+		SkipVerification:  true,
+	})
+}
+
+func (m Manager) EnqueueVerifyDeploymentRequest(applicationId string, deploymentId string, previousDeploymentId string) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(verifyDeploymentQueueName, VerifyDeploymentRequest{
+		AppId:                applicationId,
+		DeploymentId:         deploymentId,
+		PreviousDeploymentId: previousDeploymentId,
+	})
+}
+
+func (m Manager) EnqueuePromoteDeploymentRequest(applicationId string, deploymentId string) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(promoteDeploymentQueueName, PromoteDeploymentRequest{
+		AppId:        applicationId,
+		DeploymentId: deploymentId,
+	})
+}
+
+func (m Manager) EnqueueAbortDeploymentRequest(applicationId string, deploymentId string) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(abortDeploymentQueueName, AbortDeploymentRequest{
+		AppId:        applicationId,
+		DeploymentId: deploymentId,
+	})
+}
+
+func (m Manager) EnqueueRunCronJobRequest(applicationId string, runId uint) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(runCronJobQueueName, RunCronJobRequest{
+		AppId: applicationId,
+		RunId: runId,
+	})
+}
+
+func (m Manager) EnqueueScaleApplicationRequest(applicationId string, fromReplicas uint, toReplicas uint, usagePercent uint) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(scaleApplicationQueueName, ScaleApplicationRequest{
+		AppId:        applicationId,
+		FromReplicas: fromReplicas,
+		ToReplicas:   toReplicas,
+		UsagePercent: usagePercent,
+	})
+}
+
+func (m Manager) EnqueueUpdateBackendReplicasRequest(request ScaleApplicationRequest) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(updateBackendReplicasQueueName, UpdateBackendReplicasRequest(request))
+}
+
+func (m Manager) EnqueueSyncApplicationGroupRequest(applicationGroupId string) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTask(syncApplicationGroupQueueName, SyncApplicationGroupRequest{
+		ApplicationGroupId: applicationGroupId,
+	})
+}
+
+func (m Manager) EnqueueCheckCronJobRunRequest(applicationId string, runId uint, check uint) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTaskAfter(checkCronJobRunQueueName, CheckCronJobRunRequest{
+		AppId: applicationId,
+		RunId: runId,
+		Check: check,
+	}, cronJobRunCheckInterval)
+}
+
+func (m Manager) EnqueueWaitForDependenciesRequest(applicationId string, deploymentId string, dependencyApplicationIds []string, check uint) error {
+	return m.ServiceManager.TaskQueueClient.EnqueueTaskAfter(waitForDependenciesQueueName, WaitForDependenciesRequest{
+		AppId:            applicationId,
+		DeploymentId:     deploymentId,
+		DependencyAppIds: dependencyApplicationIds,
+		Check:            check,
+	}, dependenciesCheckInterval)
+}
+
 func (m Manager) EnqueueDeleteApplicationRequest(applicationId string) error {
 	return m.ServiceManager.TaskQueueClient.EnqueueTask(deleteApplicationQueueName, DeleteApplicationRequest{
 		Id: applicationId,
@@ -72,15 +146,17 @@ func (m Manager) EnqueueRedirectRuleDeleteRequest(redirectRuleId uint) error {
 	})
 }
 
-func (m Manager) EnqueuePersistentVolumeBackupRequest(persistentVolumeBackupId uint) error {
+func (m Manager) EnqueuePersistentVolumeBackupRequest(persistentVolumeBackupId uint, persistentVolumeId uint) error {
 	return m.ServiceManager.TaskQueueClient.EnqueueTask(persistentVolumeBackupQueueName, PersistentVolumeBackupRequest{
-		Id: persistentVolumeBackupId,
+		Id:                 persistentVolumeBackupId,
+		PersistentVolumeId: persistentVolumeId,
 	})
 }
 
-func (m Manager) EnqueuePersistentVolumeRestoreRequest(persistentVolumeRestoreId uint) error {
+func (m Manager) EnqueuePersistentVolumeRestoreRequest(persistentVolumeRestoreId uint, persistentVolumeId uint) error {
 	return m.ServiceManager.TaskQueueClient.EnqueueTask(persistentVolumeRestoreQueueName, PersistentVolumeRestoreRequest{
-		Id: persistentVolumeRestoreId,
+		Id:                 persistentVolumeRestoreId,
+		PersistentVolumeId: persistentVolumeId,
 	})
 }
 
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	containermanger "github.com/swiftwave-org/swiftwave/pkg/container_manager"
+	"github.com/swiftwave-org/swiftwave/swiftwave_service/core"
+)
+
+const (
+	// releaseCommandTimeout : maximum time given to the release command to complete
+	releaseCommandTimeout      = 30 * time.Minute
+	releaseCommandPollInterval = 2 * time.Second
+	// releaseCommandLogFlushDelay : time given to the log stream to catch up after the command has completed
+	releaseCommandLogFlushDelay = 3 * time.Second
+)
+
+// isReleaseCommandRequired : release command runs once per deployment
+// It's skipped for automatic rollbacks, promotions and re-deployments of the deployed deployment
+func isReleaseCommandRequired(request DeployApplicationRequest, application *core.Application, previousDeploymentId string) bool {
+	if strings.TrimSpace(application.ReleaseCommand) == "" {
+		return false
+	}
+	if request.SkipVerification || request.IgnoreDeploymentStrategy || request.IgnoreProxyUpdate {
+		return false
+	}
+	return previousDeploymentId != request.DeploymentId
+}
+
+// runReleaseCommand : run the release command with the image and environment of the deployment as a one-off task
+// The output is streamed to the deployment logs, an error is returned if the command doesn't succeed
+func (m Manager) runReleaseCommand(application *core.Application, deployment *core.Deployment, service containermanger.Service, imageRegistryUsername string, imageRegistryPassword string, refetchImage bool, dockerManager *containermanger.Manager) error {
+	dbWithoutTx := m.ServiceManager.DbClient
+	pubSubClient := m.ServiceManager.PubSubClient
+	addLog := func(content string) {
+		addPersistentDeploymentLog(dbWithoutTx, pubSubClient, deployment.ID, content, false)
+	}
+	command, err := application.ReleaseCommandArgs()
+	if err != nil {
+		return err
+	}
+	service.Name = application.ReleaseCommandServiceName()
+	service.DeploymentMode = containermanger.DeploymentModeReplicatedJob
+	service.Replicas = 1
+	service.Command = command
+	service.CustomHealthCheck = containermanger.CustomHealthCheck{}
+	// remove the leftover of an interrupted deployment
+	_ = dockerManager.RemoveService(service.Name)
+	addLog(fmt.Sprintf("Running release command `%s`\n", application.ReleaseCommand))
+	err = dockerManager.CreateService(service, imageRegistryUsername, imageRegistryPassword, refetchImage)
+	if err != nil {
+		return errors.New("failed to start release command > " + err.Error())
+	}
+	defer func() {
+		err := dockerManager.RemoveService(service.Name)
+		if err != nil {
+			log.Println("failed to remove release command service "+service.Name, err)
+		}
+	}()
+	// stream the output to the deployment logs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logsStreamed := make(chan struct{})
+	go func() {
+		defer close(logsStreamed)
+		err := dockerManager.FollowJobLogs(ctx, service.Name, func(line string) {
+			addLog(line + "\n")
+		})
+		if err != nil {
+			log.Println("failed to stream logs of release command", err)
+		}
+	}()
+	// wait for the command to complete
+	deadline := time.Now().Add(releaseCommandTimeout)
+	var status containermanger.JobStatus
+	for !status.Completed {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("release command didn't complete within %s", releaseCommandTimeout)
+		}
+		<-time.After(releaseCommandPollInterval)
+		status, err = dockerManager.JobStatus(service.Name)
+		if err != nil {
+			// can be a temporary issue with the swarm manager, retry till the deadline
+			log.Println("failed to fetch status of release command", err)
+		}
+	}
+	select {
+	case <-logsStreamed:
+	case <-time.After(releaseCommandLogFlushDelay):
+	}
+	cancel()
+	<-logsStreamed
+	if !status.Succeeded {
+		return fmt.Errorf("release command failed with exit code %d > %s", status.ExitCode, status.Message)
+	}
+	addLog("Release command completed successfully\n")
+	return nil
+}
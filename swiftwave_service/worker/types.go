@@ -3,6 +3,7 @@ package worker
 import (
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/config"
 	"github.com/swiftwave-org/swiftwave/swiftwave_service/service_manager"
+	"strconv"
 )
 
 // Manager : hold references to other functions of service
@@ -30,6 +31,15 @@ const (
 	setupAndEnableProxyQueueName                               = "setup_and_enable_proxy"
 	deletePersistentVolumeQueueName                            = "delete_persistent_volume"
 	updateApplicationOnServerScheduleDeploymentUpdateQueueName = "update_application_on_server_schedule_deployment_status_update"
+	verifyDeploymentQueueName                                  = "verify_deployment"
+	promoteDeploymentQueueName                                 = "promote_deployment"
+	abortDeploymentQueueName                                   = "abort_deployment"
+	runCronJobQueueName                                        = "run_cron_job"
+	syncApplicationGroupQueueName                              = "sync_application_group"
+	scaleApplicationQueueName                                  = "scale_application"
+	checkCronJobRunQueueName                                   = "check_cron_job_run"
+	waitForDependenciesQueueName                               = "wait_for_dependencies"
+	updateBackendReplicasQueueName                             = "update_backend_replicas"
 )
 
 // Request Payload
@@ -39,6 +49,9 @@ type DeployApplicationRequest struct {
 	AppId             string `json:"app_id"`
 	DeploymentId      string `json:"deployment_id"`
 	IgnoreProxyUpdate bool   `json:"ignore_proxy_update"`
+	SkipVerification  bool   `json:"skip_verification"` // set for automatic rollbacks, so a failed rollback doesn't trigger another one
+	// IgnoreDeploymentStrategy - update the service in place even if blue/green or canary strategy is configured, used on promotion
+	IgnoreDeploymentStrategy bool `json:"ignore_deployment_strategy"`
 }
 
 // BuildApplicationRequest : request payload for deploy application
@@ -90,12 +103,14 @@ type DeleteApplicationRequest struct {
 
 // PersistentVolumeBackupRequest : request payload for persistent volume backup
 type PersistentVolumeBackupRequest struct {
-	Id uint `json:"id"`
+	Id                 uint `json:"id"`
+	PersistentVolumeId uint `json:"persistent_volume_id"` // used to serialize the tasks of the volume
 }
 
 // PersistentVolumeRestoreRequest : request payload for persistent volume restore
 type PersistentVolumeRestoreRequest struct {
-	Id uint `json:"id"`
+	Id                 uint `json:"id"`
+	PersistentVolumeId uint `json:"persistent_volume_id"` // used to serialize the tasks of the volume
 }
 
 // InstallDependenciesOnServerRequest : request payload for install dependencies on server
@@ -126,3 +141,164 @@ type PersistentVolumeDeletionRequest struct {
 type UpdateApplicationOnServerScheduleDeploymentStatusUpdateRequest struct {
 	ServerId uint `json:"server_id"`
 }
+
+// VerifyDeploymentRequest : request payload for post-deploy verification of a deployment
+type VerifyDeploymentRequest struct {
+	AppId                string `json:"app_id"`
+	DeploymentId         string `json:"deployment_id"`
+	PreviousDeploymentId string `json:"previous_deployment_id"` // deployment to restore if the verification fails
+}
+
+// PromoteDeploymentRequest : request payload for promotion of blue/green or canary deployment
+type PromoteDeploymentRequest struct {
+	AppId        string `json:"app_id"`
+	DeploymentId string `json:"deployment_id"`
+}
+
+// AbortDeploymentRequest : request payload for abort of blue/green or canary deployment
+type AbortDeploymentRequest struct {
+	AppId        string `json:"app_id"`
+	DeploymentId string `json:"deployment_id"`
+}
+
+// RunCronJobRequest : request payload for a run of cron job application
+type RunCronJobRequest struct {
+	AppId string `json:"app_id"`
+	RunId uint   `json:"run_id"`
+}
+
+// CheckCronJobRunRequest : request payload for checking the job of a run of cron job application
+type CheckCronJobRunRequest struct {
+	AppId string `json:"app_id"`
+	RunId uint   `json:"run_id"`
+	Check uint   `json:"check"` // number of the check, keeps the payload of the next check different from the running one
+}
+
+// ScaleApplicationRequest : request payload for scaling of application by auto scaling
+type ScaleApplicationRequest struct {
+	AppId        string `json:"app_id"`
+	FromReplicas uint   `json:"from_replicas"` // replicas on which the decision was made, the request is dropped if those have changed meanwhile
+	ToReplicas   uint   `json:"to_replicas"`
+	UsagePercent uint   `json:"usage_percent"`
+}
+
+// WaitForDependenciesRequest : request payload for holding the build of a deployment until its dependencies are deployed
+type WaitForDependenciesRequest struct {
+	AppId            string   `json:"app_id"`
+	DeploymentId     string   `json:"deployment_id"`
+	DependencyAppIds []string `json:"dependency_app_ids"`
+	Check            uint     `json:"check"` // number of the check, keeps the payload of the next check different from the running one
+}
+
+// UpdateBackendReplicasRequest : request payload for retrying the update of the proxies after auto scaling, same as the scaling request
+type UpdateBackendReplicasRequest struct {
+	AppId        string `json:"app_id"`
+	FromReplicas uint   `json:"from_replicas"`
+	ToReplicas   uint   `json:"to_replicas"` // replicas of the scaling, the request is dropped if those have changed meanwhile
+	UsagePercent uint   `json:"usage_percent"`
+}
+
+// SyncApplicationGroupRequest : request payload for sync of application group from git repository
+type SyncApplicationGroupRequest struct {
+	ApplicationGroupId string `json:"application_group_id"`
+}
+
+// Concurrency keys
+// At most one task of a key runs at a time across all the workers, tasks of a busy key are postponed by the task queue
+
+func applicationConcurrencyKey(appId string) string {
+	return "application:" + appId
+}
+
+// ApplicationTaskRunLabel : label of the task runs of an application in the task run history
+func ApplicationTaskRunLabel(appId string) string {
+	return applicationConcurrencyKey(appId)
+}
+
+func ingressRuleConcurrencyKey(id uint) string {
+	return "ingress_rule:" + strconv.Itoa(int(id))
+}
+
+// persistentVolumeConcurrencyKey : empty key for the tasks enqueued without volume id, they are not serialized
+func persistentVolumeConcurrencyKey(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return "persistent_volume:" + strconv.Itoa(int(id))
+}
+
+func (r BuildApplicationRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.AppId)
+}
+
+func (r DeployApplicationRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.AppId)
+}
+
+func (r DeleteApplicationRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.Id)
+}
+
+func (r ScaleApplicationRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.AppId)
+}
+
+func (r UpdateBackendReplicasRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.AppId)
+}
+
+func (r PromoteDeploymentRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.AppId)
+}
+
+func (r AbortDeploymentRequest) ConcurrencyKey() string {
+	return applicationConcurrencyKey(r.AppId)
+}
+
+// VerifyDeploymentRequest is not serialized, it only watches the rollout for minutes and stops by itself once a newer deployment is deployed
+// Its runs are still labeled with the application in the task run history
+func (r VerifyDeploymentRequest) TaskRunLabel() string {
+	return ApplicationTaskRunLabel(r.AppId)
+}
+
+// Runs of cron job are not serialized, concurrency policy of the cron job decides whether those can run in parallel
+func (r RunCronJobRequest) TaskRunLabel() string {
+	return ApplicationTaskRunLabel(r.AppId)
+}
+
+func (r CheckCronJobRunRequest) TaskRunLabel() string {
+	return ApplicationTaskRunLabel(r.AppId)
+}
+
+// WaitForDependenciesRequest is not serialized, it only enqueues the build once the dependencies are deployed
+func (r WaitForDependenciesRequest) TaskRunLabel() string {
+	return ApplicationTaskRunLabel(r.AppId)
+}
+
+func (r IngressRuleApplyRequest) ConcurrencyKey() string {
+	return ingressRuleConcurrencyKey(r.Id)
+}
+
+func (r IngressRuleDeleteRequest) ConcurrencyKey() string {
+	return ingressRuleConcurrencyKey(r.Id)
+}
+
+func (r IngressRuleHttpsRedirectRequest) ConcurrencyKey() string {
+	return ingressRuleConcurrencyKey(r.Id)
+}
+
+func (r PersistentVolumeBackupRequest) ConcurrencyKey() string {
+	return persistentVolumeConcurrencyKey(r.PersistentVolumeId)
+}
+
+func (r PersistentVolumeRestoreRequest) ConcurrencyKey() string {
+	return persistentVolumeConcurrencyKey(r.PersistentVolumeId)
+}
+
+func (r PersistentVolumeDeletionRequest) ConcurrencyKey() string {
+	return persistentVolumeConcurrencyKey(r.Id)
+}
+
+func (r SyncApplicationGroupRequest) ConcurrencyKey() string {
+	return "application_group:" + r.ApplicationGroupId
+}